@@ -0,0 +1,159 @@
+package eventsv1_test
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/manabie-com/togo/api/eventsv1"
+	"github.com/manabie-com/togo/internal/notify"
+	"github.com/manabie-com/togo/internal/push"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var update = flag.Bool("update", false, "add the new fields to events.lock")
+
+// emitted pairs each message with the struct sent as its JSON
+var emitted = map[protoreflect.FullName]interface{}{
+	"togo.events.v1.QuotaWarning":     push.QuotaWarning{},
+	"togo.events.v1.LimitReached":     notify.LimitReached{},
+	"togo.events.v1.CarryOverSkipped": notify.CarryOverSkipped{},
+}
+
+// TestEventsLock fails when the event schemas break consumers: a field of events.lock that
+// was removed, renumbered or retyped, a new field missing from the lock, or an event struct
+// whose JSON doesn't match its message. Run with -update to add the new fields to the lock
+// once they're released.
+func TestEventsLock(t *testing.T) {
+	const lockPath = "events.lock"
+	current := fields(eventsv1.File_eventsv1_events_proto)
+	locked, err := readLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var problems []string
+	for name, want := range locked {
+		got, ok := current[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s was removed or renamed", name))
+		case got != want:
+			problems = append(problems, fmt.Sprintf("%s changed from %s to %s", name, want, got))
+		}
+	}
+	var added []string
+	for name := range current {
+		if _, ok := locked[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	problems = append(problems, mismatches(eventsv1.File_eventsv1_events_proto)...)
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		t.Fatal("incompatible event schema changes:\n  " + strings.Join(problems, "\n  "))
+	}
+	if len(added) == 0 {
+		return
+	}
+	sort.Strings(added)
+	if !*update {
+		t.Fatal("fields missing from " + lockPath + ", run with -update once they're released:\n  " + strings.Join(added, "\n  "))
+	}
+	if err := writeLock(lockPath, current); err != nil {
+		t.Fatal(err)
+	}
+	t.Log("locked", strings.Join(added, ", "))
+}
+
+// fields maps the full name of each field of fd's messages to "<number> <cardinality> <kind>"
+func fields(fd protoreflect.FileDescriptor) map[string]string {
+	m := make(map[string]string)
+	msgs := fd.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		fs := msgs.Get(i).Fields()
+		for j := 0; j < fs.Len(); j++ {
+			f := fs.Get(j)
+			kind := f.Kind().String()
+			if f.Message() != nil {
+				kind = string(f.Message().FullName())
+			}
+			m[string(f.FullName())] = fmt.Sprintf("%d %s %s", f.Number(), f.Cardinality(), kind)
+		}
+	}
+	return m
+}
+
+// mismatches lists the JSON fields of the emitted structs and the message fields that don't match
+func mismatches(fd protoreflect.FileDescriptor) []string {
+	var problems []string
+	for name, v := range emitted {
+		md := fd.Messages().ByName(name.Name())
+		if md == nil {
+			problems = append(problems, fmt.Sprintf("%s is emitted but not defined", name))
+			continue
+		}
+		seen := make(map[string]bool)
+		t := reflect.TypeOf(v)
+		for i := 0; i < t.NumField(); i++ {
+			tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			seen[tag] = true
+			if md.Fields().ByName(protoreflect.Name(tag)) == nil {
+				problems = append(problems, fmt.Sprintf("%s.%s sends %q, not a field of %s", t.PkgPath(), t.Name(), tag, name))
+			}
+		}
+		for i := 0; i < md.Fields().Len(); i++ {
+			if f := md.Fields().Get(i); !seen[string(f.Name())] {
+				problems = append(problems, fmt.Sprintf("%s is never sent by %s.%s", f.FullName(), t.PkgPath(), t.Name()))
+			}
+		}
+	}
+	return problems
+}
+
+func readLock(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s: malformed line %q", path, line)
+		}
+		m[parts[0]] = parts[1]
+	}
+	return m, sc.Err()
+}
+
+func writeLock(path string, m map[string]string) error {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Published event fields: <field> <number> <cardinality> <kind>. Written by go test -update.\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s %s\n", name, m[name])
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,4 @@
+// Package eventsv1 holds the protobuf schemas of the events togo sends
+package eventsv1
+
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative eventsv1/events.proto
@@ -0,0 +1,4 @@
+// Package togov1 holds the protobuf messages of the togo API
+package togov1
+
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative togov1/togo.proto
@@ -0,0 +1,137 @@
+// Command smoketest checks a deployed togo end to end, for use as a post-deploy gate. It
+// signs up a throwaway user with an invite, adds tasks up to their daily limit, checks the
+// next one is refused, lists them, then deletes them and deactivates the user.
+//
+// Usage:
+//
+//	TOGO_PASSWORD=example smoketest -admin firstUser [-url http://localhost:5050] [-timeout 30s]
+//
+// The -admin user creates the invite and deactivates the user afterwards, so must be an
+// administrator; with TOGO_USERS_INVITE any user can run it, and the user is left active.
+// It exits 1 when a check fails.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manabie-com/togo/pkg/client"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:5050", "base URL of the togo API")
+	admin := flag.String("admin", "", "user ID creating the invite, its password is read from TOGO_PASSWORD")
+	timeout := flag.Duration("timeout", 30*time.Second, "time the whole test may take")
+	flag.Parse()
+	if *admin == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := run(ctx, client.New(*url, *admin, os.Getenv("TOGO_PASSWORD"))); err != nil {
+		fmt.Fprintln(os.Stderr, "smoketest: FAIL:", err)
+		os.Exit(1)
+	}
+	fmt.Println("smoketest: PASS")
+}
+
+func run(ctx context.Context, admin *client.Client) (err error) {
+	code, err := admin.CreateInvite(ctx)
+	if err != nil {
+		return fmt.Errorf("creating an invite as %s: %w", admin.UserID, err)
+	}
+	user := client.New(admin.BaseURL, "smoketest-"+uuid.New().String(), uuid.New().String())
+	user.HTTP = admin.HTTP
+	if err := user.SignUp(ctx, code); err != nil {
+		return fmt.Errorf("signing up: %w", err)
+	}
+	step("signed up %s", user.UserID)
+
+	var added []string
+	defer func() {
+		// cleaning up runs after a failure too, and fails the test when it doesn't work
+		if cerr := cleanUp(ctx, admin, user, added); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	_, remaining, err := user.CountTasks(ctx, "")
+	if err != nil {
+		return fmt.Errorf("counting tasks: %w", err)
+	}
+	if remaining == 0 {
+		return errors.New("a new user can't add any task today")
+	}
+	for i := 0; i < remaining; i++ {
+		t, err := user.CreateTask(ctx, client.NewTask{Content: fmt.Sprintf("smoke test task %d", i+1)})
+		if err != nil {
+			return fmt.Errorf("adding task %d of %d: %w", i+1, remaining, err)
+		}
+		added = append(added, t.ID)
+	}
+	step("added %d tasks, the daily limit", remaining)
+
+	_, err = user.CreateTask(ctx, client.NewTask{Content: "smoke test task over the limit"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		return fmt.Errorf("adding a task over the limit: got %v, want 403 daily task limit reached", err)
+	}
+	step("the task over the limit was refused: %s", apiErr.Message)
+
+	tasks, err := user.ListTasks(ctx, client.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+	listed := map[string]bool{}
+	for _, t := range tasks {
+		listed[t.ID] = true
+	}
+	for _, id := range added {
+		if !listed[id] {
+			return fmt.Errorf("listing tasks: task %s is missing", id)
+		}
+	}
+	if len(tasks) != len(added) {
+		return fmt.Errorf("listing tasks: got %d tasks, want %d", len(tasks), len(added))
+	}
+	step("listed the %d tasks", len(tasks))
+	return nil
+}
+
+// cleanUp deletes the tasks added and deactivates the user, as users can't be deleted
+func cleanUp(ctx context.Context, admin, user *client.Client, added []string) error {
+	if len(added) > 0 {
+		notFound, err := user.DeleteTasks(ctx, added)
+		if err != nil {
+			return fmt.Errorf("deleting tasks: %w", err)
+		}
+		if len(notFound) > 0 {
+			return fmt.Errorf("deleting tasks: %d weren't found", len(notFound))
+		}
+		step("deleted the tasks")
+	}
+
+	err := admin.DeactivateUser(ctx, user.UserID)
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		step("%s isn't an administrator, %s is left active", admin.UserID, user.UserID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deactivating %s: %w", user.UserID, err)
+	}
+	step("deactivated %s", user.UserID)
+	return nil
+}
+
+func step(format string, args ...interface{}) {
+	fmt.Printf("ok    "+format+"\n", args...)
+}
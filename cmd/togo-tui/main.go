@@ -0,0 +1,193 @@
+// Command togo-tui lists, adds and completes today's tasks from the terminal.
+//
+// Usage:
+//
+//	TOGO_PASSWORD=example togo-tui -user firstUser [-url http://localhost:5050]
+//
+// Keys: up/down (or k/j) move, enter or space completes, a adds a task, r reloads, q quits.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/manabie-com/togo/pkg/client"
+)
+
+// requestTimeout bounds each call to the API
+const requestTimeout = 10 * time.Second
+
+func main() {
+	url := flag.String("url", "http://localhost:5050", "base URL of the togo API")
+	user := flag.String("user", "", "user ID to log in as, its password is read from TOGO_PASSWORD")
+	flag.Parse()
+	if *user == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	m := &model{api: client.New(*url, *user, os.Getenv("TOGO_PASSWORD"))}
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "togo-tui:", err)
+		os.Exit(1)
+	}
+}
+
+type model struct {
+	api    *client.Client
+	tasks  []*client.Task
+	cursor int
+	// adding is true while the content of a new task is typed into input
+	adding bool
+	input  []rune
+	status string
+}
+
+// messages the commands answer with
+type (
+	tasksMsg   []*client.Task
+	changedMsg string
+	errMsg     struct{ error }
+)
+
+func (m *model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *model) load() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	tasks, err := m.api.ListTasks(ctx, client.ListOptions{})
+	if err != nil {
+		return errMsg{err}
+	}
+	return tasksMsg(tasks)
+}
+
+func (m *model) create(content string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if _, err := m.api.CreateTask(ctx, client.NewTask{Content: content}); err != nil {
+			return errMsg{err}
+		}
+		return changedMsg("added " + content)
+	}
+}
+
+func (m *model) complete(t *client.Task) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if _, err := m.api.CompleteTask(ctx, t.ID); err != nil {
+			return errMsg{err}
+		}
+		return changedMsg("completed " + t.Content)
+	}
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tasksMsg:
+		m.tasks = msg
+		if m.cursor >= len(m.tasks) {
+			m.cursor = len(m.tasks) - 1
+		}
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+	case changedMsg:
+		m.status = string(msg)
+		return m, m.load
+	case errMsg:
+		m.status = "error: " + msg.Error()
+	case tea.KeyMsg:
+		if m.adding {
+			return m, m.typed(msg)
+		}
+		return m, m.pressed(msg)
+	}
+	return m, nil
+}
+
+// typed edits the content of the task being added
+func (m *model) typed(key tea.KeyMsg) tea.Cmd {
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		m.adding, m.input = false, nil
+	case tea.KeyEnter:
+		content := strings.TrimSpace(string(m.input))
+		m.adding, m.input = false, nil
+		if content != "" {
+			return m.create(content)
+		}
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input = append(m.input, key.Runes...)
+	}
+	return nil
+}
+
+func (m *model) pressed(key tea.KeyMsg) tea.Cmd {
+	switch key.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.tasks)-1 {
+			m.cursor++
+		}
+	case "enter", " ":
+		if m.cursor < len(m.tasks) && m.tasks[m.cursor].CompletedAt == "" {
+			return m.complete(m.tasks[m.cursor])
+		}
+	case "a":
+		m.adding, m.status = true, ""
+	case "r":
+		m.status = ""
+		return m.load
+	}
+	return nil
+}
+
+func (m *model) View() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Today's tasks, %s\n\n", time.Now().Format("Mon Jan 2"))
+	if len(m.tasks) == 0 {
+		b.WriteString("  nothing yet\n")
+	}
+	for i, t := range m.tasks {
+		cursor, done := " ", " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		if t.CompletedAt != "" {
+			done = "x"
+		}
+		fmt.Fprintf(&b, "%s [%s] %s\n", cursor, done, t.Content)
+	}
+
+	b.WriteString("\n")
+	if m.adding {
+		fmt.Fprintf(&b, "new task: %s_\n\nenter adds, esc cancels\n", string(m.input))
+		return b.String()
+	}
+	if m.status != "" {
+		b.WriteString(m.status + "\n\n")
+	}
+	b.WriteString("up/down move · enter complete · a add · r reload · q quit\n")
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manabie-com/togo/internal/auth"
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// apikeyCreate prints a new API key for the user named by the first argument,
+// the other arguments name the key
+func apikeyCreate(ctx context.Context, store *sqllite.LiteDB, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: togoctl apikey create <user_id> [name]")
+	}
+
+	if _, err := store.RetrievePasswordHash(ctx, sql.NullString{String: args[0], Valid: true}); err != nil {
+		return fmt.Errorf("user %s: %w", args[0], err)
+	}
+
+	key, err := auth.NewAPIKey()
+	if err != nil {
+		return err
+	}
+	err = store.AddAPIKey(ctx, &storages.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    args[0],
+		Name:      strings.Join(args[1:], " "),
+		KeyHash:   auth.HashAPIKey(key),
+		CreatedAt: time.Now().UTC().Format(storages.TimeLayout),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(key)
+	return nil
+}
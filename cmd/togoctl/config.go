@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/manabie-com/togo/internal/config"
+	"github.com/manabie-com/togo/internal/idgen"
+	"github.com/manabie-com/togo/internal/metrics"
+	"github.com/manabie-com/togo/internal/moderation"
+	"github.com/manabie-com/togo/internal/password"
+	"github.com/manabie-com/togo/internal/rules"
+	"github.com/manabie-com/togo/internal/secrets"
+	"github.com/manabie-com/togo/internal/signing"
+	"github.com/manabie-com/togo/internal/tlsconfig"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// minJWTKeyLen is the shortest TOGO_JWT_KEY accepted, HS256 keys ought to be longer still
+const minJWTKeyLen = 16
+
+// configErr is why config.Load failed, config validate reports it with the other problems
+var configErr error
+
+// configValidate checks what the server would fail on or shouldn't run with, without
+// starting it or changing the database, and fails when anything is wrong
+func configValidate(ctx context.Context, cfg config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("config validate takes no arguments")
+	}
+
+	problems := 0
+	report := func(name, detail string, err error) {
+		if err != nil {
+			problems++
+			fmt.Printf("FAIL  %s: %v\n", name, err)
+			return
+		}
+		fmt.Printf("ok    %s: %s\n", name, detail)
+	}
+	// warnings are what the server runs with but production shouldn't
+	warn := func(name, detail string) {
+		fmt.Printf("warn  %s: %s\n", name, detail)
+	}
+	if configErr != nil {
+		report("environment", "", configErr)
+	}
+
+	resolver := secrets.NewResolver()
+	resolved := map[string]string{}
+	for _, s := range secretSettings(&cfg) {
+		if *s.value == "" {
+			continue
+		}
+		if !secrets.IsReference(*s.value) {
+			resolved[s.env] = *s.value
+			continue
+		}
+		v, err := resolver.Resolve(ctx, *s.value)
+		report(s.env, "resolved "+*s.value, err)
+		if err == nil {
+			resolved[s.env] = v
+		}
+	}
+
+	// a key that didn't resolve was reported already
+	if jwtKey, ok := resolved["TOGO_JWT_KEY"]; ok || cfg.JWTKey == "" {
+		switch {
+		case jwtKey == config.DefaultJWTKey && cfg.Dev:
+			warn("jwt key", "TOGO_JWT_KEY is the default, which TOGO_DEV allows, anyone can sign tokens with it")
+		case jwtKey == config.DefaultJWTKey:
+			report("jwt key", "", errors.New("TOGO_JWT_KEY is the default, anyone can sign tokens with it, set a key or TOGO_DEV=true"))
+		case len(jwtKey) < minJWTKeyLen:
+			report("jwt key", "", fmt.Errorf("TOGO_JWT_KEY is %d bytes, at least %d are needed", len(jwtKey), minJWTKeyLen))
+		default:
+			report("jwt key", fmt.Sprintf("%d bytes", len(jwtKey)), nil)
+		}
+	}
+
+	backend, detail, err := storages.Check(ctx, cfg.DBBackend, storages.OpenOptions{DSN: cfg.DBPath})
+	report("database", cfg.DBBackend+", "+detail, err)
+	if backend != nil {
+		defer backend.DB.Close()
+		report(checkSigningKeys(ctx, backend.Store))
+	}
+
+	_, err = idgen.New(cfg.IDStrategy, cfg.NodeID)
+	report("id strategy", cfg.IDStrategy, err)
+	_, err = password.New(cfg.PasswordHash)
+	report("password hash", cfg.PasswordHash, err)
+	_, err = metrics.New(cfg.Metrics, cfg.StatsDAddr, cfg.StatsDPrefix)
+	report("metrics", cfg.Metrics, err)
+
+	if cfg.TLSCert != "" || cfg.TLSKey != "" {
+		_, err = tlsconfig.New(tlsconfig.Files{Cert: cfg.TLSCert, Key: cfg.TLSKey, ClientCA: cfg.TLSClientCA})
+		report("tls", cfg.TLSCert, err)
+	}
+	if cfg.RulesDir != "" {
+		scripts, err := rules.Load(cfg.RulesDir, time.Duration(cfg.RulesTimeoutMS)*time.Millisecond)
+		if err == nil {
+			detail = fmt.Sprintf("%d rules", scripts.Len())
+		}
+		report("rules", detail, err)
+	}
+	if terms, ok := resolved["TOGO_BLOCKED_TERMS"]; ok {
+		blocked, err := moderation.Parse(terms)
+		if err == nil {
+			detail = fmt.Sprintf("%d terms", blocked.Len())
+		}
+		report("blocked terms", detail, err)
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problems", problems)
+	}
+	return nil
+}
+
+// checkSigningKeys loads the keys tokens are signed and verified with, like servers do
+func checkSigningKeys(ctx context.Context, store storages.Store) (string, string, error) {
+	keys, err := store.RetrieveSigningKeys(ctx, time.Now().UTC().Format(storages.TimeLayout))
+	if err != nil {
+		return "signing keys", "", err
+	}
+	if _, err := signing.NewRing(keys); err != nil {
+		return "signing keys", "", err
+	}
+	if len(keys) == 0 {
+		return "signing keys", "none, tokens are signed with TOGO_JWT_KEY", nil
+	}
+	return "signing keys", fmt.Sprintf("%d, %s signs tokens", len(keys), keys[0].ID), nil
+}
+
+// configPrint prints the config the server would run with as JSON, with secrets that aren't
+// references redacted
+func configPrint(ctx context.Context, cfg config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("config print takes no arguments")
+	}
+
+	for _, s := range secretSettings(&cfg) {
+		if *s.value != "" && !secrets.IsReference(*s.value) {
+			*s.value = "REDACTED"
+		}
+	}
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	return out.Encode(cfg)
+}
+
+// secretSetting is a setting holding a secret or a reference to one
+type secretSetting struct {
+	env   string
+	value *string
+}
+
+// secretSettings returns the settings of cfg the server resolves as secrets
+func secretSettings(cfg *config.Config) []secretSetting {
+	return []secretSetting{
+		{"TOGO_JWT_KEY", &cfg.JWTKey},
+		{"TOGO_CAPTCHA_SECRET", &cfg.CaptchaSecret},
+		{"TOGO_OIDC_CLIENT_SECRET", &cfg.OIDCClientSecret},
+		{"TOGO_LIMIT_WEBHOOK_SECRET", &cfg.LimitWebhookSecret},
+		{"TOGO_SMTP_PASSWORD", &cfg.SMTPPassword},
+		{"TOGO_STANDUP_SLACK_URL", &cfg.StandupSlackURL},
+		{"TOGO_BLOCKED_TERMS", &cfg.BlockedTerms},
+		{"TOGO_SIGNUP_BLOCKED_DOMAINS", &cfg.SignupBlockedDomains},
+		{"TOGO_ARCHIVE_SECRET_ACCESS_KEY", &cfg.ArchiveSecretAccessKey},
+	}
+}
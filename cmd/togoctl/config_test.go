@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manabie-com/togo/internal/config"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// validateEnv is the config a server loads with env set, on a database in a directory of its own
+func validateEnv(t *testing.T, env map[string]string) config.Config {
+	t.Helper()
+	t.Setenv("TOGO_DB_PATH", filepath.Join(t.TempDir(), "togo.db"))
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return cfg
+}
+
+func TestConfigValidateDevKey(t *testing.T) {
+	ctx := context.Background()
+	cfg := validateEnv(t, map[string]string{"TOGO_DEV": "true"})
+	if err := configValidate(ctx, cfg, nil); err != nil {
+		t.Errorf("config validate of the dev key under TOGO_DEV: %v", err)
+	}
+
+	cfg = validateEnv(t, map[string]string{"TOGO_DEV": "false"})
+	if err := configValidate(ctx, cfg, nil); err == nil || err.Error() != "1 problems" {
+		t.Errorf("config validate of the dev key outside TOGO_DEV: got %v, want 1 problem", err)
+	}
+}
+
+func TestConfigValidateBackend(t *testing.T) {
+	ctx := context.Background()
+	cfg := validateEnv(t, map[string]string{"TOGO_DEV": "true", "TOGO_DB_BACKEND": "nosuch"})
+	if err := configValidate(ctx, cfg, nil); err == nil {
+		t.Error("config validate of an unknown backend succeeded")
+	}
+
+	// the database the server created checks out, signing keys included
+	cfg = validateEnv(t, map[string]string{"TOGO_DEV": "true", "TOGO_DB_BACKEND": "sqlite"})
+	b, err := storages.Open(ctx, cfg.DBBackend, storages.OpenOptions{DSN: cfg.DBPath})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	b.DB.Close()
+	backend, detail, err := storages.Check(ctx, cfg.DBBackend, storages.OpenOptions{DSN: cfg.DBPath})
+	if err != nil || backend == nil || !strings.Contains(detail, "at migration") {
+		t.Fatalf("Check of a migrated database: got %v, %q, %v", backend, detail, err)
+	}
+	defer backend.DB.Close()
+	if name, detail, err := checkSigningKeys(ctx, backend.Store); err != nil || !strings.HasPrefix(detail, "none") {
+		t.Errorf("%s: got %q, %v, want none yet", name, detail, err)
+	}
+	if err := configValidate(ctx, cfg, nil); err != nil {
+		t.Errorf("config validate of a migrated database: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+)
+
+// dbAnalyze prints the known queries of the store an index would help, with the steps of their
+// plans to blame, and the indexes none of them use
+func dbAnalyze(ctx context.Context, store *sqllite.LiteDB, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: togoctl db analyze")
+	}
+	advice, err := store.AdviseIndexes(ctx, sqllite.KnownQueries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d of %d known queries could use an index:\n", len(advice.Missing), len(sqllite.KnownQueries))
+	for _, plan := range advice.Missing {
+		fmt.Printf("  %s\n", plan.Pattern)
+		for _, step := range plan.Problems {
+			fmt.Printf("    %s\n", step)
+		}
+	}
+	fmt.Printf("%d indexes no known query uses:\n", len(advice.Unused))
+	for _, name := range advice.Unused {
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manabie-com/togo/internal/signing"
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+)
+
+// jwtRotate generates a signing key and puts it in use, servers pick it up within a minute
+func jwtRotate(ctx context.Context, store *sqllite.LiteDB, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("jwt rotate takes no arguments")
+	}
+
+	k, err := signing.Generate(time.Now())
+	if err != nil {
+		return err
+	}
+	if err := store.RotateSigningKey(ctx, k); err != nil {
+		return err
+	}
+
+	fmt.Println(k.ID)
+	return nil
+}
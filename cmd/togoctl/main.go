@@ -0,0 +1,110 @@
+// Command togoctl administers a togo database.
+//
+// Usage:
+//
+//	togoctl apikey create <user_id> [name]    print a new API key acting as the user
+//	togoctl config print                      print the config as JSON, secrets that aren't references redacted
+//	togoctl config validate                   check the config, database and keys without starting the server
+//	togoctl db analyze                        report the known queries missing an index and the indexes none of them use
+//	togoctl jwt rotate                        make a new key sign tokens, the previous one keeps verifying until its tokens expire
+//	togoctl tasks export <from> <to>          print the tasks created between two dates, both included, as JSON Lines
+//	togoctl user role <user_id> <admin|"">    change the role of a user
+//
+// It reads the same TOGO_* environment variables as the server.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manabie-com/togo/internal/config"
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// commands are keyed by their words, e.g. "jwt rotate"
+var commands = map[string]func(ctx context.Context, store *sqllite.LiteDB, args []string) error{
+	"apikey create": apikeyCreate,
+	"db analyze":    dbAnalyze,
+	"jwt rotate":    jwtRotate,
+	"tasks export":  tasksExport,
+	"user role":     userRole,
+}
+
+// configCommands only need the config, the database isn't opened for them
+var configCommands = map[string]func(ctx context.Context, cfg config.Config, args []string) error{
+	"config print":    configPrint,
+	"config validate": configValidate,
+}
+
+func main() {
+	name, args := lookup(os.Args[1:])
+	cfg, err := config.Load()
+	if err != nil && name != "config validate" {
+		fail(err)
+	}
+	configErr = err
+	ctx := context.Background()
+	if run, ok := configCommands[name]; ok {
+		if err := run(ctx, cfg, args); err != nil {
+			fail(err)
+		}
+		return
+	}
+	run, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	db, err := sql.Open("sqlite3", cfg.DBPath+"?_txlock=immediate")
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+
+	store := &sqllite.LiteDB{DB: db}
+	if err := store.Migrate(ctx); err != nil {
+		fail(err)
+	}
+
+	if err := run(ctx, store, args); err != nil {
+		fail(err)
+	}
+}
+
+// lookup returns the name of the command args start with and the arguments following it
+func lookup(args []string) (string, []string) {
+	for n := len(args); n > 0; n-- {
+		name := strings.Join(args[:n], " ")
+		if commands[name] != nil || configCommands[name] != nil {
+			return name, args[n:]
+		}
+	}
+	return "", nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: togoctl <command>\n\ncommands:")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	for name := range configCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  "+name)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "togoctl:", err)
+	os.Exit(1)
+}
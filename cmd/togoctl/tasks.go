@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+)
+
+// tasksExport prints every user's tasks created between two dates as JSON Lines, reading
+// them one at a time so exports of any size run in bounded memory
+func tasksExport(ctx context.Context, store *sqllite.LiteDB, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: togoctl tasks export <from> <to>")
+	}
+	for _, d := range args {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("date %q: want YYYY-MM-DD", d)
+		}
+	}
+
+	c, err := store.IterateTasks(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	w := bufio.NewWriter(os.Stdout)
+	enc := json.NewEncoder(w)
+	for c.Next() {
+		if err := enc.Encode(c.Task()); err != nil {
+			return err
+		}
+	}
+	if err := c.Err(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// userRole sets the role of a user, e.g. to create the first administrator
+func userRole(ctx context.Context, store *sqllite.LiteDB, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf(`usage: togoctl user role <user_id> <admin|"">`)
+	}
+	if args[1] != "" && args[1] != storages.RoleAdmin {
+		return fmt.Errorf("unknown role %q", args[1])
+	}
+
+	u, err := store.RetrieveUser(ctx, sql.NullString{String: args[0], Valid: true})
+	if err != nil {
+		return fmt.Errorf("user %s: %w", args[0], err)
+	}
+	u.Role = args[1]
+	return store.UpdateUser(ctx, u)
+}
@@ -1,9 +1,18 @@
 module github.com/manabie-com/togo
 
-go 1.14
+go 1.16
 
 require (
+	github.com/charmbracelet/bubbletea v0.23.1
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/go-playground/validator/v10 v10.11.1
 	github.com/google/uuid v1.1.1
 	github.com/mattn/go-sqlite3 v1.14.0
+	github.com/microcosm-cc/bluemonday v1.0.18
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	github.com/yuin/goldmark v1.4.11
+	github.com/yuin/gopher-lua v0.0.0-20220413183635-c841877397d8
+	golang.org/x/crypto v0.0.0-20211215153901-e495a2d5b3d3
+	google.golang.org/protobuf v1.28.1
 )
@@ -0,0 +1,158 @@
+// Package app starts the components of the server in order, each one once those before it
+// are ready, and stops them in reverse order so nothing outlives what it depends on
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Component is a part of the server with a lifecycle. Start returns once the component is
+// up, running its work in goroutines until Stop.
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Checker is implemented by components others can only start after, Ready returns nil
+// once they can be used
+type Checker interface {
+	Ready(ctx context.Context) error
+}
+
+// Failer is implemented by components that can fail after starting, e.g. a server whose
+// listener breaks. The channel receives at most one error.
+type Failer interface {
+	Failed() <-chan error
+}
+
+// App runs components
+type App struct {
+	// ReadyTimeout bounds how long a Checker may take to become ready, a minute by default
+	ReadyTimeout time.Duration
+	// ShutdownTimeout bounds stopping all the components, 30 seconds by default
+	ShutdownTimeout time.Duration
+
+	components []Component
+	started    []Component
+}
+
+// readyPoll is how often a Checker that isn't ready yet is asked again
+const readyPoll = 200 * time.Millisecond
+
+// Add appends components, started after those added before them
+func (a *App) Add(components ...Component) {
+	a.components = append(a.components, components...)
+}
+
+// Start starts the components in order, waiting for each Checker to be ready before starting
+// the next. When one fails the components started so far are stopped.
+func (a *App) Start(ctx context.Context) error {
+	for _, c := range a.components {
+		if err := a.start(ctx, c); err != nil {
+			stopCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+			defer cancel()
+			if stopErr := a.Stop(stopCtx); stopErr != nil {
+				log.Println(stopErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) start(ctx context.Context, c Component) error {
+	log.Println("starting", c.Name())
+	if err := c.Start(ctx); err != nil {
+		return fmt.Errorf("starting %s: %w", c.Name(), err)
+	}
+	a.started = append(a.started, c)
+
+	checker, ok := c.(Checker)
+	if !ok {
+		return nil
+	}
+	timeout := a.ReadyTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	readyCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		err := checker.Ready(readyCtx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-readyCtx.Done():
+			return fmt.Errorf("%s not ready: %w", c.Name(), err)
+		case <-time.After(readyPoll):
+		}
+	}
+}
+
+// Stop stops the started components in reverse order, returning what failed to stop
+func (a *App) Stop(ctx context.Context) error {
+	var errs []string
+	for i := len(a.started) - 1; i >= 0; i-- {
+		c := a.started[i]
+		log.Println("stopping", c.Name())
+		if err := c.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("stopping %s: %v", c.Name(), err))
+		}
+	}
+	a.started = nil
+	if len(errs) > 0 {
+		return fmt.Errorf("app: %v", errs)
+	}
+	return nil
+}
+
+// Run starts the components and stops them once ctx is done or one of them fails, returning
+// the failure or nil after a requested shutdown
+func (a *App) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+
+	failed := make(chan error, len(a.started))
+	for _, c := range a.started {
+		if f, ok := c.(Failer); ok {
+			go func(name string, errs <-chan error) {
+				if err, ok := <-errs; ok && err != nil {
+					failed <- fmt.Errorf("%s failed: %w", name, err)
+				}
+			}(c.Name(), f.Failed())
+		}
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-failed:
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+	defer cancel()
+	if err := a.Stop(stopCtx); err != nil {
+		if runErr == nil {
+			return err
+		}
+		log.Println(err)
+	}
+	if errors.Is(runErr, context.Canceled) {
+		return nil
+	}
+	return runErr
+}
+
+func (a *App) shutdownTimeout() time.Duration {
+	if a.ShutdownTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return a.ShutdownTimeout
+}
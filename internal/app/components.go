@@ -0,0 +1,161 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Job is background work running until its context is done, like a jobs.RunDaily loop
+type Job struct {
+	name string
+	run  func(ctx context.Context)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJob returns the Job name running run
+func NewJob(name string, run func(ctx context.Context)) *Job {
+	return &Job{name: name, run: run}
+}
+
+// Name implements Component
+func (j *Job) Name() string {
+	return j.name
+}
+
+// Start implements Component, running the job in a goroutine
+func (j *Job) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+	go func() {
+		defer close(j.done)
+		j.run(ctx)
+	}()
+	return nil
+}
+
+// Stop implements Component, waiting for the running job to see its context is done
+func (j *Job) Stop(ctx context.Context) error {
+	j.cancel()
+	select {
+	case <-j.done:
+		return nil
+	default:
+	}
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Database is a database the other components use, ready once it answers a ping
+// and closed when stopping
+type Database struct {
+	DB *sql.DB
+	// Warm is how many connections to open when starting, so the first requests don't wait
+	// for them. A connection that fails to open fails the start.
+	Warm int
+}
+
+// Name implements Component
+func (d *Database) Name() string {
+	return "database"
+}
+
+// Start implements Component, opening Warm connections and keeping them idle in the pool
+func (d *Database) Start(ctx context.Context) error {
+	if d.Warm <= 0 {
+		return nil
+	}
+	if d.Warm > 2 {
+		// the pool keeps 2 idle connections by default, closing the others when released
+		d.DB.SetMaxIdleConns(d.Warm)
+	}
+
+	conns := make([]*sql.Conn, 0, d.Warm)
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	for len(conns) < d.Warm {
+		c, err := d.DB.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		conns = append(conns, c)
+		if err := c.PingContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ready implements Checker
+func (d *Database) Ready(ctx context.Context) error {
+	return d.DB.PingContext(ctx)
+}
+
+// Stop implements Component
+func (d *Database) Stop(context.Context) error {
+	return d.DB.Close()
+}
+
+// HTTPServer serves Server on its Addr, with TLS when Server has a TLSConfig
+type HTTPServer struct {
+	Server *http.Server
+
+	failed chan error
+}
+
+// Name implements Component
+func (h *HTTPServer) Name() string {
+	return "http server " + h.Server.Addr
+}
+
+// Start implements Component, listening right away so a taken address fails the start
+func (h *HTTPServer) Start(context.Context) error {
+	addr := h.Server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	h.failed = make(chan error, 1)
+	go func() {
+		serve := h.Server.Serve
+		if h.Server.TLSConfig != nil {
+			serve = func(ln net.Listener) error { return h.Server.ServeTLS(ln, "", "") }
+		}
+		if err := serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			h.failed <- err
+		}
+		close(h.failed)
+	}()
+	return nil
+}
+
+// Failed implements Failer
+func (h *HTTPServer) Failed() <-chan error {
+	return h.failed
+}
+
+// Stop implements Component, letting requests in flight finish until ctx is done and then
+// closing the connections left, like event streams that never finish on their own
+func (h *HTTPServer) Stop(ctx context.Context) error {
+	err := h.Server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return h.Server.Close()
+	}
+	return err
+}
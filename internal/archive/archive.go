@@ -0,0 +1,92 @@
+// Package archive keeps completed tasks in an object store for long-term analytics,
+// one gzipped JSON Lines object per day
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+	"github.com/manabie-com/togo/internal/sigv4"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// ContentType is the type of archived objects
+const ContentType = "application/gzip"
+
+// ObjectStore stores objects under keys, replacing what was stored under them before
+type ObjectStore interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// Key returns the object key of the tasks completed on day, under prefix
+func Key(prefix string, day time.Time) string {
+	return path.Join(prefix, "tasks", day.Format("2006-01-02")+".jsonl.gz")
+}
+
+// Encode writes the tasks of c as gzipped JSON Lines, one task per line, returning how many
+// there were. Only the compressed body is held in memory, not the tasks.
+func Encode(c storages.TaskCursor) ([]byte, int, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	n := 0
+	for c.Next() {
+		if err := enc.Encode(c.Task()); err != nil {
+			return nil, 0, err
+		}
+		n++
+	}
+	if err := c.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), n, nil
+}
+
+// S3 stores objects in an S3 bucket, or a bucket of an S3-compatible service like MinIO
+type S3 struct {
+	Bucket      string
+	Region      string
+	Credentials sigv4.Credentials
+	// Endpoint is the URL of an S3-compatible service, addressed path-style as
+	// <endpoint>/<bucket>/<key>. It defaults to https://<bucket>.s3.<region>.amazonaws.com.
+	Endpoint string
+	Client   httpclient.Doer
+}
+
+// Put implements ObjectStore
+func (s *S3) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	u := "https://" + s.Bucket + ".s3." + s.Region + ".amazonaws.com/" + key
+	if s.Endpoint != "" {
+		u = strings.TrimSuffix(s.Endpoint, "/") + "/" + s.Bucket + "/" + key
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sigv4.Sign(req, body, "s3", s.Region, s.Credentials, time.Now())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("s3 responded %s: %s", resp.Status, b)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
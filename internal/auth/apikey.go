@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// APIKeyHeader carries API keys
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyStore finds the user an API key belongs to, storages.Store is one
+type APIKeyStore interface {
+	RetrieveAPIKeyUser(ctx context.Context, keyHash string) (string, error)
+}
+
+// APIKey accepts long-lived keys for scripts and integrations, sent in APIKeyHeader
+type APIKey struct {
+	Store APIKeyStore
+}
+
+// Validate implements Validator
+func (a *APIKey) Validate(req *http.Request) (*Principal, error) {
+	key := req.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	userID, err := a.Store.RetrieveAPIKeyUser(req.Context(), HashAPIKey(key))
+	if errors.Is(err, storages.ErrNotFound) {
+		return nil, errors.New("auth: unknown API key")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{UserID: userID, Method: "api_key"}, nil
+}
+
+// NewAPIKey returns a random key, only its HashAPIKey should be stored
+func NewAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "togo_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of key. Keys are random so they need no salt,
+// and an unsalted hash can be looked up directly.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
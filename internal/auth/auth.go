@@ -0,0 +1,99 @@
+// Package auth authenticates API requests. Validators each understand one kind of
+// credential and Require runs them before a handler, which then finds the caller with
+// FromContext instead of parsing headers itself.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/manabie-com/togo/internal/requestid"
+)
+
+// ErrNoCredentials is returned by validators when the request carries no credential they handle
+var ErrNoCredentials = errors.New("auth: no credentials")
+
+// Principal is who a request acts as
+type Principal struct {
+	UserID string
+	// SessionID is the login a token was issued for, empty for other credentials
+	SessionID string
+	// Method names the validator that authenticated the request, e.g. "jwt"
+	Method string
+	// ImpersonatorID is the admin acting as UserID with an impersonation token, empty otherwise
+	ImpersonatorID string
+	// Scope limits what an impersonation token may do, ScopeRead or ScopeWrite
+	Scope string
+}
+
+// Impersonation token scopes
+const (
+	// ScopeRead only allows reading
+	ScopeRead = "read"
+	// ScopeWrite allows what the user could do, except administering
+	ScopeWrite = "write"
+)
+
+// Validator authenticates requests
+type Validator interface {
+	Validate(req *http.Request) (*Principal, error)
+}
+
+// Chain tries validators in order and returns the first principal one of them accepts
+type Chain []Validator
+
+// Validate implements Validator, returning the last error other than ErrNoCredentials
+// when no validator accepts the request
+func (c Chain) Validate(req *http.Request) (*Principal, error) {
+	err := ErrNoCredentials
+	for _, v := range c {
+		p, verr := v.Validate(req)
+		if verr == nil {
+			return p, nil
+		}
+		if !errors.Is(verr, ErrNoCredentials) {
+			err = verr
+		}
+	}
+	return nil, err
+}
+
+// Require responds 401 to requests v doesn't accept and serves the others with next,
+// with the principal in the request context
+func Require(v Validator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		p, err := v.Validate(req)
+		if err != nil {
+			if !errors.Is(err, ErrNoCredentials) {
+				requestid.Println(req.Context(), err)
+			}
+			resp.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(resp, req.WithContext(WithPrincipal(req.Context(), p)))
+	})
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal Require stored in ctx
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// BearerToken returns the Authorization header without its optional "Bearer " prefix
+func BearerToken(req *http.Request) string {
+	h := req.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return h
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// introspectionCacheTTL bounds how long an introspection answer is reused, so a revoked
+// token stops working soon after
+const introspectionCacheTTL = 30 * time.Second
+
+// Introspection accepts access tokens issued by an OpenID Connect provider by asking its
+// RFC 7662 introspection endpoint. The token's sub must be a togo user ID.
+type Introspection struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+	Client       httpclient.Doer
+
+	mu    sync.Mutex
+	cache map[[32]byte]introspected
+}
+
+type introspected struct {
+	userID string
+	until  time.Time
+}
+
+// NewIntrospection returns an Introspection calling url through the shared retrying client
+func NewIntrospection(url, clientID, clientSecret string) *Introspection {
+	return &Introspection{URL: url, ClientID: clientID, ClientSecret: clientSecret, Client: httpclient.New()}
+}
+
+// Validate implements Validator
+func (in *Introspection) Validate(req *http.Request) (*Principal, error) {
+	token := BearerToken(req)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	in.mu.Lock()
+	cached, ok := in.cache[key]
+	in.mu.Unlock()
+	if !ok || now.After(cached.until) {
+		userID, until, err := in.introspect(req, token)
+		if err != nil {
+			return nil, err
+		}
+		cached = introspected{userID: userID, until: until}
+		in.remember(key, cached, now)
+	}
+
+	if cached.userID == "" {
+		return nil, errors.New("auth: inactive token")
+	}
+	return &Principal{UserID: cached.userID, Method: "oidc"}, nil
+}
+
+// introspect returns the subject of an active token, or an empty one for an inactive token,
+// and until when to trust the answer
+func (in *Introspection) introspect(req *http.Request, token string) (string, time.Time, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	r, err := http.NewRequestWithContext(req.Context(), http.MethodPost, in.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Accept", "application/json")
+	r.SetBasicAuth(url.QueryEscape(in.ClientID), url.QueryEscape(in.ClientSecret))
+
+	resp, err := in.Client.Do(r)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("auth: introspection responded %s", resp.Status)
+	}
+
+	var body struct {
+		Active bool   `json:"active"`
+		Sub    string `json:"sub"`
+		Exp    int64  `json:"exp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, err
+	}
+
+	until := time.Now().Add(introspectionCacheTTL)
+	if !body.Active || body.Sub == "" {
+		return "", until, nil
+	}
+	if body.Exp > 0 && time.Unix(body.Exp, 0).Before(until) {
+		until = time.Unix(body.Exp, 0)
+	}
+	return body.Sub, until, nil
+}
+
+func (in *Introspection) remember(key [32]byte, v introspected, now time.Time) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.cache == nil {
+		in.cache = map[[32]byte]introspected{}
+	}
+	if len(in.cache) > 10000 {
+		for k, e := range in.cache {
+			if now.After(e.until) {
+				delete(in.cache, k)
+			}
+		}
+	}
+	in.cache[key] = v
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// SessionValidator reports whether a session is still live, storages.Store is one
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, userID, sessionID sql.NullString, now string) bool
+}
+
+// JWT accepts tokens togo issued at login, as long as their session wasn't revoked
+type JWT struct {
+	// Key returns the key verifying a token and must check its signing method
+	Key      jwt.Keyfunc
+	Sessions SessionValidator
+}
+
+// Validate implements Validator
+func (j *JWT) Validate(req *http.Request) (*Principal, error) {
+	token := BearerToken(req)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims := make(jwt.MapClaims)
+	t, err := jwt.ParseWithClaims(token, claims, j.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Valid {
+		return nil, errors.New("auth: invalid token")
+	}
+
+	id, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, errors.New("auth: token has no user_id")
+	}
+	sid, ok := claims["sid"].(string)
+	if !ok {
+		return nil, errors.New("auth: token has no sid")
+	}
+	if !j.Sessions.ValidateSession(
+		req.Context(),
+		sql.NullString{String: id, Valid: true},
+		sql.NullString{String: sid, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	) {
+		return nil, errors.New("auth: session expired or revoked")
+	}
+
+	p := &Principal{UserID: id, SessionID: sid, Method: "jwt"}
+	// impersonation tokens name the admin the way RFC 8693 does, {"act": {"sub": ...}}
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		p.ImpersonatorID, _ = act["sub"].(string)
+		p.Scope, _ = claims["scope"].(string)
+		if p.ImpersonatorID == "" || (p.Scope != ScopeRead && p.Scope != ScopeWrite) {
+			return nil, errors.New("auth: malformed impersonation token")
+		}
+	}
+	return p, nil
+}
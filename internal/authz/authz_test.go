@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+func TestPolicies(t *testing.T) {
+	const owner, other = "owner", "other"
+	subjects := map[string]Subject{
+		"owner":              {UserID: owner},
+		"other user":         {UserID: other},
+		"admin":              {UserID: other, Role: storages.RoleAdmin},
+		"admin owning it":    {UserID: owner, Role: storages.RoleAdmin},
+		"impersonated admin": {UserID: other, Role: storages.RoleAdmin, Impersonated: true},
+	}
+	task := Resource{OwnerID: owner}
+
+	// routes as policies in internal/services/routes.go, with what each subject gets
+	tests := []struct {
+		route  string
+		policy Policy
+		res    Resource
+		want   map[string]error
+	}{
+		{"GET /tasks", Authenticated, Resource{}, map[string]error{
+			"owner": nil, "other user": nil, "admin": nil, "admin owning it": nil, "impersonated admin": nil,
+		}},
+		{"GET /tasks/{id}", Owner, task, map[string]error{
+			"owner": nil, "other user": ErrHidden, "admin": ErrHidden, "admin owning it": nil, "impersonated admin": ErrHidden,
+		}},
+		{"POST /tasks/{id}/complete", Owner, task, map[string]error{
+			"owner": nil, "other user": ErrHidden, "admin": ErrHidden, "admin owning it": nil, "impersonated admin": ErrHidden,
+		}},
+		{"PUT /tasks/{id}/estimate", Owner, task, map[string]error{
+			"owner": nil, "other user": ErrHidden, "admin": ErrHidden, "admin owning it": nil, "impersonated admin": ErrHidden,
+		}},
+		{"DELETE /tasks/{id}/blockers/{blocker}", Owner, task, map[string]error{
+			"owner": nil, "other user": ErrHidden, "admin": ErrHidden, "admin owning it": nil, "impersonated admin": ErrHidden,
+		}},
+		{"Owner of a resource without one", Owner, Resource{}, map[string]error{
+			"owner": ErrHidden, "other user": ErrHidden, "admin": ErrHidden, "admin owning it": ErrHidden, "impersonated admin": ErrHidden,
+		}},
+		{"PUT /admin/users/{id}", Admin, Resource{OwnerID: owner}, map[string]error{
+			"owner": ErrForbidden, "other user": ErrForbidden, "admin": nil, "admin owning it": nil, "impersonated admin": ErrForbidden,
+		}},
+		{"POST /admin/users/{id}/deactivate", Admin, Resource{}, map[string]error{
+			"owner": ErrForbidden, "other user": ErrForbidden, "admin": nil, "admin owning it": nil, "impersonated admin": ErrForbidden,
+		}},
+		{"PUT /admin/users/{id}/limits", Admin, Resource{}, map[string]error{
+			"owner": ErrForbidden, "other user": ErrForbidden, "admin": nil, "admin owning it": nil, "impersonated admin": ErrForbidden,
+		}},
+		{"Any(Owner, Admin)", Any(Owner, Admin), task, map[string]error{
+			"owner": nil, "other user": ErrHidden, "admin": nil, "admin owning it": nil, "impersonated admin": ErrHidden,
+		}},
+	}
+	for _, tt := range tests {
+		for name, sub := range subjects {
+			want, ok := tt.want[name]
+			if !ok {
+				t.Fatalf("%s: no expectation for %s", tt.route, name)
+			}
+			if err := tt.policy(sub, tt.res); !errors.Is(err, want) {
+				t.Errorf("%s as %s: got %v, want %v", tt.route, name, err, want)
+			}
+		}
+	}
+}
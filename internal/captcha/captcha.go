@@ -0,0 +1,79 @@
+// Package captcha verifies CAPTCHA challenges solved by clients
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// Verifier checks the response token a client got from solving a challenge
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// TurnstileURL is Cloudflare Turnstile's verification endpoint
+const TurnstileURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+// SiteVerify implements Verifier for the siteverify protocol shared by
+// Cloudflare Turnstile, reCAPTCHA and hCaptcha
+type SiteVerify struct {
+	URL    string
+	Secret string
+	Client httpclient.Doer
+}
+
+// NewSiteVerify returns a SiteVerify posting to verifyURL. Tokens are single use,
+// so a verification that may have reached the provider is never retried.
+func NewSiteVerify(verifyURL, secret string) *SiteVerify {
+	client := httpclient.New()
+	client.AttemptTimeout = 5 * time.Second
+	client.MaxAttempts = 1
+	return &SiteVerify{
+		URL:    verifyURL,
+		Secret: secret,
+		Client: client,
+	}
+}
+
+// Verify implements Verifier
+func (v *SiteVerify) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{
+		"secret":   {v.Secret},
+		"response": {token},
+		"remoteip": {remoteIP},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verification returned %s", resp.Status)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/manabie-com/togo/internal/captcha"
+)
+
+// Config holds settings for running the togo server.
+// Secrets may be references like env:NAME, file:/path, vault:path#field or awssm:name#field.
+type Config struct {
+	Addr string
+	// AdminAddr serves the admin routes, metrics and SLO status on their own listener, like
+	// 127.0.0.1:5051, instead of Addr
+	AdminAddr string
+	// TLSCert and TLSKey serve HTTPS instead of HTTP when set, TLSClientCA also requires
+	// client certificates it signed. The files are reloaded when they change.
+	TLSCert     string
+	TLSKey      string
+	TLSClientCA string
+
+	DBPath string
+	// DBBackend names the registered storage backend DBPath is opened with
+	DBBackend string
+	// DBWarmConnections is how many database connections are opened at startup, 0 opens
+	// them on demand
+	DBWarmConnections int64
+	// SQLComments tags SQL statements with the request ID and user they run for
+	SQLComments bool
+	JWTKey      string
+	// Dev allows what only suits a development machine, like signing tokens with DefaultJWTKey
+	Dev bool
+	// SecretRefreshSeconds is how often the JWT key is resolved again, 0 resolves it once
+	SecretRefreshSeconds int64
+
+	// IDStrategy selects the task ID generator: uuidv7, ulid or snowflake
+	IDStrategy string
+	// NodeID identifies this instance for snowflake IDs
+	NodeID int64
+
+	// PasswordHash is the algorithm new password hashes use: argon2id or bcrypt
+	PasswordHash string
+
+	// CaptchaThreshold is how many failed logins from one IP trigger a CAPTCHA, 0 disables it
+	CaptchaThreshold int64
+	CaptchaVerifyURL string
+	CaptchaSecret    string
+
+	// OIDCIntrospectionURL accepts access tokens from an OpenID Connect provider too,
+	// checked with its RFC 7662 endpoint, disabled when empty
+	OIDCIntrospectionURL string
+	OIDCClientID         string
+	OIDCClientSecret     string
+
+	// LimitWebhookURL receives limit reached events for users who opted in, disabled when empty
+	LimitWebhookURL    string
+	LimitWebhookSecret string
+
+	// SMTPAddr is the host:port email goes through, the daily digest is disabled when empty
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	// DigestHour is the local hour users get their daily digest at
+	DigestHour int64
+	// StandupEmails is a comma separated list of addresses getting the team standup report
+	// by email, StandupSlackURL a Slack incoming webhook it's posted to, usually a secret
+	// reference. It isn't sent when both are empty, and goes out from StandupHour, server time.
+	StandupEmails   string
+	StandupSlackURL string
+	StandupHour     int64
+
+	// Holidays is a comma separated list of YYYY-MM-DD holidays, HolidayCountry an ISO 3166-1
+	// code whose public holidays are fetched from date.nager.at instead
+	Holidays       string
+	HolidayCountry string
+
+	// Metrics is where request and SLI metrics go: prometheus serves them at /metrics,
+	// statsd and dogstatsd send them to the agent at StatsDAddr with names under StatsDPrefix
+	Metrics      string
+	StatsDAddr   string
+	StatsDPrefix string
+
+	// APIDailyQuota is how many API calls a user may make per UTC day, 0 doesn't limit them
+	APIDailyQuota int64
+
+	// RulesDir holds Lua scripts checking new tasks, none are checked when empty. Each
+	// check runs for at most RulesTimeoutMS milliseconds.
+	RulesDir       string
+	RulesTimeoutMS int64
+	// BlockedTerms refuses new tasks containing one of its terms, one per line with /.../ for
+	// regular expressions, usually a reference like file:/etc/togo/blocked.txt. None are
+	// blocked when empty.
+	BlockedTerms string
+
+	// UsersInvite lets every user create invite codes for signing up, not only administrators
+	UsersInvite bool
+	// SignupBlockedDomains refuses signups with an email of one of its domains, separated by
+	// commas, spaces or lines, usually a reference to a list of disposable email domains.
+	// SignupMaxPerIP caps the signups one IP attempts within SignupWindowMinutes, 0 doesn't.
+	// Suspicious signups are answered after SignupDelayMS milliseconds.
+	SignupBlockedDomains string
+	SignupMaxPerIP       int64
+	SignupWindowMinutes  int64
+	SignupDelayMS        int64
+
+	// DuplicateSimilarity warns about new tasks whose content is at least this percent similar
+	// to another task of the same day, 0 doesn't check
+	DuplicateSimilarity int64
+
+	// AnomalyMinTasks and AnomalyFactor flag users who created at least that many tasks in an
+	// hour and that many times their usual rate, AnomalyMinTasks 0 doesn't look for them.
+	// AnomalyThrottle limits flagged users to that many tasks an hour for AnomalyThrottleHours,
+	// 0 only flags them.
+	AnomalyMinTasks      int64
+	AnomalyFactor        int64
+	AnomalyThrottle      int64
+	AnomalyThrottleHours int64
+
+	// MaxPageSize is the most tasks a list answers and the largest limit of a page,
+	// MaxRangeDays the most days a range of dates read at once may span. 0 doesn't cap.
+	MaxPageSize  int64
+	MaxRangeDays int64
+
+	// WriteConcurrency is how many API requests may write at once, with up to WriteQueueDepth
+	// more waiting, 0 doesn't limit them
+	WriteConcurrency int64
+	WriteQueueDepth  int64
+
+	// ArchiveBucket receives each day's completed tasks, the export is disabled when empty.
+	// ArchiveEndpoint points at an S3-compatible service instead of AWS S3.
+	ArchiveBucket   string
+	ArchiveRegion   string
+	ArchiveEndpoint string
+	ArchivePrefix   string
+	// ArchiveAccessKeyID and ArchiveSecretAccessKey default to the AWS_* credentials
+	ArchiveAccessKeyID     string
+	ArchiveSecretAccessKey string
+}
+
+// DefaultJWTKey signs tokens when TOGO_JWT_KEY isn't set. It's in the source, so anyone can
+// sign tokens a server using it accepts, and the server only starts with it when Dev is set.
+const DefaultJWTKey = "wqGyEBBfPK9w3Lxw"
+
+// Load reads the config from TOGO_* environment variables, falling back to defaults. It fails
+// naming the variables whose values don't parse, the config is incomplete then.
+func Load() (Config, error) {
+	l := &loader{}
+	cfg := Config{
+		Addr:      env("TOGO_ADDR", ":5050"),
+		AdminAddr: env("TOGO_ADMIN_ADDR", ""),
+		DBPath:    env("TOGO_DB_PATH", "./data.db"),
+		DBBackend: env("TOGO_DB_BACKEND", "sqlite"),
+
+		DBWarmConnections: l.envInt("TOGO_DB_WARM_CONNECTIONS", 0),
+		SQLComments:       l.envBool("TOGO_SQL_COMMENTS", true),
+
+		TLSCert:     env("TOGO_TLS_CERT", ""),
+		TLSKey:      env("TOGO_TLS_KEY", ""),
+		TLSClientCA: env("TOGO_TLS_CLIENT_CA", ""),
+
+		JWTKey: env("TOGO_JWT_KEY", DefaultJWTKey),
+		Dev:    l.envBool("TOGO_DEV", false),
+
+		SecretRefreshSeconds: l.envInt("TOGO_SECRET_REFRESH_SECONDS", 0),
+
+		IDStrategy: env("TOGO_ID_STRATEGY", "uuidv7"),
+		NodeID:     l.envInt("TOGO_NODE_ID", 0),
+
+		PasswordHash: env("TOGO_PASSWORD_HASH", "argon2id"),
+
+		CaptchaThreshold: l.envInt("TOGO_CAPTCHA_THRESHOLD", 0),
+		CaptchaVerifyURL: env("TOGO_CAPTCHA_VERIFY_URL", captcha.TurnstileURL),
+		CaptchaSecret:    env("TOGO_CAPTCHA_SECRET", ""),
+
+		OIDCIntrospectionURL: env("TOGO_OIDC_INTROSPECTION_URL", ""),
+		OIDCClientID:         env("TOGO_OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:     env("TOGO_OIDC_CLIENT_SECRET", ""),
+
+		LimitWebhookURL:    env("TOGO_LIMIT_WEBHOOK_URL", ""),
+		LimitWebhookSecret: env("TOGO_LIMIT_WEBHOOK_SECRET", ""),
+
+		SMTPAddr:     env("TOGO_SMTP_ADDR", ""),
+		SMTPUsername: env("TOGO_SMTP_USERNAME", ""),
+		SMTPPassword: env("TOGO_SMTP_PASSWORD", ""),
+		MailFrom:     env("TOGO_MAIL_FROM", "togo@localhost"),
+		DigestHour:   l.envInt("TOGO_DIGEST_HOUR", 7),
+
+		StandupEmails:   env("TOGO_STANDUP_EMAILS", ""),
+		StandupSlackURL: env("TOGO_STANDUP_SLACK_URL", ""),
+		StandupHour:     l.envInt("TOGO_STANDUP_HOUR", 9),
+
+		Holidays:       env("TOGO_HOLIDAYS", ""),
+		HolidayCountry: env("TOGO_HOLIDAY_COUNTRY", ""),
+
+		APIDailyQuota: l.envInt("TOGO_API_DAILY_QUOTA", 0),
+
+		Metrics:      env("TOGO_METRICS", "prometheus"),
+		StatsDAddr:   env("TOGO_STATSD_ADDR", "127.0.0.1:8125"),
+		StatsDPrefix: env("TOGO_STATSD_PREFIX", "togo"),
+
+		RulesDir:       env("TOGO_RULES_DIR", ""),
+		RulesTimeoutMS: l.envInt("TOGO_RULES_TIMEOUT_MS", 50),
+		BlockedTerms:   env("TOGO_BLOCKED_TERMS", ""),
+
+		UsersInvite:          l.envBool("TOGO_USERS_INVITE", false),
+		SignupBlockedDomains: env("TOGO_SIGNUP_BLOCKED_DOMAINS", ""),
+		SignupMaxPerIP:       l.envInt("TOGO_SIGNUP_MAX_PER_IP", 10),
+		SignupWindowMinutes:  l.envInt("TOGO_SIGNUP_WINDOW_MINUTES", 60),
+		SignupDelayMS:        l.envInt("TOGO_SIGNUP_DELAY_MS", 0),
+
+		DuplicateSimilarity: l.envInt("TOGO_DUPLICATE_SIMILARITY", 0),
+
+		AnomalyMinTasks:      l.envInt("TOGO_ANOMALY_MIN_TASKS", 30),
+		AnomalyFactor:        l.envInt("TOGO_ANOMALY_FACTOR", 10),
+		AnomalyThrottle:      l.envInt("TOGO_ANOMALY_THROTTLE", 0),
+		AnomalyThrottleHours: l.envInt("TOGO_ANOMALY_THROTTLE_HOURS", 24),
+
+		MaxPageSize:  l.envInt("TOGO_MAX_PAGE_SIZE", 1000),
+		MaxRangeDays: l.envInt("TOGO_MAX_RANGE_DAYS", 366),
+
+		WriteConcurrency: l.envInt("TOGO_WRITE_CONCURRENCY", 16),
+		WriteQueueDepth:  l.envInt("TOGO_WRITE_QUEUE_DEPTH", 256),
+
+		ArchiveBucket:          env("TOGO_ARCHIVE_BUCKET", ""),
+		ArchiveRegion:          env("TOGO_ARCHIVE_REGION", "us-east-1"),
+		ArchiveEndpoint:        env("TOGO_ARCHIVE_ENDPOINT", ""),
+		ArchivePrefix:          env("TOGO_ARCHIVE_PREFIX", "togo"),
+		ArchiveAccessKeyID:     env("TOGO_ARCHIVE_ACCESS_KEY_ID", ""),
+		ArchiveSecretAccessKey: env("TOGO_ARCHIVE_SECRET_ACCESS_KEY", ""),
+	}
+	return cfg, l.err()
+}
+
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+// loader reads typed variables, collecting those that don't parse
+type loader struct {
+	invalid []string
+}
+
+func (l *loader) envBool(key string, def bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		l.invalid = append(l.invalid, fmt.Sprintf("%s=%q is not a boolean", key, v))
+		return def
+	}
+	return b
+}
+
+func (l *loader) envInt(key string, def int64) int64 {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		l.invalid = append(l.invalid, fmt.Sprintf("%s=%q is not an integer", key, v))
+		return def
+	}
+	return n
+}
+
+func (l *loader) err() error {
+	if len(l.invalid) == 0 {
+		return nil
+	}
+	return errors.New("invalid config: " + strings.Join(l.invalid, ", "))
+}
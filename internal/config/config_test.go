@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("TOGO_MAX_PAGE_SIZE", "250")
+	t.Setenv("TOGO_SQL_COMMENTS", "false")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.MaxPageSize != 250 || cfg.SQLComments {
+		t.Errorf("got MaxPageSize %d and SQLComments %v, want 250 and false", cfg.MaxPageSize, cfg.SQLComments)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Setenv("TOGO_MAX_PAGE_SIZE", "ten")
+	t.Setenv("TOGO_SQL_COMMENTS", "maybe")
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load succeeded with invalid values")
+	}
+	for _, want := range []string{`TOGO_MAX_PAGE_SIZE="ten"`, `TOGO_SQL_COMMENTS="maybe"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %q, want it to name %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package geo measures distances between points on Earth, for finding tasks near someone
+package geo
+
+import "math"
+
+// earthRadiusKM is the mean radius of Earth
+const earthRadiusKM = 6371.0088
+
+// DistanceKM is the great circle distance in kilometers between two points given in decimal
+// degrees, by the haversine formula. It's within 0.5% of the distance on the WGS 84 ellipsoid.
+func DistanceKM(lat1, lng1, lat2, lng2 float64) float64 {
+	φ1, φ2 := radians(lat1), radians(lat2)
+	dφ, dλ := radians(lat2-lat1), radians(lng2-lng1)
+	h := math.Sin(dφ/2)*math.Sin(dφ/2) + math.Cos(φ1)*math.Cos(φ2)*math.Sin(dλ/2)*math.Sin(dλ/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box bounds the points within some distance of a center, some of its corners are farther
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+	// AllLng is set when the box reaches a pole or crosses the antimeridian, MinLng and
+	// MaxLng don't bound it then
+	AllLng bool
+}
+
+// BoundingBox returns the box holding every point within radiusKM of lat, lng
+func BoundingBox(lat, lng, radiusKM float64) Box {
+	dLat := degrees(radiusKM / earthRadiusKM)
+	b := Box{MinLat: lat - dLat, MaxLat: lat + dLat}
+	if b.MinLat <= -90 || b.MaxLat >= 90 {
+		b.AllLng = true
+		return b
+	}
+	dLng := degrees(math.Asin(math.Sin(radiusKM/earthRadiusKM) / math.Cos(radians(lat))))
+	b.MinLng, b.MaxLng = lng-dLng, lng+dLng
+	b.AllLng = b.MinLng < -180 || b.MaxLng > 180
+	return b
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,79 @@
+// Package holidays tells which dates are public holidays, for the limits users have on them
+package holidays
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// Calendar lists the holidays of a year as YYYY-MM-DD dates
+type Calendar interface {
+	Holidays(ctx context.Context, year int) ([]string, error)
+}
+
+// List is a fixed set of YYYY-MM-DD dates
+type List []string
+
+// Holidays implements Calendar
+func (l List) Holidays(_ context.Context, year int) ([]string, error) {
+	prefix := strconv.Itoa(year) + "-"
+	var dates []string
+	for _, d := range l {
+		if strings.HasPrefix(d, prefix) {
+			dates = append(dates, d)
+		}
+	}
+	return dates, nil
+}
+
+// NagerURL is the public holiday API of https://date.nager.at
+const NagerURL = "https://date.nager.at/api/v3/PublicHolidays"
+
+// Nager reads the public holidays of Country, an ISO 3166-1 alpha-2 code like VN,
+// from the Nager.Date API at URL
+type Nager struct {
+	URL     string
+	Country string
+	Client  httpclient.Doer
+}
+
+// NewNager returns a Nager for country calling the public API through the shared retrying client
+func NewNager(country string) *Nager {
+	return &Nager{URL: NagerURL, Country: country, Client: httpclient.New()}
+}
+
+// Holidays implements Calendar
+func (n *Nager) Holidays(ctx context.Context, year int) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%d/%s", n.URL, year, n.Country), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return nil, fmt.Errorf("holidays responded %s: %s", resp.Status, b)
+	}
+
+	var days []struct {
+		Date string `json:"date"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&days); err != nil {
+		return nil, err
+	}
+	dates := make([]string, 0, len(days))
+	for _, d := range days {
+		dates = append(dates, d.Date)
+	}
+	return dates, nil
+}
@@ -0,0 +1,295 @@
+// Package httpclient is the HTTP client for calls to third parties. It bounds every
+// attempt with a timeout, retries transient failures with backoff and stops calling
+// a host that keeps failing, so a slow integration can't pile up goroutines.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/manabie-com/togo/internal/retry"
+)
+
+// ErrCircuitOpen is returned without calling a host whose circuit breaker is open
+var ErrCircuitOpen = errors.New("httpclient: circuit open")
+
+// Doer is satisfied by *http.Client and *Client
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client retries requests and tracks a circuit breaker per host.
+// Only requests whose body can be replayed (nil, or created by http.NewRequest
+// from a bytes or strings reader) are retried. Failures once a connection was made,
+// when the server may have acted on the request, are only retried for idempotent
+// methods and requests with an Idempotency-Key header, others get them answered.
+type Client struct {
+	HTTP *http.Client
+	// AttemptTimeout bounds each attempt, the request context bounds all of them
+	AttemptTimeout time.Duration
+	MaxAttempts    int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	// BreakerThreshold consecutive failures open a host's circuit for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// New returns a Client with defaults suited to webhooks and chat integrations
+func New() *Client {
+	return &Client{
+		HTTP: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 10 * time.Second,
+				MaxIdleConnsPerHost:   10,
+				MaxConnsPerHost:       50,
+				IdleConnTimeout:       90 * time.Second,
+			},
+		},
+		AttemptTimeout:   10 * time.Second,
+		MaxAttempts:      3,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Do sends req, retrying network errors, 429 and 5xx gateway statuses.
+// The returned response body must be closed by the caller.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	b := c.breaker(req.URL.Host)
+	replayable := idempotent(req)
+	policy := retry.Policy{
+		MaxAttempts: c.MaxAttempts,
+		Backoff:     retry.Exponential{Base: c.BaseDelay, Max: c.MaxDelay},
+		Retryable: func(err error) bool {
+			var sent *sentError
+			return err != ErrCircuitOpen && !errors.As(err, &sent)
+		},
+	}
+	if req.Body != nil && req.GetBody == nil {
+		policy.MaxAttempts = 1
+	}
+
+	// a retryable status is answered as is once attempts run out
+	var last *http.Response
+	err := policy.Do(req.Context(), func(context.Context) error {
+		if last != nil {
+			io.Copy(io.Discard, io.LimitReader(last.Body, 64<<10))
+			last.Body.Close()
+			last = nil
+		}
+		if !b.allow() {
+			return ErrCircuitOpen
+		}
+
+		resp, connected, err := c.attempt(req)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			b.success()
+			last = resp
+			return nil
+		}
+		b.failure()
+		if err != nil {
+			if connected && !replayable {
+				return &sentError{err}
+			}
+			return err
+		}
+		last = resp
+		// a gateway may have passed the request on, 429 says it wasn't handled
+		if !replayable && resp.StatusCode != http.StatusTooManyRequests {
+			return nil
+		}
+		return &statusError{resp.StatusCode, retryAfter(resp)}
+	})
+
+	var sent *sentError
+	if errors.As(err, &sent) {
+		err = sent.err
+	}
+	var se *statusError
+	if last != nil && (err == nil || errors.As(err, &se)) {
+		return last, nil
+	}
+	if last != nil {
+		last.Body.Close()
+	}
+	return nil, err
+}
+
+// attempt sends req once, connected tells whether it got as far as a connection to the server
+func (c *Client) attempt(req *http.Request) (resp *http.Response, connected bool, err error) {
+	r := req
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false, err
+		}
+		r = req.Clone(req.Context())
+		r.Body = body
+	}
+	ctx := httptrace.WithClientTrace(r.Context(), &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { connected = true },
+	})
+	if c.AttemptTimeout <= 0 {
+		resp, err = c.HTTP.Do(r.WithContext(ctx))
+		return resp, connected, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.AttemptTimeout)
+	resp, err = c.HTTP.Do(r.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, connected, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, connected, nil
+}
+
+// idempotent reports whether sending req twice does what sending it once does
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// sentError is a failure after req may have reached the server, not retried for requests
+// that aren't idempotent
+type sentError struct {
+	err error
+}
+
+func (e *sentError) Error() string { return e.err.Error() }
+func (e *sentError) Unwrap() error { return e.err }
+
+func (c *Client) breaker(host string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.breakers == nil {
+		c.breakers = map[string]*breaker{}
+	}
+	b, ok := c.breakers[host]
+	if !ok {
+		b = &breaker{threshold: c.BreakerThreshold, cooldown: c.BreakerCooldown}
+		c.breakers[host] = b
+	}
+	return b
+}
+
+// breaker opens after threshold consecutive failures. Once cooldown passed it lets
+// a single trial request through, whose outcome closes or reopens it.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+// RetryAfter is the wait the server asked for, which backoff honours when longer
+func (e *statusError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *statusError) Error() string {
+	return "httpclient: server responded " + strconv.Itoa(e.code) + " " + http.StatusText(e.code)
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// cancelBody releases the attempt's timeout once the caller is done with the body
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient() *Client {
+	c := New()
+	c.BaseDelay, c.MaxDelay = time.Millisecond, time.Millisecond
+	c.BreakerThreshold = 0
+	return c
+}
+
+func TestRetriesAfterConnecting(t *testing.T) {
+	var calls int32
+	dropped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer dropped.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+
+	tests := []struct {
+		name, method, url string
+		key               bool
+		want              int32
+	}{
+		{"GET dropped", http.MethodGet, dropped.URL, false, 3},
+		{"PUT dropped", http.MethodPut, dropped.URL, false, 3},
+		{"POST dropped", http.MethodPost, dropped.URL, false, 1},
+		{"POST with a key dropped", http.MethodPost, dropped.URL, true, 3},
+		{"GET 503", http.MethodGet, unavailable.URL, false, 3},
+		{"POST 503", http.MethodPost, unavailable.URL, false, 1},
+		{"POST with a key 503", http.MethodPost, unavailable.URL, true, 3},
+		{"POST 429", http.MethodPost, limited.URL, false, 3},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&calls, 0)
+		req, err := http.NewRequest(tt.method, tt.url, strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.key {
+			req.Header.Set("Idempotency-Key", "k1")
+		}
+		resp, err := testClient().Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if got := atomic.LoadInt32(&calls); got != tt.want {
+			t.Errorf("%s: %d attempts, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetriesBeforeConnecting(t *testing.T) {
+	var dials int32
+	c := testClient()
+	c.HTTP = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return nil, errors.New("connection refused")
+		},
+	}}
+
+	req, err := http.NewRequest(http.MethodPost, "http://togo.invalid/hook", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("Do succeeded without a connection")
+	}
+	if got := atomic.LoadInt32(&dials); got != 3 {
+		t.Errorf("POST that never connected: %d attempts, want 3", got)
+	}
+}
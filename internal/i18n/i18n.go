@@ -0,0 +1,97 @@
+// Package i18n translates the messages users see, such as validation failures. English
+// messages are their own keys: a catalog, embedded from locales/<language>.json, maps them
+// to their translations. Languages or messages without a translation stay in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fallback is the language messages are written in
+const Fallback = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogs = mustLoad()
+
+func mustLoad() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	catalogs := map[string]map[string]string{}
+	for _, f := range files {
+		b, err := locales.ReadFile("locales/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(b, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = catalog
+	}
+	return catalogs
+}
+
+// Match returns the most preferred language of acceptLanguage, an Accept-Language header,
+// that has a catalog, or Fallback. A region falls back to its language, so en-GB
+// matches en and vi-VN matches vi.
+func Match(acceptLanguage string) string {
+	type choice struct {
+		tag string
+		q   float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			choices = append(choices, choice{tag, q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+
+	for _, c := range choices {
+		lang := c.tag
+		if i := strings.IndexByte(lang, '-'); i >= 0 {
+			lang = lang[:i]
+		}
+		if _, ok := catalogs[lang]; ok || lang == Fallback {
+			return lang
+		}
+	}
+	return Fallback
+}
+
+// Message translates msg into lang
+func Message(lang, msg string) string {
+	if t, ok := catalogs[lang][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf translates format into lang and formats args with it
+func Sprintf(lang, format string, args ...interface{}) string {
+	return fmt.Sprintf(Message(lang, format), args...)
+}
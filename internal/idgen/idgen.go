@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generator produces IDs for new records.
+// All strategies are time-ordered so new rows land at the end of the primary key index.
+type Generator interface {
+	NewID() string
+}
+
+// New returns the generator for strategy. nodeID is only used by snowflake.
+func New(strategy string, nodeID int64) (Generator, error) {
+	switch strategy {
+	case "", "uuidv7":
+		return UUIDv7{}, nil
+	case "ulid":
+		return ULID{}, nil
+	case "snowflake":
+		return NewSnowflake(nodeID)
+	}
+	return nil, fmt.Errorf("unknown id strategy %q", strategy)
+}
+
+// UUIDv7 generates RFC 9562 version 7 UUIDs
+type UUIDv7 struct{}
+
+// NewID returns a new UUIDv7 string
+func (UUIDv7) NewID() string {
+	var u uuid.UUID
+	putMillis(u[:6], time.Now())
+	randomBytes(u[6:])
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u.String()
+}
+
+// ULID generates 26 character Crockford base32 ULIDs
+type ULID struct{}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a new ULID string
+func (ULID) NewID() string {
+	var b [16]byte
+	putMillis(b[:6], time.Now())
+	randomBytes(b[6:])
+
+	// 128 bits encoded 5 bits at a time, the first character carries the top 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+const (
+	snowflakeEpoch    = int64(1577836800000) // 2020-01-01T00:00:00Z in ms
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// Snowflake generates 63 bit IDs from a timestamp, node ID and per-millisecond sequence
+type Snowflake struct {
+	mu     sync.Mutex
+	node   int64
+	lastMS int64
+	seq    int64
+}
+
+// NewSnowflake returns a snowflake generator for nodeID, which must fit in 10 bits
+func NewSnowflake(nodeID int64) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d", snowflakeMaxNode)
+	}
+	return &Snowflake{node: nodeID}, nil
+}
+
+// NewID returns a new snowflake ID as a decimal string
+func (s *Snowflake) NewID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if ms < s.lastMS {
+		// clock moved backwards, keep issuing from the last seen millisecond
+		ms = s.lastMS
+	}
+	if ms == s.lastMS {
+		s.seq = (s.seq + 1) & snowflakeMaxSeq
+		if s.seq == 0 {
+			for ms <= s.lastMS {
+				time.Sleep(100 * time.Microsecond)
+				ms = time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+			}
+		}
+	} else {
+		s.seq = 0
+	}
+	s.lastMS = ms
+
+	id := ms<<(snowflakeNodeBits+snowflakeSeqBits) | s.node<<snowflakeSeqBits | s.seq
+	return strconv.FormatInt(id, 10)
+}
+
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("idgen: reading random bytes: %v", err))
+	}
+}
@@ -0,0 +1,43 @@
+// Package jobs runs background work on a schedule
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// RunDaily calls fn at every midnight in loc with the day that just started, until ctx is done.
+// Runs don't overlap, a run taking longer than a day delays the next one.
+func RunDaily(ctx context.Context, loc *time.Location, fn func(ctx context.Context, day time.Time)) {
+	for {
+		now := time.Now().In(loc)
+		next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		fn(ctx, next)
+	}
+}
+
+// RunEvery calls fn at every multiple of interval since the zero time with the current time,
+// until ctx is done. Runs don't overlap, ticks missed while fn runs are skipped.
+func RunEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context, now time.Time)) {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(interval).Add(interval).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now = <-timer.C:
+		}
+
+		fn(ctx, now)
+	}
+}
@@ -0,0 +1,87 @@
+// Package markdown renders task content, stored as markdown, to HTML that is safe to embed
+package markdown
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"html"
+	"regexp"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// MaxLength is the longest content rendered as markdown, longer content is rendered as
+// escaped text so a huge task can't make reads expensive
+const MaxLength = 10000
+
+// cacheSize is how many renderings a Renderer keeps, dropping the least recently used
+const cacheSize = 4096
+
+// Renderer turns GitHub flavored markdown into sanitized HTML, caching the results.
+// The zero Renderer is ready to use.
+type Renderer struct {
+	once   sync.Once
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[[32]byte]*list.Element
+}
+
+type rendered struct {
+	key  [32]byte
+	html string
+}
+
+func (r *Renderer) init() {
+	r.md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	r.policy = bluemonday.UGCPolicy()
+	// task list items render as disabled checkboxes
+	r.policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	r.policy.AllowAttrs("checked", "disabled").OnElements("input")
+	r.lru = list.New()
+	r.entries = make(map[[32]byte]*list.Element)
+}
+
+// Render returns src as sanitized HTML
+func (r *Renderer) Render(src string) string {
+	r.once.Do(r.init)
+
+	key := sha256.Sum256([]byte(src))
+	r.mu.Lock()
+	if e, ok := r.entries[key]; ok {
+		r.lru.MoveToFront(e)
+		r.mu.Unlock()
+		return e.Value.(*rendered).html
+	}
+	r.mu.Unlock()
+
+	out := r.render(src)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[key]; !ok {
+		r.entries[key] = r.lru.PushFront(&rendered{key: key, html: out})
+		if r.lru.Len() > cacheSize {
+			oldest := r.lru.Remove(r.lru.Back()).(*rendered)
+			delete(r.entries, oldest.key)
+		}
+	}
+	return out
+}
+
+func (r *Renderer) render(src string) string {
+	if len(src) > MaxLength {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(src), &buf); err != nil {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return r.policy.Sanitize(buf.String())
+}
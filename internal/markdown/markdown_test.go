@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"markdown", "**bold** _it_ `code`", "<p><strong>bold</strong> <em>it</em> <code>code</code></p>\n"},
+		{"link", "[docs](https://example.com/a?b=1)", `<p><a href="https://example.com/a?b=1" rel="nofollow">docs</a></p>` + "\n"},
+		{"autolink", "<https://example.com>", `<p><a href="https://example.com" rel="nofollow">https://example.com</a></p>` + "\n"},
+		{"script", "<script>alert(1)</script>", "\n"},
+		{"inline script", "hi <script>alert(1)</script>", "<p>hi alert(1)</p>\n"},
+		{"script in code", "```\n<script>alert(1)</script>\n```", "<pre><code>&lt;script&gt;alert(1)&lt;/script&gt;\n</code></pre>\n"},
+		{"iframe", `<iframe src="https://example.com"></iframe>`, "\n"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>x</p>\n"},
+		{"javascript link in mixed case", "[x](JaVaScRiPt:alert(1))", "<p>x</p>\n"},
+		{"javascript link in html", `<a href="javascript:alert(1)">x</a>`, "<p>x</p>\n"},
+		{"vbscript link", "[x](vbscript:msgbox)", "<p>x</p>\n"},
+		{"data link", "[x](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)", "<p>x</p>\n"},
+		{"data image", "![i](data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=)", `<p><img alt="i"></p>` + "\n"},
+		{"onerror", `<img src="x" onerror="alert(1)">`, "\n"},
+		{"onclick", `<p onclick="alert(1)">hi</p>`, "\n"},
+		{"onload", `<svg onload="alert(1)">`, "\n"},
+		{"inline onmouseover", `hi <b onmouseover="alert(1)">there</b>`, "<p>hi there</p>\n"},
+		{"task list", "- [ ] todo\n- [x] done", "<ul>\n" +
+			`<li><input disabled="" type="checkbox"> todo</li>` + "\n" +
+			`<li><input checked="" disabled="" type="checkbox"> done</li>` + "\n</ul>\n"},
+	}
+	var r Renderer
+	for _, tt := range tests {
+		if got := r.Render(tt.src); got != tt.want {
+			t.Errorf("%s: Render(%q) = %q, want %q", tt.name, tt.src, got, tt.want)
+		}
+		// answered from the cache the second time
+		if got := r.Render(tt.src); got != tt.want {
+			t.Errorf("%s: Render(%q) again = %q, want %q", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+// TestSanitize runs the policy on HTML itself, as goldmark leaves raw HTML out before it
+// gets there
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{"script", "<p>hi</p><script>alert(1)</script>", "<p>hi</p>"},
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, "x"},
+		{"data link", `<a href="data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;">x</a>`, "x"},
+		{"event attributes", `<p onclick="a()" onmouseover="b()">hi</p><img src="https://example.com/a.png" onerror="c()">`,
+			`<p>hi</p><img src="https://example.com/a.png">`},
+		{"style", `<p style="background:url(javascript:alert(1))">hi</p>`, "<p>hi</p>"},
+		{"checkbox", `<input type="checkbox" checked="">`, `<input type="checkbox" checked="">`},
+		{"other inputs", `<input type="text" value="x"><input type="submit">`, ""},
+	}
+	var r Renderer
+	r.once.Do(r.init)
+	for _, tt := range tests {
+		if got := r.policy.Sanitize(tt.html); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.html, got, tt.want)
+		}
+	}
+}
+
+func TestRenderLongContent(t *testing.T) {
+	src := "<script>" + strings.Repeat("**a**", MaxLength/5)
+	got := (&Renderer{}).Render(src)
+	if !strings.HasPrefix(got, "<p>&lt;script&gt;**a**") || strings.Contains(got, "<strong>") {
+		t.Errorf("Render of content over MaxLength = %.60q..., want it escaped as text", got)
+	}
+}
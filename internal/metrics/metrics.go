@@ -0,0 +1,194 @@
+// Package metrics emits counters and timings to a backend: Prometheus scraping them, or a
+// StatsD or DogStatsD agent they're sent to
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend receives metrics. Names are dotted, like slo.events, and tags are key:value, like
+// slo:availability. Implementations must be safe for concurrent use and not block.
+type Backend interface {
+	// Count adds delta to a counter
+	Count(name string, delta int64, tags ...string)
+	// Timing records how long one event took
+	Timing(name string, d time.Duration, tags ...string)
+}
+
+// New returns the backend kind names: prometheus, statsd or dogstatsd. StatsD backends send
+// to the agent at addr with metric names starting with prefix.
+func New(kind, addr, prefix string) (Backend, error) {
+	switch kind {
+	case "prometheus":
+		return &Prometheus{}, nil
+	case "statsd":
+		return NewStatsD(addr, prefix, false)
+	case "dogstatsd":
+		return NewStatsD(addr, prefix, true)
+	}
+	return nil, fmt.Errorf("unknown metrics backend %q", kind)
+}
+
+// Prometheus keeps the metrics for WriteMetrics to expose in the Prometheus text format,
+// counters as togo_<name>_total and timings as summaries of seconds without quantiles. The
+// zero Prometheus is ready to use.
+type Prometheus struct {
+	mu      sync.Mutex
+	series  map[string]*promSeries
+	ordered []*promSeries
+}
+
+type promSeries struct {
+	// name is the family, labels the rendered label set
+	name, labels, typ string
+	count             int64
+	sum               float64
+}
+
+// Count implements Backend
+func (p *Prometheus) Count(name string, delta int64, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.get("togo_"+promName(name)+"_total", "counter", tags)
+	s.count += delta
+}
+
+// Timing implements Backend
+func (p *Prometheus) Timing(name string, d time.Duration, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.get("togo_"+promName(name)+"_seconds", "summary", tags)
+	s.count++
+	s.sum += d.Seconds()
+}
+
+func (p *Prometheus) get(name, typ string, tags []string) *promSeries {
+	labels := promLabels(tags)
+	key := name + labels
+	s := p.series[key]
+	if s == nil {
+		if p.series == nil {
+			p.series = map[string]*promSeries{}
+		}
+		s = &promSeries{name: name, labels: labels, typ: typ}
+		p.series[key] = s
+		p.ordered = append(p.ordered, s)
+		sort.SliceStable(p.ordered, func(i, j int) bool { return p.ordered[i].name < p.ordered[j].name })
+	}
+	return s
+}
+
+// WriteMetrics writes every series counted so far
+func (p *Prometheus) WriteMetrics(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, s := range p.ordered {
+		if i == 0 || p.ordered[i-1].name != s.name {
+			if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", s.name, s.typ); err != nil {
+				return err
+			}
+		}
+		var err error
+		if s.typ == "summary" {
+			_, err = fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n", s.name, s.labels,
+				strconv.FormatFloat(s.sum, 'g', -1, 64), s.name, s.labels, s.count)
+		} else {
+			_, err = fmt.Fprintf(w, "%s%s %d\n", s.name, s.labels, s.count)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promName turns a dotted name into a Prometheus one
+func promName(name string) string {
+	return strings.NewReplacer(".", "_", "-", "_").Replace(name)
+}
+
+// promLabels renders key:value tags as {key="value",...}
+func promLabels(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(tags))
+	for _, t := range tags {
+		k, v := t, ""
+		if i := strings.Index(t, ":"); i >= 0 {
+			k, v = t[:i], t[i+1:]
+		}
+		parts = append(parts, fmt.Sprintf("%s=%q", promName(k), v))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// StatsD sends metrics over UDP to a StatsD agent, or a Datadog agent with DogStatsD tags.
+// Plain StatsD has no tags, their values are appended to the name instead, so
+// slo.events with slo:availability is sent as togo.slo.events.availability. Metrics that
+// can't be sent are dropped.
+type StatsD struct {
+	prefix string
+	dog    bool
+	conn   net.Conn
+}
+
+// NewStatsD returns a StatsD sending to the agent at addr, like 127.0.0.1:8125
+func NewStatsD(addr, prefix string, dogstatsd bool) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &StatsD{prefix: prefix, dog: dogstatsd, conn: conn}, nil
+}
+
+// Count implements Backend
+func (s *StatsD) Count(name string, delta int64, tags ...string) {
+	s.send(name, strconv.FormatInt(delta, 10)+"|c", tags)
+}
+
+// Timing implements Backend
+func (s *StatsD) Timing(name string, d time.Duration, tags ...string) {
+	s.send(name, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)+"|ms", tags)
+}
+
+func (s *StatsD) send(name, value string, tags []string) {
+	var b strings.Builder
+	b.WriteString(s.prefix)
+	b.WriteString(name)
+	if !s.dog {
+		for _, t := range tags {
+			if i := strings.Index(t, ":"); i >= 0 {
+				t = t[i+1:]
+			}
+			b.WriteString(".")
+			b.WriteString(strings.Map(nameChar, t))
+		}
+	}
+	b.WriteString(":")
+	b.WriteString(value)
+	if s.dog && len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+	// UDP doesn't wait for the agent, an error means the packet is lost
+	s.conn.Write([]byte(b.String()))
+}
+
+// nameChar keeps the characters graphite-style names may have, replacing others with _
+func nameChar(r rune) rune {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+		return r
+	}
+	return '_'
+}
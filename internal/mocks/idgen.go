@@ -0,0 +1,68 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/manabie-com/togo/internal/idgen"
+	"sync"
+)
+
+// Ensure, that GeneratorMock does implement idgen.Generator.
+// If this is not the case, regenerate this file with moq.
+var _ idgen.Generator = &GeneratorMock{}
+
+// GeneratorMock is a mock implementation of idgen.Generator.
+//
+//	func TestSomethingThatUsesGenerator(t *testing.T) {
+//
+//		// make and configure a mocked idgen.Generator
+//		mockedGenerator := &GeneratorMock{
+//			NewIDFunc: func() string {
+//				panic("mock out the NewID method")
+//			},
+//		}
+//
+//		// use mockedGenerator in code that requires idgen.Generator
+//		// and then make assertions.
+//
+//	}
+type GeneratorMock struct {
+	// NewIDFunc mocks the NewID method.
+	NewIDFunc func() string
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// NewID holds details about calls to the NewID method.
+		NewID []struct {
+		}
+	}
+	lockNewID sync.RWMutex
+}
+
+// NewID calls NewIDFunc.
+func (mock *GeneratorMock) NewID() string {
+	if mock.NewIDFunc == nil {
+		panic("GeneratorMock.NewIDFunc: method is nil but Generator.NewID was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockNewID.Lock()
+	mock.calls.NewID = append(mock.calls.NewID, callInfo)
+	mock.lockNewID.Unlock()
+	return mock.NewIDFunc()
+}
+
+// NewIDCalls gets all the calls that were made to NewID.
+// Check the length with:
+//
+//	len(mockedGenerator.NewIDCalls())
+func (mock *GeneratorMock) NewIDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockNewID.RLock()
+	calls = mock.calls.NewID
+	mock.lockNewID.RUnlock()
+	return calls
+}
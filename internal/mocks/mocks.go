@@ -0,0 +1,7 @@
+// Package mocks holds generated test doubles for the domain interfaces,
+// regenerate them with `go generate ./internal/mocks` after changing an interface.
+// The generator is github.com/matryer/moq.
+package mocks
+
+//go:generate moq -out store.go -pkg mocks ../../pkg/storages Store
+//go:generate moq -out idgen.go -pkg mocks ../idgen Generator
@@ -0,0 +1,5087 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"github.com/manabie-com/togo/pkg/storages"
+	"sync"
+)
+
+// Ensure, that StoreMock does implement storages.Store.
+// If this is not the case, regenerate this file with moq.
+var _ storages.Store = &StoreMock{}
+
+// StoreMock is a mock implementation of storages.Store.
+//
+//	func TestSomethingThatUsesStore(t *testing.T) {
+//
+//		// make and configure a mocked storages.Store
+//		mockedStore := &StoreMock{
+//			AddAPIKeyFunc: func(ctx context.Context, k *storages.APIKey) error {
+//				panic("mock out the AddAPIKey method")
+//			},
+//			AddAnomalyFunc: func(ctx context.Context, a *storages.Anomaly, throttle *storages.CreationThrottle) (bool, error) {
+//				panic("mock out the AddAnomaly method")
+//			},
+//			AddAuditEntryFunc: func(ctx context.Context, e *storages.AuditEntry) error {
+//				panic("mock out the AddAuditEntry method")
+//			},
+//			AddInviteFunc: func(ctx context.Context, inv *storages.Invite) error {
+//				panic("mock out the AddInvite method")
+//			},
+//			AddLabelFunc: func(ctx context.Context, label *storages.Label) error {
+//				panic("mock out the AddLabel method")
+//			},
+//			AddQuotaAdjustmentFunc: func(ctx context.Context, a *storages.QuotaAdjustment) error {
+//				panic("mock out the AddQuotaAdjustment method")
+//			},
+//			AddSessionFunc: func(ctx context.Context, sess *storages.Session) error {
+//				panic("mock out the AddSession method")
+//			},
+//			AddShareFunc: func(ctx context.Context, share *storages.Share) error {
+//				panic("mock out the AddShare method")
+//			},
+//			AddTaskFunc: func(ctx context.Context, t *storages.Task) error {
+//				panic("mock out the AddTask method")
+//			},
+//			AddTaskLinkFunc: func(ctx context.Context, link *storages.TaskLink) error {
+//				panic("mock out the AddTaskLink method")
+//			},
+//			AddTaskWithLimitPerDayFunc: func(ctx context.Context, t *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+//				panic("mock out the AddTaskWithLimitPerDay method")
+//			},
+//			AddTasksFunc: func(ctx context.Context, tasks []*storages.Task) error {
+//				panic("mock out the AddTasks method")
+//			},
+//			AddUserFunc: func(ctx context.Context, u *storages.User) error {
+//				panic("mock out the AddUser method")
+//			},
+//			AssignLabelFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString, at string) error {
+//				panic("mock out the AssignLabel method")
+//			},
+//			CarryOverTasksFunc: func(ctx context.Context, co *storages.CarryOver) (int, error) {
+//				panic("mock out the CarryOverTasks method")
+//			},
+//			CompletePomodoroFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, pomodoroID sql.NullString, at string) (*storages.Pomodoro, error) {
+//				panic("mock out the CompletePomodoro method")
+//			},
+//			CompleteTaskFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.Task, error) {
+//				panic("mock out the CompleteTask method")
+//			},
+//			CompleteTasksFunc: func(ctx context.Context, userID sql.NullString, taskIDs []string, at string, undo *storages.Undo) ([]string, error) {
+//				panic("mock out the CompleteTasks method")
+//			},
+//			CountTasksFunc: func(ctx context.Context, userID sql.NullString, createdDate sql.NullString) (int, int, error) {
+//				panic("mock out the CountTasks method")
+//			},
+//			CountTasksCreatedSinceFunc: func(ctx context.Context, userID sql.NullString, since string) (int, string, error) {
+//				panic("mock out the CountTasksCreatedSince method")
+//			},
+//			DeleteLabelFunc: func(ctx context.Context, userID sql.NullString, labelID sql.NullString) error {
+//				panic("mock out the DeleteLabel method")
+//			},
+//			DeleteTasksFunc: func(ctx context.Context, userID sql.NullString, taskIDs []string, undo *storages.Undo) ([]string, error) {
+//				panic("mock out the DeleteTasks method")
+//			},
+//			IncrementAPIUsageFunc: func(ctx context.Context, userID sql.NullString, day string) (int, error) {
+//				panic("mock out the IncrementAPIUsage method")
+//			},
+//			IterateCompletedTasksFunc: func(ctx context.Context, from string, to string) (storages.TaskCursor, error) {
+//				panic("mock out the IterateCompletedTasks method")
+//			},
+//			IterateTasksFunc: func(ctx context.Context, from string, to string) (storages.TaskCursor, error) {
+//				panic("mock out the IterateTasks method")
+//			},
+//			LiftCreationThrottleFunc: func(ctx context.Context, userID sql.NullString) error {
+//				panic("mock out the LiftCreationThrottle method")
+//			},
+//			MarkDigestSentFunc: func(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error) {
+//				panic("mock out the MarkDigestSent method")
+//			},
+//			MarkLimitNotifiedFunc: func(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error) {
+//				panic("mock out the MarkLimitNotified method")
+//			},
+//			MarkStandupSentFunc: func(ctx context.Context, date string, at string) (bool, error) {
+//				panic("mock out the MarkStandupSent method")
+//			},
+//			MoveTaskFunc: func(ctx context.Context, move *storages.TaskMove) (*storages.Task, error) {
+//				panic("mock out the MoveTask method")
+//			},
+//			RecordLimitHitFunc: func(ctx context.Context, userID sql.NullString, date sql.NullString, at string) error {
+//				panic("mock out the RecordLimitHit method")
+//			},
+//			RemoveTaskLinkFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, blockedByID sql.NullString) error {
+//				panic("mock out the RemoveTaskLink method")
+//			},
+//			RemoveTaskLocationFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) error {
+//				panic("mock out the RemoveTaskLocation method")
+//			},
+//			ReplaceHolidaysFunc: func(ctx context.Context, from string, to string, dates []string) error {
+//				panic("mock out the ReplaceHolidays method")
+//			},
+//			RetrieveAPIKeyUserFunc: func(ctx context.Context, keyHash string) (string, error) {
+//				panic("mock out the RetrieveAPIKeyUser method")
+//			},
+//			RetrieveAPIUsageFunc: func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.APIUsage, error) {
+//				panic("mock out the RetrieveAPIUsage method")
+//			},
+//			RetrieveAccountAuditFunc: func(ctx context.Context, q *storages.AuditQuery) ([]*storages.AuditEntry, error) {
+//				panic("mock out the RetrieveAccountAudit method")
+//			},
+//			RetrieveAnomaliesFunc: func(ctx context.Context, from string, to string) ([]*storages.Anomaly, error) {
+//				panic("mock out the RetrieveAnomalies method")
+//			},
+//			RetrieveAuditLogFunc: func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.AuditEntry, error) {
+//				panic("mock out the RetrieveAuditLog method")
+//			},
+//			RetrieveCarryOverUsersFunc: func(ctx context.Context) (map[string]string, error) {
+//				panic("mock out the RetrieveCarryOverUsers method")
+//			},
+//			RetrieveCompletedTasksFunc: func(ctx context.Context, from string, to string) ([]*storages.Task, error) {
+//				panic("mock out the RetrieveCompletedTasks method")
+//			},
+//			RetrieveContentSuggestionsFunc: func(ctx context.Context, userID sql.NullString, prefix string, n int) ([]*storages.ContentSuggestion, error) {
+//				panic("mock out the RetrieveContentSuggestions method")
+//			},
+//			RetrieveCreationRatesFunc: func(ctx context.Context, baselineFrom string, from string, to string, min int) ([]*storages.CreationRate, error) {
+//				panic("mock out the RetrieveCreationRates method")
+//			},
+//			RetrieveCreationThrottleFunc: func(ctx context.Context, userID sql.NullString, now string) (*storages.CreationThrottle, error) {
+//				panic("mock out the RetrieveCreationThrottle method")
+//			},
+//			RetrieveDayCountsFunc: func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.DayCount, error) {
+//				panic("mock out the RetrieveDayCounts method")
+//			},
+//			RetrieveDigestRecipientsFunc: func(ctx context.Context) ([]*storages.DigestRecipient, error) {
+//				panic("mock out the RetrieveDigestRecipients method")
+//			},
+//			RetrieveLabelsFunc: func(ctx context.Context, userID sql.NullString) ([]*storages.Label, error) {
+//				panic("mock out the RetrieveLabels method")
+//			},
+//			RetrieveLimitHitRollupsFunc: func(ctx context.Context, from string, to string) ([]*storages.LimitHitRollup, error) {
+//				panic("mock out the RetrieveLimitHitRollups method")
+//			},
+//			RetrieveLimitOffendersFunc: func(ctx context.Context, from string, to string, n int) ([]*storages.LimitOffender, error) {
+//				panic("mock out the RetrieveLimitOffenders method")
+//			},
+//			RetrieveLimitScheduleFunc: func(ctx context.Context, userID sql.NullString) (*storages.LimitSchedule, error) {
+//				panic("mock out the RetrieveLimitSchedule method")
+//			},
+//			RetrieveLinkedTasksFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Task, []*storages.Task, error) {
+//				panic("mock out the RetrieveLinkedTasks method")
+//			},
+//			RetrieveNearbyTasksFunc: func(ctx context.Context, q *storages.NearbyQuery) ([]*storages.NearbyTask, error) {
+//				panic("mock out the RetrieveNearbyTasks method")
+//			},
+//			RetrievePasswordHashFunc: func(ctx context.Context, userID sql.NullString) (string, error) {
+//				panic("mock out the RetrievePasswordHash method")
+//			},
+//			RetrievePomodorosFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Pomodoro, error) {
+//				panic("mock out the RetrievePomodoros method")
+//			},
+//			RetrievePriorityCountsFunc: func(ctx context.Context, userID sql.NullString, createdDate sql.NullString) ([]*storages.PriorityCount, error) {
+//				panic("mock out the RetrievePriorityCounts method")
+//			},
+//			RetrieveQuotaAdjustmentsFunc: func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.QuotaAdjustment, error) {
+//				panic("mock out the RetrieveQuotaAdjustments method")
+//			},
+//			RetrieveSessionsFunc: func(ctx context.Context, userID sql.NullString, now string) ([]*storages.Session, error) {
+//				panic("mock out the RetrieveSessions method")
+//			},
+//			RetrieveShareFunc: func(ctx context.Context, shareID sql.NullString, now string) (*storages.Share, error) {
+//				panic("mock out the RetrieveShare method")
+//			},
+//			RetrieveSharesFunc: func(ctx context.Context, userID sql.NullString, now string) ([]*storages.Share, error) {
+//				panic("mock out the RetrieveShares method")
+//			},
+//			RetrieveSigningKeysFunc: func(ctx context.Context, retiredAfter string) ([]*storages.SigningKey, error) {
+//				panic("mock out the RetrieveSigningKeys method")
+//			},
+//			RetrieveStandupFunc: func(ctx context.Context, yesterday string, today string) ([]*storages.StandupMember, error) {
+//				panic("mock out the RetrieveStandup method")
+//			},
+//			RetrieveStreakFunc: func(ctx context.Context, userID sql.NullString) (*storages.Streak, error) {
+//				panic("mock out the RetrieveStreak method")
+//			},
+//			RetrieveTaskFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Task, error) {
+//				panic("mock out the RetrieveTask method")
+//			},
+//			RetrieveTaskLabelsFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Label, error) {
+//				panic("mock out the RetrieveTaskLabels method")
+//			},
+//			RetrieveTaskLinksFunc: func(ctx context.Context, userID sql.NullString) ([]*storages.TaskLink, error) {
+//				panic("mock out the RetrieveTaskLinks method")
+//			},
+//			RetrieveTaskLocationFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Location, error) {
+//				panic("mock out the RetrieveTaskLocation method")
+//			},
+//			RetrieveTaskOwnerFunc: func(ctx context.Context, taskID sql.NullString) (string, error) {
+//				panic("mock out the RetrieveTaskOwner method")
+//			},
+//			RetrieveTasksFunc: func(ctx context.Context, userID sql.NullString, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+//				panic("mock out the RetrieveTasks method")
+//			},
+//			RetrieveTasksAsOfFunc: func(ctx context.Context, userID sql.NullString, createdDate sql.NullString, at string) ([]*storages.Task, error) {
+//				panic("mock out the RetrieveTasksAsOf method")
+//			},
+//			RetrieveTimeEntriesFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.TimeEntry, error) {
+//				panic("mock out the RetrieveTimeEntries method")
+//			},
+//			RetrieveUserFunc: func(ctx context.Context, userID sql.NullString) (*storages.User, error) {
+//				panic("mock out the RetrieveUser method")
+//			},
+//			RetrieveUserSettingsFunc: func(ctx context.Context, userID sql.NullString) (*storages.UserSettings, error) {
+//				panic("mock out the RetrieveUserSettings method")
+//			},
+//			RevokeSessionFunc: func(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) error {
+//				panic("mock out the RevokeSession method")
+//			},
+//			RevokeShareFunc: func(ctx context.Context, userID sql.NullString, shareID sql.NullString, now string) error {
+//				panic("mock out the RevokeShare method")
+//			},
+//			RollUpLimitHitsFunc: func(ctx context.Context, date string) (int, error) {
+//				panic("mock out the RollUpLimitHits method")
+//			},
+//			RotateSigningKeyFunc: func(ctx context.Context, k *storages.SigningKey) error {
+//				panic("mock out the RotateSigningKey method")
+//			},
+//			SetTaskEstimateFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, minutes int) error {
+//				panic("mock out the SetTaskEstimate method")
+//			},
+//			SetTaskLocationFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, loc storages.Location) error {
+//				panic("mock out the SetTaskLocation method")
+//			},
+//			SignUpFunc: func(ctx context.Context, code string, u *storages.User, at string) error {
+//				panic("mock out the SignUp method")
+//			},
+//			StartPomodoroFunc: func(ctx context.Context, p *storages.Pomodoro) error {
+//				panic("mock out the StartPomodoro method")
+//			},
+//			StartTimerFunc: func(ctx context.Context, e *storages.TimeEntry) (*storages.TimeEntry, error) {
+//				panic("mock out the StartTimer method")
+//			},
+//			StopTimerFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.TimeEntry, error) {
+//				panic("mock out the StopTimer method")
+//			},
+//			UnassignLabelFunc: func(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString) error {
+//				panic("mock out the UnassignLabel method")
+//			},
+//			UndoTasksFunc: func(ctx context.Context, userID sql.NullString, token string, now string) (int, error) {
+//				panic("mock out the UndoTasks method")
+//			},
+//			UpdateLabelFunc: func(ctx context.Context, label *storages.Label) error {
+//				panic("mock out the UpdateLabel method")
+//			},
+//			UpdateLimitScheduleFunc: func(ctx context.Context, userID sql.NullString, sched *storages.LimitSchedule) error {
+//				panic("mock out the UpdateLimitSchedule method")
+//			},
+//			UpdatePasswordHashFunc: func(ctx context.Context, userID sql.NullString, hash string) error {
+//				panic("mock out the UpdatePasswordHash method")
+//			},
+//			UpdateStreaksFunc: func(ctx context.Context, day string) (int, error) {
+//				panic("mock out the UpdateStreaks method")
+//			},
+//			UpdateUserFunc: func(ctx context.Context, u *storages.User) error {
+//				panic("mock out the UpdateUser method")
+//			},
+//			UpdateUserSettingsFunc: func(ctx context.Context, userID sql.NullString, settings *storages.UserSettings) error {
+//				panic("mock out the UpdateUserSettings method")
+//			},
+//			ValidateSessionFunc: func(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) bool {
+//				panic("mock out the ValidateSession method")
+//			},
+//		}
+//
+//		// use mockedStore in code that requires storages.Store
+//		// and then make assertions.
+//
+//	}
+type StoreMock struct {
+	// AddAPIKeyFunc mocks the AddAPIKey method.
+	AddAPIKeyFunc func(ctx context.Context, k *storages.APIKey) error
+
+	// AddAnomalyFunc mocks the AddAnomaly method.
+	AddAnomalyFunc func(ctx context.Context, a *storages.Anomaly, throttle *storages.CreationThrottle) (bool, error)
+
+	// AddAuditEntryFunc mocks the AddAuditEntry method.
+	AddAuditEntryFunc func(ctx context.Context, e *storages.AuditEntry) error
+
+	// AddInviteFunc mocks the AddInvite method.
+	AddInviteFunc func(ctx context.Context, inv *storages.Invite) error
+
+	// AddLabelFunc mocks the AddLabel method.
+	AddLabelFunc func(ctx context.Context, label *storages.Label) error
+
+	// AddQuotaAdjustmentFunc mocks the AddQuotaAdjustment method.
+	AddQuotaAdjustmentFunc func(ctx context.Context, a *storages.QuotaAdjustment) error
+
+	// AddSessionFunc mocks the AddSession method.
+	AddSessionFunc func(ctx context.Context, sess *storages.Session) error
+
+	// AddShareFunc mocks the AddShare method.
+	AddShareFunc func(ctx context.Context, share *storages.Share) error
+
+	// AddTaskFunc mocks the AddTask method.
+	AddTaskFunc func(ctx context.Context, t *storages.Task) error
+
+	// AddTaskLinkFunc mocks the AddTaskLink method.
+	AddTaskLinkFunc func(ctx context.Context, link *storages.TaskLink) error
+
+	// AddTaskWithLimitPerDayFunc mocks the AddTaskWithLimitPerDay method.
+	AddTaskWithLimitPerDayFunc func(ctx context.Context, t *storages.Task, key *storages.IdempotencyKey) (int, int, error)
+
+	// AddTasksFunc mocks the AddTasks method.
+	AddTasksFunc func(ctx context.Context, tasks []*storages.Task) error
+
+	// AddUserFunc mocks the AddUser method.
+	AddUserFunc func(ctx context.Context, u *storages.User) error
+
+	// AssignLabelFunc mocks the AssignLabel method.
+	AssignLabelFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString, at string) error
+
+	// CarryOverTasksFunc mocks the CarryOverTasks method.
+	CarryOverTasksFunc func(ctx context.Context, co *storages.CarryOver) (int, error)
+
+	// CompletePomodoroFunc mocks the CompletePomodoro method.
+	CompletePomodoroFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, pomodoroID sql.NullString, at string) (*storages.Pomodoro, error)
+
+	// CompleteTaskFunc mocks the CompleteTask method.
+	CompleteTaskFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.Task, error)
+
+	// CompleteTasksFunc mocks the CompleteTasks method.
+	CompleteTasksFunc func(ctx context.Context, userID sql.NullString, taskIDs []string, at string, undo *storages.Undo) ([]string, error)
+
+	// CountTasksFunc mocks the CountTasks method.
+	CountTasksFunc func(ctx context.Context, userID sql.NullString, createdDate sql.NullString) (int, int, error)
+
+	// CountTasksCreatedSinceFunc mocks the CountTasksCreatedSince method.
+	CountTasksCreatedSinceFunc func(ctx context.Context, userID sql.NullString, since string) (int, string, error)
+
+	// DeleteLabelFunc mocks the DeleteLabel method.
+	DeleteLabelFunc func(ctx context.Context, userID sql.NullString, labelID sql.NullString) error
+
+	// DeleteTasksFunc mocks the DeleteTasks method.
+	DeleteTasksFunc func(ctx context.Context, userID sql.NullString, taskIDs []string, undo *storages.Undo) ([]string, error)
+
+	// IncrementAPIUsageFunc mocks the IncrementAPIUsage method.
+	IncrementAPIUsageFunc func(ctx context.Context, userID sql.NullString, day string) (int, error)
+
+	// IterateCompletedTasksFunc mocks the IterateCompletedTasks method.
+	IterateCompletedTasksFunc func(ctx context.Context, from string, to string) (storages.TaskCursor, error)
+
+	// IterateTasksFunc mocks the IterateTasks method.
+	IterateTasksFunc func(ctx context.Context, from string, to string) (storages.TaskCursor, error)
+
+	// LiftCreationThrottleFunc mocks the LiftCreationThrottle method.
+	LiftCreationThrottleFunc func(ctx context.Context, userID sql.NullString) error
+
+	// MarkDigestSentFunc mocks the MarkDigestSent method.
+	MarkDigestSentFunc func(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error)
+
+	// MarkLimitNotifiedFunc mocks the MarkLimitNotified method.
+	MarkLimitNotifiedFunc func(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error)
+
+	// MarkStandupSentFunc mocks the MarkStandupSent method.
+	MarkStandupSentFunc func(ctx context.Context, date string, at string) (bool, error)
+
+	// MoveTaskFunc mocks the MoveTask method.
+	MoveTaskFunc func(ctx context.Context, move *storages.TaskMove) (*storages.Task, error)
+
+	// RecordLimitHitFunc mocks the RecordLimitHit method.
+	RecordLimitHitFunc func(ctx context.Context, userID sql.NullString, date sql.NullString, at string) error
+
+	// RemoveTaskLinkFunc mocks the RemoveTaskLink method.
+	RemoveTaskLinkFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, blockedByID sql.NullString) error
+
+	// RemoveTaskLocationFunc mocks the RemoveTaskLocation method.
+	RemoveTaskLocationFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) error
+
+	// ReplaceHolidaysFunc mocks the ReplaceHolidays method.
+	ReplaceHolidaysFunc func(ctx context.Context, from string, to string, dates []string) error
+
+	// RetrieveAPIKeyUserFunc mocks the RetrieveAPIKeyUser method.
+	RetrieveAPIKeyUserFunc func(ctx context.Context, keyHash string) (string, error)
+
+	// RetrieveAPIUsageFunc mocks the RetrieveAPIUsage method.
+	RetrieveAPIUsageFunc func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.APIUsage, error)
+
+	// RetrieveAccountAuditFunc mocks the RetrieveAccountAudit method.
+	RetrieveAccountAuditFunc func(ctx context.Context, q *storages.AuditQuery) ([]*storages.AuditEntry, error)
+
+	// RetrieveAnomaliesFunc mocks the RetrieveAnomalies method.
+	RetrieveAnomaliesFunc func(ctx context.Context, from string, to string) ([]*storages.Anomaly, error)
+
+	// RetrieveAuditLogFunc mocks the RetrieveAuditLog method.
+	RetrieveAuditLogFunc func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.AuditEntry, error)
+
+	// RetrieveCarryOverUsersFunc mocks the RetrieveCarryOverUsers method.
+	RetrieveCarryOverUsersFunc func(ctx context.Context) (map[string]string, error)
+
+	// RetrieveCompletedTasksFunc mocks the RetrieveCompletedTasks method.
+	RetrieveCompletedTasksFunc func(ctx context.Context, from string, to string) ([]*storages.Task, error)
+
+	// RetrieveContentSuggestionsFunc mocks the RetrieveContentSuggestions method.
+	RetrieveContentSuggestionsFunc func(ctx context.Context, userID sql.NullString, prefix string, n int) ([]*storages.ContentSuggestion, error)
+
+	// RetrieveCreationRatesFunc mocks the RetrieveCreationRates method.
+	RetrieveCreationRatesFunc func(ctx context.Context, baselineFrom string, from string, to string, min int) ([]*storages.CreationRate, error)
+
+	// RetrieveCreationThrottleFunc mocks the RetrieveCreationThrottle method.
+	RetrieveCreationThrottleFunc func(ctx context.Context, userID sql.NullString, now string) (*storages.CreationThrottle, error)
+
+	// RetrieveDayCountsFunc mocks the RetrieveDayCounts method.
+	RetrieveDayCountsFunc func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.DayCount, error)
+
+	// RetrieveDigestRecipientsFunc mocks the RetrieveDigestRecipients method.
+	RetrieveDigestRecipientsFunc func(ctx context.Context) ([]*storages.DigestRecipient, error)
+
+	// RetrieveLabelsFunc mocks the RetrieveLabels method.
+	RetrieveLabelsFunc func(ctx context.Context, userID sql.NullString) ([]*storages.Label, error)
+
+	// RetrieveLimitHitRollupsFunc mocks the RetrieveLimitHitRollups method.
+	RetrieveLimitHitRollupsFunc func(ctx context.Context, from string, to string) ([]*storages.LimitHitRollup, error)
+
+	// RetrieveLimitOffendersFunc mocks the RetrieveLimitOffenders method.
+	RetrieveLimitOffendersFunc func(ctx context.Context, from string, to string, n int) ([]*storages.LimitOffender, error)
+
+	// RetrieveLimitScheduleFunc mocks the RetrieveLimitSchedule method.
+	RetrieveLimitScheduleFunc func(ctx context.Context, userID sql.NullString) (*storages.LimitSchedule, error)
+
+	// RetrieveLinkedTasksFunc mocks the RetrieveLinkedTasks method.
+	RetrieveLinkedTasksFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Task, []*storages.Task, error)
+
+	// RetrieveNearbyTasksFunc mocks the RetrieveNearbyTasks method.
+	RetrieveNearbyTasksFunc func(ctx context.Context, q *storages.NearbyQuery) ([]*storages.NearbyTask, error)
+
+	// RetrievePasswordHashFunc mocks the RetrievePasswordHash method.
+	RetrievePasswordHashFunc func(ctx context.Context, userID sql.NullString) (string, error)
+
+	// RetrievePomodorosFunc mocks the RetrievePomodoros method.
+	RetrievePomodorosFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Pomodoro, error)
+
+	// RetrievePriorityCountsFunc mocks the RetrievePriorityCounts method.
+	RetrievePriorityCountsFunc func(ctx context.Context, userID sql.NullString, createdDate sql.NullString) ([]*storages.PriorityCount, error)
+
+	// RetrieveQuotaAdjustmentsFunc mocks the RetrieveQuotaAdjustments method.
+	RetrieveQuotaAdjustmentsFunc func(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.QuotaAdjustment, error)
+
+	// RetrieveSessionsFunc mocks the RetrieveSessions method.
+	RetrieveSessionsFunc func(ctx context.Context, userID sql.NullString, now string) ([]*storages.Session, error)
+
+	// RetrieveShareFunc mocks the RetrieveShare method.
+	RetrieveShareFunc func(ctx context.Context, shareID sql.NullString, now string) (*storages.Share, error)
+
+	// RetrieveSharesFunc mocks the RetrieveShares method.
+	RetrieveSharesFunc func(ctx context.Context, userID sql.NullString, now string) ([]*storages.Share, error)
+
+	// RetrieveSigningKeysFunc mocks the RetrieveSigningKeys method.
+	RetrieveSigningKeysFunc func(ctx context.Context, retiredAfter string) ([]*storages.SigningKey, error)
+
+	// RetrieveStandupFunc mocks the RetrieveStandup method.
+	RetrieveStandupFunc func(ctx context.Context, yesterday string, today string) ([]*storages.StandupMember, error)
+
+	// RetrieveStreakFunc mocks the RetrieveStreak method.
+	RetrieveStreakFunc func(ctx context.Context, userID sql.NullString) (*storages.Streak, error)
+
+	// RetrieveTaskFunc mocks the RetrieveTask method.
+	RetrieveTaskFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Task, error)
+
+	// RetrieveTaskLabelsFunc mocks the RetrieveTaskLabels method.
+	RetrieveTaskLabelsFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Label, error)
+
+	// RetrieveTaskLinksFunc mocks the RetrieveTaskLinks method.
+	RetrieveTaskLinksFunc func(ctx context.Context, userID sql.NullString) ([]*storages.TaskLink, error)
+
+	// RetrieveTaskLocationFunc mocks the RetrieveTaskLocation method.
+	RetrieveTaskLocationFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Location, error)
+
+	// RetrieveTaskOwnerFunc mocks the RetrieveTaskOwner method.
+	RetrieveTaskOwnerFunc func(ctx context.Context, taskID sql.NullString) (string, error)
+
+	// RetrieveTasksFunc mocks the RetrieveTasks method.
+	RetrieveTasksFunc func(ctx context.Context, userID sql.NullString, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error)
+
+	// RetrieveTasksAsOfFunc mocks the RetrieveTasksAsOf method.
+	RetrieveTasksAsOfFunc func(ctx context.Context, userID sql.NullString, createdDate sql.NullString, at string) ([]*storages.Task, error)
+
+	// RetrieveTimeEntriesFunc mocks the RetrieveTimeEntries method.
+	RetrieveTimeEntriesFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.TimeEntry, error)
+
+	// RetrieveUserFunc mocks the RetrieveUser method.
+	RetrieveUserFunc func(ctx context.Context, userID sql.NullString) (*storages.User, error)
+
+	// RetrieveUserSettingsFunc mocks the RetrieveUserSettings method.
+	RetrieveUserSettingsFunc func(ctx context.Context, userID sql.NullString) (*storages.UserSettings, error)
+
+	// RevokeSessionFunc mocks the RevokeSession method.
+	RevokeSessionFunc func(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) error
+
+	// RevokeShareFunc mocks the RevokeShare method.
+	RevokeShareFunc func(ctx context.Context, userID sql.NullString, shareID sql.NullString, now string) error
+
+	// RollUpLimitHitsFunc mocks the RollUpLimitHits method.
+	RollUpLimitHitsFunc func(ctx context.Context, date string) (int, error)
+
+	// RotateSigningKeyFunc mocks the RotateSigningKey method.
+	RotateSigningKeyFunc func(ctx context.Context, k *storages.SigningKey) error
+
+	// SetTaskEstimateFunc mocks the SetTaskEstimate method.
+	SetTaskEstimateFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, minutes int) error
+
+	// SetTaskLocationFunc mocks the SetTaskLocation method.
+	SetTaskLocationFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, loc storages.Location) error
+
+	// SignUpFunc mocks the SignUp method.
+	SignUpFunc func(ctx context.Context, code string, u *storages.User, at string) error
+
+	// StartPomodoroFunc mocks the StartPomodoro method.
+	StartPomodoroFunc func(ctx context.Context, p *storages.Pomodoro) error
+
+	// StartTimerFunc mocks the StartTimer method.
+	StartTimerFunc func(ctx context.Context, e *storages.TimeEntry) (*storages.TimeEntry, error)
+
+	// StopTimerFunc mocks the StopTimer method.
+	StopTimerFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.TimeEntry, error)
+
+	// UnassignLabelFunc mocks the UnassignLabel method.
+	UnassignLabelFunc func(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString) error
+
+	// UndoTasksFunc mocks the UndoTasks method.
+	UndoTasksFunc func(ctx context.Context, userID sql.NullString, token string, now string) (int, error)
+
+	// UpdateLabelFunc mocks the UpdateLabel method.
+	UpdateLabelFunc func(ctx context.Context, label *storages.Label) error
+
+	// UpdateLimitScheduleFunc mocks the UpdateLimitSchedule method.
+	UpdateLimitScheduleFunc func(ctx context.Context, userID sql.NullString, sched *storages.LimitSchedule) error
+
+	// UpdatePasswordHashFunc mocks the UpdatePasswordHash method.
+	UpdatePasswordHashFunc func(ctx context.Context, userID sql.NullString, hash string) error
+
+	// UpdateStreaksFunc mocks the UpdateStreaks method.
+	UpdateStreaksFunc func(ctx context.Context, day string) (int, error)
+
+	// UpdateUserFunc mocks the UpdateUser method.
+	UpdateUserFunc func(ctx context.Context, u *storages.User) error
+
+	// UpdateUserSettingsFunc mocks the UpdateUserSettings method.
+	UpdateUserSettingsFunc func(ctx context.Context, userID sql.NullString, settings *storages.UserSettings) error
+
+	// ValidateSessionFunc mocks the ValidateSession method.
+	ValidateSessionFunc func(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) bool
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddAPIKey holds details about calls to the AddAPIKey method.
+		AddAPIKey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// K is the k argument value.
+			K *storages.APIKey
+		}
+		// AddAnomaly holds details about calls to the AddAnomaly method.
+		AddAnomaly []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// A is the a argument value.
+			A *storages.Anomaly
+			// Throttle is the throttle argument value.
+			Throttle *storages.CreationThrottle
+		}
+		// AddAuditEntry holds details about calls to the AddAuditEntry method.
+		AddAuditEntry []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *storages.AuditEntry
+		}
+		// AddInvite holds details about calls to the AddInvite method.
+		AddInvite []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Inv is the inv argument value.
+			Inv *storages.Invite
+		}
+		// AddLabel holds details about calls to the AddLabel method.
+		AddLabel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Label is the label argument value.
+			Label *storages.Label
+		}
+		// AddQuotaAdjustment holds details about calls to the AddQuotaAdjustment method.
+		AddQuotaAdjustment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// A is the a argument value.
+			A *storages.QuotaAdjustment
+		}
+		// AddSession holds details about calls to the AddSession method.
+		AddSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Sess is the sess argument value.
+			Sess *storages.Session
+		}
+		// AddShare holds details about calls to the AddShare method.
+		AddShare []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Share is the share argument value.
+			Share *storages.Share
+		}
+		// AddTask holds details about calls to the AddTask method.
+		AddTask []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// T is the t argument value.
+			T *storages.Task
+		}
+		// AddTaskLink holds details about calls to the AddTaskLink method.
+		AddTaskLink []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Link is the link argument value.
+			Link *storages.TaskLink
+		}
+		// AddTaskWithLimitPerDay holds details about calls to the AddTaskWithLimitPerDay method.
+		AddTaskWithLimitPerDay []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// T is the t argument value.
+			T *storages.Task
+			// Key is the key argument value.
+			Key *storages.IdempotencyKey
+		}
+		// AddTasks holds details about calls to the AddTasks method.
+		AddTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Tasks is the tasks argument value.
+			Tasks []*storages.Task
+		}
+		// AddUser holds details about calls to the AddUser method.
+		AddUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// U is the u argument value.
+			U *storages.User
+		}
+		// AssignLabel holds details about calls to the AssignLabel method.
+		AssignLabel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// LabelID is the labelID argument value.
+			LabelID sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// CarryOverTasks holds details about calls to the CarryOverTasks method.
+		CarryOverTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Co is the co argument value.
+			Co *storages.CarryOver
+		}
+		// CompletePomodoro holds details about calls to the CompletePomodoro method.
+		CompletePomodoro []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// PomodoroID is the pomodoroID argument value.
+			PomodoroID sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// CompleteTask holds details about calls to the CompleteTask method.
+		CompleteTask []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// CompleteTasks holds details about calls to the CompleteTasks method.
+		CompleteTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskIDs is the taskIDs argument value.
+			TaskIDs []string
+			// At is the at argument value.
+			At string
+			// Undo is the undo argument value.
+			Undo *storages.Undo
+		}
+		// CountTasks holds details about calls to the CountTasks method.
+		CountTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// CreatedDate is the createdDate argument value.
+			CreatedDate sql.NullString
+		}
+		// CountTasksCreatedSince holds details about calls to the CountTasksCreatedSince method.
+		CountTasksCreatedSince []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Since is the since argument value.
+			Since string
+		}
+		// DeleteLabel holds details about calls to the DeleteLabel method.
+		DeleteLabel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// LabelID is the labelID argument value.
+			LabelID sql.NullString
+		}
+		// DeleteTasks holds details about calls to the DeleteTasks method.
+		DeleteTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskIDs is the taskIDs argument value.
+			TaskIDs []string
+			// Undo is the undo argument value.
+			Undo *storages.Undo
+		}
+		// IncrementAPIUsage holds details about calls to the IncrementAPIUsage method.
+		IncrementAPIUsage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Day is the day argument value.
+			Day string
+		}
+		// IterateCompletedTasks holds details about calls to the IterateCompletedTasks method.
+		IterateCompletedTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// IterateTasks holds details about calls to the IterateTasks method.
+		IterateTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// LiftCreationThrottle holds details about calls to the LiftCreationThrottle method.
+		LiftCreationThrottle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// MarkDigestSent holds details about calls to the MarkDigestSent method.
+		MarkDigestSent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Date is the date argument value.
+			Date sql.NullString
+		}
+		// MarkLimitNotified holds details about calls to the MarkLimitNotified method.
+		MarkLimitNotified []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Date is the date argument value.
+			Date sql.NullString
+		}
+		// MarkStandupSent holds details about calls to the MarkStandupSent method.
+		MarkStandupSent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Date is the date argument value.
+			Date string
+			// At is the at argument value.
+			At string
+		}
+		// MoveTask holds details about calls to the MoveTask method.
+		MoveTask []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Move is the move argument value.
+			Move *storages.TaskMove
+		}
+		// RecordLimitHit holds details about calls to the RecordLimitHit method.
+		RecordLimitHit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Date is the date argument value.
+			Date sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// RemoveTaskLink holds details about calls to the RemoveTaskLink method.
+		RemoveTaskLink []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// BlockedByID is the blockedByID argument value.
+			BlockedByID sql.NullString
+		}
+		// RemoveTaskLocation holds details about calls to the RemoveTaskLocation method.
+		RemoveTaskLocation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// ReplaceHolidays holds details about calls to the ReplaceHolidays method.
+		ReplaceHolidays []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+			// Dates is the dates argument value.
+			Dates []string
+		}
+		// RetrieveAPIKeyUser holds details about calls to the RetrieveAPIKeyUser method.
+		RetrieveAPIKeyUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// KeyHash is the keyHash argument value.
+			KeyHash string
+		}
+		// RetrieveAPIUsage holds details about calls to the RetrieveAPIUsage method.
+		RetrieveAPIUsage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveAccountAudit holds details about calls to the RetrieveAccountAudit method.
+		RetrieveAccountAudit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Q is the q argument value.
+			Q *storages.AuditQuery
+		}
+		// RetrieveAnomalies holds details about calls to the RetrieveAnomalies method.
+		RetrieveAnomalies []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveAuditLog holds details about calls to the RetrieveAuditLog method.
+		RetrieveAuditLog []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveCarryOverUsers holds details about calls to the RetrieveCarryOverUsers method.
+		RetrieveCarryOverUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RetrieveCompletedTasks holds details about calls to the RetrieveCompletedTasks method.
+		RetrieveCompletedTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveContentSuggestions holds details about calls to the RetrieveContentSuggestions method.
+		RetrieveContentSuggestions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Prefix is the prefix argument value.
+			Prefix string
+			// N is the n argument value.
+			N int
+		}
+		// RetrieveCreationRates holds details about calls to the RetrieveCreationRates method.
+		RetrieveCreationRates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BaselineFrom is the baselineFrom argument value.
+			BaselineFrom string
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+			// Min is the min argument value.
+			Min int
+		}
+		// RetrieveCreationThrottle holds details about calls to the RetrieveCreationThrottle method.
+		RetrieveCreationThrottle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RetrieveDayCounts holds details about calls to the RetrieveDayCounts method.
+		RetrieveDayCounts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveDigestRecipients holds details about calls to the RetrieveDigestRecipients method.
+		RetrieveDigestRecipients []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RetrieveLabels holds details about calls to the RetrieveLabels method.
+		RetrieveLabels []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrieveLimitHitRollups holds details about calls to the RetrieveLimitHitRollups method.
+		RetrieveLimitHitRollups []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveLimitOffenders holds details about calls to the RetrieveLimitOffenders method.
+		RetrieveLimitOffenders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+			// N is the n argument value.
+			N int
+		}
+		// RetrieveLimitSchedule holds details about calls to the RetrieveLimitSchedule method.
+		RetrieveLimitSchedule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrieveLinkedTasks holds details about calls to the RetrieveLinkedTasks method.
+		RetrieveLinkedTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveNearbyTasks holds details about calls to the RetrieveNearbyTasks method.
+		RetrieveNearbyTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Q is the q argument value.
+			Q *storages.NearbyQuery
+		}
+		// RetrievePasswordHash holds details about calls to the RetrievePasswordHash method.
+		RetrievePasswordHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrievePomodoros holds details about calls to the RetrievePomodoros method.
+		RetrievePomodoros []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrievePriorityCounts holds details about calls to the RetrievePriorityCounts method.
+		RetrievePriorityCounts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// CreatedDate is the createdDate argument value.
+			CreatedDate sql.NullString
+		}
+		// RetrieveQuotaAdjustments holds details about calls to the RetrieveQuotaAdjustments method.
+		RetrieveQuotaAdjustments []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// From is the from argument value.
+			From string
+			// To is the to argument value.
+			To string
+		}
+		// RetrieveSessions holds details about calls to the RetrieveSessions method.
+		RetrieveSessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RetrieveShare holds details about calls to the RetrieveShare method.
+		RetrieveShare []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ShareID is the shareID argument value.
+			ShareID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RetrieveShares holds details about calls to the RetrieveShares method.
+		RetrieveShares []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RetrieveSigningKeys holds details about calls to the RetrieveSigningKeys method.
+		RetrieveSigningKeys []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RetiredAfter is the retiredAfter argument value.
+			RetiredAfter string
+		}
+		// RetrieveStandup holds details about calls to the RetrieveStandup method.
+		RetrieveStandup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Yesterday is the yesterday argument value.
+			Yesterday string
+			// Today is the today argument value.
+			Today string
+		}
+		// RetrieveStreak holds details about calls to the RetrieveStreak method.
+		RetrieveStreak []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrieveTask holds details about calls to the RetrieveTask method.
+		RetrieveTask []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveTaskLabels holds details about calls to the RetrieveTaskLabels method.
+		RetrieveTaskLabels []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveTaskLinks holds details about calls to the RetrieveTaskLinks method.
+		RetrieveTaskLinks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrieveTaskLocation holds details about calls to the RetrieveTaskLocation method.
+		RetrieveTaskLocation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveTaskOwner holds details about calls to the RetrieveTaskOwner method.
+		RetrieveTaskOwner []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveTasks holds details about calls to the RetrieveTasks method.
+		RetrieveTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// CreatedDate is the createdDate argument value.
+			CreatedDate sql.NullString
+			// Opts is the opts argument value.
+			Opts storages.ListOptions
+		}
+		// RetrieveTasksAsOf holds details about calls to the RetrieveTasksAsOf method.
+		RetrieveTasksAsOf []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// CreatedDate is the createdDate argument value.
+			CreatedDate sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// RetrieveTimeEntries holds details about calls to the RetrieveTimeEntries method.
+		RetrieveTimeEntries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+		}
+		// RetrieveUser holds details about calls to the RetrieveUser method.
+		RetrieveUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RetrieveUserSettings holds details about calls to the RetrieveUserSettings method.
+		RetrieveUserSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+		}
+		// RevokeSession holds details about calls to the RevokeSession method.
+		RevokeSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// SessionID is the sessionID argument value.
+			SessionID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RevokeShare holds details about calls to the RevokeShare method.
+		RevokeShare []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// ShareID is the shareID argument value.
+			ShareID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+		// RollUpLimitHits holds details about calls to the RollUpLimitHits method.
+		RollUpLimitHits []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Date is the date argument value.
+			Date string
+		}
+		// RotateSigningKey holds details about calls to the RotateSigningKey method.
+		RotateSigningKey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// K is the k argument value.
+			K *storages.SigningKey
+		}
+		// SetTaskEstimate holds details about calls to the SetTaskEstimate method.
+		SetTaskEstimate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// Minutes is the minutes argument value.
+			Minutes int
+		}
+		// SetTaskLocation holds details about calls to the SetTaskLocation method.
+		SetTaskLocation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// Loc is the loc argument value.
+			Loc storages.Location
+		}
+		// SignUp holds details about calls to the SignUp method.
+		SignUp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+			// U is the u argument value.
+			U *storages.User
+			// At is the at argument value.
+			At string
+		}
+		// StartPomodoro holds details about calls to the StartPomodoro method.
+		StartPomodoro []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// P is the p argument value.
+			P *storages.Pomodoro
+		}
+		// StartTimer holds details about calls to the StartTimer method.
+		StartTimer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *storages.TimeEntry
+		}
+		// StopTimer holds details about calls to the StopTimer method.
+		StopTimer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// At is the at argument value.
+			At string
+		}
+		// UnassignLabel holds details about calls to the UnassignLabel method.
+		UnassignLabel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// TaskID is the taskID argument value.
+			TaskID sql.NullString
+			// LabelID is the labelID argument value.
+			LabelID sql.NullString
+		}
+		// UndoTasks holds details about calls to the UndoTasks method.
+		UndoTasks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Token is the token argument value.
+			Token string
+			// Now is the now argument value.
+			Now string
+		}
+		// UpdateLabel holds details about calls to the UpdateLabel method.
+		UpdateLabel []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Label is the label argument value.
+			Label *storages.Label
+		}
+		// UpdateLimitSchedule holds details about calls to the UpdateLimitSchedule method.
+		UpdateLimitSchedule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Sched is the sched argument value.
+			Sched *storages.LimitSchedule
+		}
+		// UpdatePasswordHash holds details about calls to the UpdatePasswordHash method.
+		UpdatePasswordHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Hash is the hash argument value.
+			Hash string
+		}
+		// UpdateStreaks holds details about calls to the UpdateStreaks method.
+		UpdateStreaks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Day is the day argument value.
+			Day string
+		}
+		// UpdateUser holds details about calls to the UpdateUser method.
+		UpdateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// U is the u argument value.
+			U *storages.User
+		}
+		// UpdateUserSettings holds details about calls to the UpdateUserSettings method.
+		UpdateUserSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// Settings is the settings argument value.
+			Settings *storages.UserSettings
+		}
+		// ValidateSession holds details about calls to the ValidateSession method.
+		ValidateSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID sql.NullString
+			// SessionID is the sessionID argument value.
+			SessionID sql.NullString
+			// Now is the now argument value.
+			Now string
+		}
+	}
+	lockAddAPIKey                  sync.RWMutex
+	lockAddAnomaly                 sync.RWMutex
+	lockAddAuditEntry              sync.RWMutex
+	lockAddInvite                  sync.RWMutex
+	lockAddLabel                   sync.RWMutex
+	lockAddQuotaAdjustment         sync.RWMutex
+	lockAddSession                 sync.RWMutex
+	lockAddShare                   sync.RWMutex
+	lockAddTask                    sync.RWMutex
+	lockAddTaskLink                sync.RWMutex
+	lockAddTaskWithLimitPerDay     sync.RWMutex
+	lockAddTasks                   sync.RWMutex
+	lockAddUser                    sync.RWMutex
+	lockAssignLabel                sync.RWMutex
+	lockCarryOverTasks             sync.RWMutex
+	lockCompletePomodoro           sync.RWMutex
+	lockCompleteTask               sync.RWMutex
+	lockCompleteTasks              sync.RWMutex
+	lockCountTasks                 sync.RWMutex
+	lockCountTasksCreatedSince     sync.RWMutex
+	lockDeleteLabel                sync.RWMutex
+	lockDeleteTasks                sync.RWMutex
+	lockIncrementAPIUsage          sync.RWMutex
+	lockIterateCompletedTasks      sync.RWMutex
+	lockIterateTasks               sync.RWMutex
+	lockLiftCreationThrottle       sync.RWMutex
+	lockMarkDigestSent             sync.RWMutex
+	lockMarkLimitNotified          sync.RWMutex
+	lockMarkStandupSent            sync.RWMutex
+	lockMoveTask                   sync.RWMutex
+	lockRecordLimitHit             sync.RWMutex
+	lockRemoveTaskLink             sync.RWMutex
+	lockRemoveTaskLocation         sync.RWMutex
+	lockReplaceHolidays            sync.RWMutex
+	lockRetrieveAPIKeyUser         sync.RWMutex
+	lockRetrieveAPIUsage           sync.RWMutex
+	lockRetrieveAccountAudit       sync.RWMutex
+	lockRetrieveAnomalies          sync.RWMutex
+	lockRetrieveAuditLog           sync.RWMutex
+	lockRetrieveCarryOverUsers     sync.RWMutex
+	lockRetrieveCompletedTasks     sync.RWMutex
+	lockRetrieveContentSuggestions sync.RWMutex
+	lockRetrieveCreationRates      sync.RWMutex
+	lockRetrieveCreationThrottle   sync.RWMutex
+	lockRetrieveDayCounts          sync.RWMutex
+	lockRetrieveDigestRecipients   sync.RWMutex
+	lockRetrieveLabels             sync.RWMutex
+	lockRetrieveLimitHitRollups    sync.RWMutex
+	lockRetrieveLimitOffenders     sync.RWMutex
+	lockRetrieveLimitSchedule      sync.RWMutex
+	lockRetrieveLinkedTasks        sync.RWMutex
+	lockRetrieveNearbyTasks        sync.RWMutex
+	lockRetrievePasswordHash       sync.RWMutex
+	lockRetrievePomodoros          sync.RWMutex
+	lockRetrievePriorityCounts     sync.RWMutex
+	lockRetrieveQuotaAdjustments   sync.RWMutex
+	lockRetrieveSessions           sync.RWMutex
+	lockRetrieveShare              sync.RWMutex
+	lockRetrieveShares             sync.RWMutex
+	lockRetrieveSigningKeys        sync.RWMutex
+	lockRetrieveStandup            sync.RWMutex
+	lockRetrieveStreak             sync.RWMutex
+	lockRetrieveTask               sync.RWMutex
+	lockRetrieveTaskLabels         sync.RWMutex
+	lockRetrieveTaskLinks          sync.RWMutex
+	lockRetrieveTaskLocation       sync.RWMutex
+	lockRetrieveTaskOwner          sync.RWMutex
+	lockRetrieveTasks              sync.RWMutex
+	lockRetrieveTasksAsOf          sync.RWMutex
+	lockRetrieveTimeEntries        sync.RWMutex
+	lockRetrieveUser               sync.RWMutex
+	lockRetrieveUserSettings       sync.RWMutex
+	lockRevokeSession              sync.RWMutex
+	lockRevokeShare                sync.RWMutex
+	lockRollUpLimitHits            sync.RWMutex
+	lockRotateSigningKey           sync.RWMutex
+	lockSetTaskEstimate            sync.RWMutex
+	lockSetTaskLocation            sync.RWMutex
+	lockSignUp                     sync.RWMutex
+	lockStartPomodoro              sync.RWMutex
+	lockStartTimer                 sync.RWMutex
+	lockStopTimer                  sync.RWMutex
+	lockUnassignLabel              sync.RWMutex
+	lockUndoTasks                  sync.RWMutex
+	lockUpdateLabel                sync.RWMutex
+	lockUpdateLimitSchedule        sync.RWMutex
+	lockUpdatePasswordHash         sync.RWMutex
+	lockUpdateStreaks              sync.RWMutex
+	lockUpdateUser                 sync.RWMutex
+	lockUpdateUserSettings         sync.RWMutex
+	lockValidateSession            sync.RWMutex
+}
+
+// AddAPIKey calls AddAPIKeyFunc.
+func (mock *StoreMock) AddAPIKey(ctx context.Context, k *storages.APIKey) error {
+	if mock.AddAPIKeyFunc == nil {
+		panic("StoreMock.AddAPIKeyFunc: method is nil but Store.AddAPIKey was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		K   *storages.APIKey
+	}{
+		Ctx: ctx,
+		K:   k,
+	}
+	mock.lockAddAPIKey.Lock()
+	mock.calls.AddAPIKey = append(mock.calls.AddAPIKey, callInfo)
+	mock.lockAddAPIKey.Unlock()
+	return mock.AddAPIKeyFunc(ctx, k)
+}
+
+// AddAPIKeyCalls gets all the calls that were made to AddAPIKey.
+// Check the length with:
+//
+//	len(mockedStore.AddAPIKeyCalls())
+func (mock *StoreMock) AddAPIKeyCalls() []struct {
+	Ctx context.Context
+	K   *storages.APIKey
+} {
+	var calls []struct {
+		Ctx context.Context
+		K   *storages.APIKey
+	}
+	mock.lockAddAPIKey.RLock()
+	calls = mock.calls.AddAPIKey
+	mock.lockAddAPIKey.RUnlock()
+	return calls
+}
+
+// AddAnomaly calls AddAnomalyFunc.
+func (mock *StoreMock) AddAnomaly(ctx context.Context, a *storages.Anomaly, throttle *storages.CreationThrottle) (bool, error) {
+	if mock.AddAnomalyFunc == nil {
+		panic("StoreMock.AddAnomalyFunc: method is nil but Store.AddAnomaly was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		A        *storages.Anomaly
+		Throttle *storages.CreationThrottle
+	}{
+		Ctx:      ctx,
+		A:        a,
+		Throttle: throttle,
+	}
+	mock.lockAddAnomaly.Lock()
+	mock.calls.AddAnomaly = append(mock.calls.AddAnomaly, callInfo)
+	mock.lockAddAnomaly.Unlock()
+	return mock.AddAnomalyFunc(ctx, a, throttle)
+}
+
+// AddAnomalyCalls gets all the calls that were made to AddAnomaly.
+// Check the length with:
+//
+//	len(mockedStore.AddAnomalyCalls())
+func (mock *StoreMock) AddAnomalyCalls() []struct {
+	Ctx      context.Context
+	A        *storages.Anomaly
+	Throttle *storages.CreationThrottle
+} {
+	var calls []struct {
+		Ctx      context.Context
+		A        *storages.Anomaly
+		Throttle *storages.CreationThrottle
+	}
+	mock.lockAddAnomaly.RLock()
+	calls = mock.calls.AddAnomaly
+	mock.lockAddAnomaly.RUnlock()
+	return calls
+}
+
+// AddAuditEntry calls AddAuditEntryFunc.
+func (mock *StoreMock) AddAuditEntry(ctx context.Context, e *storages.AuditEntry) error {
+	if mock.AddAuditEntryFunc == nil {
+		panic("StoreMock.AddAuditEntryFunc: method is nil but Store.AddAuditEntry was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		E   *storages.AuditEntry
+	}{
+		Ctx: ctx,
+		E:   e,
+	}
+	mock.lockAddAuditEntry.Lock()
+	mock.calls.AddAuditEntry = append(mock.calls.AddAuditEntry, callInfo)
+	mock.lockAddAuditEntry.Unlock()
+	return mock.AddAuditEntryFunc(ctx, e)
+}
+
+// AddAuditEntryCalls gets all the calls that were made to AddAuditEntry.
+// Check the length with:
+//
+//	len(mockedStore.AddAuditEntryCalls())
+func (mock *StoreMock) AddAuditEntryCalls() []struct {
+	Ctx context.Context
+	E   *storages.AuditEntry
+} {
+	var calls []struct {
+		Ctx context.Context
+		E   *storages.AuditEntry
+	}
+	mock.lockAddAuditEntry.RLock()
+	calls = mock.calls.AddAuditEntry
+	mock.lockAddAuditEntry.RUnlock()
+	return calls
+}
+
+// AddInvite calls AddInviteFunc.
+func (mock *StoreMock) AddInvite(ctx context.Context, inv *storages.Invite) error {
+	if mock.AddInviteFunc == nil {
+		panic("StoreMock.AddInviteFunc: method is nil but Store.AddInvite was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Inv *storages.Invite
+	}{
+		Ctx: ctx,
+		Inv: inv,
+	}
+	mock.lockAddInvite.Lock()
+	mock.calls.AddInvite = append(mock.calls.AddInvite, callInfo)
+	mock.lockAddInvite.Unlock()
+	return mock.AddInviteFunc(ctx, inv)
+}
+
+// AddInviteCalls gets all the calls that were made to AddInvite.
+// Check the length with:
+//
+//	len(mockedStore.AddInviteCalls())
+func (mock *StoreMock) AddInviteCalls() []struct {
+	Ctx context.Context
+	Inv *storages.Invite
+} {
+	var calls []struct {
+		Ctx context.Context
+		Inv *storages.Invite
+	}
+	mock.lockAddInvite.RLock()
+	calls = mock.calls.AddInvite
+	mock.lockAddInvite.RUnlock()
+	return calls
+}
+
+// AddLabel calls AddLabelFunc.
+func (mock *StoreMock) AddLabel(ctx context.Context, label *storages.Label) error {
+	if mock.AddLabelFunc == nil {
+		panic("StoreMock.AddLabelFunc: method is nil but Store.AddLabel was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Label *storages.Label
+	}{
+		Ctx:   ctx,
+		Label: label,
+	}
+	mock.lockAddLabel.Lock()
+	mock.calls.AddLabel = append(mock.calls.AddLabel, callInfo)
+	mock.lockAddLabel.Unlock()
+	return mock.AddLabelFunc(ctx, label)
+}
+
+// AddLabelCalls gets all the calls that were made to AddLabel.
+// Check the length with:
+//
+//	len(mockedStore.AddLabelCalls())
+func (mock *StoreMock) AddLabelCalls() []struct {
+	Ctx   context.Context
+	Label *storages.Label
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Label *storages.Label
+	}
+	mock.lockAddLabel.RLock()
+	calls = mock.calls.AddLabel
+	mock.lockAddLabel.RUnlock()
+	return calls
+}
+
+// AddQuotaAdjustment calls AddQuotaAdjustmentFunc.
+func (mock *StoreMock) AddQuotaAdjustment(ctx context.Context, a *storages.QuotaAdjustment) error {
+	if mock.AddQuotaAdjustmentFunc == nil {
+		panic("StoreMock.AddQuotaAdjustmentFunc: method is nil but Store.AddQuotaAdjustment was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		A   *storages.QuotaAdjustment
+	}{
+		Ctx: ctx,
+		A:   a,
+	}
+	mock.lockAddQuotaAdjustment.Lock()
+	mock.calls.AddQuotaAdjustment = append(mock.calls.AddQuotaAdjustment, callInfo)
+	mock.lockAddQuotaAdjustment.Unlock()
+	return mock.AddQuotaAdjustmentFunc(ctx, a)
+}
+
+// AddQuotaAdjustmentCalls gets all the calls that were made to AddQuotaAdjustment.
+// Check the length with:
+//
+//	len(mockedStore.AddQuotaAdjustmentCalls())
+func (mock *StoreMock) AddQuotaAdjustmentCalls() []struct {
+	Ctx context.Context
+	A   *storages.QuotaAdjustment
+} {
+	var calls []struct {
+		Ctx context.Context
+		A   *storages.QuotaAdjustment
+	}
+	mock.lockAddQuotaAdjustment.RLock()
+	calls = mock.calls.AddQuotaAdjustment
+	mock.lockAddQuotaAdjustment.RUnlock()
+	return calls
+}
+
+// AddSession calls AddSessionFunc.
+func (mock *StoreMock) AddSession(ctx context.Context, sess *storages.Session) error {
+	if mock.AddSessionFunc == nil {
+		panic("StoreMock.AddSessionFunc: method is nil but Store.AddSession was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Sess *storages.Session
+	}{
+		Ctx:  ctx,
+		Sess: sess,
+	}
+	mock.lockAddSession.Lock()
+	mock.calls.AddSession = append(mock.calls.AddSession, callInfo)
+	mock.lockAddSession.Unlock()
+	return mock.AddSessionFunc(ctx, sess)
+}
+
+// AddSessionCalls gets all the calls that were made to AddSession.
+// Check the length with:
+//
+//	len(mockedStore.AddSessionCalls())
+func (mock *StoreMock) AddSessionCalls() []struct {
+	Ctx  context.Context
+	Sess *storages.Session
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Sess *storages.Session
+	}
+	mock.lockAddSession.RLock()
+	calls = mock.calls.AddSession
+	mock.lockAddSession.RUnlock()
+	return calls
+}
+
+// AddShare calls AddShareFunc.
+func (mock *StoreMock) AddShare(ctx context.Context, share *storages.Share) error {
+	if mock.AddShareFunc == nil {
+		panic("StoreMock.AddShareFunc: method is nil but Store.AddShare was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Share *storages.Share
+	}{
+		Ctx:   ctx,
+		Share: share,
+	}
+	mock.lockAddShare.Lock()
+	mock.calls.AddShare = append(mock.calls.AddShare, callInfo)
+	mock.lockAddShare.Unlock()
+	return mock.AddShareFunc(ctx, share)
+}
+
+// AddShareCalls gets all the calls that were made to AddShare.
+// Check the length with:
+//
+//	len(mockedStore.AddShareCalls())
+func (mock *StoreMock) AddShareCalls() []struct {
+	Ctx   context.Context
+	Share *storages.Share
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Share *storages.Share
+	}
+	mock.lockAddShare.RLock()
+	calls = mock.calls.AddShare
+	mock.lockAddShare.RUnlock()
+	return calls
+}
+
+// AddTask calls AddTaskFunc.
+func (mock *StoreMock) AddTask(ctx context.Context, t *storages.Task) error {
+	if mock.AddTaskFunc == nil {
+		panic("StoreMock.AddTaskFunc: method is nil but Store.AddTask was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		T   *storages.Task
+	}{
+		Ctx: ctx,
+		T:   t,
+	}
+	mock.lockAddTask.Lock()
+	mock.calls.AddTask = append(mock.calls.AddTask, callInfo)
+	mock.lockAddTask.Unlock()
+	return mock.AddTaskFunc(ctx, t)
+}
+
+// AddTaskCalls gets all the calls that were made to AddTask.
+// Check the length with:
+//
+//	len(mockedStore.AddTaskCalls())
+func (mock *StoreMock) AddTaskCalls() []struct {
+	Ctx context.Context
+	T   *storages.Task
+} {
+	var calls []struct {
+		Ctx context.Context
+		T   *storages.Task
+	}
+	mock.lockAddTask.RLock()
+	calls = mock.calls.AddTask
+	mock.lockAddTask.RUnlock()
+	return calls
+}
+
+// AddTaskLink calls AddTaskLinkFunc.
+func (mock *StoreMock) AddTaskLink(ctx context.Context, link *storages.TaskLink) error {
+	if mock.AddTaskLinkFunc == nil {
+		panic("StoreMock.AddTaskLinkFunc: method is nil but Store.AddTaskLink was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Link *storages.TaskLink
+	}{
+		Ctx:  ctx,
+		Link: link,
+	}
+	mock.lockAddTaskLink.Lock()
+	mock.calls.AddTaskLink = append(mock.calls.AddTaskLink, callInfo)
+	mock.lockAddTaskLink.Unlock()
+	return mock.AddTaskLinkFunc(ctx, link)
+}
+
+// AddTaskLinkCalls gets all the calls that were made to AddTaskLink.
+// Check the length with:
+//
+//	len(mockedStore.AddTaskLinkCalls())
+func (mock *StoreMock) AddTaskLinkCalls() []struct {
+	Ctx  context.Context
+	Link *storages.TaskLink
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Link *storages.TaskLink
+	}
+	mock.lockAddTaskLink.RLock()
+	calls = mock.calls.AddTaskLink
+	mock.lockAddTaskLink.RUnlock()
+	return calls
+}
+
+// AddTaskWithLimitPerDay calls AddTaskWithLimitPerDayFunc.
+func (mock *StoreMock) AddTaskWithLimitPerDay(ctx context.Context, t *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+	if mock.AddTaskWithLimitPerDayFunc == nil {
+		panic("StoreMock.AddTaskWithLimitPerDayFunc: method is nil but Store.AddTaskWithLimitPerDay was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		T   *storages.Task
+		Key *storages.IdempotencyKey
+	}{
+		Ctx: ctx,
+		T:   t,
+		Key: key,
+	}
+	mock.lockAddTaskWithLimitPerDay.Lock()
+	mock.calls.AddTaskWithLimitPerDay = append(mock.calls.AddTaskWithLimitPerDay, callInfo)
+	mock.lockAddTaskWithLimitPerDay.Unlock()
+	return mock.AddTaskWithLimitPerDayFunc(ctx, t, key)
+}
+
+// AddTaskWithLimitPerDayCalls gets all the calls that were made to AddTaskWithLimitPerDay.
+// Check the length with:
+//
+//	len(mockedStore.AddTaskWithLimitPerDayCalls())
+func (mock *StoreMock) AddTaskWithLimitPerDayCalls() []struct {
+	Ctx context.Context
+	T   *storages.Task
+	Key *storages.IdempotencyKey
+} {
+	var calls []struct {
+		Ctx context.Context
+		T   *storages.Task
+		Key *storages.IdempotencyKey
+	}
+	mock.lockAddTaskWithLimitPerDay.RLock()
+	calls = mock.calls.AddTaskWithLimitPerDay
+	mock.lockAddTaskWithLimitPerDay.RUnlock()
+	return calls
+}
+
+// AddTasks calls AddTasksFunc.
+func (mock *StoreMock) AddTasks(ctx context.Context, tasks []*storages.Task) error {
+	if mock.AddTasksFunc == nil {
+		panic("StoreMock.AddTasksFunc: method is nil but Store.AddTasks was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Tasks []*storages.Task
+	}{
+		Ctx:   ctx,
+		Tasks: tasks,
+	}
+	mock.lockAddTasks.Lock()
+	mock.calls.AddTasks = append(mock.calls.AddTasks, callInfo)
+	mock.lockAddTasks.Unlock()
+	return mock.AddTasksFunc(ctx, tasks)
+}
+
+// AddTasksCalls gets all the calls that were made to AddTasks.
+// Check the length with:
+//
+//	len(mockedStore.AddTasksCalls())
+func (mock *StoreMock) AddTasksCalls() []struct {
+	Ctx   context.Context
+	Tasks []*storages.Task
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Tasks []*storages.Task
+	}
+	mock.lockAddTasks.RLock()
+	calls = mock.calls.AddTasks
+	mock.lockAddTasks.RUnlock()
+	return calls
+}
+
+// AddUser calls AddUserFunc.
+func (mock *StoreMock) AddUser(ctx context.Context, u *storages.User) error {
+	if mock.AddUserFunc == nil {
+		panic("StoreMock.AddUserFunc: method is nil but Store.AddUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		U   *storages.User
+	}{
+		Ctx: ctx,
+		U:   u,
+	}
+	mock.lockAddUser.Lock()
+	mock.calls.AddUser = append(mock.calls.AddUser, callInfo)
+	mock.lockAddUser.Unlock()
+	return mock.AddUserFunc(ctx, u)
+}
+
+// AddUserCalls gets all the calls that were made to AddUser.
+// Check the length with:
+//
+//	len(mockedStore.AddUserCalls())
+func (mock *StoreMock) AddUserCalls() []struct {
+	Ctx context.Context
+	U   *storages.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		U   *storages.User
+	}
+	mock.lockAddUser.RLock()
+	calls = mock.calls.AddUser
+	mock.lockAddUser.RUnlock()
+	return calls
+}
+
+// AssignLabel calls AssignLabelFunc.
+func (mock *StoreMock) AssignLabel(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString, at string) error {
+	if mock.AssignLabelFunc == nil {
+		panic("StoreMock.AssignLabelFunc: method is nil but Store.AssignLabel was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		LabelID sql.NullString
+		At      string
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TaskID:  taskID,
+		LabelID: labelID,
+		At:      at,
+	}
+	mock.lockAssignLabel.Lock()
+	mock.calls.AssignLabel = append(mock.calls.AssignLabel, callInfo)
+	mock.lockAssignLabel.Unlock()
+	return mock.AssignLabelFunc(ctx, userID, taskID, labelID, at)
+}
+
+// AssignLabelCalls gets all the calls that were made to AssignLabel.
+// Check the length with:
+//
+//	len(mockedStore.AssignLabelCalls())
+func (mock *StoreMock) AssignLabelCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	TaskID  sql.NullString
+	LabelID sql.NullString
+	At      string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		LabelID sql.NullString
+		At      string
+	}
+	mock.lockAssignLabel.RLock()
+	calls = mock.calls.AssignLabel
+	mock.lockAssignLabel.RUnlock()
+	return calls
+}
+
+// CarryOverTasks calls CarryOverTasksFunc.
+func (mock *StoreMock) CarryOverTasks(ctx context.Context, co *storages.CarryOver) (int, error) {
+	if mock.CarryOverTasksFunc == nil {
+		panic("StoreMock.CarryOverTasksFunc: method is nil but Store.CarryOverTasks was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Co  *storages.CarryOver
+	}{
+		Ctx: ctx,
+		Co:  co,
+	}
+	mock.lockCarryOverTasks.Lock()
+	mock.calls.CarryOverTasks = append(mock.calls.CarryOverTasks, callInfo)
+	mock.lockCarryOverTasks.Unlock()
+	return mock.CarryOverTasksFunc(ctx, co)
+}
+
+// CarryOverTasksCalls gets all the calls that were made to CarryOverTasks.
+// Check the length with:
+//
+//	len(mockedStore.CarryOverTasksCalls())
+func (mock *StoreMock) CarryOverTasksCalls() []struct {
+	Ctx context.Context
+	Co  *storages.CarryOver
+} {
+	var calls []struct {
+		Ctx context.Context
+		Co  *storages.CarryOver
+	}
+	mock.lockCarryOverTasks.RLock()
+	calls = mock.calls.CarryOverTasks
+	mock.lockCarryOverTasks.RUnlock()
+	return calls
+}
+
+// CompletePomodoro calls CompletePomodoroFunc.
+func (mock *StoreMock) CompletePomodoro(ctx context.Context, userID sql.NullString, taskID sql.NullString, pomodoroID sql.NullString, at string) (*storages.Pomodoro, error) {
+	if mock.CompletePomodoroFunc == nil {
+		panic("StoreMock.CompletePomodoroFunc: method is nil but Store.CompletePomodoro was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     sql.NullString
+		TaskID     sql.NullString
+		PomodoroID sql.NullString
+		At         string
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		TaskID:     taskID,
+		PomodoroID: pomodoroID,
+		At:         at,
+	}
+	mock.lockCompletePomodoro.Lock()
+	mock.calls.CompletePomodoro = append(mock.calls.CompletePomodoro, callInfo)
+	mock.lockCompletePomodoro.Unlock()
+	return mock.CompletePomodoroFunc(ctx, userID, taskID, pomodoroID, at)
+}
+
+// CompletePomodoroCalls gets all the calls that were made to CompletePomodoro.
+// Check the length with:
+//
+//	len(mockedStore.CompletePomodoroCalls())
+func (mock *StoreMock) CompletePomodoroCalls() []struct {
+	Ctx        context.Context
+	UserID     sql.NullString
+	TaskID     sql.NullString
+	PomodoroID sql.NullString
+	At         string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     sql.NullString
+		TaskID     sql.NullString
+		PomodoroID sql.NullString
+		At         string
+	}
+	mock.lockCompletePomodoro.RLock()
+	calls = mock.calls.CompletePomodoro
+	mock.lockCompletePomodoro.RUnlock()
+	return calls
+}
+
+// CompleteTask calls CompleteTaskFunc.
+func (mock *StoreMock) CompleteTask(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.Task, error) {
+	if mock.CompleteTaskFunc == nil {
+		panic("StoreMock.CompleteTaskFunc: method is nil but Store.CompleteTask was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		At     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+		At:     at,
+	}
+	mock.lockCompleteTask.Lock()
+	mock.calls.CompleteTask = append(mock.calls.CompleteTask, callInfo)
+	mock.lockCompleteTask.Unlock()
+	return mock.CompleteTaskFunc(ctx, userID, taskID, at)
+}
+
+// CompleteTaskCalls gets all the calls that were made to CompleteTask.
+// Check the length with:
+//
+//	len(mockedStore.CompleteTaskCalls())
+func (mock *StoreMock) CompleteTaskCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+	At     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		At     string
+	}
+	mock.lockCompleteTask.RLock()
+	calls = mock.calls.CompleteTask
+	mock.lockCompleteTask.RUnlock()
+	return calls
+}
+
+// CompleteTasks calls CompleteTasksFunc.
+func (mock *StoreMock) CompleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, at string, undo *storages.Undo) ([]string, error) {
+	if mock.CompleteTasksFunc == nil {
+		panic("StoreMock.CompleteTasksFunc: method is nil but Store.CompleteTasks was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskIDs []string
+		At      string
+		Undo    *storages.Undo
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TaskIDs: taskIDs,
+		At:      at,
+		Undo:    undo,
+	}
+	mock.lockCompleteTasks.Lock()
+	mock.calls.CompleteTasks = append(mock.calls.CompleteTasks, callInfo)
+	mock.lockCompleteTasks.Unlock()
+	return mock.CompleteTasksFunc(ctx, userID, taskIDs, at, undo)
+}
+
+// CompleteTasksCalls gets all the calls that were made to CompleteTasks.
+// Check the length with:
+//
+//	len(mockedStore.CompleteTasksCalls())
+func (mock *StoreMock) CompleteTasksCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	TaskIDs []string
+	At      string
+	Undo    *storages.Undo
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskIDs []string
+		At      string
+		Undo    *storages.Undo
+	}
+	mock.lockCompleteTasks.RLock()
+	calls = mock.calls.CompleteTasks
+	mock.lockCompleteTasks.RUnlock()
+	return calls
+}
+
+// CountTasks calls CountTasksFunc.
+func (mock *StoreMock) CountTasks(ctx context.Context, userID sql.NullString, createdDate sql.NullString) (int, int, error) {
+	if mock.CountTasksFunc == nil {
+		panic("StoreMock.CountTasksFunc: method is nil but Store.CountTasks was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		CreatedDate: createdDate,
+	}
+	mock.lockCountTasks.Lock()
+	mock.calls.CountTasks = append(mock.calls.CountTasks, callInfo)
+	mock.lockCountTasks.Unlock()
+	return mock.CountTasksFunc(ctx, userID, createdDate)
+}
+
+// CountTasksCalls gets all the calls that were made to CountTasks.
+// Check the length with:
+//
+//	len(mockedStore.CountTasksCalls())
+func (mock *StoreMock) CountTasksCalls() []struct {
+	Ctx         context.Context
+	UserID      sql.NullString
+	CreatedDate sql.NullString
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+	}
+	mock.lockCountTasks.RLock()
+	calls = mock.calls.CountTasks
+	mock.lockCountTasks.RUnlock()
+	return calls
+}
+
+// CountTasksCreatedSince calls CountTasksCreatedSinceFunc.
+func (mock *StoreMock) CountTasksCreatedSince(ctx context.Context, userID sql.NullString, since string) (int, string, error) {
+	if mock.CountTasksCreatedSinceFunc == nil {
+		panic("StoreMock.CountTasksCreatedSinceFunc: method is nil but Store.CountTasksCreatedSince was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Since  string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Since:  since,
+	}
+	mock.lockCountTasksCreatedSince.Lock()
+	mock.calls.CountTasksCreatedSince = append(mock.calls.CountTasksCreatedSince, callInfo)
+	mock.lockCountTasksCreatedSince.Unlock()
+	return mock.CountTasksCreatedSinceFunc(ctx, userID, since)
+}
+
+// CountTasksCreatedSinceCalls gets all the calls that were made to CountTasksCreatedSince.
+// Check the length with:
+//
+//	len(mockedStore.CountTasksCreatedSinceCalls())
+func (mock *StoreMock) CountTasksCreatedSinceCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Since  string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Since  string
+	}
+	mock.lockCountTasksCreatedSince.RLock()
+	calls = mock.calls.CountTasksCreatedSince
+	mock.lockCountTasksCreatedSince.RUnlock()
+	return calls
+}
+
+// DeleteLabel calls DeleteLabelFunc.
+func (mock *StoreMock) DeleteLabel(ctx context.Context, userID sql.NullString, labelID sql.NullString) error {
+	if mock.DeleteLabelFunc == nil {
+		panic("StoreMock.DeleteLabelFunc: method is nil but Store.DeleteLabel was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		LabelID sql.NullString
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		LabelID: labelID,
+	}
+	mock.lockDeleteLabel.Lock()
+	mock.calls.DeleteLabel = append(mock.calls.DeleteLabel, callInfo)
+	mock.lockDeleteLabel.Unlock()
+	return mock.DeleteLabelFunc(ctx, userID, labelID)
+}
+
+// DeleteLabelCalls gets all the calls that were made to DeleteLabel.
+// Check the length with:
+//
+//	len(mockedStore.DeleteLabelCalls())
+func (mock *StoreMock) DeleteLabelCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	LabelID sql.NullString
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		LabelID sql.NullString
+	}
+	mock.lockDeleteLabel.RLock()
+	calls = mock.calls.DeleteLabel
+	mock.lockDeleteLabel.RUnlock()
+	return calls
+}
+
+// DeleteTasks calls DeleteTasksFunc.
+func (mock *StoreMock) DeleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, undo *storages.Undo) ([]string, error) {
+	if mock.DeleteTasksFunc == nil {
+		panic("StoreMock.DeleteTasksFunc: method is nil but Store.DeleteTasks was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskIDs []string
+		Undo    *storages.Undo
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TaskIDs: taskIDs,
+		Undo:    undo,
+	}
+	mock.lockDeleteTasks.Lock()
+	mock.calls.DeleteTasks = append(mock.calls.DeleteTasks, callInfo)
+	mock.lockDeleteTasks.Unlock()
+	return mock.DeleteTasksFunc(ctx, userID, taskIDs, undo)
+}
+
+// DeleteTasksCalls gets all the calls that were made to DeleteTasks.
+// Check the length with:
+//
+//	len(mockedStore.DeleteTasksCalls())
+func (mock *StoreMock) DeleteTasksCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	TaskIDs []string
+	Undo    *storages.Undo
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskIDs []string
+		Undo    *storages.Undo
+	}
+	mock.lockDeleteTasks.RLock()
+	calls = mock.calls.DeleteTasks
+	mock.lockDeleteTasks.RUnlock()
+	return calls
+}
+
+// IncrementAPIUsage calls IncrementAPIUsageFunc.
+func (mock *StoreMock) IncrementAPIUsage(ctx context.Context, userID sql.NullString, day string) (int, error) {
+	if mock.IncrementAPIUsageFunc == nil {
+		panic("StoreMock.IncrementAPIUsageFunc: method is nil but Store.IncrementAPIUsage was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Day    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Day:    day,
+	}
+	mock.lockIncrementAPIUsage.Lock()
+	mock.calls.IncrementAPIUsage = append(mock.calls.IncrementAPIUsage, callInfo)
+	mock.lockIncrementAPIUsage.Unlock()
+	return mock.IncrementAPIUsageFunc(ctx, userID, day)
+}
+
+// IncrementAPIUsageCalls gets all the calls that were made to IncrementAPIUsage.
+// Check the length with:
+//
+//	len(mockedStore.IncrementAPIUsageCalls())
+func (mock *StoreMock) IncrementAPIUsageCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Day    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Day    string
+	}
+	mock.lockIncrementAPIUsage.RLock()
+	calls = mock.calls.IncrementAPIUsage
+	mock.lockIncrementAPIUsage.RUnlock()
+	return calls
+}
+
+// IterateCompletedTasks calls IterateCompletedTasksFunc.
+func (mock *StoreMock) IterateCompletedTasks(ctx context.Context, from string, to string) (storages.TaskCursor, error) {
+	if mock.IterateCompletedTasksFunc == nil {
+		panic("StoreMock.IterateCompletedTasksFunc: method is nil but Store.IterateCompletedTasks was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockIterateCompletedTasks.Lock()
+	mock.calls.IterateCompletedTasks = append(mock.calls.IterateCompletedTasks, callInfo)
+	mock.lockIterateCompletedTasks.Unlock()
+	return mock.IterateCompletedTasksFunc(ctx, from, to)
+}
+
+// IterateCompletedTasksCalls gets all the calls that were made to IterateCompletedTasks.
+// Check the length with:
+//
+//	len(mockedStore.IterateCompletedTasksCalls())
+func (mock *StoreMock) IterateCompletedTasksCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}
+	mock.lockIterateCompletedTasks.RLock()
+	calls = mock.calls.IterateCompletedTasks
+	mock.lockIterateCompletedTasks.RUnlock()
+	return calls
+}
+
+// IterateTasks calls IterateTasksFunc.
+func (mock *StoreMock) IterateTasks(ctx context.Context, from string, to string) (storages.TaskCursor, error) {
+	if mock.IterateTasksFunc == nil {
+		panic("StoreMock.IterateTasksFunc: method is nil but Store.IterateTasks was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockIterateTasks.Lock()
+	mock.calls.IterateTasks = append(mock.calls.IterateTasks, callInfo)
+	mock.lockIterateTasks.Unlock()
+	return mock.IterateTasksFunc(ctx, from, to)
+}
+
+// IterateTasksCalls gets all the calls that were made to IterateTasks.
+// Check the length with:
+//
+//	len(mockedStore.IterateTasksCalls())
+func (mock *StoreMock) IterateTasksCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}
+	mock.lockIterateTasks.RLock()
+	calls = mock.calls.IterateTasks
+	mock.lockIterateTasks.RUnlock()
+	return calls
+}
+
+// LiftCreationThrottle calls LiftCreationThrottleFunc.
+func (mock *StoreMock) LiftCreationThrottle(ctx context.Context, userID sql.NullString) error {
+	if mock.LiftCreationThrottleFunc == nil {
+		panic("StoreMock.LiftCreationThrottleFunc: method is nil but Store.LiftCreationThrottle was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockLiftCreationThrottle.Lock()
+	mock.calls.LiftCreationThrottle = append(mock.calls.LiftCreationThrottle, callInfo)
+	mock.lockLiftCreationThrottle.Unlock()
+	return mock.LiftCreationThrottleFunc(ctx, userID)
+}
+
+// LiftCreationThrottleCalls gets all the calls that were made to LiftCreationThrottle.
+// Check the length with:
+//
+//	len(mockedStore.LiftCreationThrottleCalls())
+func (mock *StoreMock) LiftCreationThrottleCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockLiftCreationThrottle.RLock()
+	calls = mock.calls.LiftCreationThrottle
+	mock.lockLiftCreationThrottle.RUnlock()
+	return calls
+}
+
+// MarkDigestSent calls MarkDigestSentFunc.
+func (mock *StoreMock) MarkDigestSent(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error) {
+	if mock.MarkDigestSentFunc == nil {
+		panic("StoreMock.MarkDigestSentFunc: method is nil but Store.MarkDigestSent was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Date:   date,
+	}
+	mock.lockMarkDigestSent.Lock()
+	mock.calls.MarkDigestSent = append(mock.calls.MarkDigestSent, callInfo)
+	mock.lockMarkDigestSent.Unlock()
+	return mock.MarkDigestSentFunc(ctx, userID, date)
+}
+
+// MarkDigestSentCalls gets all the calls that were made to MarkDigestSent.
+// Check the length with:
+//
+//	len(mockedStore.MarkDigestSentCalls())
+func (mock *StoreMock) MarkDigestSentCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Date   sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+	}
+	mock.lockMarkDigestSent.RLock()
+	calls = mock.calls.MarkDigestSent
+	mock.lockMarkDigestSent.RUnlock()
+	return calls
+}
+
+// MarkLimitNotified calls MarkLimitNotifiedFunc.
+func (mock *StoreMock) MarkLimitNotified(ctx context.Context, userID sql.NullString, date sql.NullString) (bool, error) {
+	if mock.MarkLimitNotifiedFunc == nil {
+		panic("StoreMock.MarkLimitNotifiedFunc: method is nil but Store.MarkLimitNotified was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Date:   date,
+	}
+	mock.lockMarkLimitNotified.Lock()
+	mock.calls.MarkLimitNotified = append(mock.calls.MarkLimitNotified, callInfo)
+	mock.lockMarkLimitNotified.Unlock()
+	return mock.MarkLimitNotifiedFunc(ctx, userID, date)
+}
+
+// MarkLimitNotifiedCalls gets all the calls that were made to MarkLimitNotified.
+// Check the length with:
+//
+//	len(mockedStore.MarkLimitNotifiedCalls())
+func (mock *StoreMock) MarkLimitNotifiedCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Date   sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+	}
+	mock.lockMarkLimitNotified.RLock()
+	calls = mock.calls.MarkLimitNotified
+	mock.lockMarkLimitNotified.RUnlock()
+	return calls
+}
+
+// MarkStandupSent calls MarkStandupSentFunc.
+func (mock *StoreMock) MarkStandupSent(ctx context.Context, date string, at string) (bool, error) {
+	if mock.MarkStandupSentFunc == nil {
+		panic("StoreMock.MarkStandupSentFunc: method is nil but Store.MarkStandupSent was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Date string
+		At   string
+	}{
+		Ctx:  ctx,
+		Date: date,
+		At:   at,
+	}
+	mock.lockMarkStandupSent.Lock()
+	mock.calls.MarkStandupSent = append(mock.calls.MarkStandupSent, callInfo)
+	mock.lockMarkStandupSent.Unlock()
+	return mock.MarkStandupSentFunc(ctx, date, at)
+}
+
+// MarkStandupSentCalls gets all the calls that were made to MarkStandupSent.
+// Check the length with:
+//
+//	len(mockedStore.MarkStandupSentCalls())
+func (mock *StoreMock) MarkStandupSentCalls() []struct {
+	Ctx  context.Context
+	Date string
+	At   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Date string
+		At   string
+	}
+	mock.lockMarkStandupSent.RLock()
+	calls = mock.calls.MarkStandupSent
+	mock.lockMarkStandupSent.RUnlock()
+	return calls
+}
+
+// MoveTask calls MoveTaskFunc.
+func (mock *StoreMock) MoveTask(ctx context.Context, move *storages.TaskMove) (*storages.Task, error) {
+	if mock.MoveTaskFunc == nil {
+		panic("StoreMock.MoveTaskFunc: method is nil but Store.MoveTask was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Move *storages.TaskMove
+	}{
+		Ctx:  ctx,
+		Move: move,
+	}
+	mock.lockMoveTask.Lock()
+	mock.calls.MoveTask = append(mock.calls.MoveTask, callInfo)
+	mock.lockMoveTask.Unlock()
+	return mock.MoveTaskFunc(ctx, move)
+}
+
+// MoveTaskCalls gets all the calls that were made to MoveTask.
+// Check the length with:
+//
+//	len(mockedStore.MoveTaskCalls())
+func (mock *StoreMock) MoveTaskCalls() []struct {
+	Ctx  context.Context
+	Move *storages.TaskMove
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Move *storages.TaskMove
+	}
+	mock.lockMoveTask.RLock()
+	calls = mock.calls.MoveTask
+	mock.lockMoveTask.RUnlock()
+	return calls
+}
+
+// RecordLimitHit calls RecordLimitHitFunc.
+func (mock *StoreMock) RecordLimitHit(ctx context.Context, userID sql.NullString, date sql.NullString, at string) error {
+	if mock.RecordLimitHitFunc == nil {
+		panic("StoreMock.RecordLimitHitFunc: method is nil but Store.RecordLimitHit was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+		At     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Date:   date,
+		At:     at,
+	}
+	mock.lockRecordLimitHit.Lock()
+	mock.calls.RecordLimitHit = append(mock.calls.RecordLimitHit, callInfo)
+	mock.lockRecordLimitHit.Unlock()
+	return mock.RecordLimitHitFunc(ctx, userID, date, at)
+}
+
+// RecordLimitHitCalls gets all the calls that were made to RecordLimitHit.
+// Check the length with:
+//
+//	len(mockedStore.RecordLimitHitCalls())
+func (mock *StoreMock) RecordLimitHitCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Date   sql.NullString
+	At     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Date   sql.NullString
+		At     string
+	}
+	mock.lockRecordLimitHit.RLock()
+	calls = mock.calls.RecordLimitHit
+	mock.lockRecordLimitHit.RUnlock()
+	return calls
+}
+
+// RemoveTaskLink calls RemoveTaskLinkFunc.
+func (mock *StoreMock) RemoveTaskLink(ctx context.Context, userID sql.NullString, taskID sql.NullString, blockedByID sql.NullString) error {
+	if mock.RemoveTaskLinkFunc == nil {
+		panic("StoreMock.RemoveTaskLinkFunc: method is nil but Store.RemoveTaskLink was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		TaskID      sql.NullString
+		BlockedByID sql.NullString
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		TaskID:      taskID,
+		BlockedByID: blockedByID,
+	}
+	mock.lockRemoveTaskLink.Lock()
+	mock.calls.RemoveTaskLink = append(mock.calls.RemoveTaskLink, callInfo)
+	mock.lockRemoveTaskLink.Unlock()
+	return mock.RemoveTaskLinkFunc(ctx, userID, taskID, blockedByID)
+}
+
+// RemoveTaskLinkCalls gets all the calls that were made to RemoveTaskLink.
+// Check the length with:
+//
+//	len(mockedStore.RemoveTaskLinkCalls())
+func (mock *StoreMock) RemoveTaskLinkCalls() []struct {
+	Ctx         context.Context
+	UserID      sql.NullString
+	TaskID      sql.NullString
+	BlockedByID sql.NullString
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		TaskID      sql.NullString
+		BlockedByID sql.NullString
+	}
+	mock.lockRemoveTaskLink.RLock()
+	calls = mock.calls.RemoveTaskLink
+	mock.lockRemoveTaskLink.RUnlock()
+	return calls
+}
+
+// RemoveTaskLocation calls RemoveTaskLocationFunc.
+func (mock *StoreMock) RemoveTaskLocation(ctx context.Context, userID sql.NullString, taskID sql.NullString) error {
+	if mock.RemoveTaskLocationFunc == nil {
+		panic("StoreMock.RemoveTaskLocationFunc: method is nil but Store.RemoveTaskLocation was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRemoveTaskLocation.Lock()
+	mock.calls.RemoveTaskLocation = append(mock.calls.RemoveTaskLocation, callInfo)
+	mock.lockRemoveTaskLocation.Unlock()
+	return mock.RemoveTaskLocationFunc(ctx, userID, taskID)
+}
+
+// RemoveTaskLocationCalls gets all the calls that were made to RemoveTaskLocation.
+// Check the length with:
+//
+//	len(mockedStore.RemoveTaskLocationCalls())
+func (mock *StoreMock) RemoveTaskLocationCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRemoveTaskLocation.RLock()
+	calls = mock.calls.RemoveTaskLocation
+	mock.lockRemoveTaskLocation.RUnlock()
+	return calls
+}
+
+// ReplaceHolidays calls ReplaceHolidaysFunc.
+func (mock *StoreMock) ReplaceHolidays(ctx context.Context, from string, to string, dates []string) error {
+	if mock.ReplaceHolidaysFunc == nil {
+		panic("StoreMock.ReplaceHolidaysFunc: method is nil but Store.ReplaceHolidays was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		From  string
+		To    string
+		Dates []string
+	}{
+		Ctx:   ctx,
+		From:  from,
+		To:    to,
+		Dates: dates,
+	}
+	mock.lockReplaceHolidays.Lock()
+	mock.calls.ReplaceHolidays = append(mock.calls.ReplaceHolidays, callInfo)
+	mock.lockReplaceHolidays.Unlock()
+	return mock.ReplaceHolidaysFunc(ctx, from, to, dates)
+}
+
+// ReplaceHolidaysCalls gets all the calls that were made to ReplaceHolidays.
+// Check the length with:
+//
+//	len(mockedStore.ReplaceHolidaysCalls())
+func (mock *StoreMock) ReplaceHolidaysCalls() []struct {
+	Ctx   context.Context
+	From  string
+	To    string
+	Dates []string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		From  string
+		To    string
+		Dates []string
+	}
+	mock.lockReplaceHolidays.RLock()
+	calls = mock.calls.ReplaceHolidays
+	mock.lockReplaceHolidays.RUnlock()
+	return calls
+}
+
+// RetrieveAPIKeyUser calls RetrieveAPIKeyUserFunc.
+func (mock *StoreMock) RetrieveAPIKeyUser(ctx context.Context, keyHash string) (string, error) {
+	if mock.RetrieveAPIKeyUserFunc == nil {
+		panic("StoreMock.RetrieveAPIKeyUserFunc: method is nil but Store.RetrieveAPIKeyUser was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		KeyHash string
+	}{
+		Ctx:     ctx,
+		KeyHash: keyHash,
+	}
+	mock.lockRetrieveAPIKeyUser.Lock()
+	mock.calls.RetrieveAPIKeyUser = append(mock.calls.RetrieveAPIKeyUser, callInfo)
+	mock.lockRetrieveAPIKeyUser.Unlock()
+	return mock.RetrieveAPIKeyUserFunc(ctx, keyHash)
+}
+
+// RetrieveAPIKeyUserCalls gets all the calls that were made to RetrieveAPIKeyUser.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveAPIKeyUserCalls())
+func (mock *StoreMock) RetrieveAPIKeyUserCalls() []struct {
+	Ctx     context.Context
+	KeyHash string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		KeyHash string
+	}
+	mock.lockRetrieveAPIKeyUser.RLock()
+	calls = mock.calls.RetrieveAPIKeyUser
+	mock.lockRetrieveAPIKeyUser.RUnlock()
+	return calls
+}
+
+// RetrieveAPIUsage calls RetrieveAPIUsageFunc.
+func (mock *StoreMock) RetrieveAPIUsage(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.APIUsage, error) {
+	if mock.RetrieveAPIUsageFunc == nil {
+		panic("StoreMock.RetrieveAPIUsageFunc: method is nil but Store.RetrieveAPIUsage was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		From:   from,
+		To:     to,
+	}
+	mock.lockRetrieveAPIUsage.Lock()
+	mock.calls.RetrieveAPIUsage = append(mock.calls.RetrieveAPIUsage, callInfo)
+	mock.lockRetrieveAPIUsage.Unlock()
+	return mock.RetrieveAPIUsageFunc(ctx, userID, from, to)
+}
+
+// RetrieveAPIUsageCalls gets all the calls that were made to RetrieveAPIUsage.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveAPIUsageCalls())
+func (mock *StoreMock) RetrieveAPIUsageCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	From   string
+	To     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}
+	mock.lockRetrieveAPIUsage.RLock()
+	calls = mock.calls.RetrieveAPIUsage
+	mock.lockRetrieveAPIUsage.RUnlock()
+	return calls
+}
+
+// RetrieveAccountAudit calls RetrieveAccountAuditFunc.
+func (mock *StoreMock) RetrieveAccountAudit(ctx context.Context, q *storages.AuditQuery) ([]*storages.AuditEntry, error) {
+	if mock.RetrieveAccountAuditFunc == nil {
+		panic("StoreMock.RetrieveAccountAuditFunc: method is nil but Store.RetrieveAccountAudit was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Q   *storages.AuditQuery
+	}{
+		Ctx: ctx,
+		Q:   q,
+	}
+	mock.lockRetrieveAccountAudit.Lock()
+	mock.calls.RetrieveAccountAudit = append(mock.calls.RetrieveAccountAudit, callInfo)
+	mock.lockRetrieveAccountAudit.Unlock()
+	return mock.RetrieveAccountAuditFunc(ctx, q)
+}
+
+// RetrieveAccountAuditCalls gets all the calls that were made to RetrieveAccountAudit.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveAccountAuditCalls())
+func (mock *StoreMock) RetrieveAccountAuditCalls() []struct {
+	Ctx context.Context
+	Q   *storages.AuditQuery
+} {
+	var calls []struct {
+		Ctx context.Context
+		Q   *storages.AuditQuery
+	}
+	mock.lockRetrieveAccountAudit.RLock()
+	calls = mock.calls.RetrieveAccountAudit
+	mock.lockRetrieveAccountAudit.RUnlock()
+	return calls
+}
+
+// RetrieveAnomalies calls RetrieveAnomaliesFunc.
+func (mock *StoreMock) RetrieveAnomalies(ctx context.Context, from string, to string) ([]*storages.Anomaly, error) {
+	if mock.RetrieveAnomaliesFunc == nil {
+		panic("StoreMock.RetrieveAnomaliesFunc: method is nil but Store.RetrieveAnomalies was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockRetrieveAnomalies.Lock()
+	mock.calls.RetrieveAnomalies = append(mock.calls.RetrieveAnomalies, callInfo)
+	mock.lockRetrieveAnomalies.Unlock()
+	return mock.RetrieveAnomaliesFunc(ctx, from, to)
+}
+
+// RetrieveAnomaliesCalls gets all the calls that were made to RetrieveAnomalies.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveAnomaliesCalls())
+func (mock *StoreMock) RetrieveAnomaliesCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}
+	mock.lockRetrieveAnomalies.RLock()
+	calls = mock.calls.RetrieveAnomalies
+	mock.lockRetrieveAnomalies.RUnlock()
+	return calls
+}
+
+// RetrieveAuditLog calls RetrieveAuditLogFunc.
+func (mock *StoreMock) RetrieveAuditLog(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.AuditEntry, error) {
+	if mock.RetrieveAuditLogFunc == nil {
+		panic("StoreMock.RetrieveAuditLogFunc: method is nil but Store.RetrieveAuditLog was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		From:   from,
+		To:     to,
+	}
+	mock.lockRetrieveAuditLog.Lock()
+	mock.calls.RetrieveAuditLog = append(mock.calls.RetrieveAuditLog, callInfo)
+	mock.lockRetrieveAuditLog.Unlock()
+	return mock.RetrieveAuditLogFunc(ctx, userID, from, to)
+}
+
+// RetrieveAuditLogCalls gets all the calls that were made to RetrieveAuditLog.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveAuditLogCalls())
+func (mock *StoreMock) RetrieveAuditLogCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	From   string
+	To     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}
+	mock.lockRetrieveAuditLog.RLock()
+	calls = mock.calls.RetrieveAuditLog
+	mock.lockRetrieveAuditLog.RUnlock()
+	return calls
+}
+
+// RetrieveCarryOverUsers calls RetrieveCarryOverUsersFunc.
+func (mock *StoreMock) RetrieveCarryOverUsers(ctx context.Context) (map[string]string, error) {
+	if mock.RetrieveCarryOverUsersFunc == nil {
+		panic("StoreMock.RetrieveCarryOverUsersFunc: method is nil but Store.RetrieveCarryOverUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockRetrieveCarryOverUsers.Lock()
+	mock.calls.RetrieveCarryOverUsers = append(mock.calls.RetrieveCarryOverUsers, callInfo)
+	mock.lockRetrieveCarryOverUsers.Unlock()
+	return mock.RetrieveCarryOverUsersFunc(ctx)
+}
+
+// RetrieveCarryOverUsersCalls gets all the calls that were made to RetrieveCarryOverUsers.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveCarryOverUsersCalls())
+func (mock *StoreMock) RetrieveCarryOverUsersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockRetrieveCarryOverUsers.RLock()
+	calls = mock.calls.RetrieveCarryOverUsers
+	mock.lockRetrieveCarryOverUsers.RUnlock()
+	return calls
+}
+
+// RetrieveCompletedTasks calls RetrieveCompletedTasksFunc.
+func (mock *StoreMock) RetrieveCompletedTasks(ctx context.Context, from string, to string) ([]*storages.Task, error) {
+	if mock.RetrieveCompletedTasksFunc == nil {
+		panic("StoreMock.RetrieveCompletedTasksFunc: method is nil but Store.RetrieveCompletedTasks was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockRetrieveCompletedTasks.Lock()
+	mock.calls.RetrieveCompletedTasks = append(mock.calls.RetrieveCompletedTasks, callInfo)
+	mock.lockRetrieveCompletedTasks.Unlock()
+	return mock.RetrieveCompletedTasksFunc(ctx, from, to)
+}
+
+// RetrieveCompletedTasksCalls gets all the calls that were made to RetrieveCompletedTasks.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveCompletedTasksCalls())
+func (mock *StoreMock) RetrieveCompletedTasksCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}
+	mock.lockRetrieveCompletedTasks.RLock()
+	calls = mock.calls.RetrieveCompletedTasks
+	mock.lockRetrieveCompletedTasks.RUnlock()
+	return calls
+}
+
+// RetrieveContentSuggestions calls RetrieveContentSuggestionsFunc.
+func (mock *StoreMock) RetrieveContentSuggestions(ctx context.Context, userID sql.NullString, prefix string, n int) ([]*storages.ContentSuggestion, error) {
+	if mock.RetrieveContentSuggestionsFunc == nil {
+		panic("StoreMock.RetrieveContentSuggestionsFunc: method is nil but Store.RetrieveContentSuggestions was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Prefix string
+		N      int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Prefix: prefix,
+		N:      n,
+	}
+	mock.lockRetrieveContentSuggestions.Lock()
+	mock.calls.RetrieveContentSuggestions = append(mock.calls.RetrieveContentSuggestions, callInfo)
+	mock.lockRetrieveContentSuggestions.Unlock()
+	return mock.RetrieveContentSuggestionsFunc(ctx, userID, prefix, n)
+}
+
+// RetrieveContentSuggestionsCalls gets all the calls that were made to RetrieveContentSuggestions.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveContentSuggestionsCalls())
+func (mock *StoreMock) RetrieveContentSuggestionsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Prefix string
+	N      int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Prefix string
+		N      int
+	}
+	mock.lockRetrieveContentSuggestions.RLock()
+	calls = mock.calls.RetrieveContentSuggestions
+	mock.lockRetrieveContentSuggestions.RUnlock()
+	return calls
+}
+
+// RetrieveCreationRates calls RetrieveCreationRatesFunc.
+func (mock *StoreMock) RetrieveCreationRates(ctx context.Context, baselineFrom string, from string, to string, min int) ([]*storages.CreationRate, error) {
+	if mock.RetrieveCreationRatesFunc == nil {
+		panic("StoreMock.RetrieveCreationRatesFunc: method is nil but Store.RetrieveCreationRates was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		BaselineFrom string
+		From         string
+		To           string
+		Min          int
+	}{
+		Ctx:          ctx,
+		BaselineFrom: baselineFrom,
+		From:         from,
+		To:           to,
+		Min:          min,
+	}
+	mock.lockRetrieveCreationRates.Lock()
+	mock.calls.RetrieveCreationRates = append(mock.calls.RetrieveCreationRates, callInfo)
+	mock.lockRetrieveCreationRates.Unlock()
+	return mock.RetrieveCreationRatesFunc(ctx, baselineFrom, from, to, min)
+}
+
+// RetrieveCreationRatesCalls gets all the calls that were made to RetrieveCreationRates.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveCreationRatesCalls())
+func (mock *StoreMock) RetrieveCreationRatesCalls() []struct {
+	Ctx          context.Context
+	BaselineFrom string
+	From         string
+	To           string
+	Min          int
+} {
+	var calls []struct {
+		Ctx          context.Context
+		BaselineFrom string
+		From         string
+		To           string
+		Min          int
+	}
+	mock.lockRetrieveCreationRates.RLock()
+	calls = mock.calls.RetrieveCreationRates
+	mock.lockRetrieveCreationRates.RUnlock()
+	return calls
+}
+
+// RetrieveCreationThrottle calls RetrieveCreationThrottleFunc.
+func (mock *StoreMock) RetrieveCreationThrottle(ctx context.Context, userID sql.NullString, now string) (*storages.CreationThrottle, error) {
+	if mock.RetrieveCreationThrottleFunc == nil {
+		panic("StoreMock.RetrieveCreationThrottleFunc: method is nil but Store.RetrieveCreationThrottle was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Now:    now,
+	}
+	mock.lockRetrieveCreationThrottle.Lock()
+	mock.calls.RetrieveCreationThrottle = append(mock.calls.RetrieveCreationThrottle, callInfo)
+	mock.lockRetrieveCreationThrottle.Unlock()
+	return mock.RetrieveCreationThrottleFunc(ctx, userID, now)
+}
+
+// RetrieveCreationThrottleCalls gets all the calls that were made to RetrieveCreationThrottle.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveCreationThrottleCalls())
+func (mock *StoreMock) RetrieveCreationThrottleCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Now    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}
+	mock.lockRetrieveCreationThrottle.RLock()
+	calls = mock.calls.RetrieveCreationThrottle
+	mock.lockRetrieveCreationThrottle.RUnlock()
+	return calls
+}
+
+// RetrieveDayCounts calls RetrieveDayCountsFunc.
+func (mock *StoreMock) RetrieveDayCounts(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.DayCount, error) {
+	if mock.RetrieveDayCountsFunc == nil {
+		panic("StoreMock.RetrieveDayCountsFunc: method is nil but Store.RetrieveDayCounts was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		From:   from,
+		To:     to,
+	}
+	mock.lockRetrieveDayCounts.Lock()
+	mock.calls.RetrieveDayCounts = append(mock.calls.RetrieveDayCounts, callInfo)
+	mock.lockRetrieveDayCounts.Unlock()
+	return mock.RetrieveDayCountsFunc(ctx, userID, from, to)
+}
+
+// RetrieveDayCountsCalls gets all the calls that were made to RetrieveDayCounts.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveDayCountsCalls())
+func (mock *StoreMock) RetrieveDayCountsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	From   string
+	To     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}
+	mock.lockRetrieveDayCounts.RLock()
+	calls = mock.calls.RetrieveDayCounts
+	mock.lockRetrieveDayCounts.RUnlock()
+	return calls
+}
+
+// RetrieveDigestRecipients calls RetrieveDigestRecipientsFunc.
+func (mock *StoreMock) RetrieveDigestRecipients(ctx context.Context) ([]*storages.DigestRecipient, error) {
+	if mock.RetrieveDigestRecipientsFunc == nil {
+		panic("StoreMock.RetrieveDigestRecipientsFunc: method is nil but Store.RetrieveDigestRecipients was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockRetrieveDigestRecipients.Lock()
+	mock.calls.RetrieveDigestRecipients = append(mock.calls.RetrieveDigestRecipients, callInfo)
+	mock.lockRetrieveDigestRecipients.Unlock()
+	return mock.RetrieveDigestRecipientsFunc(ctx)
+}
+
+// RetrieveDigestRecipientsCalls gets all the calls that were made to RetrieveDigestRecipients.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveDigestRecipientsCalls())
+func (mock *StoreMock) RetrieveDigestRecipientsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockRetrieveDigestRecipients.RLock()
+	calls = mock.calls.RetrieveDigestRecipients
+	mock.lockRetrieveDigestRecipients.RUnlock()
+	return calls
+}
+
+// RetrieveLabels calls RetrieveLabelsFunc.
+func (mock *StoreMock) RetrieveLabels(ctx context.Context, userID sql.NullString) ([]*storages.Label, error) {
+	if mock.RetrieveLabelsFunc == nil {
+		panic("StoreMock.RetrieveLabelsFunc: method is nil but Store.RetrieveLabels was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveLabels.Lock()
+	mock.calls.RetrieveLabels = append(mock.calls.RetrieveLabels, callInfo)
+	mock.lockRetrieveLabels.Unlock()
+	return mock.RetrieveLabelsFunc(ctx, userID)
+}
+
+// RetrieveLabelsCalls gets all the calls that were made to RetrieveLabels.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveLabelsCalls())
+func (mock *StoreMock) RetrieveLabelsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveLabels.RLock()
+	calls = mock.calls.RetrieveLabels
+	mock.lockRetrieveLabels.RUnlock()
+	return calls
+}
+
+// RetrieveLimitHitRollups calls RetrieveLimitHitRollupsFunc.
+func (mock *StoreMock) RetrieveLimitHitRollups(ctx context.Context, from string, to string) ([]*storages.LimitHitRollup, error) {
+	if mock.RetrieveLimitHitRollupsFunc == nil {
+		panic("StoreMock.RetrieveLimitHitRollupsFunc: method is nil but Store.RetrieveLimitHitRollups was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockRetrieveLimitHitRollups.Lock()
+	mock.calls.RetrieveLimitHitRollups = append(mock.calls.RetrieveLimitHitRollups, callInfo)
+	mock.lockRetrieveLimitHitRollups.Unlock()
+	return mock.RetrieveLimitHitRollupsFunc(ctx, from, to)
+}
+
+// RetrieveLimitHitRollupsCalls gets all the calls that were made to RetrieveLimitHitRollups.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveLimitHitRollupsCalls())
+func (mock *StoreMock) RetrieveLimitHitRollupsCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+	}
+	mock.lockRetrieveLimitHitRollups.RLock()
+	calls = mock.calls.RetrieveLimitHitRollups
+	mock.lockRetrieveLimitHitRollups.RUnlock()
+	return calls
+}
+
+// RetrieveLimitOffenders calls RetrieveLimitOffendersFunc.
+func (mock *StoreMock) RetrieveLimitOffenders(ctx context.Context, from string, to string, n int) ([]*storages.LimitOffender, error) {
+	if mock.RetrieveLimitOffendersFunc == nil {
+		panic("StoreMock.RetrieveLimitOffendersFunc: method is nil but Store.RetrieveLimitOffenders was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From string
+		To   string
+		N    int
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+		N:    n,
+	}
+	mock.lockRetrieveLimitOffenders.Lock()
+	mock.calls.RetrieveLimitOffenders = append(mock.calls.RetrieveLimitOffenders, callInfo)
+	mock.lockRetrieveLimitOffenders.Unlock()
+	return mock.RetrieveLimitOffendersFunc(ctx, from, to, n)
+}
+
+// RetrieveLimitOffendersCalls gets all the calls that were made to RetrieveLimitOffenders.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveLimitOffendersCalls())
+func (mock *StoreMock) RetrieveLimitOffendersCalls() []struct {
+	Ctx  context.Context
+	From string
+	To   string
+	N    int
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From string
+		To   string
+		N    int
+	}
+	mock.lockRetrieveLimitOffenders.RLock()
+	calls = mock.calls.RetrieveLimitOffenders
+	mock.lockRetrieveLimitOffenders.RUnlock()
+	return calls
+}
+
+// RetrieveLimitSchedule calls RetrieveLimitScheduleFunc.
+func (mock *StoreMock) RetrieveLimitSchedule(ctx context.Context, userID sql.NullString) (*storages.LimitSchedule, error) {
+	if mock.RetrieveLimitScheduleFunc == nil {
+		panic("StoreMock.RetrieveLimitScheduleFunc: method is nil but Store.RetrieveLimitSchedule was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveLimitSchedule.Lock()
+	mock.calls.RetrieveLimitSchedule = append(mock.calls.RetrieveLimitSchedule, callInfo)
+	mock.lockRetrieveLimitSchedule.Unlock()
+	return mock.RetrieveLimitScheduleFunc(ctx, userID)
+}
+
+// RetrieveLimitScheduleCalls gets all the calls that were made to RetrieveLimitSchedule.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveLimitScheduleCalls())
+func (mock *StoreMock) RetrieveLimitScheduleCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveLimitSchedule.RLock()
+	calls = mock.calls.RetrieveLimitSchedule
+	mock.lockRetrieveLimitSchedule.RUnlock()
+	return calls
+}
+
+// RetrieveLinkedTasks calls RetrieveLinkedTasksFunc.
+func (mock *StoreMock) RetrieveLinkedTasks(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Task, []*storages.Task, error) {
+	if mock.RetrieveLinkedTasksFunc == nil {
+		panic("StoreMock.RetrieveLinkedTasksFunc: method is nil but Store.RetrieveLinkedTasks was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveLinkedTasks.Lock()
+	mock.calls.RetrieveLinkedTasks = append(mock.calls.RetrieveLinkedTasks, callInfo)
+	mock.lockRetrieveLinkedTasks.Unlock()
+	return mock.RetrieveLinkedTasksFunc(ctx, userID, taskID)
+}
+
+// RetrieveLinkedTasksCalls gets all the calls that were made to RetrieveLinkedTasks.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveLinkedTasksCalls())
+func (mock *StoreMock) RetrieveLinkedTasksCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveLinkedTasks.RLock()
+	calls = mock.calls.RetrieveLinkedTasks
+	mock.lockRetrieveLinkedTasks.RUnlock()
+	return calls
+}
+
+// RetrieveNearbyTasks calls RetrieveNearbyTasksFunc.
+func (mock *StoreMock) RetrieveNearbyTasks(ctx context.Context, q *storages.NearbyQuery) ([]*storages.NearbyTask, error) {
+	if mock.RetrieveNearbyTasksFunc == nil {
+		panic("StoreMock.RetrieveNearbyTasksFunc: method is nil but Store.RetrieveNearbyTasks was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Q   *storages.NearbyQuery
+	}{
+		Ctx: ctx,
+		Q:   q,
+	}
+	mock.lockRetrieveNearbyTasks.Lock()
+	mock.calls.RetrieveNearbyTasks = append(mock.calls.RetrieveNearbyTasks, callInfo)
+	mock.lockRetrieveNearbyTasks.Unlock()
+	return mock.RetrieveNearbyTasksFunc(ctx, q)
+}
+
+// RetrieveNearbyTasksCalls gets all the calls that were made to RetrieveNearbyTasks.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveNearbyTasksCalls())
+func (mock *StoreMock) RetrieveNearbyTasksCalls() []struct {
+	Ctx context.Context
+	Q   *storages.NearbyQuery
+} {
+	var calls []struct {
+		Ctx context.Context
+		Q   *storages.NearbyQuery
+	}
+	mock.lockRetrieveNearbyTasks.RLock()
+	calls = mock.calls.RetrieveNearbyTasks
+	mock.lockRetrieveNearbyTasks.RUnlock()
+	return calls
+}
+
+// RetrievePasswordHash calls RetrievePasswordHashFunc.
+func (mock *StoreMock) RetrievePasswordHash(ctx context.Context, userID sql.NullString) (string, error) {
+	if mock.RetrievePasswordHashFunc == nil {
+		panic("StoreMock.RetrievePasswordHashFunc: method is nil but Store.RetrievePasswordHash was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrievePasswordHash.Lock()
+	mock.calls.RetrievePasswordHash = append(mock.calls.RetrievePasswordHash, callInfo)
+	mock.lockRetrievePasswordHash.Unlock()
+	return mock.RetrievePasswordHashFunc(ctx, userID)
+}
+
+// RetrievePasswordHashCalls gets all the calls that were made to RetrievePasswordHash.
+// Check the length with:
+//
+//	len(mockedStore.RetrievePasswordHashCalls())
+func (mock *StoreMock) RetrievePasswordHashCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrievePasswordHash.RLock()
+	calls = mock.calls.RetrievePasswordHash
+	mock.lockRetrievePasswordHash.RUnlock()
+	return calls
+}
+
+// RetrievePomodoros calls RetrievePomodorosFunc.
+func (mock *StoreMock) RetrievePomodoros(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Pomodoro, error) {
+	if mock.RetrievePomodorosFunc == nil {
+		panic("StoreMock.RetrievePomodorosFunc: method is nil but Store.RetrievePomodoros was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrievePomodoros.Lock()
+	mock.calls.RetrievePomodoros = append(mock.calls.RetrievePomodoros, callInfo)
+	mock.lockRetrievePomodoros.Unlock()
+	return mock.RetrievePomodorosFunc(ctx, userID, taskID)
+}
+
+// RetrievePomodorosCalls gets all the calls that were made to RetrievePomodoros.
+// Check the length with:
+//
+//	len(mockedStore.RetrievePomodorosCalls())
+func (mock *StoreMock) RetrievePomodorosCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrievePomodoros.RLock()
+	calls = mock.calls.RetrievePomodoros
+	mock.lockRetrievePomodoros.RUnlock()
+	return calls
+}
+
+// RetrievePriorityCounts calls RetrievePriorityCountsFunc.
+func (mock *StoreMock) RetrievePriorityCounts(ctx context.Context, userID sql.NullString, createdDate sql.NullString) ([]*storages.PriorityCount, error) {
+	if mock.RetrievePriorityCountsFunc == nil {
+		panic("StoreMock.RetrievePriorityCountsFunc: method is nil but Store.RetrievePriorityCounts was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		CreatedDate: createdDate,
+	}
+	mock.lockRetrievePriorityCounts.Lock()
+	mock.calls.RetrievePriorityCounts = append(mock.calls.RetrievePriorityCounts, callInfo)
+	mock.lockRetrievePriorityCounts.Unlock()
+	return mock.RetrievePriorityCountsFunc(ctx, userID, createdDate)
+}
+
+// RetrievePriorityCountsCalls gets all the calls that were made to RetrievePriorityCounts.
+// Check the length with:
+//
+//	len(mockedStore.RetrievePriorityCountsCalls())
+func (mock *StoreMock) RetrievePriorityCountsCalls() []struct {
+	Ctx         context.Context
+	UserID      sql.NullString
+	CreatedDate sql.NullString
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+	}
+	mock.lockRetrievePriorityCounts.RLock()
+	calls = mock.calls.RetrievePriorityCounts
+	mock.lockRetrievePriorityCounts.RUnlock()
+	return calls
+}
+
+// RetrieveQuotaAdjustments calls RetrieveQuotaAdjustmentsFunc.
+func (mock *StoreMock) RetrieveQuotaAdjustments(ctx context.Context, userID sql.NullString, from string, to string) ([]*storages.QuotaAdjustment, error) {
+	if mock.RetrieveQuotaAdjustmentsFunc == nil {
+		panic("StoreMock.RetrieveQuotaAdjustmentsFunc: method is nil but Store.RetrieveQuotaAdjustments was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		From:   from,
+		To:     to,
+	}
+	mock.lockRetrieveQuotaAdjustments.Lock()
+	mock.calls.RetrieveQuotaAdjustments = append(mock.calls.RetrieveQuotaAdjustments, callInfo)
+	mock.lockRetrieveQuotaAdjustments.Unlock()
+	return mock.RetrieveQuotaAdjustmentsFunc(ctx, userID, from, to)
+}
+
+// RetrieveQuotaAdjustmentsCalls gets all the calls that were made to RetrieveQuotaAdjustments.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveQuotaAdjustmentsCalls())
+func (mock *StoreMock) RetrieveQuotaAdjustmentsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	From   string
+	To     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		From   string
+		To     string
+	}
+	mock.lockRetrieveQuotaAdjustments.RLock()
+	calls = mock.calls.RetrieveQuotaAdjustments
+	mock.lockRetrieveQuotaAdjustments.RUnlock()
+	return calls
+}
+
+// RetrieveSessions calls RetrieveSessionsFunc.
+func (mock *StoreMock) RetrieveSessions(ctx context.Context, userID sql.NullString, now string) ([]*storages.Session, error) {
+	if mock.RetrieveSessionsFunc == nil {
+		panic("StoreMock.RetrieveSessionsFunc: method is nil but Store.RetrieveSessions was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Now:    now,
+	}
+	mock.lockRetrieveSessions.Lock()
+	mock.calls.RetrieveSessions = append(mock.calls.RetrieveSessions, callInfo)
+	mock.lockRetrieveSessions.Unlock()
+	return mock.RetrieveSessionsFunc(ctx, userID, now)
+}
+
+// RetrieveSessionsCalls gets all the calls that were made to RetrieveSessions.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveSessionsCalls())
+func (mock *StoreMock) RetrieveSessionsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Now    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}
+	mock.lockRetrieveSessions.RLock()
+	calls = mock.calls.RetrieveSessions
+	mock.lockRetrieveSessions.RUnlock()
+	return calls
+}
+
+// RetrieveShare calls RetrieveShareFunc.
+func (mock *StoreMock) RetrieveShare(ctx context.Context, shareID sql.NullString, now string) (*storages.Share, error) {
+	if mock.RetrieveShareFunc == nil {
+		panic("StoreMock.RetrieveShareFunc: method is nil but Store.RetrieveShare was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		ShareID sql.NullString
+		Now     string
+	}{
+		Ctx:     ctx,
+		ShareID: shareID,
+		Now:     now,
+	}
+	mock.lockRetrieveShare.Lock()
+	mock.calls.RetrieveShare = append(mock.calls.RetrieveShare, callInfo)
+	mock.lockRetrieveShare.Unlock()
+	return mock.RetrieveShareFunc(ctx, shareID, now)
+}
+
+// RetrieveShareCalls gets all the calls that were made to RetrieveShare.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveShareCalls())
+func (mock *StoreMock) RetrieveShareCalls() []struct {
+	Ctx     context.Context
+	ShareID sql.NullString
+	Now     string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		ShareID sql.NullString
+		Now     string
+	}
+	mock.lockRetrieveShare.RLock()
+	calls = mock.calls.RetrieveShare
+	mock.lockRetrieveShare.RUnlock()
+	return calls
+}
+
+// RetrieveShares calls RetrieveSharesFunc.
+func (mock *StoreMock) RetrieveShares(ctx context.Context, userID sql.NullString, now string) ([]*storages.Share, error) {
+	if mock.RetrieveSharesFunc == nil {
+		panic("StoreMock.RetrieveSharesFunc: method is nil but Store.RetrieveShares was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Now:    now,
+	}
+	mock.lockRetrieveShares.Lock()
+	mock.calls.RetrieveShares = append(mock.calls.RetrieveShares, callInfo)
+	mock.lockRetrieveShares.Unlock()
+	return mock.RetrieveSharesFunc(ctx, userID, now)
+}
+
+// RetrieveSharesCalls gets all the calls that were made to RetrieveShares.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveSharesCalls())
+func (mock *StoreMock) RetrieveSharesCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Now    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Now    string
+	}
+	mock.lockRetrieveShares.RLock()
+	calls = mock.calls.RetrieveShares
+	mock.lockRetrieveShares.RUnlock()
+	return calls
+}
+
+// RetrieveSigningKeys calls RetrieveSigningKeysFunc.
+func (mock *StoreMock) RetrieveSigningKeys(ctx context.Context, retiredAfter string) ([]*storages.SigningKey, error) {
+	if mock.RetrieveSigningKeysFunc == nil {
+		panic("StoreMock.RetrieveSigningKeysFunc: method is nil but Store.RetrieveSigningKeys was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		RetiredAfter string
+	}{
+		Ctx:          ctx,
+		RetiredAfter: retiredAfter,
+	}
+	mock.lockRetrieveSigningKeys.Lock()
+	mock.calls.RetrieveSigningKeys = append(mock.calls.RetrieveSigningKeys, callInfo)
+	mock.lockRetrieveSigningKeys.Unlock()
+	return mock.RetrieveSigningKeysFunc(ctx, retiredAfter)
+}
+
+// RetrieveSigningKeysCalls gets all the calls that were made to RetrieveSigningKeys.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveSigningKeysCalls())
+func (mock *StoreMock) RetrieveSigningKeysCalls() []struct {
+	Ctx          context.Context
+	RetiredAfter string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		RetiredAfter string
+	}
+	mock.lockRetrieveSigningKeys.RLock()
+	calls = mock.calls.RetrieveSigningKeys
+	mock.lockRetrieveSigningKeys.RUnlock()
+	return calls
+}
+
+// RetrieveStandup calls RetrieveStandupFunc.
+func (mock *StoreMock) RetrieveStandup(ctx context.Context, yesterday string, today string) ([]*storages.StandupMember, error) {
+	if mock.RetrieveStandupFunc == nil {
+		panic("StoreMock.RetrieveStandupFunc: method is nil but Store.RetrieveStandup was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Yesterday string
+		Today     string
+	}{
+		Ctx:       ctx,
+		Yesterday: yesterday,
+		Today:     today,
+	}
+	mock.lockRetrieveStandup.Lock()
+	mock.calls.RetrieveStandup = append(mock.calls.RetrieveStandup, callInfo)
+	mock.lockRetrieveStandup.Unlock()
+	return mock.RetrieveStandupFunc(ctx, yesterday, today)
+}
+
+// RetrieveStandupCalls gets all the calls that were made to RetrieveStandup.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveStandupCalls())
+func (mock *StoreMock) RetrieveStandupCalls() []struct {
+	Ctx       context.Context
+	Yesterday string
+	Today     string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Yesterday string
+		Today     string
+	}
+	mock.lockRetrieveStandup.RLock()
+	calls = mock.calls.RetrieveStandup
+	mock.lockRetrieveStandup.RUnlock()
+	return calls
+}
+
+// RetrieveStreak calls RetrieveStreakFunc.
+func (mock *StoreMock) RetrieveStreak(ctx context.Context, userID sql.NullString) (*storages.Streak, error) {
+	if mock.RetrieveStreakFunc == nil {
+		panic("StoreMock.RetrieveStreakFunc: method is nil but Store.RetrieveStreak was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveStreak.Lock()
+	mock.calls.RetrieveStreak = append(mock.calls.RetrieveStreak, callInfo)
+	mock.lockRetrieveStreak.Unlock()
+	return mock.RetrieveStreakFunc(ctx, userID)
+}
+
+// RetrieveStreakCalls gets all the calls that were made to RetrieveStreak.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveStreakCalls())
+func (mock *StoreMock) RetrieveStreakCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveStreak.RLock()
+	calls = mock.calls.RetrieveStreak
+	mock.lockRetrieveStreak.RUnlock()
+	return calls
+}
+
+// RetrieveTask calls RetrieveTaskFunc.
+func (mock *StoreMock) RetrieveTask(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Task, error) {
+	if mock.RetrieveTaskFunc == nil {
+		panic("StoreMock.RetrieveTaskFunc: method is nil but Store.RetrieveTask was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveTask.Lock()
+	mock.calls.RetrieveTask = append(mock.calls.RetrieveTask, callInfo)
+	mock.lockRetrieveTask.Unlock()
+	return mock.RetrieveTaskFunc(ctx, userID, taskID)
+}
+
+// RetrieveTaskCalls gets all the calls that were made to RetrieveTask.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTaskCalls())
+func (mock *StoreMock) RetrieveTaskCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveTask.RLock()
+	calls = mock.calls.RetrieveTask
+	mock.lockRetrieveTask.RUnlock()
+	return calls
+}
+
+// RetrieveTaskLabels calls RetrieveTaskLabelsFunc.
+func (mock *StoreMock) RetrieveTaskLabels(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.Label, error) {
+	if mock.RetrieveTaskLabelsFunc == nil {
+		panic("StoreMock.RetrieveTaskLabelsFunc: method is nil but Store.RetrieveTaskLabels was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveTaskLabels.Lock()
+	mock.calls.RetrieveTaskLabels = append(mock.calls.RetrieveTaskLabels, callInfo)
+	mock.lockRetrieveTaskLabels.Unlock()
+	return mock.RetrieveTaskLabelsFunc(ctx, userID, taskID)
+}
+
+// RetrieveTaskLabelsCalls gets all the calls that were made to RetrieveTaskLabels.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTaskLabelsCalls())
+func (mock *StoreMock) RetrieveTaskLabelsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveTaskLabels.RLock()
+	calls = mock.calls.RetrieveTaskLabels
+	mock.lockRetrieveTaskLabels.RUnlock()
+	return calls
+}
+
+// RetrieveTaskLinks calls RetrieveTaskLinksFunc.
+func (mock *StoreMock) RetrieveTaskLinks(ctx context.Context, userID sql.NullString) ([]*storages.TaskLink, error) {
+	if mock.RetrieveTaskLinksFunc == nil {
+		panic("StoreMock.RetrieveTaskLinksFunc: method is nil but Store.RetrieveTaskLinks was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveTaskLinks.Lock()
+	mock.calls.RetrieveTaskLinks = append(mock.calls.RetrieveTaskLinks, callInfo)
+	mock.lockRetrieveTaskLinks.Unlock()
+	return mock.RetrieveTaskLinksFunc(ctx, userID)
+}
+
+// RetrieveTaskLinksCalls gets all the calls that were made to RetrieveTaskLinks.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTaskLinksCalls())
+func (mock *StoreMock) RetrieveTaskLinksCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveTaskLinks.RLock()
+	calls = mock.calls.RetrieveTaskLinks
+	mock.lockRetrieveTaskLinks.RUnlock()
+	return calls
+}
+
+// RetrieveTaskLocation calls RetrieveTaskLocationFunc.
+func (mock *StoreMock) RetrieveTaskLocation(ctx context.Context, userID sql.NullString, taskID sql.NullString) (*storages.Location, error) {
+	if mock.RetrieveTaskLocationFunc == nil {
+		panic("StoreMock.RetrieveTaskLocationFunc: method is nil but Store.RetrieveTaskLocation was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveTaskLocation.Lock()
+	mock.calls.RetrieveTaskLocation = append(mock.calls.RetrieveTaskLocation, callInfo)
+	mock.lockRetrieveTaskLocation.Unlock()
+	return mock.RetrieveTaskLocationFunc(ctx, userID, taskID)
+}
+
+// RetrieveTaskLocationCalls gets all the calls that were made to RetrieveTaskLocation.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTaskLocationCalls())
+func (mock *StoreMock) RetrieveTaskLocationCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveTaskLocation.RLock()
+	calls = mock.calls.RetrieveTaskLocation
+	mock.lockRetrieveTaskLocation.RUnlock()
+	return calls
+}
+
+// RetrieveTaskOwner calls RetrieveTaskOwnerFunc.
+func (mock *StoreMock) RetrieveTaskOwner(ctx context.Context, taskID sql.NullString) (string, error) {
+	if mock.RetrieveTaskOwnerFunc == nil {
+		panic("StoreMock.RetrieveTaskOwnerFunc: method is nil but Store.RetrieveTaskOwner was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveTaskOwner.Lock()
+	mock.calls.RetrieveTaskOwner = append(mock.calls.RetrieveTaskOwner, callInfo)
+	mock.lockRetrieveTaskOwner.Unlock()
+	return mock.RetrieveTaskOwnerFunc(ctx, taskID)
+}
+
+// RetrieveTaskOwnerCalls gets all the calls that were made to RetrieveTaskOwner.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTaskOwnerCalls())
+func (mock *StoreMock) RetrieveTaskOwnerCalls() []struct {
+	Ctx    context.Context
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveTaskOwner.RLock()
+	calls = mock.calls.RetrieveTaskOwner
+	mock.lockRetrieveTaskOwner.RUnlock()
+	return calls
+}
+
+// RetrieveTasks calls RetrieveTasksFunc.
+func (mock *StoreMock) RetrieveTasks(ctx context.Context, userID sql.NullString, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+	if mock.RetrieveTasksFunc == nil {
+		panic("StoreMock.RetrieveTasksFunc: method is nil but Store.RetrieveTasks was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+		Opts        storages.ListOptions
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		CreatedDate: createdDate,
+		Opts:        opts,
+	}
+	mock.lockRetrieveTasks.Lock()
+	mock.calls.RetrieveTasks = append(mock.calls.RetrieveTasks, callInfo)
+	mock.lockRetrieveTasks.Unlock()
+	return mock.RetrieveTasksFunc(ctx, userID, createdDate, opts)
+}
+
+// RetrieveTasksCalls gets all the calls that were made to RetrieveTasks.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTasksCalls())
+func (mock *StoreMock) RetrieveTasksCalls() []struct {
+	Ctx         context.Context
+	UserID      sql.NullString
+	CreatedDate sql.NullString
+	Opts        storages.ListOptions
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+		Opts        storages.ListOptions
+	}
+	mock.lockRetrieveTasks.RLock()
+	calls = mock.calls.RetrieveTasks
+	mock.lockRetrieveTasks.RUnlock()
+	return calls
+}
+
+// RetrieveTasksAsOf calls RetrieveTasksAsOfFunc.
+func (mock *StoreMock) RetrieveTasksAsOf(ctx context.Context, userID sql.NullString, createdDate sql.NullString, at string) ([]*storages.Task, error) {
+	if mock.RetrieveTasksAsOfFunc == nil {
+		panic("StoreMock.RetrieveTasksAsOfFunc: method is nil but Store.RetrieveTasksAsOf was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+		At          string
+	}{
+		Ctx:         ctx,
+		UserID:      userID,
+		CreatedDate: createdDate,
+		At:          at,
+	}
+	mock.lockRetrieveTasksAsOf.Lock()
+	mock.calls.RetrieveTasksAsOf = append(mock.calls.RetrieveTasksAsOf, callInfo)
+	mock.lockRetrieveTasksAsOf.Unlock()
+	return mock.RetrieveTasksAsOfFunc(ctx, userID, createdDate, at)
+}
+
+// RetrieveTasksAsOfCalls gets all the calls that were made to RetrieveTasksAsOf.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTasksAsOfCalls())
+func (mock *StoreMock) RetrieveTasksAsOfCalls() []struct {
+	Ctx         context.Context
+	UserID      sql.NullString
+	CreatedDate sql.NullString
+	At          string
+} {
+	var calls []struct {
+		Ctx         context.Context
+		UserID      sql.NullString
+		CreatedDate sql.NullString
+		At          string
+	}
+	mock.lockRetrieveTasksAsOf.RLock()
+	calls = mock.calls.RetrieveTasksAsOf
+	mock.lockRetrieveTasksAsOf.RUnlock()
+	return calls
+}
+
+// RetrieveTimeEntries calls RetrieveTimeEntriesFunc.
+func (mock *StoreMock) RetrieveTimeEntries(ctx context.Context, userID sql.NullString, taskID sql.NullString) ([]*storages.TimeEntry, error) {
+	if mock.RetrieveTimeEntriesFunc == nil {
+		panic("StoreMock.RetrieveTimeEntriesFunc: method is nil but Store.RetrieveTimeEntries was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+	}
+	mock.lockRetrieveTimeEntries.Lock()
+	mock.calls.RetrieveTimeEntries = append(mock.calls.RetrieveTimeEntries, callInfo)
+	mock.lockRetrieveTimeEntries.Unlock()
+	return mock.RetrieveTimeEntriesFunc(ctx, userID, taskID)
+}
+
+// RetrieveTimeEntriesCalls gets all the calls that were made to RetrieveTimeEntries.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveTimeEntriesCalls())
+func (mock *StoreMock) RetrieveTimeEntriesCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+	}
+	mock.lockRetrieveTimeEntries.RLock()
+	calls = mock.calls.RetrieveTimeEntries
+	mock.lockRetrieveTimeEntries.RUnlock()
+	return calls
+}
+
+// RetrieveUser calls RetrieveUserFunc.
+func (mock *StoreMock) RetrieveUser(ctx context.Context, userID sql.NullString) (*storages.User, error) {
+	if mock.RetrieveUserFunc == nil {
+		panic("StoreMock.RetrieveUserFunc: method is nil but Store.RetrieveUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveUser.Lock()
+	mock.calls.RetrieveUser = append(mock.calls.RetrieveUser, callInfo)
+	mock.lockRetrieveUser.Unlock()
+	return mock.RetrieveUserFunc(ctx, userID)
+}
+
+// RetrieveUserCalls gets all the calls that were made to RetrieveUser.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveUserCalls())
+func (mock *StoreMock) RetrieveUserCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveUser.RLock()
+	calls = mock.calls.RetrieveUser
+	mock.lockRetrieveUser.RUnlock()
+	return calls
+}
+
+// RetrieveUserSettings calls RetrieveUserSettingsFunc.
+func (mock *StoreMock) RetrieveUserSettings(ctx context.Context, userID sql.NullString) (*storages.UserSettings, error) {
+	if mock.RetrieveUserSettingsFunc == nil {
+		panic("StoreMock.RetrieveUserSettingsFunc: method is nil but Store.RetrieveUserSettings was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockRetrieveUserSettings.Lock()
+	mock.calls.RetrieveUserSettings = append(mock.calls.RetrieveUserSettings, callInfo)
+	mock.lockRetrieveUserSettings.Unlock()
+	return mock.RetrieveUserSettingsFunc(ctx, userID)
+}
+
+// RetrieveUserSettingsCalls gets all the calls that were made to RetrieveUserSettings.
+// Check the length with:
+//
+//	len(mockedStore.RetrieveUserSettingsCalls())
+func (mock *StoreMock) RetrieveUserSettingsCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+	}
+	mock.lockRetrieveUserSettings.RLock()
+	calls = mock.calls.RetrieveUserSettings
+	mock.lockRetrieveUserSettings.RUnlock()
+	return calls
+}
+
+// RevokeSession calls RevokeSessionFunc.
+func (mock *StoreMock) RevokeSession(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) error {
+	if mock.RevokeSessionFunc == nil {
+		panic("StoreMock.RevokeSessionFunc: method is nil but Store.RevokeSession was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    sql.NullString
+		SessionID sql.NullString
+		Now       string
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		SessionID: sessionID,
+		Now:       now,
+	}
+	mock.lockRevokeSession.Lock()
+	mock.calls.RevokeSession = append(mock.calls.RevokeSession, callInfo)
+	mock.lockRevokeSession.Unlock()
+	return mock.RevokeSessionFunc(ctx, userID, sessionID, now)
+}
+
+// RevokeSessionCalls gets all the calls that were made to RevokeSession.
+// Check the length with:
+//
+//	len(mockedStore.RevokeSessionCalls())
+func (mock *StoreMock) RevokeSessionCalls() []struct {
+	Ctx       context.Context
+	UserID    sql.NullString
+	SessionID sql.NullString
+	Now       string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    sql.NullString
+		SessionID sql.NullString
+		Now       string
+	}
+	mock.lockRevokeSession.RLock()
+	calls = mock.calls.RevokeSession
+	mock.lockRevokeSession.RUnlock()
+	return calls
+}
+
+// RevokeShare calls RevokeShareFunc.
+func (mock *StoreMock) RevokeShare(ctx context.Context, userID sql.NullString, shareID sql.NullString, now string) error {
+	if mock.RevokeShareFunc == nil {
+		panic("StoreMock.RevokeShareFunc: method is nil but Store.RevokeShare was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		ShareID sql.NullString
+		Now     string
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		ShareID: shareID,
+		Now:     now,
+	}
+	mock.lockRevokeShare.Lock()
+	mock.calls.RevokeShare = append(mock.calls.RevokeShare, callInfo)
+	mock.lockRevokeShare.Unlock()
+	return mock.RevokeShareFunc(ctx, userID, shareID, now)
+}
+
+// RevokeShareCalls gets all the calls that were made to RevokeShare.
+// Check the length with:
+//
+//	len(mockedStore.RevokeShareCalls())
+func (mock *StoreMock) RevokeShareCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	ShareID sql.NullString
+	Now     string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		ShareID sql.NullString
+		Now     string
+	}
+	mock.lockRevokeShare.RLock()
+	calls = mock.calls.RevokeShare
+	mock.lockRevokeShare.RUnlock()
+	return calls
+}
+
+// RollUpLimitHits calls RollUpLimitHitsFunc.
+func (mock *StoreMock) RollUpLimitHits(ctx context.Context, date string) (int, error) {
+	if mock.RollUpLimitHitsFunc == nil {
+		panic("StoreMock.RollUpLimitHitsFunc: method is nil but Store.RollUpLimitHits was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Date string
+	}{
+		Ctx:  ctx,
+		Date: date,
+	}
+	mock.lockRollUpLimitHits.Lock()
+	mock.calls.RollUpLimitHits = append(mock.calls.RollUpLimitHits, callInfo)
+	mock.lockRollUpLimitHits.Unlock()
+	return mock.RollUpLimitHitsFunc(ctx, date)
+}
+
+// RollUpLimitHitsCalls gets all the calls that were made to RollUpLimitHits.
+// Check the length with:
+//
+//	len(mockedStore.RollUpLimitHitsCalls())
+func (mock *StoreMock) RollUpLimitHitsCalls() []struct {
+	Ctx  context.Context
+	Date string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Date string
+	}
+	mock.lockRollUpLimitHits.RLock()
+	calls = mock.calls.RollUpLimitHits
+	mock.lockRollUpLimitHits.RUnlock()
+	return calls
+}
+
+// RotateSigningKey calls RotateSigningKeyFunc.
+func (mock *StoreMock) RotateSigningKey(ctx context.Context, k *storages.SigningKey) error {
+	if mock.RotateSigningKeyFunc == nil {
+		panic("StoreMock.RotateSigningKeyFunc: method is nil but Store.RotateSigningKey was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		K   *storages.SigningKey
+	}{
+		Ctx: ctx,
+		K:   k,
+	}
+	mock.lockRotateSigningKey.Lock()
+	mock.calls.RotateSigningKey = append(mock.calls.RotateSigningKey, callInfo)
+	mock.lockRotateSigningKey.Unlock()
+	return mock.RotateSigningKeyFunc(ctx, k)
+}
+
+// RotateSigningKeyCalls gets all the calls that were made to RotateSigningKey.
+// Check the length with:
+//
+//	len(mockedStore.RotateSigningKeyCalls())
+func (mock *StoreMock) RotateSigningKeyCalls() []struct {
+	Ctx context.Context
+	K   *storages.SigningKey
+} {
+	var calls []struct {
+		Ctx context.Context
+		K   *storages.SigningKey
+	}
+	mock.lockRotateSigningKey.RLock()
+	calls = mock.calls.RotateSigningKey
+	mock.lockRotateSigningKey.RUnlock()
+	return calls
+}
+
+// SetTaskEstimate calls SetTaskEstimateFunc.
+func (mock *StoreMock) SetTaskEstimate(ctx context.Context, userID sql.NullString, taskID sql.NullString, minutes int) error {
+	if mock.SetTaskEstimateFunc == nil {
+		panic("StoreMock.SetTaskEstimateFunc: method is nil but Store.SetTaskEstimate was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		Minutes int
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TaskID:  taskID,
+		Minutes: minutes,
+	}
+	mock.lockSetTaskEstimate.Lock()
+	mock.calls.SetTaskEstimate = append(mock.calls.SetTaskEstimate, callInfo)
+	mock.lockSetTaskEstimate.Unlock()
+	return mock.SetTaskEstimateFunc(ctx, userID, taskID, minutes)
+}
+
+// SetTaskEstimateCalls gets all the calls that were made to SetTaskEstimate.
+// Check the length with:
+//
+//	len(mockedStore.SetTaskEstimateCalls())
+func (mock *StoreMock) SetTaskEstimateCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	TaskID  sql.NullString
+	Minutes int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		Minutes int
+	}
+	mock.lockSetTaskEstimate.RLock()
+	calls = mock.calls.SetTaskEstimate
+	mock.lockSetTaskEstimate.RUnlock()
+	return calls
+}
+
+// SetTaskLocation calls SetTaskLocationFunc.
+func (mock *StoreMock) SetTaskLocation(ctx context.Context, userID sql.NullString, taskID sql.NullString, loc storages.Location) error {
+	if mock.SetTaskLocationFunc == nil {
+		panic("StoreMock.SetTaskLocationFunc: method is nil but Store.SetTaskLocation was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		Loc    storages.Location
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+		Loc:    loc,
+	}
+	mock.lockSetTaskLocation.Lock()
+	mock.calls.SetTaskLocation = append(mock.calls.SetTaskLocation, callInfo)
+	mock.lockSetTaskLocation.Unlock()
+	return mock.SetTaskLocationFunc(ctx, userID, taskID, loc)
+}
+
+// SetTaskLocationCalls gets all the calls that were made to SetTaskLocation.
+// Check the length with:
+//
+//	len(mockedStore.SetTaskLocationCalls())
+func (mock *StoreMock) SetTaskLocationCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+	Loc    storages.Location
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		Loc    storages.Location
+	}
+	mock.lockSetTaskLocation.RLock()
+	calls = mock.calls.SetTaskLocation
+	mock.lockSetTaskLocation.RUnlock()
+	return calls
+}
+
+// SignUp calls SignUpFunc.
+func (mock *StoreMock) SignUp(ctx context.Context, code string, u *storages.User, at string) error {
+	if mock.SignUpFunc == nil {
+		panic("StoreMock.SignUpFunc: method is nil but Store.SignUp was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+		U    *storages.User
+		At   string
+	}{
+		Ctx:  ctx,
+		Code: code,
+		U:    u,
+		At:   at,
+	}
+	mock.lockSignUp.Lock()
+	mock.calls.SignUp = append(mock.calls.SignUp, callInfo)
+	mock.lockSignUp.Unlock()
+	return mock.SignUpFunc(ctx, code, u, at)
+}
+
+// SignUpCalls gets all the calls that were made to SignUp.
+// Check the length with:
+//
+//	len(mockedStore.SignUpCalls())
+func (mock *StoreMock) SignUpCalls() []struct {
+	Ctx  context.Context
+	Code string
+	U    *storages.User
+	At   string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+		U    *storages.User
+		At   string
+	}
+	mock.lockSignUp.RLock()
+	calls = mock.calls.SignUp
+	mock.lockSignUp.RUnlock()
+	return calls
+}
+
+// StartPomodoro calls StartPomodoroFunc.
+func (mock *StoreMock) StartPomodoro(ctx context.Context, p *storages.Pomodoro) error {
+	if mock.StartPomodoroFunc == nil {
+		panic("StoreMock.StartPomodoroFunc: method is nil but Store.StartPomodoro was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		P   *storages.Pomodoro
+	}{
+		Ctx: ctx,
+		P:   p,
+	}
+	mock.lockStartPomodoro.Lock()
+	mock.calls.StartPomodoro = append(mock.calls.StartPomodoro, callInfo)
+	mock.lockStartPomodoro.Unlock()
+	return mock.StartPomodoroFunc(ctx, p)
+}
+
+// StartPomodoroCalls gets all the calls that were made to StartPomodoro.
+// Check the length with:
+//
+//	len(mockedStore.StartPomodoroCalls())
+func (mock *StoreMock) StartPomodoroCalls() []struct {
+	Ctx context.Context
+	P   *storages.Pomodoro
+} {
+	var calls []struct {
+		Ctx context.Context
+		P   *storages.Pomodoro
+	}
+	mock.lockStartPomodoro.RLock()
+	calls = mock.calls.StartPomodoro
+	mock.lockStartPomodoro.RUnlock()
+	return calls
+}
+
+// StartTimer calls StartTimerFunc.
+func (mock *StoreMock) StartTimer(ctx context.Context, e *storages.TimeEntry) (*storages.TimeEntry, error) {
+	if mock.StartTimerFunc == nil {
+		panic("StoreMock.StartTimerFunc: method is nil but Store.StartTimer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		E   *storages.TimeEntry
+	}{
+		Ctx: ctx,
+		E:   e,
+	}
+	mock.lockStartTimer.Lock()
+	mock.calls.StartTimer = append(mock.calls.StartTimer, callInfo)
+	mock.lockStartTimer.Unlock()
+	return mock.StartTimerFunc(ctx, e)
+}
+
+// StartTimerCalls gets all the calls that were made to StartTimer.
+// Check the length with:
+//
+//	len(mockedStore.StartTimerCalls())
+func (mock *StoreMock) StartTimerCalls() []struct {
+	Ctx context.Context
+	E   *storages.TimeEntry
+} {
+	var calls []struct {
+		Ctx context.Context
+		E   *storages.TimeEntry
+	}
+	mock.lockStartTimer.RLock()
+	calls = mock.calls.StartTimer
+	mock.lockStartTimer.RUnlock()
+	return calls
+}
+
+// StopTimer calls StopTimerFunc.
+func (mock *StoreMock) StopTimer(ctx context.Context, userID sql.NullString, taskID sql.NullString, at string) (*storages.TimeEntry, error) {
+	if mock.StopTimerFunc == nil {
+		panic("StoreMock.StopTimerFunc: method is nil but Store.StopTimer was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		At     string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		TaskID: taskID,
+		At:     at,
+	}
+	mock.lockStopTimer.Lock()
+	mock.calls.StopTimer = append(mock.calls.StopTimer, callInfo)
+	mock.lockStopTimer.Unlock()
+	return mock.StopTimerFunc(ctx, userID, taskID, at)
+}
+
+// StopTimerCalls gets all the calls that were made to StopTimer.
+// Check the length with:
+//
+//	len(mockedStore.StopTimerCalls())
+func (mock *StoreMock) StopTimerCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	TaskID sql.NullString
+	At     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		TaskID sql.NullString
+		At     string
+	}
+	mock.lockStopTimer.RLock()
+	calls = mock.calls.StopTimer
+	mock.lockStopTimer.RUnlock()
+	return calls
+}
+
+// UnassignLabel calls UnassignLabelFunc.
+func (mock *StoreMock) UnassignLabel(ctx context.Context, userID sql.NullString, taskID sql.NullString, labelID sql.NullString) error {
+	if mock.UnassignLabelFunc == nil {
+		panic("StoreMock.UnassignLabelFunc: method is nil but Store.UnassignLabel was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		LabelID sql.NullString
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TaskID:  taskID,
+		LabelID: labelID,
+	}
+	mock.lockUnassignLabel.Lock()
+	mock.calls.UnassignLabel = append(mock.calls.UnassignLabel, callInfo)
+	mock.lockUnassignLabel.Unlock()
+	return mock.UnassignLabelFunc(ctx, userID, taskID, labelID)
+}
+
+// UnassignLabelCalls gets all the calls that were made to UnassignLabel.
+// Check the length with:
+//
+//	len(mockedStore.UnassignLabelCalls())
+func (mock *StoreMock) UnassignLabelCalls() []struct {
+	Ctx     context.Context
+	UserID  sql.NullString
+	TaskID  sql.NullString
+	LabelID sql.NullString
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  sql.NullString
+		TaskID  sql.NullString
+		LabelID sql.NullString
+	}
+	mock.lockUnassignLabel.RLock()
+	calls = mock.calls.UnassignLabel
+	mock.lockUnassignLabel.RUnlock()
+	return calls
+}
+
+// UndoTasks calls UndoTasksFunc.
+func (mock *StoreMock) UndoTasks(ctx context.Context, userID sql.NullString, token string, now string) (int, error) {
+	if mock.UndoTasksFunc == nil {
+		panic("StoreMock.UndoTasksFunc: method is nil but Store.UndoTasks was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Token  string
+		Now    string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Token:  token,
+		Now:    now,
+	}
+	mock.lockUndoTasks.Lock()
+	mock.calls.UndoTasks = append(mock.calls.UndoTasks, callInfo)
+	mock.lockUndoTasks.Unlock()
+	return mock.UndoTasksFunc(ctx, userID, token, now)
+}
+
+// UndoTasksCalls gets all the calls that were made to UndoTasks.
+// Check the length with:
+//
+//	len(mockedStore.UndoTasksCalls())
+func (mock *StoreMock) UndoTasksCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Token  string
+	Now    string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Token  string
+		Now    string
+	}
+	mock.lockUndoTasks.RLock()
+	calls = mock.calls.UndoTasks
+	mock.lockUndoTasks.RUnlock()
+	return calls
+}
+
+// UpdateLabel calls UpdateLabelFunc.
+func (mock *StoreMock) UpdateLabel(ctx context.Context, label *storages.Label) error {
+	if mock.UpdateLabelFunc == nil {
+		panic("StoreMock.UpdateLabelFunc: method is nil but Store.UpdateLabel was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Label *storages.Label
+	}{
+		Ctx:   ctx,
+		Label: label,
+	}
+	mock.lockUpdateLabel.Lock()
+	mock.calls.UpdateLabel = append(mock.calls.UpdateLabel, callInfo)
+	mock.lockUpdateLabel.Unlock()
+	return mock.UpdateLabelFunc(ctx, label)
+}
+
+// UpdateLabelCalls gets all the calls that were made to UpdateLabel.
+// Check the length with:
+//
+//	len(mockedStore.UpdateLabelCalls())
+func (mock *StoreMock) UpdateLabelCalls() []struct {
+	Ctx   context.Context
+	Label *storages.Label
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Label *storages.Label
+	}
+	mock.lockUpdateLabel.RLock()
+	calls = mock.calls.UpdateLabel
+	mock.lockUpdateLabel.RUnlock()
+	return calls
+}
+
+// UpdateLimitSchedule calls UpdateLimitScheduleFunc.
+func (mock *StoreMock) UpdateLimitSchedule(ctx context.Context, userID sql.NullString, sched *storages.LimitSchedule) error {
+	if mock.UpdateLimitScheduleFunc == nil {
+		panic("StoreMock.UpdateLimitScheduleFunc: method is nil but Store.UpdateLimitSchedule was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Sched  *storages.LimitSchedule
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Sched:  sched,
+	}
+	mock.lockUpdateLimitSchedule.Lock()
+	mock.calls.UpdateLimitSchedule = append(mock.calls.UpdateLimitSchedule, callInfo)
+	mock.lockUpdateLimitSchedule.Unlock()
+	return mock.UpdateLimitScheduleFunc(ctx, userID, sched)
+}
+
+// UpdateLimitScheduleCalls gets all the calls that were made to UpdateLimitSchedule.
+// Check the length with:
+//
+//	len(mockedStore.UpdateLimitScheduleCalls())
+func (mock *StoreMock) UpdateLimitScheduleCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Sched  *storages.LimitSchedule
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Sched  *storages.LimitSchedule
+	}
+	mock.lockUpdateLimitSchedule.RLock()
+	calls = mock.calls.UpdateLimitSchedule
+	mock.lockUpdateLimitSchedule.RUnlock()
+	return calls
+}
+
+// UpdatePasswordHash calls UpdatePasswordHashFunc.
+func (mock *StoreMock) UpdatePasswordHash(ctx context.Context, userID sql.NullString, hash string) error {
+	if mock.UpdatePasswordHashFunc == nil {
+		panic("StoreMock.UpdatePasswordHashFunc: method is nil but Store.UpdatePasswordHash was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Hash   string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Hash:   hash,
+	}
+	mock.lockUpdatePasswordHash.Lock()
+	mock.calls.UpdatePasswordHash = append(mock.calls.UpdatePasswordHash, callInfo)
+	mock.lockUpdatePasswordHash.Unlock()
+	return mock.UpdatePasswordHashFunc(ctx, userID, hash)
+}
+
+// UpdatePasswordHashCalls gets all the calls that were made to UpdatePasswordHash.
+// Check the length with:
+//
+//	len(mockedStore.UpdatePasswordHashCalls())
+func (mock *StoreMock) UpdatePasswordHashCalls() []struct {
+	Ctx    context.Context
+	UserID sql.NullString
+	Hash   string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID sql.NullString
+		Hash   string
+	}
+	mock.lockUpdatePasswordHash.RLock()
+	calls = mock.calls.UpdatePasswordHash
+	mock.lockUpdatePasswordHash.RUnlock()
+	return calls
+}
+
+// UpdateStreaks calls UpdateStreaksFunc.
+func (mock *StoreMock) UpdateStreaks(ctx context.Context, day string) (int, error) {
+	if mock.UpdateStreaksFunc == nil {
+		panic("StoreMock.UpdateStreaksFunc: method is nil but Store.UpdateStreaks was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Day string
+	}{
+		Ctx: ctx,
+		Day: day,
+	}
+	mock.lockUpdateStreaks.Lock()
+	mock.calls.UpdateStreaks = append(mock.calls.UpdateStreaks, callInfo)
+	mock.lockUpdateStreaks.Unlock()
+	return mock.UpdateStreaksFunc(ctx, day)
+}
+
+// UpdateStreaksCalls gets all the calls that were made to UpdateStreaks.
+// Check the length with:
+//
+//	len(mockedStore.UpdateStreaksCalls())
+func (mock *StoreMock) UpdateStreaksCalls() []struct {
+	Ctx context.Context
+	Day string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Day string
+	}
+	mock.lockUpdateStreaks.RLock()
+	calls = mock.calls.UpdateStreaks
+	mock.lockUpdateStreaks.RUnlock()
+	return calls
+}
+
+// UpdateUser calls UpdateUserFunc.
+func (mock *StoreMock) UpdateUser(ctx context.Context, u *storages.User) error {
+	if mock.UpdateUserFunc == nil {
+		panic("StoreMock.UpdateUserFunc: method is nil but Store.UpdateUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		U   *storages.User
+	}{
+		Ctx: ctx,
+		U:   u,
+	}
+	mock.lockUpdateUser.Lock()
+	mock.calls.UpdateUser = append(mock.calls.UpdateUser, callInfo)
+	mock.lockUpdateUser.Unlock()
+	return mock.UpdateUserFunc(ctx, u)
+}
+
+// UpdateUserCalls gets all the calls that were made to UpdateUser.
+// Check the length with:
+//
+//	len(mockedStore.UpdateUserCalls())
+func (mock *StoreMock) UpdateUserCalls() []struct {
+	Ctx context.Context
+	U   *storages.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		U   *storages.User
+	}
+	mock.lockUpdateUser.RLock()
+	calls = mock.calls.UpdateUser
+	mock.lockUpdateUser.RUnlock()
+	return calls
+}
+
+// UpdateUserSettings calls UpdateUserSettingsFunc.
+func (mock *StoreMock) UpdateUserSettings(ctx context.Context, userID sql.NullString, settings *storages.UserSettings) error {
+	if mock.UpdateUserSettingsFunc == nil {
+		panic("StoreMock.UpdateUserSettingsFunc: method is nil but Store.UpdateUserSettings was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   sql.NullString
+		Settings *storages.UserSettings
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		Settings: settings,
+	}
+	mock.lockUpdateUserSettings.Lock()
+	mock.calls.UpdateUserSettings = append(mock.calls.UpdateUserSettings, callInfo)
+	mock.lockUpdateUserSettings.Unlock()
+	return mock.UpdateUserSettingsFunc(ctx, userID, settings)
+}
+
+// UpdateUserSettingsCalls gets all the calls that were made to UpdateUserSettings.
+// Check the length with:
+//
+//	len(mockedStore.UpdateUserSettingsCalls())
+func (mock *StoreMock) UpdateUserSettingsCalls() []struct {
+	Ctx      context.Context
+	UserID   sql.NullString
+	Settings *storages.UserSettings
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   sql.NullString
+		Settings *storages.UserSettings
+	}
+	mock.lockUpdateUserSettings.RLock()
+	calls = mock.calls.UpdateUserSettings
+	mock.lockUpdateUserSettings.RUnlock()
+	return calls
+}
+
+// ValidateSession calls ValidateSessionFunc.
+func (mock *StoreMock) ValidateSession(ctx context.Context, userID sql.NullString, sessionID sql.NullString, now string) bool {
+	if mock.ValidateSessionFunc == nil {
+		panic("StoreMock.ValidateSessionFunc: method is nil but Store.ValidateSession was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    sql.NullString
+		SessionID sql.NullString
+		Now       string
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		SessionID: sessionID,
+		Now:       now,
+	}
+	mock.lockValidateSession.Lock()
+	mock.calls.ValidateSession = append(mock.calls.ValidateSession, callInfo)
+	mock.lockValidateSession.Unlock()
+	return mock.ValidateSessionFunc(ctx, userID, sessionID, now)
+}
+
+// ValidateSessionCalls gets all the calls that were made to ValidateSession.
+// Check the length with:
+//
+//	len(mockedStore.ValidateSessionCalls())
+func (mock *StoreMock) ValidateSessionCalls() []struct {
+	Ctx       context.Context
+	UserID    sql.NullString
+	SessionID sql.NullString
+	Now       string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    sql.NullString
+		SessionID sql.NullString
+		Now       string
+	}
+	mock.lockValidateSession.RLock()
+	calls = mock.calls.ValidateSession
+	mock.lockValidateSession.RUnlock()
+	return calls
+}
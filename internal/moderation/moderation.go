@@ -0,0 +1,71 @@
+// Package moderation refuses task contents containing terms a deployment may not store, like
+// those a regulator forbids
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// List is a set of blocked terms. Words and phrases match whole words of a content, ignoring
+// case, so blocking "ass" doesn't refuse "class". Patterns match anywhere.
+type List struct {
+	// words matches any of the words and phrases between non-word characters
+	words    *regexp.Regexp
+	patterns []*regexp.Regexp
+	n        int
+}
+
+// Parse reads one term per line, skipping blank lines and those starting with #. A line
+// between slashes, like /b[a4]d/, is an RE2 regular expression, others are words or phrases.
+func Parse(text string) (*List, error) {
+	l := &List{}
+	var words []string
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case len(line) > 2 && strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/"):
+			re, err := regexp.Compile("(?i)" + line[1:len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("moderation: line %d: %w", n+1, err)
+			}
+			l.patterns = append(l.patterns, re)
+		default:
+			// the words of a phrase may be split by any spaces
+			fields := strings.Fields(line)
+			for i, f := range fields {
+				fields[i] = regexp.QuoteMeta(f)
+			}
+			words = append(words, strings.Join(fields, `\s+`))
+		}
+	}
+	l.n = len(words) + len(l.patterns)
+	if len(words) > 0 {
+		// RE2's \b only knows ASCII, these boundaries hold for Vietnamese too
+		l.words = regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])(` + strings.Join(words, "|") + `)(?:[^\pL\pN_]|$)`)
+	}
+	return l, nil
+}
+
+// Len is how many terms l has
+func (l *List) Len() int {
+	return l.n
+}
+
+// Match returns the text of content matching the first blocked term found, "" when there is
+// none
+func (l *List) Match(content string) string {
+	if l.words != nil {
+		if m := l.words.FindStringSubmatch(content); m != nil {
+			return m[1]
+		}
+	}
+	for _, re := range l.patterns {
+		if m := re.FindString(content); m != "" {
+			return m
+		}
+	}
+	return ""
+}
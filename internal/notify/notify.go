@@ -0,0 +1,151 @@
+// Package notify tells external systems about things happening to users
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// LimitReached is emitted the first time a user hits their daily task limit on a date
+type LimitReached struct {
+	UserID     string    `json:"user_id"`
+	Date       string    `json:"date"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// CarryOverSkipped is emitted when a user's incomplete tasks weren't carried over to Date
+// because Tasks more would exceed the daily limit
+type CarryOverSkipped struct {
+	UserID     string    `json:"user_id"`
+	Date       string    `json:"date"`
+	Tasks      int       `json:"tasks"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Notifier delivers events
+type Notifier interface {
+	LimitReached(ctx context.Context, e LimitReached) error
+	CarryOverSkipped(ctx context.Context, e CarryOverSkipped) error
+}
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body keyed with the webhook secret
+const SignatureHeader = "X-Togo-Signature"
+
+// Webhook POSTs events as JSON to a URL
+type Webhook struct {
+	URL    string
+	Secret string
+	Client httpclient.Doer
+}
+
+// NewWebhook returns a Webhook sending through the shared retrying client
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{URL: url, Secret: secret, Client: httpclient.New()}
+}
+
+// envelope wraps the data of an event with its type and, in Schema, the full name of the
+// api/eventsv1 message the data follows
+type envelope struct {
+	Type    string      `json:"type"`
+	Schema  string      `json:"schema"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+// LimitReached implements Notifier
+func (w *Webhook) LimitReached(ctx context.Context, e LimitReached) error {
+	return w.post(ctx, envelope{
+		Type:    "task_limit_reached",
+		Schema:  "togo.events.v1.LimitReached",
+		Message: fmt.Sprintf("quota exhausted for user %s on %s", e.UserID, e.Date),
+		Data:    e,
+	})
+}
+
+// CarryOverSkipped implements Notifier
+func (w *Webhook) CarryOverSkipped(ctx context.Context, e CarryOverSkipped) error {
+	return w.post(ctx, envelope{
+		Type:    "carry_over_skipped",
+		Schema:  "togo.events.v1.CarryOverSkipped",
+		Message: fmt.Sprintf("%d unfinished tasks of user %s not carried over to %s, daily limit would be exceeded", e.Tasks, e.UserID, e.Date),
+		Data:    e,
+	})
+}
+
+func (w *Webhook) post(ctx context.Context, env envelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+// Slack posts messages to a Slack incoming webhook
+type Slack struct {
+	URL    string
+	Client httpclient.Doer
+}
+
+// NewSlack returns a Slack posting through the shared retrying client
+func NewSlack(url string) *Slack {
+	return &Slack{URL: url, Client: httpclient.New()}
+}
+
+// Post sends text as a message, Slack formats it as mrkdwn
+func (s *Slack) Post(ctx context.Context, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// the URL holds the webhook's secret, it stays out of logs
+		return fmt.Errorf("slack webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id hashes in the PHC string format, e.g. v=19$m=65536,t=3,p=2$<salt>$<key>
+type Argon2id struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+	SaltLen int
+	KeyLen  uint32
+}
+
+// NewArgon2id returns an Argon2id hasher with the RFC 9106 low memory parameters
+func NewArgon2id() *Argon2id {
+	return &Argon2id{Time: 3, Memory: 64 * 1024, Threads: 2, SaltLen: 16, KeyLen: 32}
+}
+
+// ID implements Hasher
+func (a *Argon2id) ID() string { return "argon2id" }
+
+// Hash implements Hasher
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, a.KeyLen)
+	return fmt.Sprintf("v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, a.Memory, a.Time, a.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify implements Hasher
+func (a *Argon2id) Verify(payload, password string) bool {
+	p, salt, key, err := decodeArgon2id(payload)
+	if err != nil {
+		return false
+	}
+	got := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(got, key) == 1
+}
+
+// NeedsRehash implements Hasher
+func (a *Argon2id) NeedsRehash(payload string) bool {
+	p, salt, key, err := decodeArgon2id(payload)
+	if err != nil {
+		return true
+	}
+	return p.Time != a.Time || p.Memory != a.Memory || p.Threads != a.Threads ||
+		len(salt) != a.SaltLen || uint32(len(key)) != a.KeyLen
+}
+
+func decodeArgon2id(payload string) (p Argon2id, salt, key []byte, err error) {
+	parts := strings.Split(payload, "$")
+	if len(parts) != 4 {
+		return p, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[0], "v=%d", &version); err != nil {
+		return p, nil, nil, err
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	if _, err = fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, err
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return p, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil {
+		return p, nil, nil, err
+	}
+	return p, salt, key, nil
+}
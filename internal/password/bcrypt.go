@@ -0,0 +1,38 @@
+package password
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt stores the bcrypt modular crypt string without its leading "$"
+type Bcrypt struct {
+	Cost int
+}
+
+// NewBcrypt returns a Bcrypt hasher with bcrypt.DefaultCost
+func NewBcrypt() *Bcrypt {
+	return &Bcrypt{Cost: bcrypt.DefaultCost}
+}
+
+// ID implements Hasher
+func (b *Bcrypt) ID() string { return "bcrypt" }
+
+// Hash implements Hasher
+func (b *Bcrypt) Hash(password string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(h[1:]), nil
+}
+
+// Verify implements Hasher
+func (b *Bcrypt) Verify(payload, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte("$"+payload), []byte(password)) == nil
+}
+
+// NeedsRehash implements Hasher
+func (b *Bcrypt) NeedsRehash(payload string) bool {
+	cost, err := bcrypt.Cost([]byte("$" + payload))
+	return err != nil || cost != b.Cost
+}
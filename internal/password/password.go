@@ -0,0 +1,89 @@
+// Package password hashes and verifies user passwords.
+//
+// Stored hashes carry their algorithm in a "$<id>$" prefix, so the configured
+// algorithm can change without invalidating hashes made by an older one.
+// Values without a prefix are legacy plain text passwords.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Hasher implements one hashing algorithm. Payloads exclude the "$<id>$" prefix.
+type Hasher interface {
+	ID() string
+	Hash(password string) (string, error)
+	Verify(payload, password string) bool
+	// NeedsRehash reports whether payload was made with other parameters than the current ones
+	NeedsRehash(payload string) bool
+}
+
+// Manager hashes with one Hasher and verifies with any known one
+type Manager struct {
+	current Hasher
+	hashers map[string]Hasher
+
+	// dummy is a hash by current of a random password, made on first use
+	dummyOnce sync.Once
+	dummy     string
+}
+
+// New returns a Manager hashing new passwords with algorithm, argon2id or bcrypt
+func New(algorithm string) (*Manager, error) {
+	m := &Manager{hashers: map[string]Hasher{}}
+	for _, h := range []Hasher{NewArgon2id(), NewBcrypt()} {
+		m.hashers[h.ID()] = h
+	}
+
+	current, ok := m.hashers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unknown password hash algorithm %q", algorithm)
+	}
+	m.current = current
+	return m, nil
+}
+
+// Hash returns the prefixed hash of password
+func (m *Manager) Hash(password string) (string, error) {
+	payload, err := m.current.Hash(password)
+	if err != nil {
+		return "", err
+	}
+	return "$" + m.current.ID() + "$" + payload, nil
+}
+
+// Verify checks password against stored. rehash is set for correct passwords whose
+// hash should be replaced by a fresh call to Hash.
+func (m *Manager) Verify(stored, password string) (ok, rehash bool) {
+	if !strings.HasPrefix(stored, "$") {
+		ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return ok, ok
+	}
+
+	parts := strings.SplitN(stored[1:], "$", 2)
+	if len(parts) != 2 {
+		return false, false
+	}
+	h, known := m.hashers[parts[0]]
+	if !known || !h.Verify(parts[1], password) {
+		return false, false
+	}
+	return true, h != m.current || h.NeedsRehash(parts[1])
+}
+
+// VerifyUnknown checks password against a hash no password matches, for logins of unknown
+// users, so they take as long as a wrong password of a known one. It's always false.
+func (m *Manager) VerifyUnknown(password string) bool {
+	m.dummyOnce.Do(func() {
+		b := make([]byte, 16)
+		rand.Read(b)
+		m.dummy, _ = m.Hash(hex.EncodeToString(b))
+	})
+	m.Verify(m.dummy, password)
+	return false
+}
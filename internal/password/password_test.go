@@ -0,0 +1,33 @@
+package password
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyUnknown(t *testing.T) {
+	m, err := New("argon2id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := m.Hash("example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.VerifyUnknown("warm up")
+
+	known := time.Now()
+	m.Verify(stored, "wrong")
+	knownTook := time.Since(known)
+
+	unknown := time.Now()
+	if m.VerifyUnknown("example") {
+		t.Error("VerifyUnknown accepted a password")
+	}
+	unknownTook := time.Since(unknown)
+
+	// both run one argon2id derivation, far from a map miss
+	if unknownTook < knownTook/4 {
+		t.Errorf("VerifyUnknown took %v, a wrong password %v", unknownTook, knownTook)
+	}
+}
@@ -0,0 +1,100 @@
+// Package push delivers events to the clients a user has connected to this instance
+package push
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is sent to clients as a server-sent event named Type with Data as JSON
+type Event struct {
+	Type string
+	Data interface{}
+}
+
+// QuotaWarning is sent when a user's tasks on Date reach WarnRatio of their daily limit,
+// or go beyond it in soft quota mode
+type QuotaWarning struct {
+	Date       string    `json:"date"`
+	Count      int       `json:"count"`
+	MaxTodo    int       `json:"max_todo"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// WarnRatio is the share of max_todo from which users get a QuotaWarning
+const WarnRatio = 0.8
+
+// Crossed reports whether a user going from count-1 to count tasks out of maxTodo just
+// reached WarnRatio, so the warning is sent once per date
+func Crossed(count, maxTodo int) bool {
+	threshold := WarnRatio * float64(maxTodo)
+	return float64(count) >= threshold && float64(count-1) < threshold
+}
+
+// Hub fans events out to subscribers by user ID. The zero Hub is ready to use.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[chan Event]struct{}
+	closed bool
+}
+
+// queued is how many events a slow subscriber can fall behind before missing some
+const queued = 16
+
+// Subscribe returns a channel receiving the events published to userID until cancel is called
+func (h *Hub) Subscribe(userID string) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, queued)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subs == nil {
+		h.subs = make(map[string]map[chan Event]struct{})
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan Event]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[userID][ch]; !ok {
+			return
+		}
+		delete(h.subs[userID], ch)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+	}
+}
+
+// Close closes the channels of every subscriber and those subscribing later, so streams
+// end when the server shuts down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, chans := range h.subs {
+		for ch := range chans {
+			close(ch)
+		}
+	}
+	h.subs = nil
+}
+
+// Publish sends e to every subscriber of userID without blocking, subscribers whose
+// queue is full miss it
+func (h *Hub) Publish(userID string, e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[userID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package requestid carries the ID of the API request a context serves, for the logs and error
+// bodies users quote in support tickets, and reads and writes W3C traceparent headers
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+)
+
+type idKey struct{}
+
+// With returns a copy of ctx serving the request named id
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns the request ID With stored in ctx, empty outside requests
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// Println logs v like log.Println, followed by request_id=<id> when ctx serves a request
+func Println(ctx context.Context, v ...interface{}) {
+	if id := FromContext(ctx); id != "" {
+		v = append(v, "request_id="+id)
+	}
+	log.Println(v...)
+}
+
+// ParseTraceParent returns the trace ID and flags of a traceparent header, ok is false when
+// h isn't one of a version this package understands
+func ParseTraceParent(h string) (traceID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) ||
+		strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// TraceParent returns a version 00 traceparent header for this server's span of the trace
+// traceID, a new trace when it's empty
+func TraceParent(traceID, flags string) string {
+	if traceID == "" {
+		traceID, flags = randomHex(16), "00"
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-" + flags
+}
+
+// isHex reports whether s is n lowercase hex digits
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("requestid: reading random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,87 @@
+// Package retry runs operations again after transient failures, waiting between
+// attempts according to a backoff strategy
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff is how long to wait before attempt, counted from 1 for the first retry,
+// after err failed the previous one
+type Backoff interface {
+	Delay(attempt int, err error) time.Duration
+}
+
+// Exponential doubles the wait from Base up to Max with full jitter, or waits what
+// an error implementing RetryAfter() asks for when that is longer, still capped at Max
+type Exponential struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay implements Backoff
+func (b Exponential) Delay(attempt int, err error) time.Duration {
+	d := b.Base << uint(attempt-1)
+	if d <= 0 || d > b.Max {
+		d = b.Max
+	}
+	d = time.Duration(rand.Int63n(int64(d) + 1))
+
+	if ra, ok := err.(interface{ RetryAfter() time.Duration }); ok && ra.RetryAfter() > d {
+		d = ra.RetryAfter()
+		if d > b.Max {
+			d = b.Max
+		}
+	}
+	return d
+}
+
+// Constant always waits the same time
+type Constant time.Duration
+
+// Delay implements Backoff
+func (b Constant) Delay(int, error) time.Duration {
+	return time.Duration(b)
+}
+
+// Policy bounds how often and for which errors an operation is retried
+type Policy struct {
+	// MaxAttempts counts the first attempt, values below 1 mean a single one
+	MaxAttempts int
+	// Backoff spaces attempts, nil retries right away
+	Backoff Backoff
+	// Retryable reports whether an error is worth another attempt, every error is when nil
+	Retryable func(err error) bool
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retryable, runs out of
+// attempts or ctx is done, and returns fn's last error or ctx's
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < p.MaxAttempts || attempt == 0; attempt++ {
+		if attempt > 0 && p.Backoff != nil {
+			if serr := Sleep(ctx, p.Backoff.Delay(attempt, err)); serr != nil {
+				return serr
+			}
+		}
+		err = fn(ctx)
+		if err == nil || ctx.Err() != nil || (p.Retryable != nil && !p.Retryable(err)) {
+			return err
+		}
+	}
+	return err
+}
+
+// Sleep waits for d, returning ctx's error if it is done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,353 @@
+package rules
+
+import (
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/pm"
+)
+
+// maxAlloc bounds the bytes of the strings one check builds, so a script doubling a string
+// or joining a large table fails long before it exhausts memory. Every way scripts have of
+// building a string draws on it: .., string.rep, string.format, string.gsub, string.upper,
+// string.lower, string.reverse and table.concat. Tables themselves are only bounded by the
+// timeout.
+const maxAlloc = 16 << 20
+
+// concatGlobal is the function the .. of scripts is compiled to call. Its name isn't an
+// identifier so scripts can't refer to it, and replacing it through _G gains them nothing.
+const concatGlobal = "(concat)"
+
+// budget is what is left of maxAlloc to a check
+type budget struct {
+	left int
+}
+
+// reserve raises an error in L unless n bytes are left, without spending them
+func (b *budget) reserve(L *lua.LState, n int) {
+	if n < 0 || n > b.left {
+		L.RaiseError("script builds strings over %d bytes", maxAlloc)
+	}
+}
+
+// spend takes n bytes off b, raising an error in L once it's exhausted
+func (b *budget) spend(L *lua.LState, n int) {
+	b.reserve(L, n)
+	b.left -= n
+}
+
+// guard replaces the library functions building strings in L with ones spending b
+func (b *budget) guard(L *lua.LState) {
+	L.SetGlobal(concatGlobal, L.NewFunction(b.concat))
+
+	str := L.GetGlobal(lua.StringLibName).(*lua.LTable)
+	str.RawSetString("rep", L.NewFunction(b.rep))
+	for _, name := range []string{"upper", "lower", "reverse"} {
+		str.RawSetString(name, b.wrap(L, str.RawGetString(name), func(L *lua.LState) int {
+			return len(L.CheckString(1))
+		}))
+	}
+	str.RawSetString("format", b.wrap(L, str.RawGetString("format"), formatSize))
+	str.RawSetString("gsub", b.gsub(L, str.RawGetString("gsub")))
+
+	tab := L.GetGlobal(lua.TabLibName).(*lua.LTable)
+	tab.RawSetString("concat", b.wrap(L, tab.RawGetString("concat"), concatSize))
+}
+
+// wrap returns fn checking first that the most size says it may build is left, then spending
+// what its first result takes
+func (b *budget) wrap(L *lua.LState, fn lua.LValue, size func(L *lua.LState) int) *lua.LFunction {
+	return L.NewFunction(func(L *lua.LState) int {
+		b.reserve(L, size(L))
+		n := call(L, fn)
+		if s, ok := L.Get(1).(lua.LString); ok {
+			b.spend(L, len(s))
+		}
+		return n
+	})
+}
+
+// call calls fn with the arguments of the running Go function, leaving its results on the
+// stack, and returns how many there are
+func call(L *lua.LState, fn lua.LValue) int {
+	L.Insert(fn, 1)
+	L.Call(L.GetTop()-1, lua.MultRet)
+	return L.GetTop()
+}
+
+// concat is what a .. b compiles to, with the semantics of the operator
+func (b *budget) concat(L *lua.LState) int {
+	lhs, rhs := L.Get(1), L.Get(2)
+	if lua.LVCanConvToString(lhs) && lua.LVCanConvToString(rhs) {
+		l, r := lua.LVAsString(lhs), lua.LVAsString(rhs)
+		b.spend(L, len(l)+len(r))
+		L.Push(lua.LString(l + r))
+		return 1
+	}
+	op := L.GetMetaField(lhs, "__concat")
+	if op == lua.LNil {
+		op = L.GetMetaField(rhs, "__concat")
+	}
+	if op.Type() != lua.LTFunction {
+		L.RaiseError("cannot perform concat operation between %v and %v", lhs.Type().String(), rhs.Type().String())
+	}
+	L.Push(op)
+	L.Push(lhs)
+	L.Push(rhs)
+	L.Call(2, 1)
+	return 1
+}
+
+// rep is string.rep
+func (b *budget) rep(L *lua.LState) int {
+	s, n := L.CheckString(1), L.CheckInt(2)
+	if n <= 0 || s == "" {
+		L.Push(lua.LString(""))
+		return 1
+	}
+	if n > b.left/len(s) {
+		L.RaiseError("script builds strings over %d bytes", maxAlloc)
+	}
+	b.spend(L, len(s)*n)
+	L.Push(lua.LString(strings.Repeat(s, n)))
+	return 1
+}
+
+// gsub returns string.gsub checking what the replacements add before building the result
+func (b *budget) gsub(L *lua.LState, gsub lua.LValue) *lua.LFunction {
+	return L.NewFunction(func(L *lua.LState) int {
+		s, pat := L.CheckString(1), L.CheckString(2)
+		switch repl := L.Get(3).(type) {
+		case lua.LString:
+			mds, err := pm.Find(pat, []byte(s), 0, L.OptInt(4, -1))
+			if err == nil {
+				b.reserve(L, gsubSize(s, string(repl), mds))
+			}
+		case *lua.LTable, *lua.LFunction:
+			// each replacement is counted as it's made
+			added := len(s)
+			L.Replace(3, L.NewFunction(func(L *lua.LState) int {
+				if t, ok := repl.(*lua.LTable); ok {
+					L.Push(L.GetTable(t, L.Get(1)))
+				} else {
+					L.Insert(repl, 1)
+					L.Call(L.GetTop()-1, 1)
+				}
+				if v := L.Get(-1); !lua.LVIsFalse(v) && lua.LVCanConvToString(v) {
+					added += len(lua.LVAsString(v))
+					b.reserve(L, added)
+				}
+				return 1
+			}))
+		}
+		n := call(L, gsub)
+		b.spend(L, len(L.CheckString(1)))
+		return n
+	})
+}
+
+// gsubSize is the length of s with the matches mds replaced by repl
+func gsubSize(s, repl string, mds []*pm.MatchData) int {
+	// repl is literal bytes and %0 to %9, the captures they stand for vary by match
+	lit, refs := 0, []int(nil)
+	for i := 0; i < len(repl); i++ {
+		switch {
+		case repl[i] != '%' || i+1 == len(repl):
+			lit++
+		case repl[i+1] < '0' || repl[i+1] > '9':
+			lit += 2
+			i++
+		default:
+			refs = append(refs, 2*int(repl[i+1]-'0'))
+			i++
+		}
+	}
+
+	n := len(s)
+	for _, md := range mds {
+		n += lit - (md.Capture(1) - md.Capture(0))
+		for _, idx := range refs {
+			if idx+1 >= md.CaptureLength() {
+				// %1 without captures is the whole match
+				idx = 0
+			}
+			if md.IsPosCapture(idx) {
+				n += 20
+			} else {
+				n += md.Capture(idx+1) - md.Capture(idx)
+			}
+		}
+		if n > maxAlloc {
+			break
+		}
+	}
+	return n
+}
+
+// formatSize is the most string.format may build from its arguments: the format, the width and
+// precision of each directive and its argument, quoted arguments being escaped up to 10 bytes a byte
+func formatSize(L *lua.LState) int {
+	f := L.CheckString(1)
+	n, arg := len(f), 2
+	for i := 0; i < len(f); i++ {
+		if f[i] != '%' {
+			continue
+		}
+		if i++; i < len(f) && f[i] == '%' {
+			continue
+		}
+		for i < len(f) && strings.IndexByte("-+ #0", f[i]) >= 0 {
+			i++
+		}
+		for i < len(f) && (f[i] == '.' || f[i] >= '0' && f[i] <= '9') {
+			width := 0
+			for i < len(f) && f[i] >= '0' && f[i] <= '9' {
+				if width = width*10 + int(f[i]-'0'); width > maxAlloc {
+					width = maxAlloc + 1
+				}
+				i++
+			}
+			n += width
+			if i < len(f) && f[i] == '.' {
+				i++
+			}
+		}
+		v := L.Get(arg)
+		arg++
+		size := 32
+		if s, ok := v.(lua.LString); ok {
+			size = len(s)
+		}
+		if i < len(f) && f[i] == 'q' {
+			size = 10*size + 2
+		}
+		n += size
+	}
+	return n
+}
+
+// concatSize is the length table.concat builds from its arguments
+func concatSize(L *lua.LState) int {
+	t := L.CheckTable(1)
+	sep := len(L.OptString(2, ""))
+	i, j := L.OptInt(3, 1), L.OptInt(4, t.Len())
+	if i < 1 {
+		i = 1
+	}
+	if j > t.Len() {
+		j = t.Len()
+	}
+	n := 0
+	for ; i <= j; i++ {
+		if v := t.RawGetInt(i); lua.LVCanConvToString(v) {
+			n += len(lua.LVAsString(v))
+		}
+		if i != j {
+			n += sep
+		}
+		if n > maxAlloc {
+			break
+		}
+	}
+	return n
+}
+
+// guardConcat has every .. of the chunk call concatGlobal instead, so what it builds is counted
+func guardConcat(chunk []ast.Stmt) {
+	for _, st := range chunk {
+		guardStmt(st)
+	}
+}
+
+func guardStmt(st ast.Stmt) {
+	switch st := st.(type) {
+	case *ast.AssignStmt:
+		guardExprs(st.Lhs)
+		guardExprs(st.Rhs)
+	case *ast.LocalAssignStmt:
+		guardExprs(st.Exprs)
+	case *ast.FuncCallStmt:
+		st.Expr = guardExpr(st.Expr)
+	case *ast.DoBlockStmt:
+		guardConcat(st.Stmts)
+	case *ast.WhileStmt:
+		st.Condition = guardExpr(st.Condition)
+		guardConcat(st.Stmts)
+	case *ast.RepeatStmt:
+		st.Condition = guardExpr(st.Condition)
+		guardConcat(st.Stmts)
+	case *ast.IfStmt:
+		st.Condition = guardExpr(st.Condition)
+		guardConcat(st.Then)
+		guardConcat(st.Else)
+	case *ast.NumberForStmt:
+		st.Init, st.Limit = guardExpr(st.Init), guardExpr(st.Limit)
+		if st.Step != nil {
+			st.Step = guardExpr(st.Step)
+		}
+		guardConcat(st.Stmts)
+	case *ast.GenericForStmt:
+		guardExprs(st.Exprs)
+		guardConcat(st.Stmts)
+	case *ast.FuncDefStmt:
+		st.Name.Func = guardExpr(st.Name.Func)
+		if st.Name.Receiver != nil {
+			st.Name.Receiver = guardExpr(st.Name.Receiver)
+		}
+		guardExpr(st.Func)
+	case *ast.ReturnStmt:
+		guardExprs(st.Exprs)
+	}
+}
+
+func guardExprs(exprs []ast.Expr) {
+	for i, e := range exprs {
+		exprs[i] = guardExpr(e)
+	}
+}
+
+func guardExpr(e ast.Expr) ast.Expr {
+	switch e := e.(type) {
+	case *ast.StringConcatOpExpr:
+		fn := &ast.IdentExpr{Value: concatGlobal}
+		fn.SetLine(e.Line())
+		fn.SetLastLine(e.LastLine())
+		call := &ast.FuncCallExpr{Func: fn, Args: []ast.Expr{guardExpr(e.Lhs), guardExpr(e.Rhs)}, AdjustRet: true}
+		call.SetLine(e.Line())
+		call.SetLastLine(e.LastLine())
+		return call
+	case *ast.AttrGetExpr:
+		e.Object, e.Key = guardExpr(e.Object), guardExpr(e.Key)
+	case *ast.TableExpr:
+		for _, f := range e.Fields {
+			if f.Key != nil {
+				f.Key = guardExpr(f.Key)
+			}
+			f.Value = guardExpr(f.Value)
+		}
+	case *ast.FuncCallExpr:
+		if e.Func != nil {
+			e.Func = guardExpr(e.Func)
+		}
+		if e.Receiver != nil {
+			e.Receiver = guardExpr(e.Receiver)
+		}
+		guardExprs(e.Args)
+	case *ast.LogicalOpExpr:
+		e.Lhs, e.Rhs = guardExpr(e.Lhs), guardExpr(e.Rhs)
+	case *ast.RelationalOpExpr:
+		e.Lhs, e.Rhs = guardExpr(e.Lhs), guardExpr(e.Rhs)
+	case *ast.ArithmeticOpExpr:
+		e.Lhs, e.Rhs = guardExpr(e.Lhs), guardExpr(e.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		e.Expr = guardExpr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		e.Expr = guardExpr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		e.Expr = guardExpr(e.Expr)
+	case *ast.FunctionExpr:
+		guardConcat(e.Stmts)
+	}
+	return e
+}
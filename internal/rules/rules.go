@@ -0,0 +1,157 @@
+// Package rules runs validation scripts operators write in Lua on new tasks, so policies
+// like forbidden words or naming conventions need no rebuild.
+//
+// A script defines a global check(task) function. task is a table with content, user_id,
+// created_date, priority and due_date, and check returns nil to accept the task or a string
+// saying why it's refused. Scripts only get the base, string, table and math libraries,
+// without anything reaching files, each check is stopped after the set's timeout and the
+// strings it builds may take up to maxAlloc bytes.
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// unsafeGlobals reach outside the script or its allocation limits
+var unsafeGlobals = []string{"collectgarbage", "dofile", "load", "loadfile", "loadstring", "module", "print", "require"}
+
+// Set is the scripts of a directory, checked in file name order
+type Set struct {
+	scripts []*script
+	timeout time.Duration
+}
+
+type script struct {
+	name  string
+	proto *lua.FunctionProto
+}
+
+// Load compiles the *.lua files of dir, each check of a task running for at most timeout
+func Load(dir string, timeout time.Duration) (*Set, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.lua"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	set := &Set{timeout: timeout}
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := parse.Parse(f, filepath.Base(p))
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("rules: %w", err)
+		}
+		guardConcat(chunk)
+		proto, err := lua.Compile(chunk, filepath.Base(p))
+		if err != nil {
+			return nil, fmt.Errorf("rules: %w", err)
+		}
+		set.scripts = append(set.scripts, &script{name: filepath.Base(p), proto: proto})
+	}
+	return set, nil
+}
+
+// Len is how many scripts the set has
+func (s *Set) Len() int {
+	return len(s.scripts)
+}
+
+// Check runs the scripts on t until one refuses it, returning its reason, or "" when they
+// all accept it. A script that fails or runs out of time is an error.
+func (s *Set) Check(ctx context.Context, t *storages.Task) (reason string, err error) {
+	for _, sc := range s.scripts {
+		reason, err := s.check(ctx, sc, t)
+		if err != nil {
+			return "", fmt.Errorf("rules: %s: %w", sc.name, err)
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *Set) check(ctx context.Context, sc *script, t *storages.Task) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	L := sandbox(&budget{left: maxAlloc})
+	defer L.Close()
+	L.SetContext(ctx)
+
+	L.Push(L.NewFunctionFromProto(sc.proto))
+	if err := L.PCall(0, 0, nil); err != nil {
+		return "", err
+	}
+	check, ok := L.GetGlobal("check").(*lua.LFunction)
+	if !ok {
+		return "", errors.New("no check function")
+	}
+
+	task := L.NewTable()
+	task.RawSetString("content", lua.LString(t.Content))
+	task.RawSetString("user_id", lua.LString(t.UserID))
+	task.RawSetString("created_date", lua.LString(t.CreatedDate))
+	task.RawSetString("priority", lua.LNumber(t.Priority))
+	task.RawSetString("due_date", lua.LString(t.DueDate))
+	if err := L.CallByParam(lua.P{Fn: check, NRet: 1, Protect: true}, task); err != nil {
+		return "", err
+	}
+
+	switch ret := L.Get(-1).(type) {
+	case *lua.LNilType:
+		return "", nil
+	case lua.LString:
+		if ret == "" {
+			return "", errors.New("check refused the task without a reason")
+		}
+		return string(ret), nil
+	default:
+		return "", fmt.Errorf("check returned a %s, want nil or a string", ret.Type())
+	}
+}
+
+// sandbox returns a state with a bounded stack and only the libraries scripts need, building
+// strings out of b
+func sandbox(b *budget) *lua.LState {
+	L := lua.NewState(lua.Options{
+		CallStackSize:       64,
+		RegistrySize:        1024,
+		RegistryMaxSize:     64 * 1024,
+		SkipOpenLibs:        true,
+		MinimizeStackMemory: true,
+	})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range unsafeGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	b.guard(L)
+	return L
+}
@@ -0,0 +1,194 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// load is the set of scripts named by their file names, checking for at most timeout
+func load(t *testing.T, timeout time.Duration, scripts map[string]string) *Set {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set, err := Load(dir, timeout)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return set
+}
+
+var task = &storages.Task{Content: "buy milk", UserID: "firstUser", CreatedDate: "2020-06-29", Priority: 2}
+
+func TestCheck(t *testing.T) {
+	set := load(t, time.Second, map[string]string{
+		"10-fields.lua": `function check(task)
+			if task.user_id ~= "firstUser" or task.created_date ~= "2020-06-29" or task.priority ~= 2 then
+				return "task fields " .. task.user_id .. " " .. task.created_date .. " " .. task.priority
+			end
+		end`,
+		"20-capital.lua": `function check(task)
+			if not string.match(task.content, "^%u") then
+				return "tasks start with a capital letter, not " .. task.content:sub(1, 1)
+			end
+		end`,
+		"30-never.lua": `function check(task) return "scripts after a refusal don't run" end`,
+	})
+	if set.Len() != 3 {
+		t.Fatalf("loaded %d scripts, want 3", set.Len())
+	}
+
+	reason, err := set.Check(context.Background(), task)
+	if err != nil || reason != "tasks start with a capital letter, not b" {
+		t.Errorf("Check: got %q, %v, want the reason of 20-capital.lua", reason, err)
+	}
+	capital := *task
+	capital.Content = "Buy milk"
+	if reason, err := set.Check(context.Background(), &capital); err != nil || reason != "scripts after a refusal don't run" {
+		t.Errorf("Check: got %q, %v, want the reason of 30-never.lua", reason, err)
+	}
+}
+
+func TestCheckAccepting(t *testing.T) {
+	set := load(t, time.Second, map[string]string{
+		"ok.lua": `function check(task)
+			local words = {}
+			for w in string.gmatch(task.content, "%a+") do words[#words + 1] = string.upper(w) end
+			local s = string.format("%s: %q", table.concat(words, " "), task.content)
+			s = string.gsub(s, "(%u+)", "<%1>")
+			if s ~= '<BUY> <MILK>: "buy milk"' then return "got " .. s end
+			return nil
+		end`,
+	})
+	if reason, err := set.Check(context.Background(), task); err != nil || reason != "" {
+		t.Errorf("Check: got %q, %v, want the task accepted", reason, err)
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"timeout", `function check(task) while true do end end`, "context deadline exceeded"},
+		{"no check", `local x = 1`, "no check function"},
+		{"empty reason", `function check(task) return "" end`, "without a reason"},
+		{"wrong type", `function check(task) return 1 end`, "want nil or a string"},
+		{"runtime error", `function check(task) return task.nope.deeper end`, "deeper"},
+		{"recursion", `local function f(n) return f(n + 1) + 1 end function check(task) return f(1) end`, "stack overflow"},
+	}
+	for _, name := range []string{"os", "io", "load", "loadstring", "dofile", "loadfile", "require", "print", "collectgarbage", "module"} {
+		tests = append(tests, struct{ name, src, want string }{
+			name, `function check(task) return tostring(` + name + `.x) end`, "attempt to index a non-table object(nil)",
+		})
+	}
+	timeout := 100 * time.Millisecond
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := load(t, timeout, map[string]string{"script.lua": tt.src})
+			start := time.Now()
+			_, err := set.Check(context.Background(), task)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Check: got %v, want an error containing %q", err, tt.want)
+			}
+			if !strings.HasPrefix(err.Error(), "rules: script.lua: ") {
+				t.Errorf("Check: got %v, want it to name the script", err)
+			}
+			if d := time.Since(start); d > 10*timeout {
+				t.Errorf("Check took %v, over the %v timeout", d, timeout)
+			}
+		})
+	}
+
+	// the caller giving up stops the script too
+	set := load(t, time.Minute, map[string]string{"script.lua": `function check(task) while true do end end`})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := set.Check(ctx, task); !errors.Is(err, context.DeadlineExceeded) && (err == nil || !strings.Contains(err.Error(), "deadline")) {
+		t.Errorf("Check with a cancelled context: got %v", err)
+	}
+}
+
+// TestMemoryGuard runs scripts that would exhaust memory long before a generous timeout and
+// checks they fail on the allocation limit instead
+func TestMemoryGuard(t *testing.T) {
+	tests := map[string]string{
+		"rep":           `return string.rep("x", 1e9)`,
+		"rep method":    `return ("xy"):rep(1e8)`,
+		"doubling":      `local s = "x" for i = 1, 64 do s = s .. s end return s`,
+		"number concat": `local s = "1" for i = 1, 64 do s = s .. 0 .. s end return s`,
+		"growing":       `local s = "" local chunk = string.rep("x", 65536) for i = 1, 1e6 do s = s .. chunk end return s`,
+		"many strings": `local t, chunk = {}, string.rep("x", 65536)
+			for i = 1, 1e6 do t[i] = chunk .. i end return "kept"`,
+		"table.concat": `local t, s = {}, string.rep("x", 65536)
+			for i = 1, 1024 do t[i] = s end return table.concat(t, ",")`,
+		"table.concat doubling": `local s = "x" for i = 1, 64 do s = table.concat({s, s}) end return s`,
+		"format width":          `return string.format("%999999999d", 1)`,
+		"format doubling":       `local s = "x" for i = 1, 64 do s = string.format("%s%s", s, s) end return s`,
+		"format quoting":        `local s = string.rep("\0", 1048576) for i = 1, 64 do s = string.format("%q", s) end return s`,
+		"gsub":                  `local s = string.rep("x", 65536) return (s:gsub(".", s))`,
+		"gsub captures":         `local s = string.rep("x", 4096) return (s:gsub("(.)", s .. "%1%0"))`,
+		"gsub function":         `local s = string.rep("x", 65536) return (s:gsub(".", function(c) return s end))`,
+		"gsub table":            `local s = string.rep("x", 65536) return (s:gsub(".", {x = s}))`,
+		"upper":                 `local t, s = {}, string.rep("x", 1048576) for i = 1, 1e6 do t[i] = s:upper() end return "kept"`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := load(t, time.Minute, map[string]string{"script.lua": "function check(task) " + body + " end"})
+			start := time.Now()
+			_, err := set.Check(context.Background(), task)
+			if err == nil || !strings.Contains(err.Error(), "script builds strings over") {
+				t.Fatalf("Check: got %v, want the allocation limit", err)
+			}
+			if d := time.Since(start); d > 10*time.Second {
+				t.Errorf("Check took %v to hit the allocation limit", d)
+			}
+		})
+	}
+}
+
+func TestConcatSemantics(t *testing.T) {
+	set := load(t, time.Second, map[string]string{"script.lua": `
+		local mt = {__concat = function(a, b)
+			return (type(a) == "table" and a.name or a) .. "+" .. (type(b) == "table" and b.name or b)
+		end}
+		local obj = setmetatable({name = "obj"}, mt)
+		local function two() return "a", "b" end
+		function check(task)
+			local got = {
+				1 .. 2, "x" .. 1.5, obj .. "s", "s" .. obj, "a" .. "b" .. "c",
+				(two()) .. two(), #("abc" .. "de"),
+				pcall(function() return "x" .. nil end),
+			}
+			local want = {"12", "x1.5", "obj+s", "s+obj", "abc", "aa", 5, false}
+			for i, w in ipairs(want) do
+				if got[i] ~= w then return "value " .. i .. " is " .. tostring(got[i]) end
+			end
+		end`})
+	if reason, err := set.Check(context.Background(), task); err != nil || reason != "" {
+		t.Errorf("Check: got %q, %v, want .. to behave as the operator", reason, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.lua"), []byte("function check(task"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir, time.Second); err == nil || !strings.HasPrefix(err.Error(), "rules: ") {
+		t.Errorf("Load of a script that doesn't parse: got %v", err)
+	}
+	set, err := Load(t.TempDir(), time.Second)
+	if err != nil || set.Len() != 0 {
+		t.Errorf("Load of an empty directory: got %v scripts, %v", set, err)
+	}
+}
@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+	"github.com/manabie-com/togo/internal/sigv4"
+)
+
+// AWSSecretsManager reads AWS Secrets Manager secrets. Locations are a secret name or ARN,
+// with #field to pick one field of a JSON secret.
+type AWSSecretsManager struct {
+	Region      string
+	Credentials sigv4.Credentials
+	// Endpoint overrides https://secretsmanager.<region>.amazonaws.com
+	Endpoint string
+	Client   httpclient.Doer
+}
+
+// NewAWSSecretsManagerFromEnv returns an AWSSecretsManager using the standard AWS_REGION,
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables, nil when the
+// region or keys are unset
+func NewAWSSecretsManagerFromEnv() *AWSSecretsManager {
+	creds, ok := sigv4.CredentialsFromEnv()
+	region := os.Getenv("AWS_REGION")
+	if !ok || region == "" {
+		return nil
+	}
+	return &AWSSecretsManager{Region: region, Credentials: creds, Client: httpclient.New()}
+}
+
+// Get implements Provider
+func (sm *AWSSecretsManager) Get(ctx context.Context, location string) (string, error) {
+	id, key := splitKey(location)
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := sm.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + sm.Region + ".amazonaws.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	sigv4.Sign(req, body, "secretsmanager", sm.Region, sm.Credentials, time.Now())
+
+	resp, err := sm.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager responded %s: %s", resp.Status, b)
+	}
+
+	var out struct {
+		SecretString string
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return "", err
+	}
+	return field(out.SecretString, key)
+}
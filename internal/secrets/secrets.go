@@ -0,0 +1,175 @@
+// Package secrets resolves secret references from configuration. A reference is
+// scheme:location, e.g. env:JWT_KEY, file:/run/secrets/jwt, vault:secret/data/togo#jwt_key
+// or awssm:togo/prod#jwt_key; anything else is taken literally.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider looks up the secret at location, its meaning depends on the provider
+type Provider interface {
+	Get(ctx context.Context, location string) (string, error)
+}
+
+// Resolver dispatches references to a provider by scheme
+type Resolver struct {
+	Providers map[string]Provider
+}
+
+// NewResolver returns a Resolver knowing env and file, plus vault and awssm when configured
+// through the usual VAULT_ADDR/VAULT_TOKEN and AWS_REGION/AWS_ACCESS_KEY_ID/... variables
+func NewResolver() *Resolver {
+	r := &Resolver{Providers: map[string]Provider{
+		"env":  Env{},
+		"file": File{},
+	}}
+	if v := NewVaultFromEnv(); v != nil {
+		r.Providers["vault"] = v
+	}
+	if sm := NewAWSSecretsManagerFromEnv(); sm != nil {
+		r.Providers["awssm"] = sm
+	}
+	return r
+}
+
+// Resolve returns the secret ref points to, or ref itself when it has no known scheme
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	i := strings.Index(ref, ":")
+	if i < 0 {
+		return ref, nil
+	}
+	p, ok := r.Providers[ref[:i]]
+	if !ok {
+		if ref[:i] == "vault" || ref[:i] == "awssm" {
+			return "", fmt.Errorf("secrets: %s provider is not configured", ref[:i])
+		}
+		return ref, nil
+	}
+
+	v, err := p.Get(ctx, ref[i+1:])
+	if err != nil {
+		return "", fmt.Errorf("secrets: resolving %s: %w", ref, err)
+	}
+	return v, nil
+}
+
+// IsReference reports whether v is a reference Resolve looks up rather than a literal value
+func IsReference(v string) bool {
+	i := strings.Index(v, ":")
+	if i < 0 {
+		return false
+	}
+	switch v[:i] {
+	case "env", "file", "vault", "awssm":
+		return true
+	}
+	return false
+}
+
+// Env reads environment variables
+type Env struct{}
+
+// Get implements Provider
+func (Env) Get(_ context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%s is not set", name)
+	}
+	return v, nil
+}
+
+// File reads files such as Docker or Kubernetes mounted secrets, a trailing newline is dropped
+type File struct{}
+
+// Get implements Provider
+func (File) Get(_ context.Context, path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// splitKey splits location#key, key is empty without a #
+func splitKey(location string) (string, string) {
+	if i := strings.LastIndex(location, "#"); i >= 0 {
+		return location[:i], location[i+1:]
+	}
+	return location, ""
+}
+
+// field returns key of the JSON object doc, or doc itself when key is empty
+func field(doc, key string) (string, error) {
+	if key == "" {
+		return doc, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &m); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	v, ok := m[key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string field %q", key)
+	}
+	return v, nil
+}
+
+// Value is a secret that can change while the server runs
+type Value struct {
+	mu sync.RWMutex
+	v  string
+}
+
+// Static returns a Value that never changes
+func Static(v string) *Value {
+	return &Value{v: v}
+}
+
+// Get returns the current secret
+func (v *Value) Get() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.v
+}
+
+// Watch resolves ref now and, when every is positive, again every interval until ctx is
+// done, keeping the last good value when a refresh fails
+func (r *Resolver) Watch(ctx context.Context, ref string, every time.Duration) (*Value, error) {
+	s, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	v := Static(s)
+	if every <= 0 {
+		return v, nil
+	}
+
+	go func() {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			s, err := r.Resolve(ctx, ref)
+			if err != nil {
+				log.Println("error refreshing secret", err)
+				continue
+			}
+			v.mu.Lock()
+			v.v = s
+			v.mu.Unlock()
+		}
+	}()
+	return v, nil
+}
@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// Vault reads HashiCorp Vault KV secrets. Locations are API paths with a field,
+// e.g. secret/data/togo#jwt_key for KV v2 or secret/togo#jwt_key for KV v1.
+type Vault struct {
+	// Addr is the server URL, like https://vault.example.com:8200
+	Addr  string
+	Token string
+	// Namespace is sent as X-Vault-Namespace when set (Vault Enterprise)
+	Namespace string
+	Client    httpclient.Doer
+}
+
+// NewVaultFromEnv returns a Vault configured with VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE,
+// nil when VAULT_ADDR is unset
+func NewVaultFromEnv() *Vault {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil
+	}
+	return &Vault{
+		Addr:      strings.TrimSuffix(addr, "/"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+		Client:    httpclient.New(),
+	}
+}
+
+// Get implements Provider
+func (v *Vault) Get(ctx context.Context, location string) (string, error) {
+	path, key := splitKey(location)
+	if key == "" {
+		return "", fmt.Errorf("vault location %s needs a #field", location)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.Addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded %s", resp.Status)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	data := body.Data
+	// KV v2 nests the secret under data.data
+	if nested, ok := data["data"]; ok && data["metadata"] != nil {
+		data = nil
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return "", err
+		}
+	}
+
+	var s string
+	if err := json.Unmarshal(data[key], &s); err != nil {
+		return "", fmt.Errorf("vault secret %s has no string field %q", path, key)
+	}
+	return s, nil
+}
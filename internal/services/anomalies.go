@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// AnomalyPolicy tells which users creating tasks much faster than they usually do are flagged,
+// and what's done about them. A user is flagged when they created at least MinTasks in an hour
+// and Factor times as many as they did an hour on average over the week before.
+type AnomalyPolicy struct {
+	Factor   float64
+	MinTasks int
+	// Throttle limits flagged users to that many tasks an hour for ThrottleFor, 0 only flags them
+	Throttle    int
+	ThrottleFor time.Duration
+}
+
+// anomalyBaseline is how far before the hour analyzed a user's usual rate is measured
+const anomalyBaseline = 7 * 24 * time.Hour
+
+// AnalyzeCreationRates is the anomaly job, it flags the users who created tasks much faster in
+// the UTC hour before now than usual. Run it at every hour; instances running it for the same
+// hour flag each user once.
+func (s *ToDoService) AnalyzeCreationRates(ctx context.Context, now time.Time) {
+	p := s.Anomalies
+	to := now.UTC().Truncate(time.Hour)
+	from := to.Add(-time.Hour)
+	rates, err := s.Store.RetrieveCreationRates(ctx,
+		from.Add(-anomalyBaseline).Format(storages.TimeLayout),
+		from.Format(storages.TimeLayout),
+		to.Format(storages.TimeLayout),
+		p.MinTasks,
+	)
+	if err != nil {
+		log.Println("error retrieving task creation rates for", from, err)
+		return
+	}
+
+	for _, r := range rates {
+		baseline := float64(r.Baseline) / anomalyBaseline.Hours()
+		if float64(r.Created) < p.Factor*baseline {
+			continue
+		}
+
+		a := &storages.Anomaly{
+			ID:              s.IDGen.NewID(),
+			UserID:          r.UserID,
+			DetectedAt:      time.Now().UTC().Format(storages.TimeLayout),
+			WindowStart:     from.Format(storages.TimeLayout),
+			Created:         r.Created,
+			BaselinePerHour: baseline,
+		}
+		var throttle *storages.CreationThrottle
+		if p.Throttle > 0 {
+			a.ThrottledUntil = to.Add(p.ThrottleFor).Format(storages.TimeLayout)
+			throttle = &storages.CreationThrottle{UserID: r.UserID, PerHour: p.Throttle, Until: a.ThrottledUntil}
+		}
+		first, err := s.Store.AddAnomaly(ctx, a, throttle)
+		if err != nil {
+			log.Println("error recording the creation rate anomaly of", r.UserID, err)
+			continue
+		}
+		if first {
+			log.Printf("%s created %d tasks from %s, %.1f an hour before, throttled until %q",
+				r.UserID, r.Created, a.WindowStart, baseline, a.ThrottledUntil)
+		}
+	}
+}
+
+// checkCreationThrottle answers 429 and returns false when userID is throttled and created as
+// many tasks in the last hour as the throttle lets them
+func (s *ToDoService) checkCreationThrottle(resp http.ResponseWriter, req *http.Request, userID string) bool {
+	id := sql.NullString{String: userID, Valid: true}
+	now := time.Now().UTC()
+	th, err := s.Store.RetrieveCreationThrottle(req.Context(), id, now.Format(storages.TimeLayout))
+	if errors.Is(err, storages.ErrNotFound) {
+		return true
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	count, oldest, err := s.Store.CountTasksCreatedSince(req.Context(), id, now.Add(-time.Hour).Format(storages.TimeLayout))
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if count < th.PerHour {
+		return true
+	}
+
+	// the oldest task of the hour leaving it makes room for one more
+	retry := time.Hour
+	if at, err := time.Parse(storages.TimeLayout, oldest); err == nil {
+		retry = at.Add(time.Hour).Sub(now)
+	}
+	resp.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+	respondError(resp, req, http.StatusTooManyRequests, "task creation is throttled")
+	return false
+}
+
+// anomaliesQuery is the query of GET /admin/anomalies
+type anomaliesQuery struct {
+	From string `form:"from" validate:"omitempty,date"`
+	To   string `form:"to" validate:"omitempty,date"`
+}
+
+// listAnomalies answers the users flagged for their task creation rate from one UTC date to
+// another, both included, over the last 7 days by default
+func (s *ToDoService) listAnomalies(resp http.ResponseWriter, req *http.Request) {
+	var q anomaliesQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	now := time.Now().UTC()
+	if q.To == "" {
+		q.To = now.Format("2006-01-02")
+	}
+	if q.From == "" {
+		q.From = now.AddDate(0, 0, -6).Format("2006-01-02")
+	}
+	if !s.checkDateRange(resp, req, q.From, q.To) {
+		return
+	}
+	// detected_at is a timestamp, the day after To bounds it
+	to, _ := time.Parse("2006-01-02", q.To)
+
+	anomalies, err := s.Store.RetrieveAnomalies(req.Context(), q.From, to.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.Anomaly{
+		"data": anomalies,
+	})
+}
+
+// getCreationThrottle answers the throttle a user has, 404 when they have none
+func (s *ToDoService) getCreationThrottle(resp http.ResponseWriter, req *http.Request, id string) {
+	th, err := s.Store.RetrieveCreationThrottle(req.Context(), sql.NullString{String: id, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout))
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the user isn't throttled")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.CreationThrottle{
+		"data": th,
+	})
+}
+
+// liftCreationThrottle ends the throttle of a user flagged by mistake
+func (s *ToDoService) liftCreationThrottle(resp http.ResponseWriter, req *http.Request, id string) {
+	err := s.Store.LiftCreationThrottle(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the user isn't throttled")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	admin := req.Context().Value(userKey{}).(*storages.User)
+	s.auditLimitChange(req, admin.ID, id, "creation throttle lifted")
+	resp.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/manabie-com/togo/internal/archive"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// archiveTimeout bounds exporting one day
+const archiveTimeout = 5 * time.Minute
+
+// ArchiveCompleted exports the tasks completed on the day before day to the archive. An
+// export that is run again replaces the object, so retrying a day is safe.
+func (s *ToDoService) ArchiveCompleted(ctx context.Context, day time.Time) {
+	if s.Archive == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, archiveTimeout)
+	defer cancel()
+
+	from := day.AddDate(0, 0, -1)
+	tasks, err := s.Store.IterateCompletedTasks(ctx,
+		from.UTC().Format(storages.TimeLayout), day.UTC().Format(storages.TimeLayout))
+	if err != nil {
+		log.Println("error retrieving completed tasks", err)
+		return
+	}
+	defer tasks.Close()
+
+	body, n, err := archive.Encode(tasks)
+	if err != nil {
+		log.Println("error encoding completed tasks", err)
+		return
+	}
+
+	key := archive.Key(s.ArchivePrefix, from)
+	if err := s.Archive.Put(ctx, key, body, archive.ContentType); err != nil {
+		log.Println("error archiving completed tasks to", key, err)
+		return
+	}
+	log.Printf("archived %d completed tasks to %s", n, key)
+}
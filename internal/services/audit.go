@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// accountAuditQuery is the query of GET /me/audit
+type accountAuditQuery struct {
+	Action string `form:"action" validate:"omitempty,oneof=login limit_change delete impersonate request"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `form:"cursor"`
+	// Limit defaults to 50
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// accountAuditPage is the body answered for GET /me/audit
+type accountAuditPage struct {
+	Data []*storages.AuditEntry `json:"data"`
+	// NextCursor reads the page of older entries, empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// accountAudit answers the audit trail of the caller's account, newest first, a page at a time
+func (s *ToDoService) accountAudit(resp http.ResponseWriter, req *http.Request) {
+	var q accountAuditQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.Limit == 0 {
+		q.Limit = 50
+	}
+	if !s.checkPageSize(resp, req, q.Limit) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	aq := &storages.AuditQuery{UserID: userID, Action: q.Action, N: q.Limit + 1}
+	if q.Cursor != "" {
+		var ok bool
+		if aq.BeforeAt, aq.BeforeID, ok = decodeAuditCursor(q.Cursor); !ok {
+			respondError(resp, req, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+	}
+	entries, err := s.Store.RetrieveAccountAudit(req.Context(), aq)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	page := accountAuditPage{Data: entries}
+	if len(entries) > q.Limit {
+		page.Data = entries[:q.Limit]
+		last := page.Data[q.Limit-1]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(last.At + "," + last.ID))
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(page)
+}
+
+// decodeAuditCursor returns the time and ID of the entry a cursor points after
+func decodeAuditCursor(cursor string) (at, id string, ok bool) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", false
+	}
+	i := strings.Index(string(b), ",")
+	if i < 0 {
+		return "", "", false
+	}
+	return string(b[:i]), string(b[i+1:]), true
+}
+
+// auditAccount records actorID doing action on the account of userID
+func (s *ToDoService) auditAccount(ctx context.Context, actorID, userID, action, detail string) error {
+	return s.Store.AddAuditEntry(ctx, &storages.AuditEntry{
+		ID:      s.IDGen.NewID(),
+		At:      time.Now().UTC().Format(storages.TimeLayout),
+		ActorID: actorID,
+		UserID:  userID,
+		Action:  action,
+		Detail:  detail,
+	})
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
+)
+
+// undoWindow is how long a batch can be undone
+const undoWindow = 30 * time.Second
+
+type batchFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+type batchResult struct {
+	Succeeded     []string       `json:"succeeded"`
+	Failed        []batchFailure `json:"failed"`
+	UndoToken     string         `json:"undo_token"`
+	UndoExpiresAt string         `json:"undo_expires_at"`
+}
+
+// batchComplete completes the tasks listed in the body, reporting the ones the caller has no such task for
+func (s *ToDoService) batchComplete(resp http.ResponseWriter, req *http.Request) {
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	s.batch(resp, req, func(ctx context.Context, userID sql.NullString, ids []string, undo *storages.Undo) ([]string, error) {
+		notFound, err := s.Store.CompleteTasks(ctx, userID, ids, at, undo)
+		if err != nil || !s.completeHooked() {
+			return notFound, err
+		}
+
+		missing := map[string]bool{}
+		for _, id := range notFound {
+			missing[id] = true
+		}
+		for _, id := range ids {
+			if missing[id] {
+				continue
+			}
+			t, err := s.Store.RetrieveTask(ctx, userID, sql.NullString{String: id, Valid: true})
+			if err != nil {
+				requestid.Println(ctx, "error retrieving completed task", id, "for hooks", err)
+				continue
+			}
+			s.afterComplete(ctx, t)
+		}
+		return notFound, nil
+	})
+}
+
+// batchDelete deletes the tasks listed in the body, reporting the ones the caller has no such task for
+func (s *ToDoService) batchDelete(resp http.ResponseWriter, req *http.Request) {
+	s.batch(resp, req, func(ctx context.Context, userID sql.NullString, ids []string, undo *storages.Undo) ([]string, error) {
+		notFound, err := s.Store.DeleteTasks(ctx, userID, ids, undo)
+		if err != nil || len(notFound) == len(ids) {
+			return notFound, err
+		}
+
+		missing := map[string]bool{}
+		for _, id := range notFound {
+			missing[id] = true
+		}
+		deleted := make([]string, 0, len(ids)-len(notFound))
+		for _, id := range ids {
+			if !missing[id] {
+				deleted = append(deleted, id)
+			}
+		}
+		// the tasks are gone, failing to record it is only logged
+		detail := fmt.Sprintf("%d tasks: %s", len(deleted), strings.Join(deleted, ", "))
+		if err := s.auditAccount(ctx, userID.String, userID.String, storages.AuditDelete, detail); err != nil {
+			requestid.Println(ctx, "error auditing deletion", err)
+		}
+		return notFound, nil
+	})
+}
+
+// batchRequest is the body of the batch endpoints, one batch acts on at most 100 tasks
+type batchRequest struct {
+	IDs []string `json:"ids" validate:"min=1,max=100,dive,required"`
+}
+
+// batch decodes {"ids": [...]} and runs op once on the caller's tasks in it, so either all
+// found tasks change or, on error, none do. The answer carries a token undoing the batch.
+func (s *ToDoService) batch(resp http.ResponseWriter, req *http.Request,
+	op func(ctx context.Context, userID sql.NullString, ids []string, undo *storages.Undo) (notFound []string, err error)) {
+	var body batchRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	ids := make([]string, 0, len(body.IDs))
+	seen := map[string]bool{}
+	for _, id := range body.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	undo := &storages.Undo{
+		Token:     s.IDGen.NewID(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(undoWindow).UTC().Format(storages.TimeLayout),
+	}
+	notFound, err := op(req.Context(), sql.NullString{String: userID, Valid: true}, ids, undo)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	missing := map[string]bool{}
+	for _, id := range notFound {
+		missing[id] = true
+	}
+	result := batchResult{Succeeded: []string{}, Failed: []batchFailure{}, UndoToken: undo.Token, UndoExpiresAt: undo.ExpiresAt}
+	for _, id := range ids {
+		if missing[id] {
+			result.Failed = append(result.Failed, batchFailure{ID: id, Error: "task not found"})
+		} else {
+			result.Succeeded = append(result.Succeeded, id)
+		}
+	}
+
+	respond(resp, req, http.StatusOK, map[string]batchResult{
+		"data": result,
+	}, func() proto.Message {
+		pb := &togov1.BatchResult{Succeeded: result.Succeeded, UndoToken: result.UndoToken, UndoExpiresAt: result.UndoExpiresAt}
+		for _, f := range result.Failed {
+			pb.Failed = append(pb.Failed, &togov1.BatchFailure{Id: f.ID, Error: f.Error})
+		}
+		return &togov1.BatchResponse{Data: pb}
+	})
+}
+
+// undoRequest is the body of POST /undo
+type undoRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// undo reverses the batch given by the token in the body, if it's still in its undo window
+func (s *ToDoService) undo(resp http.ResponseWriter, req *http.Request) {
+	var body undoRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	restored, err := s.Store.UndoTasks(req.Context(), sql.NullString{String: userID, Valid: true}, body.Token,
+		time.Now().UTC().Format(storages.TimeLayout))
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusGone, "nothing to undo, the token is unknown, used or expired")
+		return
+	}
+	var limitErr *storages.TaskLimitReached
+	if errors.As(err, &limitErr) {
+		respondError(resp, req, http.StatusForbidden, "daily task limit reached")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respond(resp, req, http.StatusOK, map[string]map[string]int{
+		"data": {"restored": restored},
+	}, func() proto.Message {
+		return &togov1.UndoResponse{Data: &togov1.UndoResult{Restored: int32(restored)}}
+	})
+}
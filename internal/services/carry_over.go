@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/manabie-com/togo/internal/notify"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// CarryOver carries the tasks users left incomplete the day before day over to day, for the
+// users that opted in. Users whose tasks would exceed their limit keep none and are notified.
+func (s *ToDoService) CarryOver(ctx context.Context, day time.Time) {
+	users, err := s.Store.RetrieveCarryOverUsers(ctx)
+	if err != nil {
+		log.Println("error retrieving carry over users", err)
+		return
+	}
+
+	from := day.AddDate(0, 0, -1).Format("2006-01-02")
+	to := day.Format("2006-01-02")
+	for userID, mode := range users {
+		n, err := s.Store.CarryOverTasks(ctx, &storages.CarryOver{
+			UserID:   userID,
+			FromDate: from,
+			ToDate:   to,
+			Move:     mode == storages.CarryOverMove,
+			NewID:    s.IDGen.NewID,
+			At:       time.Now().UTC().Format(storages.TimeLayout),
+		})
+		var limitErr *storages.TaskLimitReached
+		if errors.As(err, &limitErr) {
+			s.notifyCarryOverSkipped(ctx, userID, to, n)
+			continue
+		}
+		if err != nil {
+			log.Println("error carrying over tasks of", userID, err)
+		}
+	}
+}
+
+func (s *ToDoService) notifyCarryOverSkipped(ctx context.Context, userID, date string, tasks int) {
+	if s.Notifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	err := s.Notifier.CarryOverSkipped(ctx, notify.CarryOverSkipped{
+		UserID:     userID,
+		Date:       date,
+		Tasks:      tasks,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Println("error sending carry over notification", err)
+	}
+}
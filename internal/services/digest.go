@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// SendDigests emails the opted-in users whose morning started, in their timezone, at or
+// before now and who didn't get today's digest yet. Running it hourly catches everyone.
+func (s *ToDoService) SendDigests(ctx context.Context, now time.Time) {
+	if s.Mailer == nil {
+		return
+	}
+
+	recipients, err := s.Store.RetrieveDigestRecipients(ctx)
+	if err != nil {
+		log.Println("error retrieving digest recipients", err)
+		return
+	}
+
+	for _, r := range recipients {
+		loc := time.Local
+		if r.Timezone != "" {
+			if loc, err = time.LoadLocation(r.Timezone); err != nil {
+				log.Println("error loading timezone of", r.UserID, err)
+				continue
+			}
+		}
+		local := now.In(loc)
+		if local.Hour() < s.DigestHour {
+			continue
+		}
+
+		if err := s.sendDigest(ctx, r, local); err != nil {
+			log.Println("error sending digest to", r.UserID, err)
+		}
+	}
+}
+
+func (s *ToDoService) sendDigest(ctx context.Context, r *storages.DigestRecipient, local time.Time) error {
+	id := sql.NullString{String: r.UserID, Valid: true}
+	today := local.Format("2006-01-02")
+	yesterday := local.AddDate(0, 0, -1).Format("2006-01-02")
+
+	tasks, err := s.Store.RetrieveTasks(ctx, id, sql.NullString{String: today, Valid: true}, storages.ListOptions{
+		Fields: []string{"content", "completed_at"},
+		Sort:   "priority",
+		Desc:   true,
+	})
+	if err != nil {
+		return err
+	}
+	done, err := s.Store.RetrieveTasks(ctx, id, sql.NullString{String: yesterday, Valid: true}, storages.ListOptions{
+		Fields: []string{"completed_at"},
+	})
+	if err != nil {
+		return err
+	}
+
+	first, err := s.Store.MarkDigestSent(ctx, id, sql.NullString{String: today, Valid: true})
+	if err != nil || !first {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	return s.Mailer.Send(ctx, r.Email, "Your tasks for "+today, digestBody(tasks, done))
+}
+
+func digestBody(today, yesterday []*storages.Task) string {
+	var b strings.Builder
+	if len(today) == 0 {
+		b.WriteString("You have no tasks today.\n")
+	} else {
+		fmt.Fprintf(&b, "You have %d tasks today:\n\n", len(today))
+		for _, t := range today {
+			mark := " "
+			if t.CompletedAt != "" {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, "[%s] %s\n", mark, t.Content)
+		}
+	}
+
+	if len(yesterday) > 0 {
+		completed := 0
+		for _, t := range yesterday {
+			if t.CompletedAt != "" {
+				completed++
+			}
+		}
+		fmt.Fprintf(&b, "\nYesterday you completed %d of %d tasks (%d%%).\n",
+			completed, len(yesterday), completed*100/len(yesterday))
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/internal/trigram"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// taskWarning tells about something odd in a task that was added anyway
+type taskWarning struct {
+	// Code is possible_duplicate for a task looking like TaskID
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	TaskID  string `json:"task_id,omitempty"`
+	// Similarity is the share of trigrams of the contents the tasks have in common
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+// duplicateWarnings warns about the task of t's user and date whose content looks the most
+// like t's, when it's at least DuplicateSimilarity similar. Failing to read the tasks only
+// skips the check, t was added already.
+func (s *ToDoService) duplicateWarnings(req *http.Request, t *storages.Task) []taskWarning {
+	if s.DuplicateSimilarity <= 0 {
+		return nil
+	}
+	tasks, err := s.Store.RetrieveTasks(req.Context(),
+		sql.NullString{String: t.UserID, Valid: true},
+		sql.NullString{String: t.CreatedDate, Valid: true},
+		storages.ListOptions{Fields: []string{"id", "content"}},
+	)
+	if err != nil {
+		requestid.Println(req.Context(), "error retrieving tasks to check for duplicates", err)
+		return nil
+	}
+
+	content := trigram.Set(t.Content)
+	var match *storages.Task
+	best := s.DuplicateSimilarity
+	for _, other := range tasks {
+		if other.ID == t.ID {
+			continue
+		}
+		if sim := trigram.SetSimilarity(content, trigram.Set(other.Content)); sim >= best {
+			match, best = other, sim
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return []taskWarning{{
+		Code:       "possible_duplicate",
+		Message:    message(req, "a task of the same day looks like this one"),
+		TaskID:     match.ID,
+		Similarity: best,
+	}}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/internal/i18n"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeMsgpack  = "application/msgpack"
+	// contentTypeXMsgpack is the unregistered name many msgpack clients still send
+	contentTypeXMsgpack = "application/x-msgpack"
+)
+
+// supportedContentTypes in order of preference when the client accepts several equally
+var supportedContentTypes = []string{contentTypeJSON, contentTypeProtobuf, contentTypeMsgpack, contentTypeXMsgpack}
+
+// negotiate picks the response content type from the Accept header, JSON when nothing else matches
+func negotiate(req *http.Request) string {
+	accept := req.Header.Get("Accept")
+	if accept == "" {
+		return contentTypeJSON
+	}
+
+	best, bestQ, bestSpecificity := contentTypeJSON, 0.0, -1
+	for _, ct := range supportedContentTypes {
+		q, specificity := acceptQuality(accept, ct)
+		if q > bestQ || (q == bestQ && q > 0 && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = ct, q, specificity
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q value accept gives contentType and how specific the
+// matching media range was: 0 for */*, 1 for type/*, 2 for an exact match
+func acceptQuality(accept, contentType string) (q float64, specificity int) {
+	specificity = -1
+	typ := strings.SplitN(contentType, "/", 2)[0]
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		s := -1
+		switch {
+		case mediaType == contentType:
+			s = 2
+		case mediaType == typ+"/*":
+			s = 1
+		case mediaType == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return q, specificity
+}
+
+// respond writes v with status in the negotiated encoding. pb builds the protobuf
+// form of v and is only called for clients that asked for protobuf.
+func respond(resp http.ResponseWriter, req *http.Request, status int, v interface{}, pb func() proto.Message) {
+	resp.Header().Add("Vary", "Accept")
+	ct := negotiate(req)
+	switch ct {
+	case contentTypeProtobuf:
+		b, err := proto.Marshal(pb())
+		if err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.Header().Set("Content-Type", ct)
+		resp.WriteHeader(status)
+		resp.Write(b)
+	case contentTypeMsgpack, contentTypeXMsgpack:
+		resp.Header().Set("Content-Type", ct)
+		resp.WriteHeader(status)
+		enc := msgpack.NewEncoder(resp)
+		// keep the field names identical to the JSON responses
+		enc.SetCustomStructTag("json")
+		enc.Encode(v)
+	default:
+		resp.Header().Set("Content-Type", contentTypeJSON)
+		resp.WriteHeader(status)
+		json.NewEncoder(resp).Encode(v)
+	}
+}
+
+// respondError answers msg, in the language the request accepts when a catalog has it
+func respondError(resp http.ResponseWriter, req *http.Request, status int, msg string) {
+	writeError(resp, req, status, message(req, msg))
+}
+
+// respondErrorf is respondError for a message with arguments
+func respondErrorf(resp http.ResponseWriter, req *http.Request, status int, format string, args ...interface{}) {
+	writeError(resp, req, status, i18n.Sprintf(language(req), format, args...))
+}
+
+// respondCodedError is respondError naming the error with code, for clients to tell apart
+// refusals answered with the same status
+func respondCodedError(resp http.ResponseWriter, req *http.Request, status int, code, msg string) {
+	id := requestid.FromContext(req.Context())
+	msg = message(req, msg)
+	respond(resp, req, status, map[string]string{
+		"error":      msg,
+		"code":       code,
+		"request_id": id,
+	}, func() proto.Message {
+		return &togov1.ErrorResponse{Error: msg, RequestId: id, Code: code}
+	})
+}
+
+// writeError answers msg with the request ID, for users to quote when they report the error
+func writeError(resp http.ResponseWriter, req *http.Request, status int, msg string) {
+	id := requestid.FromContext(req.Context())
+	respond(resp, req, status, map[string]string{
+		"error":      msg,
+		"request_id": id,
+	}, func() proto.Message {
+		return &togov1.ErrorResponse{Error: msg, RequestId: id}
+	})
+}
+
+// message translates msg to the language req accepts
+func message(req *http.Request, msg string) string {
+	return i18n.Message(language(req), msg)
+}
+
+func language(req *http.Request) string {
+	return i18n.Match(req.Header.Get("Accept-Language"))
+}
+
+func taskProto(t *storages.Task) *togov1.Task {
+	return &togov1.Task{
+		Id:               t.ID,
+		Content:          t.Content,
+		UserId:           t.UserID,
+		CreatedDate:      t.CreatedDate,
+		CreatedAt:        t.CreatedAt,
+		Priority:         int32(t.Priority),
+		DueDate:          t.DueDate,
+		CompletedAt:      t.CompletedAt,
+		OverQuota:        t.OverQuota,
+		ContentHtml:      t.ContentHTML,
+		ContentTruncated: t.ContentTruncated,
+		EstimateMinutes:  int32(t.EstimateMinutes),
+	}
+}
+
+func tasksProto(tasks []*storages.Task) []*togov1.Task {
+	pbs := make([]*togov1.Task, 0, len(tasks))
+	for _, t := range tasks {
+		pbs = append(pbs, taskProto(t))
+	}
+	return pbs
+}
+
+func warningsProto(warnings []taskWarning) []*togov1.TaskWarning {
+	var pbs []*togov1.TaskWarning
+	for _, w := range warnings {
+		pbs = append(pbs, &togov1.TaskWarning{
+			Code:       w.Code,
+			Message:    w.Message,
+			TaskId:     w.TaskID,
+			Similarity: w.Similarity,
+		})
+	}
+	return pbs
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/push"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// heartbeatInterval keeps idle event streams from being closed by proxies
+const heartbeatInterval = 30 * time.Second
+
+// events streams the caller's events as server-sent events until they disconnect
+func (s *ToDoService) events(resp http.ResponseWriter, req *http.Request) {
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		respondError(resp, req, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	events, cancel := s.push.Subscribe(userID)
+	defer cancel()
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(resp, ": heartbeat\n\n")
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			b, err := json.Marshal(e.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", e.Type, b)
+		}
+		flusher.Flush()
+	}
+}
+
+// CloseStreams ends the event streams, which would otherwise keep a shutdown waiting
+func (s *ToDoService) CloseStreams() {
+	s.push.Close()
+}
+
+// pushQuota tells the clients of t's user when adding t took them to push.WarnRatio
+// of maxTodo, with a quota_warning, or beyond it in soft quota mode, with a quota_exceeded
+func (s *ToDoService) pushQuota(t *storages.Task, count, maxTodo int) {
+	typ := "quota_warning"
+	switch {
+	case t.OverQuota:
+		typ = "quota_exceeded"
+	case !push.Crossed(count, maxTodo):
+		return
+	}
+	s.push.Publish(t.UserID, push.Event{Type: typ, Data: push.QuotaWarning{
+		Date:       t.CreatedDate,
+		Count:      count,
+		MaxTodo:    maxTodo,
+		OccurredAt: time.Now().UTC(),
+	}})
+}
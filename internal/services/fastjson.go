@@ -0,0 +1,7 @@
+//go:build fastjson
+// +build fastjson
+
+package services
+
+// fastTaskList answers task lists with writeTaskList instead of encoding/json
+const fastTaskList = true
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// Hook runs code compiled into the server around the life of tasks, for policies the service
+// doesn't have. Any of its funcs may be nil. They run in the request, so they should be quick.
+type Hook struct {
+	// BeforeCreate runs before a task is added and may change it. Returning a *Veto refuses
+	// the task with the veto's reason, other errors fail the request.
+	BeforeCreate func(ctx context.Context, t *storages.Task) error
+	// AfterCreate runs once a task was added
+	AfterCreate func(ctx context.Context, t *storages.Task)
+	// AfterComplete runs once a task was marked done, again when it already was
+	AfterComplete func(ctx context.Context, t *storages.Task)
+}
+
+// Veto is returned by BeforeCreate hooks refusing a task, Reason is answered to the caller
+type Veto struct {
+	Reason string
+	// Code is answered with Reason when set, so clients can tell the policies apart
+	Code string
+}
+
+func (v *Veto) Error() string {
+	return "task vetoed: " + v.Reason
+}
+
+// RegisterHook runs h after the hooks registered before it. Hooks must be registered
+// before the service handles requests.
+func (s *ToDoService) RegisterHook(h Hook) {
+	s.hooks = append(s.hooks, h)
+}
+
+// beforeCreate runs the BeforeCreate hooks on t, stopping at the first error
+func (s *ToDoService) beforeCreate(ctx context.Context, t *storages.Task) error {
+	for _, h := range s.hooks {
+		if h.BeforeCreate != nil {
+			if err := h.BeforeCreate(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ToDoService) afterCreate(ctx context.Context, t *storages.Task) {
+	for _, h := range s.hooks {
+		if h.AfterCreate != nil {
+			h.AfterCreate(ctx, t)
+		}
+	}
+}
+
+func (s *ToDoService) afterComplete(ctx context.Context, t *storages.Task) {
+	for _, h := range s.hooks {
+		if h.AfterComplete != nil {
+			h.AfterComplete(ctx, t)
+		}
+	}
+}
+
+// completeHooked reports whether a hook wants to know about completed tasks
+func (s *ToDoService) completeHooked() bool {
+	for _, h := range s.hooks {
+		if h.AfterComplete != nil {
+			return true
+		}
+	}
+	return false
+}
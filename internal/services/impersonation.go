@@ -0,0 +1,175 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// impersonationTTL is how long an impersonation token stays valid, it can't be renewed
+const impersonationTTL = 10 * time.Minute
+
+// impersonateRequest is the body of POST /admin/users/{id}/impersonate
+type impersonateRequest struct {
+	// Scope defaults to auth.ScopeRead
+	Scope  string `json:"scope" validate:"omitempty,oneof=read write"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// impersonation is the token answered by POST /admin/users/{id}/impersonate
+type impersonation struct {
+	Token     string `json:"token"`
+	Scope     string `json:"scope"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// impersonate issues the calling admin a token acting as the user, recording why in the audit log.
+// The token gets its own session the user sees and can revoke from GET /me/sessions.
+func (s *ToDoService) impersonate(resp http.ResponseWriter, req *http.Request, id string) {
+	var body impersonateRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	if body.Scope == "" {
+		body.Scope = auth.ScopeRead
+	}
+
+	_, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	admin := req.Context().Value(userKey{}).(*storages.User)
+	now := time.Now().UTC()
+	sess := &storages.Session{
+		ID:        s.IDGen.NewID(),
+		UserID:    id,
+		Device:    "impersonated by " + admin.ID,
+		CreatedAt: now.Format(storages.TimeLayout),
+		ExpiresAt: now.Add(impersonationTTL).Format(storages.TimeLayout),
+	}
+	if err := s.Store.AddSession(req.Context(), sess); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	err = s.Store.AddAuditEntry(req.Context(), &storages.AuditEntry{
+		ID:      s.IDGen.NewID(),
+		At:      sess.CreatedAt,
+		ActorID: admin.ID,
+		UserID:  id,
+		Action:  storages.AuditImpersonate,
+		Detail:  body.Scope + ": " + body.Reason,
+	})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := s.signToken(jwt.MapClaims{
+		"user_id": id,
+		"sid":     sess.ID,
+		"exp":     now.Add(impersonationTTL).Unix(),
+		"act":     map[string]interface{}{"sub": admin.ID},
+		"scope":   body.Scope,
+	})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*impersonation{
+		"data": {Token: token, Scope: body.Scope, ExpiresAt: sess.ExpiresAt},
+	})
+}
+
+// audit records every request made with an impersonation token in the audit log before
+// serving it, refusing the ones it can't record, those the scope doesn't allow and those of
+// admins who lost their role since
+func (s *ToDoService) audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		p, _ := auth.FromContext(req.Context())
+		if p.ImpersonatorID == "" {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		admin, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: p.ImpersonatorID, Valid: true})
+		if err != nil || admin.Role != storages.RoleAdmin {
+			if err != nil && !errors.Is(err, storages.ErrNotFound) {
+				requestid.Println(req.Context(), "error retrieving impersonator", p.ImpersonatorID, err)
+			}
+			resp.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		err = s.Store.AddAuditEntry(req.Context(), &storages.AuditEntry{
+			ID:      s.IDGen.NewID(),
+			At:      time.Now().UTC().Format(storages.TimeLayout),
+			ActorID: p.ImpersonatorID,
+			UserID:  p.UserID,
+			Action:  storages.AuditRequest,
+			Detail:  req.Method + " " + req.URL.RequestURI(),
+		})
+		if err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if p.Scope == auth.ScopeRead && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			respondError(resp, req, http.StatusForbidden, "impersonation token is read-only")
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// auditQuery is the query of GET /admin/users/{id}/audit
+type auditQuery struct {
+	From string `form:"from" validate:"omitempty,date"`
+	To   string `form:"to" validate:"omitempty,date"`
+}
+
+// userAudit answers the audit log entries of a user, as the admin or the impersonated user,
+// from one UTC day to another, both included, over the last 30 days by default
+func (s *ToDoService) userAudit(resp http.ResponseWriter, req *http.Request, id string) {
+	var q auditQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	now := time.Now().UTC()
+	if q.To == "" {
+		q.To = now.Format("2006-01-02")
+	}
+	if q.From == "" {
+		q.From = now.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+	if !s.checkDateRange(resp, req, q.From, q.To) {
+		return
+	}
+	to, _ := time.Parse("2006-01-02", q.To)
+
+	entries, err := s.Store.RetrieveAuditLog(req.Context(), sql.NullString{String: id, Valid: true},
+		q.From, to.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.AuditEntry{
+		"data": entries,
+	})
+}
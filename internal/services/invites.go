@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// defaultInviteDays is how long invites stay usable unless asked otherwise
+const defaultInviteDays = 7
+
+// signupMaxTodo is the daily limit of users who signed up, the users table's default
+const signupMaxTodo = 5
+
+// createInviteRequest is the body of POST /invites
+type createInviteRequest struct {
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=30"`
+}
+
+// createInvite answers a new single-use invite code. Only administrators may create them
+// unless UsersInvite is set.
+func (s *ToDoService) createInvite(resp http.ResponseWriter, req *http.Request) {
+	user := req.Context().Value(userKey{}).(*storages.User)
+	if !s.UsersInvite && user.Role != storages.RoleAdmin {
+		respondError(resp, req, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	var body createInviteRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	if body.ExpiresInDays == 0 {
+		body.ExpiresInDays = defaultInviteDays
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := time.Now().UTC()
+	inv := &storages.Invite{
+		Code:      base64.RawURLEncoding.EncodeToString(b),
+		CreatedBy: user.ID,
+		CreatedAt: now.Format(storages.TimeLayout),
+		ExpiresAt: now.AddDate(0, 0, body.ExpiresInDays).Format(storages.TimeLayout),
+	}
+	if err := s.Store.AddInvite(req.Context(), inv); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]*storages.Invite{
+		"data": inv,
+	})
+}
+
+// signupRequest is the body of POST /signup
+type signupRequest struct {
+	Code     string `json:"code" validate:"required"`
+	UserID   string `json:"user_id" validate:"required,max=64"`
+	Password string `json:"password" validate:"required,min=8,max=256"`
+	// Email is optional, it's where the daily digest goes
+	Email string `json:"email" validate:"omitempty,plainemail"`
+	// Website is the honeypot, sign up forms hide it so only bots fill it in
+	Website string `json:"website"`
+}
+
+// signup adds a user with an invite code, using it up
+func (s *ToDoService) signup(resp http.ResponseWriter, req *http.Request) {
+	var body signupRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	if !s.guardSignup(resp, req, &body) {
+		return
+	}
+
+	hash, err := s.Passwords.Hash(body.Password)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	u := &storages.User{ID: body.UserID, Password: hash, MaxTodo: signupMaxTodo}
+	err = s.Store.SignUp(req.Context(), body.Code, u, time.Now().UTC().Format(storages.TimeLayout))
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusForbidden, "invite code is unknown, used or expired")
+		return
+	}
+	if errors.Is(err, storages.ErrUserExists) {
+		respondError(resp, req, http.StatusConflict, "user_id is taken")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.metricsBackend().Count("signup.users", 1)
+	if body.Email != "" {
+		// the user exists either way, they can set the email in their settings again
+		if err := s.setSignupEmail(req.Context(), u.ID, body.Email); err != nil {
+			requestid.Println(req.Context(), "error setting the email of", u.ID, err)
+		}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]*storages.User{
+		"data": u,
+	})
+}
+
+// guardSignup answers the signups SignupGuard refuses, after delaying those it suspects, and
+// returns whether to go on with the signup
+func (s *ToDoService) guardSignup(resp http.ResponseWriter, req *http.Request, body *signupRequest) bool {
+	s.metricsBackend().Count("signup.attempts", 1)
+	if s.SignupGuard == nil {
+		return true
+	}
+
+	reason, suspicious := s.SignupGuard.Check(clientIP(req), body.Email, body.Website)
+	if suspicious && s.SignupGuard.Delay > 0 {
+		s.metricsBackend().Count("signup.delayed", 1)
+		timer := time.NewTimer(s.SignupGuard.Delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return false
+		}
+	}
+	if reason == "" {
+		return true
+	}
+
+	s.metricsBackend().Count("signup.refused", 1, "reason:"+reason)
+	switch reason {
+	case signupBlockedDomain:
+		respondError(resp, req, http.StatusUnprocessableEntity, "the email domain isn't allowed")
+	case signupIPCap:
+		respondError(resp, req, http.StatusTooManyRequests, "too many signups from this address")
+	default:
+		// bots filling the honeypot are told what anyone without a good invite is
+		respondError(resp, req, http.StatusForbidden, "invite code is unknown, used or expired")
+	}
+	return false
+}
+
+// setSignupEmail keeps the email a user signed up with in their settings
+func (s *ToDoService) setSignupEmail(ctx context.Context, userID, email string) error {
+	id := sql.NullString{String: userID, Valid: true}
+	settings, err := s.Store.RetrieveUserSettings(ctx, id)
+	if err != nil {
+		return err
+	}
+	settings.Email = email
+	return s.Store.UpdateUserSettings(ctx, id, settings)
+}
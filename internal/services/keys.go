@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/signing"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// KeyRefresh is how often LoadSigningKeys should run to pick up keys rotated by togoctl
+const KeyRefresh = time.Minute
+
+// LoadSigningKeys reads the signing keys from the store. Keys retired recently enough that
+// tokens they signed may not have expired yet keep verifying.
+func (s *ToDoService) LoadSigningKeys(ctx context.Context) error {
+	retiredAfter := time.Now().Add(-sessionTTL - KeyRefresh).UTC().Format(storages.TimeLayout)
+	keys, err := s.Store.RetrieveSigningKeys(ctx, retiredAfter)
+	if err != nil {
+		return err
+	}
+	ring, err := signing.NewRing(keys)
+	if err != nil {
+		return err
+	}
+
+	s.keysMu.Lock()
+	s.keys = ring
+	s.keysMu.Unlock()
+	return nil
+}
+
+func (s *ToDoService) signingKeys() *signing.Ring {
+	s.keysMu.RLock()
+	defer s.keysMu.RUnlock()
+	return s.keys
+}
+
+// jwks publishes the public keys so other services can verify tokens issued by togo
+func (s *ToDoService) jwks(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "application/jwk-set+json")
+	resp.Header().Set("Cache-Control", "max-age=60")
+	json.NewEncoder(resp).Encode(s.signingKeys().JWKS())
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/manabie-com/togo/internal/secrets"
+	"github.com/manabie-com/togo/internal/signing"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+func TestTokenKeyWithoutKid(t *testing.T) {
+	s := &ToDoService{JWTKey: secrets.Static("shared-key")}
+	hs256 := func(exp time.Time) *jwt.Token {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "firstUser", "exp": float64(exp.Unix())})
+	}
+	now := time.Now()
+
+	if _, err := s.tokenKey(hs256(now.Add(sessionTTL))); err != nil {
+		t.Errorf("kid-less token before any rotation: %v", err)
+	}
+
+	rotatedAt := now.Add(-time.Hour)
+	key, err := signing.Generate(rotatedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := signing.NewRing([]*storages.SigningKey{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys = ring
+
+	// issued before the rotation, or before every server loaded it, and still valid
+	if _, err := s.tokenKey(hs256(rotatedAt.Add(sessionTTL))); err != nil {
+		t.Errorf("kid-less token issued at the rotation: %v", err)
+	}
+	if _, err := s.tokenKey(hs256(rotatedAt.Add(KeyRefresh + sessionTTL))); err != nil {
+		t.Errorf("kid-less token issued while servers loaded the key: %v", err)
+	}
+	// issued after, e.g. forged with a leaked key
+	if _, err := s.tokenKey(hs256(now.Add(sessionTTL))); err == nil {
+		t.Error("kid-less token issued after the rotation was accepted")
+	}
+	noExp := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "firstUser"})
+	if _, err := s.tokenKey(noExp); err == nil {
+		t.Error("kid-less token without exp was accepted after the rotation")
+	}
+
+	// with every key retired JWTKey signs again
+	key.RetiredAt = now.UTC().Format(storages.TimeLayout)
+	if s.keys, err = signing.NewRing([]*storages.SigningKey{key}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.tokenKey(hs256(now.Add(sessionTTL))); err != nil {
+		t.Errorf("kid-less token with every key retired: %v", err)
+	}
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	s := &ToDoService{Store: store, JWTKey: secrets.Static("shared-key")}
+	kid := func(token string) string {
+		t.Helper()
+		parsed, err := jwt.Parse(token, s.tokenKey)
+		if err != nil {
+			t.Fatalf("parsing a token issued by togo: %v", err)
+		}
+		if parsed.Header["alg"] != signing.Algorithm {
+			t.Errorf("token signed with %v, want %s", parsed.Header["alg"], signing.Algorithm)
+		}
+		kid, _ := parsed.Header["kid"].(string)
+		return kid
+	}
+	rotate := func(at time.Time) *storages.SigningKey {
+		t.Helper()
+		k, err := signing.Generate(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.RotateSigningKey(ctx, k); err != nil {
+			t.Fatalf("RotateSigningKey: %v", err)
+		}
+		if err := s.LoadSigningKeys(ctx); err != nil {
+			t.Fatalf("LoadSigningKeys: %v", err)
+		}
+		return k
+	}
+
+	// without keys JWTKey signs
+	if err := s.LoadSigningKeys(ctx); err != nil {
+		t.Fatalf("LoadSigningKeys: %v", err)
+	}
+	shared, err := s.createToken("firstUser", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := jwt.Parse(shared, s.tokenKey); err != nil || parsed.Header["kid"] != nil || parsed.Method != jwt.SigningMethodHS256 {
+		t.Errorf("token before any rotation: %v, %v, want HS256 without a kid", parsed.Header, err)
+	}
+
+	first := rotate(time.Now().Add(-time.Minute))
+	old, err := s.createToken("firstUser", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kid(old); got != first.ID {
+		t.Errorf("token signed by %s, want the first key %s", got, first.ID)
+	}
+
+	second := rotate(time.Now())
+	token, err := s.createToken("firstUser", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kid(token); got != second.ID {
+		t.Errorf("token signed by %s after the rotation, want the second key %s", got, second.ID)
+	}
+	// the retired key verifies the tokens it signed until they expire
+	if got := kid(old); got != first.ID {
+		t.Errorf("token of the retired key verified by %s", got)
+	}
+
+	resp := httptest.NewRecorder()
+	s.jwks(resp, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	var set signing.JWKS
+	if err := json.Unmarshal(resp.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 || set.Keys[0].Kid != second.ID || set.Keys[1].Kid != first.ID {
+		t.Errorf("JWKS has %+v, want the second key then the first", set.Keys)
+	}
+
+	// keys retired for longer than a session are dropped, and their tokens with them
+	if _, err := store.DB.ExecContext(ctx, `UPDATE signing_keys SET retired_at = ? WHERE id = ?`,
+		time.Now().Add(-sessionTTL-2*KeyRefresh).UTC().Format(storages.TimeLayout), first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadSigningKeys(ctx); err != nil {
+		t.Fatalf("LoadSigningKeys: %v", err)
+	}
+	if _, err := jwt.Parse(old, s.tokenKey); err == nil {
+		t.Error("token of a key retired for longer than a session was accepted")
+	}
+
+	// neither a kid of a key togo doesn't have nor another algorithm under a kid is accepted
+	unknown, err := signing.Generate(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := signing.NewRing([]*storages.SigningKey{unknown})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key, _ := ring.Signer()
+	forged := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"user_id": "firstUser", "exp": time.Now().Add(sessionTTL).Unix()})
+	forged.Header["kid"] = unknown.ID
+	if token, err := forged.SignedString(key); err != nil {
+		t.Fatal(err)
+	} else if _, err := jwt.Parse(token, s.tokenKey); err == nil {
+		t.Error("token signed by an unknown key was accepted")
+	}
+	hs256 := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "firstUser", "exp": time.Now().Add(sessionTTL).Unix()})
+	hs256.Header["kid"] = second.ID
+	if token, err := hs256.SignedString([]byte("shared-key")); err != nil {
+		t.Fatal(err)
+	} else if _, err := jwt.Parse(token, s.tokenKey); err == nil {
+		t.Error("HS256 token with the kid of a key was accepted")
+	}
+}
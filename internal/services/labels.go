@@ -0,0 +1,171 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// labelRequest is the body of POST /me/labels and PUT /me/labels/{id}
+type labelRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+func (s *ToDoService) listLabels(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	labels, err := s.Store.RetrieveLabels(req.Context(), sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.Label{
+		"data": labels,
+	})
+}
+
+// createLabel adds a label for the caller, answering 409 when they have one of that name
+func (s *ToDoService) createLabel(resp http.ResponseWriter, req *http.Request) {
+	var body labelRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	label := &storages.Label{
+		ID:        s.IDGen.NewID(),
+		UserID:    userID,
+		Name:      body.Name,
+		Color:     body.Color,
+		CreatedAt: time.Now().UTC().Format(storages.TimeLayout),
+	}
+	err := s.Store.AddLabel(req.Context(), label)
+	if errors.Is(err, storages.ErrLabelExists) {
+		respondError(resp, req, http.StatusConflict, "a label of that name exists already")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]*storages.Label{
+		"data": label,
+	})
+}
+
+// updateLabel renames and recolors one of the caller's labels, the tasks keep it
+func (s *ToDoService) updateLabel(resp http.ResponseWriter, req *http.Request, id string) {
+	var body labelRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	label := &storages.Label{ID: id, UserID: userID, Name: body.Name, Color: body.Color}
+	err := s.Store.UpdateLabel(req.Context(), label)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "label not found")
+		return
+	}
+	if errors.Is(err, storages.ErrLabelExists) {
+		respondError(resp, req, http.StatusConflict, "a label of that name exists already")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.Label{
+		"data": label,
+	})
+}
+
+// deleteLabel deletes one of the caller's labels, taking it off every task it was assigned to
+func (s *ToDoService) deleteLabel(resp http.ResponseWriter, req *http.Request, id string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.DeleteLabel(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: id, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "label not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (s *ToDoService) getTaskLabels(resp http.ResponseWriter, req *http.Request, taskID string) {
+	s.respondTaskLabels(resp, req, taskID)
+}
+
+// assignLabel tags a task with the label named in the path, both the caller's
+func (s *ToDoService) assignLabel(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.AssignLabel(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		sql.NullString{String: chi.URLParam(req, "label"), Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "label not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.respondTaskLabels(resp, req, taskID)
+}
+
+// unassignLabel takes the label named in the path off a task
+func (s *ToDoService) unassignLabel(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.UnassignLabel(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		sql.NullString{String: chi.URLParam(req, "label"), Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the task doesn't have this label")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.respondTaskLabels(resp, req, taskID)
+}
+
+func (s *ToDoService) respondTaskLabels(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	labels, err := s.Store.RetrieveTaskLabels(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.Label{
+		"data": labels,
+	})
+}
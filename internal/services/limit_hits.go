@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// limitReached records a user hitting the limit on date for the limit stats and notifies them.
+// It runs after the request named requestID finished so it has its own context.
+func (s *ToDoService) limitReached(requestID, userID, date string) {
+	ctx, cancel := context.WithTimeout(requestid.With(context.Background(), requestID), notifyTimeout)
+	defer cancel()
+
+	now := time.Now().UTC().Format(storages.TimeLayout)
+	err := s.Store.RecordLimitHit(ctx, sql.NullString{String: userID, Valid: true}, sql.NullString{String: date, Valid: true}, now)
+	if err != nil {
+		requestid.Println(ctx, "error recording limit hit", err)
+	}
+	s.notifyLimitReached(ctx, userID, date)
+}
+
+// rollUpLimitHits is the part of the stats job summing up the limit hits of the day that ended
+func (s *ToDoService) rollUpLimitHits(ctx context.Context, ended string) {
+	n, err := s.Store.RollUpLimitHits(ctx, ended)
+	if err != nil {
+		log.Println("error rolling up limit hits for", ended, err)
+		return
+	}
+	log.Printf("rolled up limit hits of %d max_todo values for %s", n, ended)
+}
+
+// limitStatsQuery is the query of GET /admin/stats/limit-hits and /admin/stats/limit-hits/top
+type limitStatsQuery struct {
+	From string `form:"from" validate:"omitempty,date"`
+	To   string `form:"to" validate:"omitempty,date"`
+	// Limit is how many offenders to answer, 10 by default
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// decodeLimitStatsQuery defaults the range to the 30 days before today, the last rolled up
+func (s *ToDoService) decodeLimitStatsQuery(resp http.ResponseWriter, req *http.Request) (limitStatsQuery, bool) {
+	var q limitStatsQuery
+	if !decodeQuery(resp, req, &q) {
+		return q, false
+	}
+	now := time.Now().UTC()
+	if q.To == "" {
+		q.To = now.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if q.From == "" {
+		q.From = now.AddDate(0, 0, -30).Format("2006-01-02")
+	}
+	if q.Limit == 0 {
+		q.Limit = 10
+	}
+	if !s.checkDateRange(resp, req, q.From, q.To) || !s.checkPageSize(resp, req, q.Limit) {
+		return q, false
+	}
+	return q, true
+}
+
+// limitHits answers, for each day from one date to another, both included, how many users hit
+// their limit per max_todo out of how many have it. Days are counted by the stats job once over.
+func (s *ToDoService) limitHits(resp http.ResponseWriter, req *http.Request) {
+	q, ok := s.decodeLimitStatsQuery(resp, req)
+	if !ok {
+		return
+	}
+
+	rollups, err := s.Store.RetrieveLimitHitRollups(req.Context(), q.From, q.To)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.LimitHitRollup{
+		"data": rollups,
+	})
+}
+
+// limitOffenders answers the users who hit their limit on the most days within a range of dates.
+// It reads hits as they're recorded, so ?to= today counts today's so far.
+func (s *ToDoService) limitOffenders(resp http.ResponseWriter, req *http.Request) {
+	q, ok := s.decodeLimitStatsQuery(resp, req)
+	if !ok {
+		return
+	}
+
+	offenders, err := s.Store.RetrieveLimitOffenders(req.Context(), q.From, q.To, q.Limit)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.LimitOffender{
+		"data": offenders,
+	})
+}
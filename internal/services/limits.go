@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// holidaysTimeout bounds syncing the holiday calendar
+const holidaysTimeout = time.Minute
+
+// SyncHolidays stores the holidays of day's year and the next one from the calendar,
+// replacing what was stored for them
+func (s *ToDoService) SyncHolidays(ctx context.Context, day time.Time) {
+	if s.Holidays == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, holidaysTimeout)
+	defer cancel()
+
+	for year := day.Year(); year <= day.Year()+1; year++ {
+		dates, err := s.Holidays.Holidays(ctx, year)
+		if err != nil {
+			log.Println("error retrieving the holidays of", year, err)
+			continue
+		}
+		from, to := strconv.Itoa(year)+"-01-01", strconv.Itoa(year+1)+"-01-01"
+		if err := s.Store.ReplaceHolidays(ctx, from, to, dates); err != nil {
+			log.Println("error storing the holidays of", year, err)
+		}
+	}
+}
+
+// limitScheduleRequest is the body of PUT /admin/users/{id}/limits, limits left out or
+// null fall back to max_todo
+type limitScheduleRequest struct {
+	Weekend *int `json:"weekend" validate:"omitempty,min=0"`
+	Holiday *int `json:"holiday" validate:"omitempty,min=0"`
+}
+
+// getLimits answers the weekend and holiday limits of a user
+func (s *ToDoService) getLimits(resp http.ResponseWriter, req *http.Request, id string) {
+	sched, err := s.Store.RetrieveLimitSchedule(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.LimitSchedule{
+		"data": sched,
+	})
+}
+
+// updateLimits replaces the weekend and holiday limits of a user
+func (s *ToDoService) updateLimits(resp http.ResponseWriter, req *http.Request, id string) {
+	var body limitScheduleRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	sched := &storages.LimitSchedule{Weekend: body.Weekend, Holiday: body.Holiday}
+	err := s.Store.UpdateLimitSchedule(req.Context(), sql.NullString{String: id, Valid: true}, sched)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	admin := req.Context().Value(userKey{}).(*storages.User)
+	s.auditLimitChange(req, admin.ID, id, "weekend "+scheduleLimit(sched.Weekend)+", holiday "+scheduleLimit(sched.Holiday))
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.LimitSchedule{
+		"data": sched,
+	})
+}
+
+// quotaAdjustmentRequest is the body of POST /admin/users/{id}/quota-adjustments
+type quotaAdjustmentRequest struct {
+	Date   string `json:"date" validate:"required,date"`
+	Amount int    `json:"amount" validate:"min=1,max=1000"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// addQuotaAdjustment grants a user extra tasks on one date, on top of whatever limit applies
+// to it. Adjustments add up and can't be taken back.
+func (s *ToDoService) addQuotaAdjustment(resp http.ResponseWriter, req *http.Request, id string) {
+	var body quotaAdjustmentRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	admin := req.Context().Value(userKey{}).(*storages.User)
+	a := &storages.QuotaAdjustment{
+		ID:        s.IDGen.NewID(),
+		UserID:    id,
+		Date:      body.Date,
+		Amount:    body.Amount,
+		Reason:    body.Reason,
+		CreatedBy: admin.ID,
+		CreatedAt: time.Now().UTC().Format(storages.TimeLayout),
+	}
+	err := s.Store.AddQuotaAdjustment(req.Context(), a)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.auditLimitChange(req, admin.ID, id, fmt.Sprintf("%d more on %s: %s", a.Amount, a.Date, a.Reason))
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]*storages.QuotaAdjustment{
+		"data": a,
+	})
+}
+
+// quotaAdjustmentsQuery is the query of GET /admin/users/{id}/quota-adjustments
+type quotaAdjustmentsQuery struct {
+	From string `form:"from" validate:"omitempty,date"`
+	To   string `form:"to" validate:"omitempty,date"`
+}
+
+// quotaAdjustments answers the adjustments granted to a user for a range of dates, from 30
+// days ago to 30 days ahead by default
+func (s *ToDoService) quotaAdjustments(resp http.ResponseWriter, req *http.Request, id string) {
+	var q quotaAdjustmentsQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	now := time.Now().UTC()
+	if q.From == "" {
+		q.From = now.AddDate(0, 0, -30).Format("2006-01-02")
+	}
+	if q.To == "" {
+		q.To = now.AddDate(0, 0, 30).Format("2006-01-02")
+	}
+	if !s.checkDateRange(resp, req, q.From, q.To) {
+		return
+	}
+
+	adjustments, err := s.Store.RetrieveQuotaAdjustments(req.Context(), sql.NullString{String: id, Valid: true}, q.From, q.To)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.QuotaAdjustment{
+		"data": adjustments,
+	})
+}
+
+// scheduleLimit describes a limit of a LimitSchedule for the audit log
+func scheduleLimit(limit *int) string {
+	if limit == nil {
+		return "max_todo"
+	}
+	return strconv.Itoa(*limit)
+}
+
+// auditLimitChange records an admin changing the limits of a user. The change is made, so
+// failing to record it is only logged.
+func (s *ToDoService) auditLimitChange(req *http.Request, adminID, userID, detail string) {
+	if err := s.auditAccount(req.Context(), adminID, userID, storages.AuditLimitChange, detail); err != nil {
+		requestid.Println(req.Context(), "error auditing limit change of", userID, err)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/internal/i18n"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
+)
+
+// taskLinks is a task with the tasks it's blocked by and those it blocks
+type taskLinks struct {
+	Task      *storages.Task   `json:"task"`
+	BlockedBy []*storages.Task `json:"blocked_by"`
+	Blocking  []*storages.Task `json:"blocking"`
+}
+
+// addBlockerRequest is the body of POST /tasks/{id}/blockers
+type addBlockerRequest struct {
+	TaskID string `json:"task_id" validate:"required"`
+}
+
+// getTaskLinks answers one of the caller's tasks with its blockers and the tasks it blocks
+func (s *ToDoService) getTaskLinks(resp http.ResponseWriter, req *http.Request, taskID string) {
+	s.respondLinks(resp, req, taskID)
+}
+
+// addBlocker marks a task as blocked by another task of the caller, refusing links that
+// would make tasks wait for each other
+func (s *ToDoService) addBlocker(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var body addBlockerRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	if body.TaskID == taskID {
+		msg := i18n.Sprintf(language(req), "a task can't block itself")
+		respondInvalid(resp, req, []fieldError{{Field: "task_id", Error: msg}}, msg)
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	_, err := s.Store.RetrieveTask(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: body.TaskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "blocking task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	links, err := s.Store.RetrieveTaskLinks(req.Context(), sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if waitsFor(links, body.TaskID, taskID) {
+		respondError(resp, req, http.StatusConflict, "the link would make tasks block each other")
+		return
+	}
+
+	err = s.Store.AddTaskLink(req.Context(), &storages.TaskLink{
+		TaskID:      taskID,
+		BlockedByID: body.TaskID,
+		UserID:      userID,
+		CreatedAt:   time.Now().UTC().Format(storages.TimeLayout),
+	})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.respondLinks(resp, req, taskID)
+}
+
+// removeBlocker removes the link from a task to the blocker named in the path
+func (s *ToDoService) removeBlocker(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.RemoveTaskLink(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		sql.NullString{String: chi.URLParam(req, "blocker"), Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "link not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.respondLinks(resp, req, taskID)
+}
+
+// waitsFor reports whether links make task wait for blocker, directly or through other tasks
+func waitsFor(links []*storages.TaskLink, task, blocker string) bool {
+	blockers := make(map[string][]string)
+	for _, l := range links {
+		blockers[l.TaskID] = append(blockers[l.TaskID], l.BlockedByID)
+	}
+
+	seen := map[string]bool{task: true}
+	queue := []string{task}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, b := range blockers[id] {
+			if b == blocker {
+				return true
+			}
+			if !seen[b] {
+				seen[b] = true
+				queue = append(queue, b)
+			}
+		}
+	}
+	return false
+}
+
+func (s *ToDoService) respondLinks(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	t, err := s.Store.RetrieveTask(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	blockedBy, blocking, err := s.Store.RetrieveLinkedTasks(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respond(resp, req, http.StatusOK, map[string]*taskLinks{
+		"data": {Task: t, BlockedBy: blockedBy, Blocking: blocking},
+	}, func() proto.Message {
+		return &togov1.TaskLinksResponse{Data: &togov1.TaskLinks{
+			Task:      taskProto(t),
+			BlockedBy: tasksProto(blockedBy),
+			Blocking:  tasksProto(blocking),
+		}}
+	})
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// locationRequest is the body of PUT /tasks/{id}/location
+type locationRequest struct {
+	Lat *float64 `json:"lat" validate:"required,min=-90,max=90"`
+	Lng *float64 `json:"lng" validate:"required,min=-180,max=180"`
+}
+
+// nearbyQuery is the query of GET /tasks/nearby
+type nearbyQuery struct {
+	Lat string `form:"lat" validate:"required,latitude"`
+	Lng string `form:"lng" validate:"required,longitude"`
+	// RadiusKM defaults to 5
+	RadiusKM float64 `form:"radius_km" validate:"min=0,max=500"`
+	Date     string  `form:"date" validate:"omitempty,date"`
+	// Status is open to leave out completed tasks
+	Status string `form:"status" validate:"omitempty,oneof=open all"`
+	// Limit defaults to 50
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (s *ToDoService) getTaskLocation(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	loc, err := s.Store.RetrieveTaskLocation(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the task has no location")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.Location{
+		"data": loc,
+	})
+}
+
+// setTaskLocation records where a task is done, replacing where it was
+func (s *ToDoService) setTaskLocation(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var body locationRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	loc := storages.Location{Lat: *body.Lat, Lng: *body.Lng}
+	err := s.Store.SetTaskLocation(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		loc,
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]storages.Location{
+		"data": loc,
+	})
+}
+
+func (s *ToDoService) removeTaskLocation(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.RemoveTaskLocation(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the task has no location")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// nearbyTasks answers the caller's tasks within ?radius_km= of ?lat= and ?lng=, closest first,
+// for those going from one site to the next
+func (s *ToDoService) nearbyTasks(resp http.ResponseWriter, req *http.Request) {
+	var q nearbyQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.RadiusKM == 0 {
+		q.RadiusKM = 5
+	}
+	if q.Limit == 0 {
+		q.Limit = 50
+	}
+	if !s.checkPageSize(resp, req, q.Limit) {
+		return
+	}
+	// validated as coordinates already
+	lat, _ := strconv.ParseFloat(q.Lat, 64)
+	lng, _ := strconv.ParseFloat(q.Lng, 64)
+
+	userID, _ := userIDFromCtx(req.Context())
+	tasks, err := s.Store.RetrieveNearbyTasks(req.Context(), &storages.NearbyQuery{
+		UserID:      userID,
+		At:          storages.Location{Lat: lat, Lng: lng},
+		RadiusKM:    q.RadiusKM,
+		CreatedDate: q.Date,
+		Open:        q.Status == "open",
+		N:           q.Limit,
+	})
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.NearbyTask{
+		"data": tasks,
+	})
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/manabie-com/togo/internal/captcha"
+)
+
+// LoginGuard requires a solved CAPTCHA from an IP once it failed to log in
+// Threshold times within Window
+type LoginGuard struct {
+	Verifier  captcha.Verifier
+	Threshold int
+	Window    time.Duration
+
+	mu       sync.Mutex
+	failures map[string]*loginFailures
+}
+
+type loginFailures struct {
+	count int
+	since time.Time
+}
+
+// Required reports whether the next login from ip must carry a CAPTCHA token
+func (g *LoginGuard) Required(ip string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f, ok := g.failures[ip]
+	return ok && f.count >= g.Threshold && time.Since(f.since) < g.Window
+}
+
+// Fail records a failed login from ip
+func (g *LoginGuard) Fail(ip string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if g.failures == nil {
+		g.failures = map[string]*loginFailures{}
+	}
+	if len(g.failures) > 10000 {
+		for k, f := range g.failures {
+			if now.Sub(f.since) >= g.Window {
+				delete(g.failures, k)
+			}
+		}
+	}
+
+	f, ok := g.failures[ip]
+	if !ok || now.Sub(f.since) >= g.Window {
+		f = &loginFailures{since: now}
+		g.failures[ip] = f
+	}
+	f.count++
+}
+
+// Reset forgets failures from ip after a successful login
+func (g *LoginGuard) Reset(ip string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.failures, ip)
+}
+
+// clientIP returns the address the request came from. Forwarding headers are
+// ignored since any client can set them.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
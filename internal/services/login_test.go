@@ -0,0 +1,46 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoginBodies(t *testing.T) {
+	s := newFirstUserService(t)
+	tests := []struct {
+		name, method, target, contentType, body string
+		want                                    int
+	}{
+		{"json", "POST", "/login", "application/json", `{"user_id": "firstUser", "password": "example"}`, http.StatusOK},
+		{"json with charset", "POST", "/login", "application/json; charset=utf-8", `{"user_id": "firstUser", "password": "example"}`, http.StatusOK},
+		{"form", "POST", "/login", "application/x-www-form-urlencoded", "user_id=firstUser&password=example", http.StatusOK},
+		{"query", "GET", "/login?user_id=firstUser&password=example", "", "", http.StatusOK},
+		{"wrong password", "POST", "/login", "application/json", `{"user_id": "firstUser", "password": "wrong"}`, http.StatusUnauthorized},
+		{"unknown user", "POST", "/login", "application/x-www-form-urlencoded", "user_id=nobody&password=example", http.StatusUnauthorized},
+		{"not json", "POST", "/login", "application/json", "user_id=firstUser", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+		if resp.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d: %s", tt.name, resp.Code, tt.want, resp.Body)
+			continue
+		}
+		if tt.want != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Data string `json:"data"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Data == "" {
+			t.Errorf("%s: got %s, want a token", tt.name, resp.Body)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/manabie-com/togo/internal/notify"
+	"github.com/manabie-com/togo/internal/requestid"
+)
+
+// notifyTimeout bounds delivering one notification, retries included
+const notifyTimeout = 30 * time.Second
+
+// notifyLimitReached tells the Notifier about a user hitting the limit on date if they opted in,
+// at most once per user and date
+func (s *ToDoService) notifyLimitReached(ctx context.Context, userID, date string) {
+	if s.Notifier == nil {
+		return
+	}
+
+	id := sql.NullString{String: userID, Valid: true}
+	settings, err := s.Store.RetrieveUserSettings(ctx, id)
+	if err != nil {
+		requestid.Println(ctx, "error retrieving settings for limit notification", err)
+		return
+	}
+	if !settings.NotifyLimitReached {
+		return
+	}
+
+	first, err := s.Store.MarkLimitNotified(ctx, id, sql.NullString{String: date, Valid: true})
+	if err != nil {
+		requestid.Println(ctx, "error recording limit notification", err)
+		return
+	}
+	if !first {
+		return
+	}
+
+	err = s.Notifier.LimitReached(ctx, notify.LimitReached{
+		UserID:     userID,
+		Date:       date,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		requestid.Println(ctx, "error sending limit notification", err)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// startPomodoroQuery is the query of POST /tasks/{id}/pomodoros
+type startPomodoroQuery struct {
+	// Minutes is how long the session is planned to last, 25 by default
+	Minutes int `form:"minutes" validate:"omitempty,min=1,max=120"`
+}
+
+// startPomodoro starts a focus session on a task, answering 409 while the caller has one
+// running on any task
+func (s *ToDoService) startPomodoro(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var q startPomodoroQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.Minutes == 0 {
+		q.Minutes = 25
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	p := &storages.Pomodoro{
+		ID:             s.IDGen.NewID(),
+		TaskID:         taskID,
+		UserID:         userID,
+		StartedAt:      time.Now().UTC().Format(storages.TimeLayout),
+		PlannedMinutes: q.Minutes,
+	}
+	err := s.Store.StartPomodoro(req.Context(), p)
+	if errors.Is(err, storages.ErrPomodoroRunning) {
+		respondError(resp, req, http.StatusConflict, "a pomodoro is running already")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]*storages.Pomodoro{
+		"data": p,
+	})
+}
+
+// completePomodoro ends a running focus session, recording how long it actually lasted
+func (s *ToDoService) completePomodoro(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	p, err := s.Store.CompletePomodoro(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		sql.NullString{String: chi.URLParam(req, "pomodoro"), Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "pomodoro not found or completed already")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.Pomodoro{
+		"data": p,
+	})
+}
+
+// listPomodoros answers the focus sessions on a task, oldest first
+func (s *ToDoService) listPomodoros(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	pomodoros, err := s.Store.RetrievePomodoros(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.Pomodoro{
+		"data": pomodoros,
+	})
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/manabie-com/togo/internal/idgen"
+	"github.com/manabie-com/togo/internal/password"
+	"github.com/manabie-com/togo/internal/secrets"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// postmanItem is a request of docs/togo.postman_collection.json, the parts replayed
+type postmanItem struct {
+	Name    string `json:"name"`
+	Request struct {
+		Method string `json:"method"`
+		Header []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"header"`
+		Body *struct {
+			Mode       string `json:"mode"`
+			Raw        string `json:"raw"`
+			URLEncoded []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"urlencoded"`
+		} `json:"body"`
+		URL struct {
+			Raw string `json:"raw"`
+		} `json:"url"`
+	} `json:"request"`
+}
+
+// newFirstUserService serves a fresh database holding firstUser, whose password is example
+func newFirstUserService(t *testing.T) *ToDoService {
+	t.Helper()
+	store := newTestStore(t)
+	passwords, err := password.New("argon2id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := passwords.Hash("example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddUser(context.Background(), &storages.User{ID: "firstUser", Password: hash, MaxTodo: 5}); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	return &ToDoService{
+		JWTKey:    secrets.Static("postman-test-key"),
+		Store:     store,
+		IDGen:     idgen.UUIDv7{},
+		Passwords: passwords,
+	}
+}
+
+// TestPostmanCollection replays the documented requests, in their order, against a server on
+// a fresh database holding firstUser, and checks the answers have the documented shape.
+// The collection's tokens are examples that expired long ago, requests carry the token
+// Login answered instead.
+func TestPostmanCollection(t *testing.T) {
+	f, err := os.ReadFile("../../docs/togo.postman_collection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var collection struct {
+		Item []postmanItem `json:"item"`
+	}
+	if err := json.Unmarshal(f, &collection); err != nil {
+		t.Fatalf("parsing the collection: %v", err)
+	}
+
+	srv := httptest.NewServer(newFirstUserService(t))
+	defer srv.Close()
+
+	// what each documented request must answer in data
+	checks := map[string]func(t *testing.T, item postmanItem, data json.RawMessage){
+		"Login": func(t *testing.T, _ postmanItem, data json.RawMessage) {
+			var token string
+			if err := json.Unmarshal(data, &token); err != nil || strings.Count(token, ".") != 2 {
+				t.Errorf("got data %s, want a JWT", data)
+			}
+		},
+		"List content": func(t *testing.T, _ postmanItem, data json.RawMessage) {
+			// null when the day has no tasks, like on this fresh database
+			var tasks []storages.Task
+			if err := json.Unmarshal(data, &tasks); err != nil {
+				t.Errorf("got data %s, want a list of tasks", data)
+			}
+		},
+		"Create task": func(t *testing.T, item postmanItem, data json.RawMessage) {
+			var sent, got storages.Task
+			json.Unmarshal([]byte(item.Request.Body.Raw), &sent)
+			if err := json.Unmarshal(data, &got); err != nil || got.ID == "" || got.Content != sent.Content || got.UserID != "firstUser" {
+				t.Errorf("got data %s, want the task created from %s", data, item.Request.Body.Raw)
+			}
+		},
+	}
+
+	var token string
+	for _, item := range collection.Item {
+		item := item
+		t.Run(item.Name, func(t *testing.T) {
+			check, ok := checks[item.Name]
+			if !ok {
+				t.Fatalf("no check for %q, add one to TestPostmanCollection", item.Name)
+			}
+			u, err := url.Parse("http://" + strings.TrimPrefix(item.Request.URL.Raw, "http://"))
+			if err != nil {
+				t.Fatalf("parsing %q: %v", item.Request.URL.Raw, err)
+			}
+
+			var body io.Reader
+			contentType := ""
+			if b := item.Request.Body; b != nil {
+				switch b.Mode {
+				case "raw":
+					body, contentType = strings.NewReader(b.Raw), "application/json"
+				case "urlencoded":
+					form := url.Values{}
+					for _, kv := range b.URLEncoded {
+						form.Add(kv.Key, kv.Value)
+					}
+					body, contentType = strings.NewReader(form.Encode()), "application/x-www-form-urlencoded"
+				default:
+					t.Fatalf("body mode %q isn't replayed", b.Mode)
+				}
+			}
+			req, err := http.NewRequest(item.Request.Method, srv.URL+u.RequestURI(), body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			for _, h := range item.Request.Header {
+				req.Header.Set(h.Key, h.Value)
+			}
+			if req.Header.Get("Authorization") != "" {
+				req.Header.Set("Authorization", token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var answer struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+				t.Fatalf("decoding the answer: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got status %d", resp.StatusCode)
+			}
+			check(t, item, answer.Data)
+			if item.Name == "Login" {
+				json.Unmarshal(answer.Data, &token)
+			}
+		})
+	}
+}
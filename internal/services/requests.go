@@ -0,0 +1,223 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/internal/i18n"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
+)
+
+// Request DTOs declare their constraints in validate tags, checked by decodeBody and
+// decodeQuery. Fields are named after their json or form tag in error messages.
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, key := range []string{"json", "form"} {
+			if name := strings.Split(f.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	custom := map[string]func(v string) bool{
+		"date": func(v string) bool {
+			_, err := time.Parse("2006-01-02", v)
+			return err == nil
+		},
+		"timestamp": func(v string) bool {
+			_, err := time.Parse(time.RFC3339, v)
+			return err == nil
+		},
+		// timezone and plainemail accept "", which clears a setting
+		"timezone": func(v string) bool {
+			_, err := time.LoadLocation(v)
+			return err == nil
+		},
+		"plainemail": func(v string) bool {
+			if v == "" {
+				return true
+			}
+			addr, err := mail.ParseAddress(v)
+			return err == nil && addr.Name == ""
+		},
+		"sortkey": storages.IsSortKey,
+		"taskfields": func(v string) bool {
+			for _, f := range strings.Split(v, ",") {
+				if f = strings.TrimSpace(f); f != "" && !storages.IsTaskField(f) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+	for tag, ok := range custom {
+		ok := ok
+		if err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return ok(fl.Field().String())
+		}); err != nil {
+			panic(err)
+		}
+	}
+	return v
+}
+
+// decodeBody decodes the JSON body of req into dst and validates it, answering 400 and
+// returning false when either fails
+func decodeBody(resp http.ResponseWriter, req *http.Request, dst interface{}) bool {
+	err := json.NewDecoder(req.Body).Decode(dst)
+	defer req.Body.Close()
+	if err != nil {
+		respondInvalid(resp, req, nil, message(req, "the body must be a JSON object")+": "+err.Error())
+		return false
+	}
+	return validRequest(resp, req, dst)
+}
+
+// decodeQuery sets the string, int and float64 fields of dst tagged form:"name" from the query
+// parameters of req and validates dst, answering 400 and returning false when either fails
+func decodeQuery(resp http.ResponseWriter, req *http.Request, dst interface{}) bool {
+	v := reflect.ValueOf(dst).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("form")
+		raw := req.FormValue(name)
+		if name == "" || raw == "" {
+			continue
+		}
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			f.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				msg := i18n.Sprintf(language(req), "%s must be a number", name)
+				respondInvalid(resp, req, []fieldError{{Field: name, Error: msg}}, msg)
+				return false
+			}
+			f.SetInt(int64(n))
+		case reflect.Float64:
+			x, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				msg := i18n.Sprintf(language(req), "%s must be a number", name)
+				respondInvalid(resp, req, []fieldError{{Field: name, Error: msg}}, msg)
+				return false
+			}
+			f.SetFloat(x)
+		}
+	}
+	return validRequest(resp, req, dst)
+}
+
+func validRequest(resp http.ResponseWriter, req *http.Request, dst interface{}) bool {
+	err := validate.Struct(dst)
+	if err == nil {
+		return true
+	}
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	lang := language(req)
+	fields := make([]fieldError, 0, len(fieldErrs))
+	msgs := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		msg := fieldMessage(lang, fe)
+		fields = append(fields, fieldError{Field: fe.Field(), Error: msg})
+		msgs = append(msgs, msg)
+	}
+	respondInvalid(resp, req, fields, strings.Join(msgs, "; "))
+	return false
+}
+
+// fieldError is one failed constraint in a 400 answer
+type fieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// respondInvalid answers 400 with msg and, for validation failures, what failed per field
+func respondInvalid(resp http.ResponseWriter, req *http.Request, fields []fieldError, msg string) {
+	if fields == nil {
+		fields = []fieldError{}
+	}
+	id := requestid.FromContext(req.Context())
+	respond(resp, req, http.StatusBadRequest, struct {
+		Error     string       `json:"error"`
+		Fields    []fieldError `json:"fields"`
+		RequestID string       `json:"request_id"`
+	}{msg, fields, id}, func() proto.Message {
+		pb := &togov1.ErrorResponse{Error: msg, RequestId: id}
+		for _, f := range fields {
+			pb.Fields = append(pb.Fields, &togov1.FieldError{Field: f.Field, Error: f.Error})
+		}
+		return pb
+	})
+}
+
+func fieldMessage(lang string, fe validator.FieldError) string {
+	name, param := fe.Field(), fe.Param()
+	kind := fe.Kind()
+	if t := fe.Type(); t.Kind() == reflect.Ptr {
+		kind = t.Elem().Kind()
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return i18n.Sprintf(lang, "%s is required", name)
+	case "min":
+		if param == "1" && kind != reflect.Int {
+			return i18n.Sprintf(lang, "%s must not be empty", name)
+		}
+		switch kind {
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return i18n.Sprintf(lang, "%s must have at least %s items", name, param)
+		case reflect.String:
+			return i18n.Sprintf(lang, "%s must be at least %s characters long", name, param)
+		}
+		return i18n.Sprintf(lang, "%s must be at least %s", name, param)
+	case "max":
+		switch kind {
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return i18n.Sprintf(lang, "%s must have at most %s items", name, param)
+		case reflect.String:
+			return i18n.Sprintf(lang, "%s must be at most %s characters long", name, param)
+		}
+		return i18n.Sprintf(lang, "%s must be at most %s", name, param)
+	case "oneof":
+		return i18n.Sprintf(lang, "%s must be one of %s", name, strings.Join(strings.Fields(param), ", "))
+	case "sortkey":
+		return i18n.Sprintf(lang, "%s must be one of %s", name, strings.Join(storages.SortKeys, ", "))
+	case "taskfields":
+		return i18n.Sprintf(lang, "%s must list fields from %s", name, strings.Join(storages.TaskFields, ", "))
+	case "date":
+		return i18n.Sprintf(lang, "%s must be a date formatted as YYYY-MM-DD", name)
+	case "timestamp":
+		return i18n.Sprintf(lang, "%s must be a time formatted as RFC 3339, like 2024-01-31T15:00:00+07:00", name)
+	case "timezone":
+		return i18n.Sprintf(lang, "%s must be an IANA name like Asia/Ho_Chi_Minh", name)
+	case "plainemail":
+		return i18n.Sprintf(lang, "%s must be a plain address like someone@example.com", name)
+	case "latitude":
+		return i18n.Sprintf(lang, "%s must be a latitude from -90 to 90", name)
+	case "longitude":
+		return i18n.Sprintf(lang, "%s must be a longitude from -180 to 180", name)
+	case "hexcolor":
+		return i18n.Sprintf(lang, "%s must be a color like #1e90ff", name)
+	}
+	return i18n.Sprintf(lang, "%s is invalid", name)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/internal/i18n"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// ResultLimits caps what one list request may read, so no client gets the database to scan
+// millions of rows. Zero values don't cap.
+type ResultLimits struct {
+	// MaxPageSize is the most tasks a list answers and the largest limit of a page
+	MaxPageSize int
+	// MaxRangeDays is the most days a range of dates from one to another may span
+	MaxRangeDays int
+}
+
+// checkPageSize answers 422 and returns false when a page of n results is over MaxPageSize
+func (s *ToDoService) checkPageSize(resp http.ResponseWriter, req *http.Request, n int) bool {
+	if max := s.ResultLimits.MaxPageSize; max > 0 && n > max {
+		respondResultLimit(resp, req, &storages.ResultLimitExceeded{What: "results", Max: max})
+		return false
+	}
+	return true
+}
+
+// checkDateRange answers 422 and returns false when the dates from one to another, both
+// included, span more than MaxRangeDays
+func (s *ToDoService) checkDateRange(resp http.ResponseWriter, req *http.Request, from, to string) bool {
+	max := s.ResultLimits.MaxRangeDays
+	if max == 0 {
+		return true
+	}
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	if end.Sub(start) >= time.Duration(max)*24*time.Hour {
+		respondResultLimit(resp, req, &storages.ResultLimitExceeded{What: "days", Max: max})
+		return false
+	}
+	return true
+}
+
+// respondResultLimitErr answers err when it's a *ResultLimitExceeded and returns whether it was
+func respondResultLimitErr(resp http.ResponseWriter, req *http.Request, err error) bool {
+	var limitErr *storages.ResultLimitExceeded
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	respondResultLimit(resp, req, limitErr)
+	return true
+}
+
+// respondResultLimit answers 422 with the result_limit_exceeded code
+func respondResultLimit(resp http.ResponseWriter, req *http.Request, err *storages.ResultLimitExceeded) {
+	msg := i18n.Sprintf(language(req), "more than %d results asked for at once", err.Max)
+	if err.What == "days" {
+		msg = i18n.Sprintf(language(req), "the range of dates spans more than %d days", err.Max)
+	}
+	respondCodedError(resp, req, http.StatusUnprocessableEntity, "result_limit_exceeded", msg)
+}
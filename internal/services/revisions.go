@@ -0,0 +1,41 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// asOfQuery is the query of GET /admin/users/{id}/tasks
+type asOfQuery struct {
+	CreatedDate string `form:"created_date" validate:"required,date"`
+	AsOf        string `form:"as_of" validate:"required,timestamp"`
+}
+
+// userTasksAsOf answers a user's tasks of a day as they were at some point, so support can see
+// what the user saw when they reported a problem
+func (s *ToDoService) userTasksAsOf(resp http.ResponseWriter, req *http.Request, id string) {
+	var q asOfQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	at, _ := time.Parse(time.RFC3339, q.AsOf)
+
+	tasks, err := s.Store.RetrieveTasksAsOf(req.Context(),
+		sql.NullString{String: id, Valid: true},
+		sql.NullString{String: q.CreatedDate, Valid: true},
+		at.UTC().Format(storages.TimeLayout),
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.Task{
+		"data": tasks,
+	})
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/authz"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/internal/sqlcomment"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// route is an authenticated endpoint and the policy guarding it
+type route struct {
+	method string
+	// pattern is a chi pattern, {id} matches one segment of it
+	pattern string
+	policy  authz.Policy
+	// resource loads what {id} names for policies looking at it, nil when they don't
+	resource func(s *ToDoService, ctx context.Context, id string) (authz.Resource, error)
+	handle   func(s *ToDoService, resp http.ResponseWriter, req *http.Request, id string)
+}
+
+var routes = []route{
+	{http.MethodGet, "/tasks", authz.Authenticated, nil, noID((*ToDoService).listTasks)},
+	{http.MethodPost, "/tasks", authz.Authenticated, nil, noID((*ToDoService).addTask)},
+	{http.MethodGet, "/tasks/count", authz.Authenticated, nil, noID((*ToDoService).countTasks)},
+	{http.MethodGet, "/tasks/summary", authz.Authenticated, nil, noID((*ToDoService).summary)},
+	{http.MethodGet, "/tasks/suggest", authz.Authenticated, nil, noID((*ToDoService).suggest)},
+	{http.MethodGet, "/tasks/nearby", authz.Authenticated, nil, noID((*ToDoService).nearbyTasks)},
+	// batches only touch the caller's tasks and report the others as not found
+	{http.MethodPatch, "/tasks:batchComplete", authz.Authenticated, nil, noID((*ToDoService).batchComplete)},
+	{http.MethodDelete, "/tasks:batchDelete", authz.Authenticated, nil, noID((*ToDoService).batchDelete)},
+	{http.MethodPost, "/undo", authz.Authenticated, nil, noID((*ToDoService).undo)},
+	{http.MethodGet, "/tasks/{id}", authz.Owner, taskResource, (*ToDoService).getTask},
+	{http.MethodPost, "/tasks/{id}/snooze", authz.Owner, taskResource, (*ToDoService).snoozeTask},
+	{http.MethodPost, "/tasks/{id}/complete", authz.Owner, taskResource, (*ToDoService).completeTask},
+	{http.MethodPost, "/tasks/{id}/duplicate", authz.Owner, taskResource, (*ToDoService).duplicateTask},
+	{http.MethodGet, "/tasks/{id}/links", authz.Owner, taskResource, (*ToDoService).getTaskLinks},
+	{http.MethodPost, "/tasks/{id}/blockers", authz.Owner, taskResource, (*ToDoService).addBlocker},
+	{http.MethodDelete, "/tasks/{id}/blockers/{blocker}", authz.Owner, taskResource, (*ToDoService).removeBlocker},
+	{http.MethodGet, "/tasks/{id}/location", authz.Owner, taskResource, (*ToDoService).getTaskLocation},
+	{http.MethodPut, "/tasks/{id}/location", authz.Owner, taskResource, (*ToDoService).setTaskLocation},
+	{http.MethodDelete, "/tasks/{id}/location", authz.Owner, taskResource, (*ToDoService).removeTaskLocation},
+	{http.MethodPut, "/tasks/{id}/estimate", authz.Owner, taskResource, (*ToDoService).setTaskEstimate},
+	{http.MethodPost, "/tasks/{id}/timer/start", authz.Owner, taskResource, (*ToDoService).startTimer},
+	{http.MethodPost, "/tasks/{id}/timer/stop", authz.Owner, taskResource, (*ToDoService).stopTimer},
+	{http.MethodGet, "/tasks/{id}/time", authz.Owner, taskResource, (*ToDoService).listTimeEntries},
+	{http.MethodGet, "/tasks/{id}/pomodoros", authz.Owner, taskResource, (*ToDoService).listPomodoros},
+	{http.MethodPost, "/tasks/{id}/pomodoros", authz.Owner, taskResource, (*ToDoService).startPomodoro},
+	{http.MethodPost, "/tasks/{id}/pomodoros/{pomodoro}/complete", authz.Owner, taskResource, (*ToDoService).completePomodoro},
+	{http.MethodGet, "/tasks/{id}/labels", authz.Owner, taskResource, (*ToDoService).getTaskLabels},
+	{http.MethodPut, "/tasks/{id}/labels/{label}", authz.Owner, taskResource, (*ToDoService).assignLabel},
+	{http.MethodDelete, "/tasks/{id}/labels/{label}", authz.Owner, taskResource, (*ToDoService).unassignLabel},
+	{http.MethodGet, "/stats/heatmap", authz.Authenticated, nil, noID((*ToDoService).heatmap)},
+	{http.MethodGet, "/stats/streak", authz.Authenticated, nil, noID((*ToDoService).streak)},
+	{http.MethodGet, "/me/events", authz.Authenticated, nil, noID((*ToDoService).events)},
+	{http.MethodGet, "/me/sessions", authz.Authenticated, nil, noID((*ToDoService).listSessions)},
+	{http.MethodDelete, "/me/sessions", authz.Authenticated, nil, noID((*ToDoService).revokeSession)},
+	{http.MethodGet, "/me/audit", authz.Authenticated, nil, noID((*ToDoService).accountAudit)},
+	{http.MethodGet, "/me/settings", authz.Authenticated, nil, noID((*ToDoService).getSettings)},
+	{http.MethodPut, "/me/settings", authz.Authenticated, nil, noID((*ToDoService).updateSettings)},
+	// labels are looked up among the caller's own, others' answer 404
+	{http.MethodGet, "/me/labels", authz.Authenticated, nil, noID((*ToDoService).listLabels)},
+	{http.MethodPost, "/me/labels", authz.Authenticated, nil, noID((*ToDoService).createLabel)},
+	{http.MethodPut, "/me/labels/{id}", authz.Authenticated, nil, (*ToDoService).updateLabel},
+	{http.MethodDelete, "/me/labels/{id}", authz.Authenticated, nil, (*ToDoService).deleteLabel},
+	{http.MethodGet, "/me/shares", authz.Authenticated, nil, noID((*ToDoService).listShares)},
+	{http.MethodPost, "/me/shares", authz.Authenticated, nil, noID((*ToDoService).createShare)},
+	{http.MethodDelete, "/me/shares/{id}", authz.Authenticated, nil, (*ToDoService).revokeShare},
+	{http.MethodPost, "/invites", authz.Authenticated, nil, noID((*ToDoService).createInvite)},
+	{http.MethodGet, "/admin/stats/limit-hits", authz.Admin, nil, noID((*ToDoService).limitHits)},
+	{http.MethodGet, "/admin/stats/limit-hits/top", authz.Admin, nil, noID((*ToDoService).limitOffenders)},
+	{http.MethodGet, "/admin/anomalies", authz.Admin, nil, noID((*ToDoService).listAnomalies)},
+	{http.MethodGet, "/admin/users/{id}", authz.Admin, nil, (*ToDoService).getUser},
+	{http.MethodPut, "/admin/users/{id}", authz.Admin, nil, (*ToDoService).updateUser},
+	{http.MethodPost, "/admin/users/{id}/deactivate", authz.Admin, nil, (*ToDoService).deactivateUser},
+	{http.MethodPost, "/admin/users/{id}/reactivate", authz.Admin, nil, (*ToDoService).reactivateUser},
+	{http.MethodGet, "/admin/users/{id}/limits", authz.Admin, nil, (*ToDoService).getLimits},
+	{http.MethodPut, "/admin/users/{id}/limits", authz.Admin, nil, (*ToDoService).updateLimits},
+	{http.MethodGet, "/admin/users/{id}/quota-adjustments", authz.Admin, nil, (*ToDoService).quotaAdjustments},
+	{http.MethodPost, "/admin/users/{id}/quota-adjustments", authz.Admin, nil, (*ToDoService).addQuotaAdjustment},
+	{http.MethodGet, "/admin/users/{id}/usage", authz.Admin, nil, (*ToDoService).userUsage},
+	{http.MethodGet, "/admin/users/{id}/throttle", authz.Admin, nil, (*ToDoService).getCreationThrottle},
+	{http.MethodDelete, "/admin/users/{id}/throttle", authz.Admin, nil, (*ToDoService).liftCreationThrottle},
+	{http.MethodPost, "/admin/users/{id}/impersonate", authz.Admin, nil, (*ToDoService).impersonate},
+	{http.MethodGet, "/admin/users/{id}/audit", authz.Admin, nil, (*ToDoService).userAudit},
+	{http.MethodGet, "/admin/users/{id}/tasks", authz.Admin, nil, (*ToDoService).userTasksAsOf},
+}
+
+func noID(h func(*ToDoService, http.ResponseWriter, *http.Request)) func(*ToDoService, http.ResponseWriter, *http.Request, string) {
+	return func(s *ToDoService, resp http.ResponseWriter, req *http.Request, _ string) {
+		h(s, resp, req)
+	}
+}
+
+func taskResource(s *ToDoService, ctx context.Context, id string) (authz.Resource, error) {
+	owner, err := s.Store.RetrieveTaskOwner(ctx, sql.NullString{String: id, Valid: true})
+	return authz.Resource{OwnerID: owner}, err
+}
+
+// newRouter routes the public endpoints, the authenticated routes and, for other paths, the web
+// UI. With SeparateAdmin the admin router routes the admin paths alone and the other one all but them.
+func (s *ToDoService) newRouter(admin bool) http.Handler {
+	serves := func(path string) bool {
+		return !s.SeparateAdmin || isAdminPath(path) == admin
+	}
+
+	r := chi.NewRouter()
+	r.Use(s.requestID, logRequests, allowCORS, s.recordSLIs)
+
+	if serves("/login") {
+		r.Get("/login", s.getAuthToken)
+		r.Post("/login", s.getAuthToken)
+		r.Post("/signup", s.signup)
+		r.Get("/.well-known/jwks.json", s.jwks)
+		// share links carry their own signature
+		r.Get("/shared/{id}", s.shared)
+	}
+	if serves("/metrics") {
+		r.Get("/metrics", s.metrics)
+		r.Get("/slo", s.sloStatus)
+	}
+
+	r.Group(func(r chi.Router) {
+		// only authenticated requests take a turn in the write queue
+		r.Use(s.authenticate, s.queueWrites, s.audit, s.meter)
+		for i := range routes {
+			if serves(routes[i].pattern) {
+				r.Method(routes[i].method, routes[i].pattern, s.authorize(&routes[i]))
+			}
+		}
+	})
+
+	r.NotFound(func(resp http.ResponseWriter, req *http.Request) {
+		if admin || isAPIPath(req.URL.Path) || isAdminPath(req.URL.Path) {
+			respondError(resp, req, http.StatusNotFound, "not found")
+			return
+		}
+		ui.ServeHTTP(resp, req)
+	})
+	return r
+}
+
+// isAdminPath reports whether path is served by AdminHandler with SeparateAdmin
+func isAdminPath(path string) bool {
+	return path == "/metrics" || path == "/slo" || strings.HasPrefix(path, "/admin/")
+}
+
+// requestIDHeader carries the ID of a request, kept from the caller when it sends a usable one
+const requestIDHeader = "X-Request-Id"
+
+// traceParentHeader carries the W3C trace context of a request, answered with this server's span
+const traceParentHeader = "traceparent"
+
+// maxRequestID bounds the request IDs taken from callers
+const maxRequestID = 128
+
+// requestID names every request after the caller's X-Request-Id, the trace ID of their
+// traceparent or a new ID, in that order. It answers both headers, keeps the ID for logs and
+// error bodies and tags the SQL statements run for the request with them.
+func (s *ToDoService) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		traceID, flags, traced := requestid.ParseTraceParent(req.Header.Get(traceParentHeader))
+		id := req.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestID || strings.IndexFunc(id, func(r rune) bool { return r < '!' || r > '~' }) >= 0 {
+			id = s.IDGen.NewID()
+			if traced {
+				id = traceID
+			}
+		}
+		traceParent := requestid.TraceParent(traceID, flags)
+		resp.Header().Set(requestIDHeader, id)
+		resp.Header().Set(traceParentHeader, traceParent)
+
+		ctx := requestid.With(req.Context(), id)
+		ctx = sqlcomment.With(ctx, "request_id", id)
+		ctx = sqlcomment.With(ctx, "traceparent", traceParent)
+		next.ServeHTTP(resp, req.WithContext(ctx))
+	})
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requestid.Println(req.Context(), req.Method, req.URL.Path)
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// allowCORS lets browsers call the API from any origin, answering preflight requests itself
+func allowCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Access-Control-Allow-Origin", "*")
+		resp.Header().Set("Access-Control-Allow-Headers", "*")
+		resp.Header().Set("Access-Control-Allow-Methods", "*")
+		resp.Header().Set("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After, X-Request-Id, traceparent")
+		if req.Method == http.MethodOptions {
+			resp.WriteHeader(http.StatusOK)
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+type userKey struct{}
+
+// authenticate lets requests with valid credentials of an existing, active user through,
+// with the user in their context
+func (s *ToDoService) authenticate(next http.Handler) http.Handler {
+	withUser := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		p, _ := auth.FromContext(req.Context())
+		user, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: p.UserID, Valid: true})
+		if errors.Is(err, storages.ErrNotFound) || err == nil && user.DeactivatedAt != "" {
+			resp.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ctx := context.WithValue(req.Context(), userKey{}, user)
+		next.ServeHTTP(resp, req.WithContext(sqlcomment.With(ctx, "user", user.ID)))
+	})
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		auth.Require(s.authenticator(), withUser).ServeHTTP(resp, req)
+	})
+}
+
+// authorize runs r's handler once its policy allows the user on the resource
+func (s *ToDoService) authorize(r *route) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		user := req.Context().Value(userKey{}).(*storages.User)
+		id := chi.URLParam(req, "id")
+
+		var res authz.Resource
+		if r.resource != nil {
+			var err error
+			res, err = r.resource(s, req.Context(), id)
+			if err != nil && !errors.Is(err, storages.ErrNotFound) {
+				respondError(resp, req, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		p, _ := auth.FromContext(req.Context())
+		err := r.policy(authz.Subject{UserID: user.ID, Role: user.Role, Impersonated: p.ImpersonatorID != ""}, res)
+		switch {
+		case errors.Is(err, authz.ErrHidden):
+			respondError(resp, req, http.StatusNotFound, "not found")
+			return
+		case err != nil:
+			requestid.Println(req.Context(), "denied", req.Method, req.URL.Path, "to", user.ID, err)
+			respondError(resp, req, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		r.handle(s, resp, req, id)
+	})
+}
+
+// isAPIPath reports whether path belongs to the API, unknown ones answer 404 instead of the web UI
+func isAPIPath(path string) bool {
+	return path == "/tasks" || strings.HasPrefix(path, "/tasks/") || strings.HasPrefix(path, "/tasks:") ||
+		path == "/undo" || strings.HasPrefix(path, "/me/") || strings.HasPrefix(path, "/stats/") ||
+		strings.HasPrefix(path, "/admin/") || strings.HasPrefix(path, "/shared/")
+}
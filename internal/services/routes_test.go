@@ -0,0 +1,32 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manabie-com/togo/internal/authz"
+)
+
+// TestRoutePolicies keeps the route table in line with the cases of authz.TestPolicies:
+// routes on a task are for its owner and admin routes for admins
+func TestRoutePolicies(t *testing.T) {
+	is := func(p, want authz.Policy) bool {
+		return reflect.ValueOf(p).Pointer() == reflect.ValueOf(want).Pointer()
+	}
+	for _, r := range routes {
+		name := r.method + " " + r.pattern
+		switch {
+		case strings.HasPrefix(r.pattern, "/tasks/{id}"):
+			if !is(r.policy, authz.Owner) || r.resource == nil {
+				t.Errorf("%s: want authz.Owner with the task as resource", name)
+			}
+		case strings.HasPrefix(r.pattern, "/admin/"):
+			if !is(r.policy, authz.Admin) {
+				t.Errorf("%s: want authz.Admin", name)
+			}
+		case r.policy == nil:
+			t.Errorf("%s: no policy", name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// sessionTTL is how long a login and the token issued for it stay valid
+const sessionTTL = 15 * time.Minute
+
+// newSession records a login from device, falling back to the User-Agent of the request
+func (s *ToDoService) newSession(req *http.Request, userID, device string) (*storages.Session, error) {
+	if device == "" {
+		device = req.UserAgent()
+	}
+
+	now := time.Now().UTC()
+	sess := &storages.Session{
+		ID:        s.IDGen.NewID(),
+		UserID:    userID,
+		Device:    device,
+		CreatedAt: now.Format(storages.TimeLayout),
+		ExpiresAt: now.Add(sessionTTL).Format(storages.TimeLayout),
+	}
+	return sess, s.Store.AddSession(req.Context(), sess)
+}
+
+func (s *ToDoService) listSessions(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	sessions, err := s.Store.RetrieveSessions(
+		req.Context(),
+		sql.NullString{
+			String: userID,
+			Valid:  true,
+		},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+
+	resp.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	type sessionResponse struct {
+		*storages.Session
+		Current bool `json:"current"`
+	}
+	current, _ := sessionIDFromCtx(req.Context())
+	data := make([]sessionResponse, 0, len(sessions))
+	for _, sess := range sessions {
+		data = append(data, sessionResponse{Session: sess, Current: sess.ID == current})
+	}
+	json.NewEncoder(resp).Encode(map[string][]sessionResponse{
+		"data": data,
+	})
+}
+
+// revokeSession revokes the session given by the id parameter, or the caller's own one without it
+func (s *ToDoService) revokeSession(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	sessionID := value(req, "id")
+	if sessionID.String == "" {
+		sessionID.String, _ = sessionIDFromCtx(req.Context())
+	}
+
+	err := s.Store.RevokeSession(
+		req.Context(),
+		sql.NullString{
+			String: userID,
+			Valid:  true,
+		},
+		sessionID,
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+
+	resp.Header().Set("Content-Type", "application/json")
+
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "session not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+func (s *ToDoService) getSettings(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	settings, err := s.Store.RetrieveUserSettings(req.Context(), sql.NullString{
+		String: userID,
+		Valid:  true,
+	})
+
+	resp.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	json.NewEncoder(resp).Encode(map[string]*storages.UserSettings{
+		"data": settings,
+	})
+}
+
+// updateSettingsRequest is the body of PUT /me/settings, settings left out are kept
+type updateSettingsRequest struct {
+	NotifyLimitReached *bool   `json:"notify_limit_reached"`
+	CarryOver          *string `json:"carry_over" validate:"omitempty,oneof='' copy move"`
+	DailyDigest        *bool   `json:"daily_digest"`
+	Email              *string `json:"email" validate:"omitempty,plainemail"`
+	Timezone           *string `json:"timezone" validate:"omitempty,timezone"`
+	QuotaMode          *string `json:"quota_mode" validate:"omitempty,oneof='' hard soft"`
+	StandupOptOut      *bool   `json:"standup_opt_out"`
+}
+
+// apply changes the settings r has
+func (r *updateSettingsRequest) apply(settings *storages.UserSettings) {
+	if r.NotifyLimitReached != nil {
+		settings.NotifyLimitReached = *r.NotifyLimitReached
+	}
+	if r.CarryOver != nil {
+		settings.CarryOver = *r.CarryOver
+	}
+	if r.DailyDigest != nil {
+		settings.DailyDigest = *r.DailyDigest
+	}
+	if r.Email != nil {
+		settings.Email = *r.Email
+	}
+	if r.Timezone != nil {
+		settings.Timezone = *r.Timezone
+	}
+	if r.QuotaMode != nil {
+		settings.QuotaMode = *r.QuotaMode
+	}
+	if r.StandupOptOut != nil {
+		settings.StandupOptOut = *r.StandupOptOut
+	}
+}
+
+// updateSettings changes the settings present in the body and keeps the others
+func (s *ToDoService) updateSettings(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	id := sql.NullString{
+		String: userID,
+		Valid:  true,
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+
+	settings, err := s.Store.RetrieveUserSettings(req.Context(), id)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var body updateSettingsRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	body.apply(settings)
+	if settings.DailyDigest && settings.Email == "" {
+		msg := message(req, "daily_digest needs an email")
+		respondInvalid(resp, req, []fieldError{{Field: "daily_digest", Error: msg}}, msg)
+		return
+	}
+
+	err = s.Store.UpdateUserSettings(req.Context(), id, settings)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	json.NewEncoder(resp).Encode(map[string]*storages.UserSettings{
+		"data": settings,
+	})
+}
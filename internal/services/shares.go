@@ -0,0 +1,205 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// shareRequest is the body of POST /me/shares
+type shareRequest struct {
+	Date string `json:"date" validate:"required,date"`
+	// Days the link works for, 7 by default
+	Days int `json:"days" validate:"omitempty,min=1,max=90"`
+}
+
+// shareLink is a share with the link reading it
+type shareLink struct {
+	*storages.Share
+	// URL is the path of the link on this server, anyone with it can read the tasks
+	URL string `json:"url"`
+}
+
+// sharedTask is what a share shows of a task, leaving out whose it is
+type sharedTask struct {
+	Content     string `json:"content"`
+	Priority    int    `json:"priority"`
+	DueDate     string `json:"due_date"`
+	CompletedAt string `json:"completed_at"`
+}
+
+// sharedDay is the body answered for a share link
+type sharedDay struct {
+	Date  string       `json:"date"`
+	Tasks []sharedTask `json:"tasks"`
+}
+
+// createShare issues the caller a link showing their tasks of a date to whoever has it
+func (s *ToDoService) createShare(resp http.ResponseWriter, req *http.Request) {
+	var body shareRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	if body.Days == 0 {
+		body.Days = 7
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	now := time.Now().UTC()
+	share := &storages.Share{
+		ID:        s.IDGen.NewID(),
+		UserID:    userID,
+		Date:      body.Date,
+		CreatedAt: now.Format(storages.TimeLayout),
+		ExpiresAt: now.AddDate(0, 0, body.Days).Format(storages.TimeLayout),
+	}
+	if err := s.Store.AddShare(req.Context(), share); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(map[string]shareLink{
+		"data": s.shareLink(share),
+	})
+}
+
+// listShares answers the caller's links that still work
+func (s *ToDoService) listShares(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	shares, err := s.Store.RetrieveShares(req.Context(), sql.NullString{String: userID, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout))
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	links := make([]shareLink, 0, len(shares))
+	for _, share := range shares {
+		links = append(links, s.shareLink(share))
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]shareLink{
+		"data": links,
+	})
+}
+
+// revokeShare stops one of the caller's links from working
+func (s *ToDoService) revokeShare(resp http.ResponseWriter, req *http.Request, id string) {
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.RevokeShare(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: id, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "share not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// shared answers the tasks a share link shows, without authentication, as HTML to browsers and
+// JSON to everyone else. Links with a wrong signature, revoked or expired answer 404 alike.
+func (s *ToDoService) shared(resp http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	if !hmac.Equal([]byte(req.URL.Query().Get("sig")), []byte(s.shareSignature(id))) {
+		respondError(resp, req, http.StatusNotFound, "share not found")
+		return
+	}
+	share, err := s.Store.RetrieveShare(req.Context(), sql.NullString{String: id, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout))
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "share not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tasks, err := s.Store.RetrieveTasks(req.Context(),
+		sql.NullString{String: share.UserID, Valid: true},
+		sql.NullString{String: share.Date, Valid: true},
+		storages.ListOptions{Fields: []string{"content", "priority", "due_date", "completed_at"}, Sort: "created_at"},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	day := sharedDay{Date: share.Date, Tasks: make([]sharedTask, 0, len(tasks))}
+	for _, t := range tasks {
+		day.Tasks = append(day.Tasks, sharedTask{Content: t.Content, Priority: t.Priority, DueDate: t.DueDate, CompletedAt: t.CompletedAt})
+	}
+
+	// the link is the secret, it shouldn't linger in caches or leak to other sites
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.Header().Set("Referrer-Policy", "no-referrer")
+	resp.Header().Add("Vary", "Accept")
+	if wantsHTML(req) {
+		resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+		sharedPage.Execute(resp, day)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]sharedDay{
+		"data": day,
+	})
+}
+
+// shareLink returns share with its link
+func (s *ToDoService) shareLink(share *storages.Share) shareLink {
+	return shareLink{Share: share, URL: "/shared/" + url.PathEscape(share.ID) + "?sig=" + s.shareSignature(share.ID)}
+}
+
+// shareSignature signs a share ID with the JWT key, so links can't be made up from IDs.
+// Changing the key breaks every link.
+func (s *ToDoService) shareSignature(id string) string {
+	mac := hmac.New(sha256.New, []byte(s.JWTKey.Get()))
+	mac.Write([]byte("share:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// wantsHTML reports whether req prefers HTML to JSON, as browsers do
+func wantsHTML(req *http.Request) bool {
+	if req.URL.Query().Get("format") == "html" {
+		return true
+	}
+	accept := req.Header.Get("Accept")
+	htmlQ, htmlSpecificity := acceptQuality(accept, "text/html")
+	jsonQ, jsonSpecificity := acceptQuality(accept, contentTypeJSON)
+	return htmlQ > jsonQ || (htmlQ == jsonQ && htmlQ > 0 && htmlSpecificity > jsonSpecificity)
+}
+
+var sharedPage = template.Must(template.New("shared").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Tasks of {{.Date}}</title>
+<style>body{font-family:sans-serif;max-width:40em;margin:2em auto;padding:0 1em}li{margin:.4em 0}.done{text-decoration:line-through;color:#777}</style>
+</head>
+<body>
+<h1>Tasks of {{.Date}}</h1>
+{{if .Tasks}}<ul>
+{{range .Tasks}}<li{{if .CompletedAt}} class="done"{{end}}>{{.Content}}</li>
+{{end}}</ul>{{else}}<p>No tasks.</p>{{end}}
+</body>
+</html>
+`))
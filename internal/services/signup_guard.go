@@ -0,0 +1,106 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reasons SignupGuard refuses a signup for, also the reason tag of the signup.refused metric
+const (
+	signupBlockedDomain = "blocked_domain"
+	signupIPCap         = "ip_cap"
+	signupHoneypot      = "honeypot"
+)
+
+// SignupGuard keeps bots from signing up: it refuses emails of blocked domains, signups filling
+// the honeypot field, a form field people don't see, and more than MaxPerIP attempts from one
+// IP within Window. Signups it finds suspicious, refused ones and those from an IP that tried
+// already, are answered after Delay.
+type SignupGuard struct {
+	// BlockedDomains are the email domains refused, subdomains included, like disposable ones
+	BlockedDomains map[string]bool
+	// MaxPerIP caps the attempts of an IP, 0 doesn't
+	MaxPerIP int
+	Window   time.Duration
+	// Delay slows suspicious signups down, 0 answers them right away
+	Delay time.Duration
+
+	mu       sync.Mutex
+	attempts map[string]*loginFailures
+}
+
+// ParseDomains reads the domains of a blocklist separated by commas, spaces or lines, skipping
+// lines starting with #, as the published lists of disposable email domains are
+func ParseDomains(text string) map[string]bool {
+	domains := map[string]bool{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, d := range strings.Fields(strings.ReplaceAll(line, ",", " ")) {
+			domains[strings.ToLower(strings.TrimPrefix(d, "@"))] = true
+		}
+	}
+	return domains
+}
+
+// Check counts an attempt from ip and returns why it's refused, empty when it isn't, and
+// whether it's suspicious
+func (g *SignupGuard) Check(ip, email, honeypot string) (reason string, suspicious bool) {
+	tries := g.attempt(ip)
+	switch {
+	case honeypot != "":
+		return signupHoneypot, true
+	case g.MaxPerIP > 0 && tries > g.MaxPerIP:
+		return signupIPCap, true
+	case g.blocked(email):
+		return signupBlockedDomain, true
+	}
+	return "", tries > 1
+}
+
+// blocked reports whether the domain of email, or one it's a subdomain of, is blocked
+func (g *SignupGuard) blocked(email string) bool {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return false
+	}
+	for d := strings.ToLower(email[i+1:]); d != ""; {
+		if g.BlockedDomains[d] {
+			return true
+		}
+		j := strings.Index(d, ".")
+		if j < 0 {
+			break
+		}
+		d = d[j+1:]
+	}
+	return false
+}
+
+// attempt records an attempt from ip, returning how many it made within Window
+func (g *SignupGuard) attempt(ip string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if g.attempts == nil {
+		g.attempts = map[string]*loginFailures{}
+	}
+	if len(g.attempts) > 10000 {
+		for k, a := range g.attempts {
+			if now.Sub(a.since) >= g.Window {
+				delete(g.attempts, k)
+			}
+		}
+	}
+
+	a, ok := g.attempts[ip]
+	if !ok || now.Sub(a.since) >= g.Window {
+		a = &loginFailures{since: now}
+		g.attempts[ip] = a
+	}
+	a.count++
+	return a.count
+}
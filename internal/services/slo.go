@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/manabie-com/togo/internal/metrics"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/internal/slo"
+)
+
+// statusRecorder remembers the status a handler answered
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush keeps GET /me/events streaming through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// recordSLIs counts API requests against the service level objectives
+func (s *ToDoService) recordSLIs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !isAPIPath(req.URL.Path) && req.URL.Path != "/login" {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: resp}
+		next.ServeHTTP(rec, req)
+		now := time.Now()
+
+		ok := rec.status < http.StatusInternalServerError
+		s.recordSLI(slo.Availability, ok, now)
+		if req.Method == http.MethodPost && req.URL.Path == "/tasks" {
+			s.recordSLI(slo.CreateTaskLatency, ok && now.Sub(start) <= slo.CreateTaskThreshold, now)
+		}
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		s.metricsBackend().Timing("request.duration", now.Sub(start),
+			"method:"+req.Method, "route:"+route, "status:"+strconv.Itoa(status))
+	})
+}
+
+// recordSLI counts an event of objective for /slo and the metrics backend
+func (s *ToDoService) recordSLI(o slo.Objective, good bool, now time.Time) {
+	s.slo.Record(o.Name, good, now)
+	m := s.metricsBackend()
+	m.Count("slo.events", 1, "slo:"+o.Name)
+	if good {
+		m.Count("slo.good_events", 1, "slo:"+o.Name)
+	}
+}
+
+// metricsBackend returns Metrics, setting it to a Prometheus registry when nil. Prometheus
+// counts every objective from 0, so its series exist before the first event.
+func (s *ToDoService) metricsBackend() metrics.Backend {
+	s.metricsOnce.Do(func() {
+		if s.Metrics == nil {
+			s.Metrics = &metrics.Prometheus{}
+		}
+		if prom, ok := s.Metrics.(*metrics.Prometheus); ok {
+			for _, o := range slo.Objectives {
+				prom.Count("slo.events", 0, "slo:"+o.Name)
+				prom.Count("slo.good_events", 0, "slo:"+o.Name)
+			}
+		}
+	})
+	return s.Metrics
+}
+
+// metrics exposes the metrics and objectives for Prometheus to scrape, when they go to it
+func (s *ToDoService) metrics(resp http.ResponseWriter, req *http.Request) {
+	prom, ok := s.metricsBackend().(*metrics.Prometheus)
+	if !ok {
+		respondError(resp, req, http.StatusNotFound, "metrics are sent to statsd")
+		return
+	}
+
+	resp.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := fmt.Fprint(resp, "# HELP togo_slo_objective Share of events that should meet the objective.\n# TYPE togo_slo_objective gauge\n"); err != nil {
+		return
+	}
+	for _, o := range slo.Objectives {
+		fmt.Fprintf(resp, "togo_slo_objective{slo=%q} %v\n", o.Name, o.Target)
+	}
+	if err := prom.WriteMetrics(resp); err != nil {
+		requestid.Println(req.Context(), "error writing metrics", err)
+	}
+}
+
+// sloStatus answers how each objective fared over the recent windows on this instance
+func (s *ToDoService) sloStatus(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]slo.Status{
+		"data": s.slo.Summary(time.Now()),
+	})
+}
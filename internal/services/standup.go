@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// SendStandup sends the team standup report of today, what every user who didn't opt out
+// completed yesterday and plans today, once the server's local StandupHour came and if no
+// instance sent it yet. Running it hourly catches the hour.
+func (s *ToDoService) SendStandup(ctx context.Context, now time.Time) {
+	if s.StandupSlack == nil && (s.Mailer == nil || len(s.StandupEmails) == 0) {
+		return
+	}
+	local := now.In(time.Local)
+	if local.Hour() < s.StandupHour {
+		return
+	}
+	today := local.Format("2006-01-02")
+	yesterday := local.AddDate(0, 0, -1).Format("2006-01-02")
+
+	members, err := s.Store.RetrieveStandup(ctx, yesterday, today)
+	if err != nil {
+		log.Println("error retrieving standup", err)
+		return
+	}
+	first, err := s.Store.MarkStandupSent(ctx, today, now.UTC().Format(storages.TimeLayout))
+	if err != nil {
+		log.Println("error marking standup sent", err)
+		return
+	}
+	if !first {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	body := standupBody(today, members)
+	if s.Mailer != nil {
+		for _, to := range s.StandupEmails {
+			if err := s.Mailer.Send(ctx, to, "Standup for "+today, body); err != nil {
+				log.Println("error emailing standup to", to, err)
+			}
+		}
+	}
+	if s.StandupSlack != nil {
+		if err := s.StandupSlack.Post(ctx, body); err != nil {
+			log.Println("error posting standup to slack", err)
+		}
+	}
+}
+
+func standupBody(today string, members []*storages.StandupMember) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Standup for %s\n", today)
+	if len(members) == 0 {
+		b.WriteString("\nNobody is in the standup.\n")
+	}
+	for _, m := range members {
+		fmt.Fprintf(&b, "\n%s\n", m.UserID)
+		standupList(&b, "Yesterday", m.Completed)
+		standupList(&b, "Today", m.Planned)
+	}
+	return b.String()
+}
+
+func standupList(b *strings.Builder, title string, contents []string) {
+	if len(contents) == 0 {
+		fmt.Fprintf(b, "  %s: nothing\n", title)
+		return
+	}
+	fmt.Fprintf(b, "  %s:\n", title)
+	for _, c := range contents {
+		fmt.Fprintf(b, "  - %s\n", c)
+	}
+}
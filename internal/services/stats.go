@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
+)
+
+type heatmap struct {
+	Year int                  `json:"year"`
+	Days []*storages.DayCount `json:"days"`
+}
+
+// taskSummary is the breakdown of a day's tasks answered by GET /tasks/summary
+type taskSummary struct {
+	Date       string                    `json:"date"`
+	GroupBy    string                    `json:"group_by"`
+	Priorities []*storages.PriorityCount `json:"priorities"`
+}
+
+// taskSummaryQuery is the query of GET /tasks/summary
+type taskSummaryQuery struct {
+	// Date defaults to today
+	Date string `form:"date" validate:"omitempty,date"`
+	// GroupBy defaults to priority, the only grouping so far
+	GroupBy string `form:"group_by" validate:"omitempty,oneof=priority"`
+}
+
+// summary answers how many of the caller's tasks on ?date= there are per priority, and how many
+// of those are completed, without listing them
+func (s *ToDoService) summary(resp http.ResponseWriter, req *http.Request) {
+	var q taskSummaryQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.Date == "" {
+		q.Date = time.Now().Format("2006-01-02")
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	counts, err := s.Store.RetrievePriorityCounts(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: q.Date, Valid: true},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sum := taskSummary{Date: q.Date, GroupBy: "priority", Priorities: counts}
+	respond(resp, req, http.StatusOK, map[string]taskSummary{"data": sum}, func() proto.Message {
+		pb := &togov1.TaskSummary{Date: sum.Date, GroupBy: sum.GroupBy}
+		for _, c := range counts {
+			pb.Priorities = append(pb.Priorities, &togov1.PriorityCount{
+				Priority:  int32(c.Priority),
+				Count:     int32(c.Count),
+				Completed: int32(c.Completed),
+			})
+		}
+		return &togov1.TaskSummaryResponse{Data: pb}
+	})
+}
+
+// heatmapQuery is the query of GET /stats/heatmap
+type heatmapQuery struct {
+	// Year defaults to the current one
+	Year int `form:"year" validate:"omitempty,min=1,max=9999"`
+}
+
+// heatmap answers how many tasks the caller created and completed on each day of ?year=,
+// the current one by default
+func (s *ToDoService) heatmap(resp http.ResponseWriter, req *http.Request) {
+	var q heatmapQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	year := q.Year
+	if year == 0 {
+		year = time.Now().Year()
+	}
+
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	next := first.AddDate(1, 0, 0)
+	userID, _ := userIDFromCtx(req.Context())
+	counts, err := s.Store.RetrieveDayCounts(req.Context(), sql.NullString{String: userID, Valid: true},
+		first.Format("2006-01-02"), next.Format("2006-01-02"))
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// the store leaves out empty days, a heatmap has a cell for each
+	h := heatmap{Year: year}
+	for day := first; day.Before(next); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if len(counts) > 0 && counts[0].Date == date {
+			h.Days = append(h.Days, counts[0])
+			counts = counts[1:]
+			continue
+		}
+		h.Days = append(h.Days, &storages.DayCount{Date: date})
+	}
+
+	respond(resp, req, http.StatusOK, map[string]heatmap{"data": h}, func() proto.Message {
+		pb := &togov1.Heatmap{Year: int32(year)}
+		for _, d := range h.Days {
+			pb.Days = append(pb.Days, &togov1.DayCount{
+				Date:           d.Date,
+				Created:        int32(d.Created),
+				Completed:      int32(d.Completed),
+				TrackedSeconds: int32(d.TrackedSeconds),
+				Pomodoros:      int32(d.Pomodoros),
+				FocusSeconds:   int32(d.FocusSeconds),
+			})
+		}
+		return &togov1.HeatmapResponse{Data: pb}
+	})
+}
+
+// UpdateStats is the stats job, it extends the streaks of the users who completed a task on
+// the UTC day before day and rolls up who hit their limit then. Run it at every UTC midnight.
+func (s *ToDoService) UpdateStats(ctx context.Context, day time.Time) {
+	ended := day.UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	s.rollUpLimitHits(ctx, ended)
+	n, err := s.Store.UpdateStreaks(ctx, ended)
+	if err != nil {
+		log.Println("error updating streaks for", ended, err)
+		return
+	}
+	log.Printf("updated %d streaks for %s", n, ended)
+}
+
+// streak answers the caller's current and longest streak. The stats job counts days once
+// they're over, so a streak still running includes today as soon as a task is completed.
+func (s *ToDoService) streak(resp http.ResponseWriter, req *http.Request) {
+	userID, _ := userIDFromCtx(req.Context())
+	id := sql.NullString{String: userID, Valid: true}
+	st, err := s.Store.RetrieveStreak(req.Context(), id)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	if st.LastDay < yesterday {
+		st.Current = 0
+	}
+	if st.LastDay < today {
+		counts, err := s.Store.RetrieveDayCounts(req.Context(), id, today, now.AddDate(0, 0, 1).Format("2006-01-02"))
+		if err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(counts) > 0 && counts[0].Completed > 0 {
+			st.Current++
+			st.LastDay = today
+			if st.Current > st.Longest {
+				st.Longest = st.Current
+			}
+		}
+	}
+
+	respond(resp, req, http.StatusOK, map[string]*storages.Streak{"data": st}, func() proto.Message {
+		return &togov1.StreakResponse{Data: &togov1.Streak{
+			Current: int32(st.Current),
+			Longest: int32(st.Longest),
+			LastDay: st.LastDay,
+		}}
+	})
+}
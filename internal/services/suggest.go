@@ -0,0 +1,48 @@
+package services
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
+)
+
+// suggestQuery is the query of GET /tasks/suggest
+type suggestQuery struct {
+	Q string `form:"q" validate:"required,max=200"`
+	// Limit defaults to 10
+	Limit int `form:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+// suggest answers the contents starting with ?q= the caller wrote the most, so clients can
+// complete the tasks they add day after day
+func (s *ToDoService) suggest(resp http.ResponseWriter, req *http.Request) {
+	var q suggestQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.Limit == 0 {
+		q.Limit = 10
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	suggestions, err := s.Store.RetrieveContentSuggestions(req.Context(), sql.NullString{String: userID, Valid: true}, q.Q, q.Limit)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respond(resp, req, http.StatusOK, map[string][]*storages.ContentSuggestion{"data": suggestions}, func() proto.Message {
+		pb := &togov1.SuggestionsResponse{}
+		for _, sg := range suggestions {
+			pb.Data = append(pb.Data, &togov1.ContentSuggestion{
+				Content:  sg.Content,
+				Count:    int32(sg.Count),
+				LastDate: sg.LastDate,
+			})
+		}
+		return pb
+	})
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// taskListBuffers keeps the buffers of writeTaskList between requests, those grown past
+// maxTaskListBuffer aren't kept
+var taskListBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 16<<10)
+		return &b
+	},
+}
+
+const maxTaskListBuffer = 1 << 20
+
+// sparseTaskPool keeps the slices of maps answered for lists with fields between requests,
+// the maps emptied but kept with their slots
+var sparseTaskPool = sync.Pool{
+	New: func() interface{} {
+		// not nil, an empty list is answered as []
+		s := make([]map[string]interface{}, 0, 16)
+		return &s
+	},
+}
+
+// maxSparseTasks is the longest slice of sparseTaskPool kept
+const maxSparseTasks = 1000
+
+// getSparseTasks returns the fields of tasks from sparseTaskPool, to be put back once answered
+func getSparseTasks(tasks []*storages.Task, fields []string) *[]map[string]interface{} {
+	sparse := sparseTaskPool.Get().(*[]map[string]interface{})
+	s := (*sparse)[:0]
+	for i, t := range tasks {
+		var m map[string]interface{}
+		if i < cap(s) {
+			m = s[:cap(s)][i]
+		}
+		if m == nil {
+			m = make(map[string]interface{}, len(fields))
+		}
+		s = append(s, t.FieldsInto(m, fields))
+	}
+	*sparse = s
+	return sparse
+}
+
+// putSparseTasks empties the maps of sparse and puts it back in sparseTaskPool. Nothing may
+// use them after.
+func putSparseTasks(sparse *[]map[string]interface{}) {
+	if cap(*sparse) > maxSparseTasks {
+		return
+	}
+	for _, m := range *sparse {
+		for k := range m {
+			delete(m, k)
+		}
+	}
+	sparseTaskPool.Put(sparse)
+}
+
+// writeTaskList answers tasks as JSON the way respond does, byte for byte, without going
+// through encoding/json and its reflection. It's the list path of builds tagged fastjson.
+func writeTaskList(resp http.ResponseWriter, tasks []*storages.Task) {
+	buf := taskListBuffers.Get().(*[]byte)
+	*buf = appendTaskList((*buf)[:0], tasks)
+
+	resp.Header().Set("Content-Type", contentTypeJSON)
+	resp.WriteHeader(http.StatusOK)
+	resp.Write(*buf)
+
+	if cap(*buf) <= maxTaskListBuffer {
+		taskListBuffers.Put(buf)
+	}
+}
+
+// appendTaskList appends {"data": tasks} to dst as encoding/json encodes it, trailing newline
+// included
+func appendTaskList(dst []byte, tasks []*storages.Task) []byte {
+	dst = append(dst, `{"data":`...)
+	if tasks == nil {
+		return append(dst, "null}\n"...)
+	}
+
+	dst = append(dst, '[')
+	for i, t := range tasks {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendTask(dst, t)
+	}
+	return append(dst, "]}\n"...)
+}
+
+// appendTask appends t to dst as encoding/json encodes it
+func appendTask(dst []byte, t *storages.Task) []byte {
+	if t == nil {
+		return append(dst, "null"...)
+	}
+
+	dst = append(dst, `{"id":`...)
+	dst = appendJSONString(dst, t.ID)
+	dst = append(dst, `,"content":`...)
+	dst = appendJSONString(dst, t.Content)
+	dst = append(dst, `,"user_id":`...)
+	dst = appendJSONString(dst, t.UserID)
+	dst = append(dst, `,"created_date":`...)
+	dst = appendJSONString(dst, t.CreatedDate)
+	dst = append(dst, `,"created_at":`...)
+	dst = appendJSONString(dst, t.CreatedAt)
+	dst = append(dst, `,"priority":`...)
+	dst = strconv.AppendInt(dst, int64(t.Priority), 10)
+	dst = append(dst, `,"due_date":`...)
+	dst = appendJSONString(dst, t.DueDate)
+	dst = append(dst, `,"completed_at":`...)
+	dst = appendJSONString(dst, t.CompletedAt)
+	dst = append(dst, `,"over_quota":`...)
+	dst = strconv.AppendBool(dst, t.OverQuota)
+	dst = append(dst, `,"estimate_minutes":`...)
+	dst = strconv.AppendInt(dst, int64(t.EstimateMinutes), 10)
+	if t.ContentHTML != "" {
+		dst = append(dst, `,"content_html":`...)
+		dst = appendJSONString(dst, t.ContentHTML)
+	}
+	if t.ContentTruncated {
+		dst = append(dst, `,"content_truncated":true`...)
+	}
+	return append(dst, '}')
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends s to dst quoted the way encoding/json quotes it, escaping HTML
+// characters, U+2028, U+2029 and replacing invalid UTF-8
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
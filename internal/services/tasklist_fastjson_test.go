@@ -0,0 +1,60 @@
+//go:build fastjson
+// +build fastjson
+
+package services
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// TestWriteTaskListMatchesEncodingJSON checks the fastjson list path answers what
+// encoding/json would, byte for byte
+func TestWriteTaskListMatchesEncodingJSON(t *testing.T) {
+	contents := []string{
+		"",
+		"plain",
+		`quotes " and \ backslashes`,
+		"<script>alert('&')</script>",
+		"controls \x00\x01\x1f \b\f\n\r\t",
+		"line\u2028and\u2029paragraph separators",
+		"invalid \xff\xfe utf-8 \xc3",
+		"truncated rune \xe2\x82",
+		"unicode: tiếng việt, \U0001F600",
+		"\x7f delete",
+	}
+	var tasks []*storages.Task
+	for i, c := range contents {
+		tasks = append(tasks, &storages.Task{
+			ID: c, Content: c, UserID: "firstUser", CreatedDate: "2020-06-29", Priority: i - 3,
+			OverQuota: i%2 == 0, EstimateMinutes: i * 15, ContentHTML: c, ContentTruncated: i%3 == 0,
+		})
+	}
+
+	lists := map[string][]*storages.Task{
+		"nil":      nil,
+		"empty":    {},
+		"tasks":    tasks,
+		"nil task": {nil},
+	}
+	req := httptest.NewRequest("GET", "/tasks", nil)
+	for name, list := range lists {
+		fast := httptest.NewRecorder()
+		writeTaskList(fast, list)
+		want := httptest.NewRecorder()
+		respond(want, req, 200, map[string][]*storages.Task{"data": list}, nil)
+
+		if !bytes.Equal(fast.Body.Bytes(), want.Body.Bytes()) {
+			t.Errorf("%s: writeTaskList answered\n%s\nencoding/json\n%s", name, fast.Body, want.Body)
+		}
+		if got, want := fast.Header().Get("Content-Type"), want.Header().Get("Content-Type"); got != want {
+			t.Errorf("%s: Content-Type %q, want %q", name, got, want)
+		}
+	}
+	if !fastTaskList {
+		t.Error("fastTaskList is off in a fastjson build")
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/manabie-com/togo/internal/auth"
+	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// benchTasks is a list the size of a busy day
+func benchTasks() []*storages.Task {
+	tasks := make([]*storages.Task, 100)
+	for i := range tasks {
+		tasks[i] = &storages.Task{
+			ID:          "0b8f3f8e-6f7e-4d0c-9c1a-" + strconv.Itoa(100000000000+i),
+			Content:     "write the report for <the> \"quarterly\" review & send it",
+			UserID:      "firstUser",
+			CreatedDate: "2020-06-29",
+			CreatedAt:   "2020-06-29T10:00:00.000000000Z",
+			Priority:    i % 4,
+			DueDate:     "2020-07-01",
+			OverQuota:   i%10 == 0,
+		}
+	}
+	return tasks
+}
+
+func BenchmarkListTasks(b *testing.B) {
+	tasks := benchTasks()
+	req := httptest.NewRequest("GET", "/tasks", nil)
+
+	b.Run("writeTaskList", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			writeTaskList(httptest.NewRecorder(), tasks)
+		}
+	})
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			respond(httptest.NewRecorder(), req, 200, map[string][]*storages.Task{"data": tasks}, nil)
+		}
+	})
+}
+
+// newTestStore opens a migrated SQLite database in a file of its own
+func newTestStore(t *testing.T) *sqllite.LiteDB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "togo.db")+"?_txlock=immediate")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := &sqllite.LiteDB{DB: db}
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return store
+}
+
+// TestConcurrentListTasks lists the tasks of many users at once, with and without fields, so
+// the scan targets of RetrieveTasks and the maps of sparseTaskPool are reused between them.
+// Run it with -race: a response holding another user's task or a field left from an
+// earlier list means something pooled was shared.
+func TestConcurrentListTasks(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	const users, date = 8, "2020-06-29"
+	for u := 0; u < users; u++ {
+		user := &storages.User{ID: "user" + strconv.Itoa(u), Password: "x", MaxTodo: 100}
+		if err := store.AddUser(ctx, user); err != nil {
+			t.Fatalf("AddUser: %v", err)
+		}
+		// lists of different lengths, across the blocks RetrieveTasks allocates
+		var tasks []*storages.Task
+		for i := 0; i < 5+u*7; i++ {
+			tasks = append(tasks, &storages.Task{
+				ID: fmt.Sprintf("%s-%d", user.ID, i), Content: fmt.Sprintf("task %d of %s", i, user.ID),
+				UserID: user.ID, CreatedDate: date, Priority: i % 4,
+			})
+		}
+		if err := store.AddTasks(ctx, tasks); err != nil {
+			t.Fatalf("AddTasks: %v", err)
+		}
+	}
+
+	s := &ToDoService{Store: store}
+	list := func(userID, query string) ([]map[string]interface{}, error) {
+		req := httptest.NewRequest("GET", "/tasks?created_date="+date+query, nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: userID}))
+		resp := httptest.NewRecorder()
+		s.listTasks(resp, req)
+		if resp.Code != 200 {
+			return nil, fmt.Errorf("status %d: %s", resp.Code, resp.Body)
+		}
+		var body struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			return nil, err
+		}
+		return body.Data, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users*4)
+	for w := 0; w < users*4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			u := w % users
+			userID := "user" + strconv.Itoa(u)
+			for i := 0; i < 25; i++ {
+				query, fields := "", 9
+				switch (w + i) % 3 {
+				case 1:
+					query, fields = "&fields=id,content", 2
+				case 2:
+					query, fields = "&fields=id", 1
+				}
+				tasks, err := list(userID, query)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(tasks) != 5+u*7 {
+					errs <- fmt.Errorf("%s%s: got %d tasks, want %d", userID, query, len(tasks), 5+u*7)
+					return
+				}
+				for _, task := range tasks {
+					id, _ := task["id"].(string)
+					if len(task) < fields || (fields < 9 && len(task) != fields) {
+						errs <- fmt.Errorf("%s%s: got fields %v", userID, query, task)
+						return
+					}
+					if !strings.HasPrefix(id, userID+"-") {
+						errs <- fmt.Errorf("%s%s: got task %s of another user", userID, query, id)
+						return
+					}
+					if c, ok := task["content"]; ok && c != "task "+strings.TrimPrefix(id, userID+"-")+" of "+userID {
+						errs <- fmt.Errorf("%s%s: task %s has content %q", userID, query, id, c)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -4,72 +4,187 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
-	"github.com/google/uuid"
-	"github.com/manabie-com/togo/internal/storages"
-	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/api/togov1"
+	"github.com/manabie-com/togo/internal/archive"
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/holidays"
+	"github.com/manabie-com/togo/internal/i18n"
+	"github.com/manabie-com/togo/internal/idgen"
+	"github.com/manabie-com/togo/internal/mail"
+	"github.com/manabie-com/togo/internal/markdown"
+	"github.com/manabie-com/togo/internal/metrics"
+	"github.com/manabie-com/togo/internal/notify"
+	"github.com/manabie-com/togo/internal/password"
+	"github.com/manabie-com/togo/internal/push"
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/internal/secrets"
+	"github.com/manabie-com/togo/internal/signing"
+	"github.com/manabie-com/togo/internal/slo"
+	"github.com/manabie-com/togo/internal/webui"
+	"github.com/manabie-com/togo/pkg/storages"
+	"google.golang.org/protobuf/proto"
 )
 
 // ToDoService implement HTTP server
 type ToDoService struct {
-	JWTKey string
-	Store  *sqllite.LiteDB
+	JWTKey    *secrets.Value
+	Store     storages.Store
+	IDGen     idgen.Generator
+	Passwords *password.Manager
+	// LoginGuard asks for a CAPTCHA after repeated login failures, disabled when nil
+	LoginGuard *LoginGuard
+	// SignupGuard keeps bots from signing up, disabled when nil
+	SignupGuard *SignupGuard
+	// Notifier is told about users reaching their daily limit, disabled when nil
+	Notifier notify.Notifier
+	// Mailer sends the daily digest, disabled when nil
+	Mailer mail.Sender
+	// DigestHour is the local hour from which users get their daily digest
+	DigestHour int
+	// StandupEmails get the team standup report through Mailer, StandupSlack has it posted,
+	// it isn't sent when neither is set. It goes out from StandupHour, server time.
+	StandupEmails []string
+	StandupSlack  *notify.Slack
+	StandupHour   int
+	// Archive receives each day's completed tasks under ArchivePrefix, disabled when nil
+	Archive       archive.ObjectStore
+	ArchivePrefix string
+	// Holidays keeps the dates holiday limits apply on up to date, they stay as stored when nil
+	Holidays holidays.Calendar
+	// APIQuota is how many API calls a user may make per UTC day, 0 only meters them
+	APIQuota int
+	// Anomalies flags users creating tasks much faster than usual, and may throttle them
+	Anomalies *AnomalyPolicy
+	// ResultLimits caps the results and date ranges of list requests
+	ResultLimits ResultLimits
+	// Authenticator accepts the credentials API requests carry, TokenValidator when nil
+	Authenticator auth.Validator
+	// UsersInvite lets every user create invite codes, only administrators can when false
+	UsersInvite bool
+	// DuplicateSimilarity is the trigram similarity, from 0 to 1, from which a new task is
+	// answered with a warning about a task of the same day it looks like, 0 doesn't check
+	DuplicateSimilarity float64
+	// WriteQueue bounds the API requests writing at once, they aren't bounded when nil
+	WriteQueue *WriteQueue
+	// Metrics receives request and SLI metrics, a Prometheus registry served at /metrics when nil
+	Metrics metrics.Backend
+	// SeparateAdmin leaves /metrics, /slo and the /admin/ routes to AdminHandler, for a
+	// listener the public can't reach, and answers 404 for them
+	SeparateAdmin bool
+
+	// keys sign tokens once loaded and a key was rotated in, JWTKey does until then
+	keysMu sync.RWMutex
+	keys   *signing.Ring
+
+	// push reaches the clients connected to GET /me/events
+	push push.Hub
+	// slo counts requests against the service level objectives
+	slo         slo.Recorder
+	metricsOnce sync.Once
+	// markdown renders task content for reads asking for ?render=html
+	markdown markdown.Renderer
+	// hooks run around task creation and completion, see RegisterHook
+	hooks []Hook
+
+	routerOnce sync.Once
+	router     http.Handler
+	adminOnce  sync.Once
+	admin      http.Handler
 }
 
 func (s *ToDoService) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
-	log.Println(req.Method, req.URL.Path)
-	resp.Header().Set("Access-Control-Allow-Origin", "*")
-	resp.Header().Set("Access-Control-Allow-Headers", "*")
-	resp.Header().Set("Access-Control-Allow-Methods", "*")
+	s.routerOnce.Do(func() { s.router = s.newRouter(false) })
+	s.router.ServeHTTP(resp, req)
+}
+
+// AdminHandler serves the routes SeparateAdmin takes out of ServeHTTP
+func (s *ToDoService) AdminHandler() http.Handler {
+	s.adminOnce.Do(func() { s.admin = s.newRouter(true) })
+	return s.admin
+}
+
+var ui = webui.Handler()
 
-	if req.Method == http.MethodOptions {
-		resp.WriteHeader(http.StatusOK)
+// loginRequest is what /login takes, as a JSON body, a POST form or, as it first did, query
+// parameters
+type loginRequest struct {
+	UserID       string `json:"user_id" form:"user_id"`
+	Password     string `json:"password" form:"password"`
+	CaptchaToken string `json:"captcha_token" form:"captcha_token"`
+	// Device names the session, the User-Agent does without it
+	Device string `json:"device" form:"device"`
+}
+
+func (s *ToDoService) getAuthToken(resp http.ResponseWriter, req *http.Request) {
+	var login loginRequest
+	if mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mt == contentTypeJSON {
+		if !decodeBody(resp, req, &login) {
+			return
+		}
+	} else if !decodeQuery(resp, req, &login) {
 		return
 	}
 
-	switch req.URL.Path {
-	case "/login":
-		s.getAuthToken(resp, req)
-		return
-	case "/tasks":
-		var ok bool
-		req, ok = s.validToken(req)
+	ip := clientIP(req)
+	if s.LoginGuard != nil && s.LoginGuard.Required(ip) {
+		ok, err := s.LoginGuard.Verifier.Verify(req.Context(), login.CaptchaToken, ip)
+		if err != nil {
+			requestid.Println(req.Context(), "error verifying captcha", err)
+		}
 		if !ok {
-			resp.WriteHeader(http.StatusUnauthorized)
+			respondError(resp, req, http.StatusUnauthorized, "captcha required")
 			return
 		}
+	}
 
-		switch req.Method {
-		case http.MethodGet:
-			s.listTasks(resp, req)
-		case http.MethodPost:
-			s.addTask(resp, req)
+	id := sql.NullString{String: login.UserID, Valid: true}
+	if !s.validateUser(req.Context(), id, login.Password) {
+		if s.LoginGuard != nil {
+			s.LoginGuard.Fail(ip)
 		}
+		respondError(resp, req, http.StatusUnauthorized, "incorrect user_id/pwd")
 		return
 	}
-}
+	if s.LoginGuard != nil {
+		s.LoginGuard.Reset(ip)
+	}
+	resp.Header().Set("Content-Type", "application/json")
 
-func (s *ToDoService) getAuthToken(resp http.ResponseWriter, req *http.Request) {
-	id := value(req, "user_id")
-	if !s.Store.ValidateUser(req.Context(), id, value(req, "password")) {
-		resp.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(resp).Encode(map[string]string{
-			"error": "incorrect user_id/pwd",
-		})
+	user, err := s.Store.RetrieveUser(req.Context(), id)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if user.DeactivatedAt != "" {
+		respondError(resp, req, http.StatusForbidden, "account deactivated")
 		return
 	}
-	resp.Header().Set("Content-Type", "application/json")
 
-	token, err := s.createToken(id.String)
+	sess, err := s.newSession(req, id.String, login.Device)
 	if err != nil {
-		resp.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(resp).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// no token without a trace of it
+	if err := s.auditAccount(req.Context(), id.String, id.String, storages.AuditLogin, "from "+ip); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := s.createToken(id.String, sess.ID)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -78,7 +193,56 @@ func (s *ToDoService) getAuthToken(resp http.ResponseWriter, req *http.Request)
 	})
 }
 
+// validateUser checks pwd against the stored hash, upgrading hashes made by an
+// outdated algorithm or legacy plain text on the way
+func (s *ToDoService) validateUser(ctx context.Context, userID sql.NullString, pwd string) bool {
+	hash, err := s.Store.RetrievePasswordHash(ctx, userID)
+	if err != nil {
+		// as slow as a wrong password, so timing doesn't tell which user IDs exist
+		return s.Passwords.VerifyUnknown(pwd)
+	}
+
+	ok, rehash := s.Passwords.Verify(hash, pwd)
+	if ok && rehash {
+		if upgraded, err := s.Passwords.Hash(pwd); err != nil {
+			requestid.Println(ctx, "error rehashing password", err)
+		} else if err := s.Store.UpdatePasswordHash(ctx, userID, upgraded); err != nil {
+			requestid.Println(ctx, "error storing rehashed password", err)
+		}
+	}
+	return ok
+}
+
+// listTasksQuery is the query of GET /tasks
+type listTasksQuery struct {
+	CreatedDate string `form:"created_date" validate:"omitempty,date"`
+	// Fields is a comma separated projection, empty selects every field
+	Fields string `form:"fields" validate:"taskfields"`
+	Sort   string `form:"sort" validate:"omitempty,sortkey"`
+	Order  string `form:"order" validate:"omitempty,oneof=asc desc"`
+	// Render=html adds the content rendered from markdown as content_html
+	Render string `form:"render" validate:"omitempty,oneof=html"`
+}
+
+// options returns the storage options q selects
+func (q *listTasksQuery) options() storages.ListOptions {
+	opts := storages.ListOptions{Sort: q.Sort, Desc: q.Order == "desc"}
+	for _, f := range strings.Split(q.Fields, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			opts.Fields = append(opts.Fields, f)
+		}
+	}
+	return opts
+}
+
 func (s *ToDoService) listTasks(resp http.ResponseWriter, req *http.Request) {
+	var q listTasksQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	opts := q.options()
+	opts.Max = s.ResultLimits.MaxPageSize
+
 	id, _ := userIDFromCtx(req.Context())
 	tasks, err := s.Store.RetrieveTasks(
 		req.Context(),
@@ -86,52 +250,370 @@ func (s *ToDoService) listTasks(resp http.ResponseWriter, req *http.Request) {
 			String: id,
 			Valid:  true,
 		},
-		value(req, "created_date"),
+		sql.NullString{String: q.CreatedDate, Valid: true},
+		opts,
 	)
+	if respondResultLimitErr(resp, req, err) {
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	resp.Header().Set("Content-Type", "application/json")
+	if q.Render == "html" {
+		s.renderContent(tasks...)
+	}
+	pb := func() proto.Message {
+		return &togov1.ListTasksResponse{Data: tasksProto(tasks)}
+	}
+
+	if len(opts.Fields) == 0 {
+		if fastTaskList && negotiate(req) == contentTypeJSON {
+			resp.Header().Add("Vary", "Accept")
+			writeTaskList(resp, tasks)
+			return
+		}
+		respond(resp, req, http.StatusOK, map[string][]*storages.Task{
+			"data": tasks,
+		}, pb)
+		return
+	}
+
+	sparse := getSparseTasks(tasks, opts.Fields)
+	defer putSparseTasks(sparse)
+	respond(resp, req, http.StatusOK, map[string][]map[string]interface{}{
+		"data": *sparse,
+	}, pb)
+}
+
+// countTasksQuery is the query of GET /tasks/count
+type countTasksQuery struct {
+	// Date defaults to today
+	Date string `form:"date" validate:"omitempty,date"`
+}
+
+func (s *ToDoService) countTasks(resp http.ResponseWriter, req *http.Request) {
+	var q countTasksQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	if q.Date == "" {
+		q.Date = time.Now().Format("2006-01-02")
+	}
+
+	id, _ := userIDFromCtx(req.Context())
+	count, maxTodo, err := s.Store.CountTasks(
+		req.Context(),
+		sql.NullString{
+			String: id,
+			Valid:  true,
+		},
+		sql.NullString{String: q.Date, Valid: true},
+	)
 
 	if err != nil {
-		resp.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(resp).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	json.NewEncoder(resp).Encode(map[string][]*storages.Task{
-		"data": tasks,
+	remaining := maxTodo - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	respond(resp, req, http.StatusOK, map[string]map[string]int{
+		"data": {
+			"count":     count,
+			"remaining": remaining,
+		},
+	}, func() proto.Message {
+		return &togov1.CountTasksResponse{Data: &togov1.TaskCount{
+			Count:     int32(count),
+			Remaining: int32(remaining),
+		}}
 	})
 }
 
+// addTaskRequest is the body of POST /tasks
+type addTaskRequest struct {
+	Content  string `json:"content" validate:"required"`
+	Priority int    `json:"priority"`
+	DueDate  string `json:"due_date" validate:"omitempty,date"`
+	// EstimateMinutes is optional, up to a week
+	EstimateMinutes int `json:"estimate_minutes" validate:"min=0,max=10080"`
+}
+
+const (
+	// idempotencyHeader lets clients retry POST /tasks and POST /tasks/{id}/duplicate without
+	// adding the task twice
+	idempotencyHeader = "Idempotency-Key"
+	idempotencyTTL    = 24 * time.Hour
+	maxIdempotencyKey = 255
+)
+
 func (s *ToDoService) addTask(resp http.ResponseWriter, req *http.Request) {
-	t := &storages.Task{}
-	err := json.NewDecoder(req.Body).Decode(t)
-	defer req.Body.Close()
+	var body addTaskRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	key, ok := idempotencyKey(resp, req)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	userID, _ := userIDFromCtx(req.Context())
+	s.createTask(resp, req, &storages.Task{
+		ID:              s.IDGen.NewID(),
+		Content:         body.Content,
+		UserID:          userID,
+		CreatedDate:     now.Format("2006-01-02"),
+		CreatedAt:       now.UTC().Format(storages.TimeLayout),
+		Priority:        body.Priority,
+		DueDate:         body.DueDate,
+		EstimateMinutes: body.EstimateMinutes,
+	}, key)
+}
+
+// idempotencyKey returns the key of the Idempotency-Key header, nil without one, answering
+// 400 and false when it's too long
+func idempotencyKey(resp http.ResponseWriter, req *http.Request) (*storages.IdempotencyKey, bool) {
+	k := req.Header.Get(idempotencyHeader)
+	if k == "" {
+		return nil, true
+	}
+	if len(k) > maxIdempotencyKey {
+		msg := i18n.Sprintf(language(req), "%s must be at most %s characters long", idempotencyHeader, strconv.Itoa(maxIdempotencyKey))
+		respondInvalid(resp, req, []fieldError{{Field: idempotencyHeader, Error: msg}}, msg)
+		return nil, false
+	}
+	return &storages.IdempotencyKey{Key: k, ExpiresAt: time.Now().Add(idempotencyTTL).UTC().Format(storages.TimeLayout)}, true
+}
+
+// createTask adds t subject to the daily limit of its date and answers it
+func (s *ToDoService) createTask(resp http.ResponseWriter, req *http.Request, t *storages.Task, key *storages.IdempotencyKey) {
+	if !s.checkCreationThrottle(resp, req, t.UserID) {
+		return
+	}
+
+	err := s.beforeCreate(req.Context(), t)
+	var veto *Veto
+	if errors.As(err, &veto) {
+		if veto.Code != "" {
+			respondCodedError(resp, req, http.StatusUnprocessableEntity, veto.Code, veto.Reason)
+			return
+		}
+		respondError(resp, req, http.StatusUnprocessableEntity, veto.Reason)
+		return
+	}
+	if err != nil {
+		// hook errors can carry details of the policies they enforce
+		requestid.Println(req.Context(), "error running task hooks", err)
+		respondError(resp, req, http.StatusInternalServerError, "error checking the task")
+		return
+	}
+
+	count, maxTodo, err := s.Store.AddTaskWithLimitPerDay(req.Context(), t, key)
+	var limitErr *storages.TaskLimitReached
+	if errors.As(err, &limitErr) {
+		go s.limitReached(requestid.FromContext(req.Context()), limitErr.UserID, limitErr.Date)
+		respondError(resp, req, http.StatusForbidden, "daily task limit reached")
+		return
+	}
+	// a retry answers the task the first attempt added
+	var dup *storages.DuplicateRequest
+	var warnings []taskWarning
+	switch {
+	case errors.As(err, &dup):
+		t, err = s.Store.RetrieveTask(req.Context(), sql.NullString{String: t.UserID, Valid: true}, sql.NullString{String: dup.TaskID, Valid: true})
+		if errors.Is(err, storages.ErrNotFound) {
+			respondError(resp, req, http.StatusConflict, "the task added with this idempotency key was deleted")
+			return
+		}
+		if err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+	case err != nil:
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	default:
+		if t.OverQuota {
+			go s.limitReached(requestid.FromContext(req.Context()), t.UserID, t.CreatedDate)
+		}
+		s.pushQuota(t, count, maxTodo)
+		s.afterCreate(req.Context(), t)
+		warnings = s.duplicateWarnings(req, t)
+	}
+
+	respond(resp, req, http.StatusOK, &addTaskResponse{Data: t, Warnings: warnings}, func() proto.Message {
+		return &togov1.AddTaskResponse{Data: taskProto(t), Warnings: warningsProto(warnings)}
+	})
+}
+
+// addTaskResponse answers the endpoints adding a task
+type addTaskResponse struct {
+	Data     *storages.Task `json:"data"`
+	Warnings []taskWarning  `json:"warnings,omitempty"`
+}
+
+// duplicateTaskQuery is the query of POST /tasks/{id}/duplicate
+type duplicateTaskQuery struct {
+	Date string `form:"date" validate:"omitempty,date"`
+}
+
+// duplicateTask adds a copy of a task's content, priority, estimate and labels on the date given
+// by the date parameter, today by default, subject to that day's limit
+func (s *ToDoService) duplicateTask(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var q duplicateTaskQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	key, ok := idempotencyKey(resp, req)
+	if !ok {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	orig, err := s.Store.RetrieveTask(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
 	if err != nil {
-		resp.WriteHeader(http.StatusInternalServerError)
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	now := time.Now()
+	if q.Date == "" {
+		q.Date = now.Format("2006-01-02")
+	}
+	s.createTask(resp, req, &storages.Task{
+		ID:              s.IDGen.NewID(),
+		Content:         orig.Content,
+		UserID:          userID,
+		CreatedDate:     q.Date,
+		CreatedAt:       now.UTC().Format(storages.TimeLayout),
+		Priority:        orig.Priority,
+		EstimateMinutes: orig.EstimateMinutes,
+		LabelsFrom:      orig.ID,
+	}, key)
+}
+
+// snoozeTaskQuery is the query of POST /tasks/{id}/snooze
+type snoozeTaskQuery struct {
+	To string `form:"to" validate:"required,date"`
+}
+
+// snoozeTask moves a task to the date given by the to parameter, subject to that day's limit
+func (s *ToDoService) snoozeTask(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var q snoozeTaskQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+
 	userID, _ := userIDFromCtx(req.Context())
-	t.ID = uuid.New().String()
-	t.UserID = userID
-	t.CreatedDate = now.Format("2006-01-02")
+	t, err := s.Store.MoveTask(req.Context(), &storages.TaskMove{
+		TaskID: taskID,
+		UserID: userID,
+		ToDate: q.To,
+		Action: "snooze",
+		At:     time.Now().UTC().Format(storages.TimeLayout),
+	})
+	var limitErr *storages.TaskLimitReached
+	if errors.As(err, &limitErr) {
+		go s.limitReached(requestid.FromContext(req.Context()), limitErr.UserID, limitErr.Date)
+		respondError(resp, req, http.StatusForbidden, "daily task limit reached")
+		return
+	}
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	resp.Header().Set("Content-Type", "application/json")
+	respond(resp, req, http.StatusOK, map[string]*storages.Task{
+		"data": t,
+	}, func() proto.Message {
+		return &togov1.TaskResponse{Data: taskProto(t)}
+	})
+}
+
+// getTaskQuery is the query of GET /tasks/{id}
+type getTaskQuery struct {
+	// Render=html adds the content rendered from markdown as content_html
+	Render string `form:"render" validate:"omitempty,oneof=html"`
+}
+
+// getTask answers one of the caller's tasks
+func (s *ToDoService) getTask(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var q getTaskQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	t, err := s.Store.RetrieveTask(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if q.Render == "html" {
+		s.renderContent(t)
+	}
+
+	respond(resp, req, http.StatusOK, map[string]*storages.Task{
+		"data": t,
+	}, func() proto.Message {
+		return &togov1.TaskResponse{Data: taskProto(t)}
+	})
+}
+
+// renderContent sets the ContentHTML of tasks
+func (s *ToDoService) renderContent(tasks ...*storages.Task) {
+	for _, t := range tasks {
+		t.ContentHTML = s.markdown.Render(t.Content)
+	}
+}
 
-	err = s.Store.AddTask(req.Context(), t)
+// completeTask marks a task as done, leaving it out of carry overs
+func (s *ToDoService) completeTask(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	t, err := s.Store.CompleteTask(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
 	if err != nil {
-		resp.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(resp).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
 		return
 	}
+	s.afterComplete(req.Context(), t)
 
-	json.NewEncoder(resp).Encode(map[string]*storages.Task{
+	respond(resp, req, http.StatusOK, map[string]*storages.Task{
 		"data": t,
+	}, func() proto.Message {
+		return &togov1.TaskResponse{Data: taskProto(t)}
 	})
 }
 
@@ -142,47 +624,90 @@ func value(req *http.Request, p string) sql.NullString {
 	}
 }
 
-func (s *ToDoService) createToken(id string) (string, error) {
+func (s *ToDoService) createToken(id, sessionID string) (string, error) {
 	atClaims := jwt.MapClaims{}
 	atClaims["user_id"] = id
-	atClaims["exp"] = time.Now().Add(time.Minute * 15).Unix()
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-	token, err := at.SignedString([]byte(s.JWTKey))
+	atClaims["sid"] = sessionID
+	atClaims["exp"] = time.Now().Add(sessionTTL).Unix()
+	return s.signToken(atClaims)
+}
+
+// signToken signs atClaims with the current signing key, or the shared JWT key without one
+func (s *ToDoService) signToken(atClaims jwt.MapClaims) (string, error) {
+	var token string
+	var err error
+	if kid, key, ok := s.signingKeys().Signer(); ok {
+		at := jwt.NewWithClaims(jwt.SigningMethodES256, atClaims)
+		at.Header["kid"] = kid
+		token, err = at.SignedString(key)
+	} else {
+		at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
+		token, err = at.SignedString([]byte(s.JWTKey.Get()))
+	}
 	if err != nil {
 		return "", err
 	}
 	return token, nil
 }
 
-func (s *ToDoService) validToken(req *http.Request) (*http.Request, bool) {
-	token := req.Header.Get("Authorization")
+// TokenValidator accepts the tokens issued by getAuthToken
+func (s *ToDoService) TokenValidator() auth.Validator {
+	return &auth.JWT{Key: s.tokenKey, Sessions: s.Store}
+}
 
-	claims := make(jwt.MapClaims)
-	t, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
-		return []byte(s.JWTKey), nil
-	})
-	if err != nil {
-		log.Println(err)
-		return req, false
+func (s *ToDoService) authenticator() auth.Validator {
+	if s.Authenticator != nil {
+		return s.Authenticator
 	}
+	return s.TokenValidator()
+}
 
-	if !t.Valid {
-		return req, false
+// tokenKey returns the key verifying t
+func (s *ToDoService) tokenKey(t *jwt.Token) (interface{}, error) {
+	// tokens from before keys were rotated in have no kid and are signed with JWTKey
+	kid, ok := t.Header["kid"].(string)
+	if !ok {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+		}
+		// once keys sign, only the tokens issued before every server loaded the first one are
+		// accepted, until they expire, so a leaked or default JWTKey can't sign new ones
+		if since, ok := s.signingKeys().SigningSince(); ok && !issuedBefore(t, since.Add(KeyRefresh)) {
+			return nil, errors.New("tokens without a kid are no longer accepted")
+		}
+		return []byte(s.JWTKey.Get()), nil
+	}
+	if t.Method != jwt.SigningMethodES256 {
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
 	}
+	return s.signingKeys().Verifier(kid)
+}
 
-	id, ok := claims["user_id"].(string)
+// issuedBefore reports whether t, expiring sessionTTL after it was issued, was issued by then
+func issuedBefore(t *jwt.Token, then time.Time) bool {
+	claims, ok := t.Claims.(jwt.MapClaims)
 	if !ok {
-		return req, false
+		return false
 	}
-
-	req = req.WithContext(context.WithValue(req.Context(), userAuthKey(0), id))
-	return req, true
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return false
+	}
+	return !time.Unix(int64(exp), 0).After(then.Add(sessionTTL))
 }
 
-type userAuthKey int8
-
 func userIDFromCtx(ctx context.Context) (string, bool) {
-	v := ctx.Value(userAuthKey(0))
-	id, ok := v.(string)
-	return id, ok
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return p.UserID, true
+}
+
+func sessionIDFromCtx(ctx context.Context) (string, bool) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.SessionID == "" {
+		return "", false
+	}
+	return p.SessionID, true
 }
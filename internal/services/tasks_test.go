@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/idgen"
+	"github.com/manabie-com/togo/internal/mocks"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// mockRequest is a request of userID as authenticate leaves it
+func mockRequest(userID, method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: userID}))
+}
+
+// unthrottled answers that userID has no creation throttle, as for most users
+func unthrottled(ctx context.Context, userID sql.NullString, now string) (*storages.CreationThrottle, error) {
+	return nil, storages.ErrNotFound
+}
+
+func TestAddTask(t *testing.T) {
+	hits := make(chan string, 1)
+	tests := []struct {
+		name     string
+		add      func(ctx context.Context, task *storages.Task, key *storages.IdempotencyKey) (int, int, error)
+		wantCode int
+		wantHit  bool
+	}{
+		{"added", func(ctx context.Context, task *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+			return 1, 5, nil
+		}, http.StatusOK, false},
+		{"limit reached", func(ctx context.Context, task *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+			return 0, 0, &storages.TaskLimitReached{UserID: task.UserID, Date: task.CreatedDate}
+		}, http.StatusForbidden, true},
+		{"store failing", func(ctx context.Context, task *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+			return 0, 0, errors.New("disk I/O error")
+		}, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mocks.StoreMock{
+				RetrieveCreationThrottleFunc: unthrottled,
+				AddTaskWithLimitPerDayFunc:   tt.add,
+				RecordLimitHitFunc: func(ctx context.Context, userID, date sql.NullString, at string) error {
+					hits <- userID.String + " " + date.String
+					return nil
+				},
+			}
+			s := &ToDoService{Store: store, IDGen: &mocks.GeneratorMock{NewIDFunc: func() string { return "task-1" }}}
+
+			resp := httptest.NewRecorder()
+			s.addTask(resp, mockRequest("firstUser", "POST", "/tasks", `{"content": "buy milk", "priority": 2}`))
+			if resp.Code != tt.wantCode {
+				t.Fatalf("got status %d: %s, want %d", resp.Code, resp.Body, tt.wantCode)
+			}
+
+			calls := store.AddTaskWithLimitPerDayCalls()
+			if len(calls) != 1 {
+				t.Fatalf("AddTaskWithLimitPerDay called %d times, want once", len(calls))
+			}
+			task := calls[0].T
+			today := time.Now().Format("2006-01-02")
+			if task.ID != "task-1" || task.UserID != "firstUser" || task.Content != "buy milk" || task.Priority != 2 || task.CreatedDate != today {
+				t.Errorf("stored %+v", task)
+			}
+			if calls[0].Key != nil {
+				t.Errorf("stored with idempotency key %+v, want none", calls[0].Key)
+			}
+
+			if !tt.wantHit {
+				return
+			}
+			if !strings.Contains(resp.Body.String(), "daily task limit reached") {
+				t.Errorf("got body %s, want the limit error", resp.Body)
+			}
+			select {
+			case hit := <-hits:
+				if hit != "firstUser "+today {
+					t.Errorf("recorded a limit hit of %s", hit)
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("no limit hit recorded")
+			}
+		})
+	}
+}
+
+func TestListTasks(t *testing.T) {
+	tests := []struct {
+		query string
+		want  storages.ListOptions
+	}{
+		{"", storages.ListOptions{Max: 50}},
+		{"&fields=id,%20content&sort=priority&order=desc", storages.ListOptions{Fields: []string{"id", "content"}, Sort: "priority", Desc: true, Max: 50}},
+		{"&sort=created_at&order=asc", storages.ListOptions{Sort: "created_at", Max: 50}},
+	}
+	for _, tt := range tests {
+		var gotUser, gotDate string
+		var got storages.ListOptions
+		store := &mocks.StoreMock{
+			RetrieveTasksFunc: func(ctx context.Context, userID, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+				gotUser, gotDate, got = userID.String, createdDate.String, opts
+				return []*storages.Task{{ID: "task-1", Content: "buy milk", UserID: userID.String, CreatedDate: createdDate.String}}, nil
+			},
+		}
+		s := &ToDoService{Store: store, ResultLimits: ResultLimits{MaxPageSize: 50}}
+
+		resp := httptest.NewRecorder()
+		s.listTasks(resp, mockRequest("firstUser", "GET", "/tasks?created_date=2020-06-29"+tt.query, ""))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%q: got status %d: %s", tt.query, resp.Code, resp.Body)
+		}
+		if gotUser != "firstUser" || gotDate != "2020-06-29" || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: RetrieveTasks(%s, %s, %+v), want firstUser, 2020-06-29, %+v", tt.query, gotUser, gotDate, got, tt.want)
+		}
+		var body struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || len(body.Data) != 1 || body.Data[0]["id"] != "task-1" {
+			t.Errorf("%q: got body %s", tt.query, resp.Body)
+		}
+	}
+
+	// the store refusing a page beyond the limit answers 422
+	store := &mocks.StoreMock{
+		RetrieveTasksFunc: func(ctx context.Context, userID, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+			return nil, &storages.ResultLimitExceeded{What: "tasks", Max: opts.Max}
+		},
+	}
+	resp := httptest.NewRecorder()
+	(&ToDoService{Store: store}).listTasks(resp, mockRequest("firstUser", "GET", "/tasks?created_date=2020-06-29", ""))
+	if resp.Code != http.StatusUnprocessableEntity || !strings.Contains(resp.Body.String(), "result_limit_exceeded") {
+		t.Errorf("got status %d: %s, want 422 result_limit_exceeded", resp.Code, resp.Body)
+	}
+
+	// a query that doesn't validate never reaches the store
+	store = &mocks.StoreMock{}
+	resp = httptest.NewRecorder()
+	(&ToDoService{Store: store}).listTasks(resp, mockRequest("firstUser", "GET", "/tasks?created_date=yesterday", ""))
+	if resp.Code != http.StatusBadRequest || len(store.RetrieveTasksCalls()) != 0 {
+		t.Errorf("got status %d after %d calls, want 400 without any", resp.Code, len(store.RetrieveTasksCalls()))
+	}
+}
+
+func TestDuplicateTaskLabels(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	if err := store.AddUser(ctx, &storages.User{ID: "firstUser", Password: "x", MaxTodo: 5}); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	orig := &storages.Task{ID: "orig", Content: "water the plants", UserID: "firstUser", CreatedDate: "2020-06-29", CreatedAt: at, Priority: 1}
+	if err := store.AddTask(ctx, orig); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	user := sql.NullString{String: "firstUser", Valid: true}
+	for _, id := range []string{"home", "weekly"} {
+		if err := store.AddLabel(ctx, &storages.Label{ID: id, UserID: "firstUser", Name: id, Color: "#fff", CreatedAt: at}); err != nil {
+			t.Fatalf("AddLabel: %v", err)
+		}
+		if err := store.AssignLabel(ctx, user, sql.NullString{String: "orig", Valid: true}, sql.NullString{String: id, Valid: true}, at); err != nil {
+			t.Fatalf("AssignLabel: %v", err)
+		}
+	}
+
+	s := &ToDoService{Store: store, IDGen: idgen.UUIDv7{}}
+	var copyID string
+	// the retry answers the first copy, which has its labels once
+	for i := 0; i < 2; i++ {
+		req := mockRequest("firstUser", "POST", "/tasks/orig/duplicate?date=2020-06-30", "")
+		req.Header.Set(idempotencyHeader, "duplicate-once")
+		resp := httptest.NewRecorder()
+		s.duplicateTask(resp, req, "orig")
+		if resp.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", resp.Code, resp.Body)
+		}
+		var body addTaskResponse
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if copyID != "" && body.Data.ID != copyID {
+			t.Errorf("retry answered %s, want %s", body.Data.ID, copyID)
+		}
+		copyID = body.Data.ID
+	}
+
+	labels, err := store.RetrieveTaskLabels(ctx, user, sql.NullString{String: copyID, Valid: true})
+	if err != nil {
+		t.Fatalf("RetrieveTaskLabels: %v", err)
+	}
+	if len(labels) != 2 || labels[0].ID != "home" || labels[1].ID != "weekly" {
+		t.Errorf("duplicate has labels %+v, want home and weekly", labels)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// estimateRequest is the body of PUT /tasks/{id}/estimate
+type estimateRequest struct {
+	// Minutes is 0 to remove the estimate
+	Minutes *int `json:"minutes" validate:"required,min=0,max=10080"`
+}
+
+// startTimerResponse is the body answered for POST /tasks/{id}/timer/start
+type startTimerResponse struct {
+	Data *storages.TimeEntry `json:"data"`
+	// Stopped is the entry of the timer that ran on another task, null when none did
+	Stopped *storages.TimeEntry `json:"stopped"`
+}
+
+// timeEntriesResponse is the body answered for GET /tasks/{id}/time
+type timeEntriesResponse struct {
+	Data []*storages.TimeEntry `json:"data"`
+	// TotalSeconds adds up the entries stopped
+	TotalSeconds int `json:"total_seconds"`
+}
+
+// setTaskEstimate sets how long a task is expected to take
+func (s *ToDoService) setTaskEstimate(resp http.ResponseWriter, req *http.Request, taskID string) {
+	var body estimateRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+
+	userID, _ := userIDFromCtx(req.Context())
+	err := s.Store.SetTaskEstimate(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		*body.Minutes,
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]map[string]int{
+		"data": {"estimate_minutes": *body.Minutes},
+	})
+}
+
+// startTimer starts tracking time on a task, stopping the caller's timer running on
+// another one, and answers 409 when the task's runs already
+func (s *ToDoService) startTimer(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	e := &storages.TimeEntry{
+		ID:        s.IDGen.NewID(),
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now().UTC().Format(storages.TimeLayout),
+	}
+	stopped, err := s.Store.StartTimer(req.Context(), e)
+	if errors.Is(err, storages.ErrTimerRunning) {
+		respondError(resp, req, http.StatusConflict, "the task's timer is running already")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	json.NewEncoder(resp).Encode(startTimerResponse{Data: e, Stopped: stopped})
+}
+
+// stopTimer stops the timer of a task, answering the time entry it tracked
+func (s *ToDoService) stopTimer(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	e, err := s.Store.StopTimer(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+		time.Now().UTC().Format(storages.TimeLayout),
+	)
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "the task's timer isn't running")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.TimeEntry{
+		"data": e,
+	})
+}
+
+// listTimeEntries answers the time tracked on a task, oldest first
+func (s *ToDoService) listTimeEntries(resp http.ResponseWriter, req *http.Request, taskID string) {
+	userID, _ := userIDFromCtx(req.Context())
+	entries, err := s.Store.RetrieveTimeEntries(req.Context(),
+		sql.NullString{String: userID, Valid: true},
+		sql.NullString{String: taskID, Valid: true},
+	)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	body := timeEntriesResponse{Data: entries}
+	for _, e := range entries {
+		body.TotalSeconds += e.Seconds
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(body)
+}
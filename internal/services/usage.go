@@ -0,0 +1,82 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manabie-com/togo/internal/requestid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// meter counts the authenticated user's API calls per UTC day. With an APIQuota it
+// answers how many are left in X-RateLimit-* headers and refuses calls beyond it.
+func (s *ToDoService) meter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		user := req.Context().Value(userKey{}).(*storages.User)
+		now := time.Now().UTC()
+		calls, err := s.Store.IncrementAPIUsage(req.Context(), sql.NullString{String: user.ID, Valid: true}, now.Format("2006-01-02"))
+		if err != nil {
+			// a metering failure shouldn't take the API down
+			requestid.Println(req.Context(), "error metering API call of", user.ID, err)
+			next.ServeHTTP(resp, req)
+			return
+		}
+		if s.APIQuota <= 0 {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		reset := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		remaining := s.APIQuota - calls
+		if remaining < 0 {
+			remaining = 0
+		}
+		resp.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.APIQuota))
+		resp.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		resp.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if calls > s.APIQuota {
+			resp.Header().Set("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			respondError(resp, req, http.StatusTooManyRequests, "daily API quota exceeded")
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// usageQuery is the query of GET /admin/users/{id}/usage
+type usageQuery struct {
+	From string `form:"from" validate:"omitempty,date"`
+	To   string `form:"to" validate:"omitempty,date"`
+}
+
+// userUsage answers the API calls of a user per UTC day, over the last 30 days by default
+func (s *ToDoService) userUsage(resp http.ResponseWriter, req *http.Request, id string) {
+	var q usageQuery
+	if !decodeQuery(resp, req, &q) {
+		return
+	}
+	now := time.Now().UTC()
+	if q.To == "" {
+		q.To = now.Format("2006-01-02")
+	}
+	if q.From == "" {
+		q.From = now.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+	if !s.checkDateRange(resp, req, q.From, q.To) {
+		return
+	}
+
+	usage, err := s.Store.RetrieveAPIUsage(req.Context(), sql.NullString{String: id, Valid: true}, q.From, q.To)
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string][]*storages.APIUsage{
+		"data": usage,
+	})
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// getUser shows a user to administrators
+func (s *ToDoService) getUser(resp http.ResponseWriter, req *http.Request, id string) {
+	u, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.User{
+		"data": u,
+	})
+}
+
+// updateUserRequest is the body of PUT /admin/users/{id}, fields left out are kept
+type updateUserRequest struct {
+	MaxTodo *int    `json:"max_todo" validate:"omitempty,min=0"`
+	Role    *string `json:"role" validate:"omitempty,oneof='' admin"`
+}
+
+// updateUser changes the max_todo and role present in the body and keeps the others
+func (s *ToDoService) updateUser(resp http.ResponseWriter, req *http.Request, id string) {
+	u, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var body updateUserRequest
+	if !decodeBody(resp, req, &body) {
+		return
+	}
+	maxTodo := u.MaxTodo
+	if body.MaxTodo != nil {
+		u.MaxTodo = *body.MaxTodo
+	}
+	if body.Role != nil {
+		u.Role = *body.Role
+	}
+
+	if err := s.Store.UpdateUser(req.Context(), u); err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if u.MaxTodo != maxTodo {
+		admin := req.Context().Value(userKey{}).(*storages.User)
+		s.auditLimitChange(req, admin.ID, id, fmt.Sprintf("max_todo %d to %d", maxTodo, u.MaxTodo))
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.User{
+		"data": u,
+	})
+}
+
+// deactivateUser stops a user from signing in or using the API, keeping their data
+func (s *ToDoService) deactivateUser(resp http.ResponseWriter, req *http.Request, id string) {
+	admin := req.Context().Value(userKey{}).(*storages.User)
+	if id == admin.ID {
+		respondError(resp, req, http.StatusConflict, "administrators can't deactivate themselves")
+		return
+	}
+	s.setDeactivatedAt(resp, req, id, time.Now().UTC().Format(storages.TimeLayout))
+}
+
+// reactivateUser lets a deactivated user sign in again
+func (s *ToDoService) reactivateUser(resp http.ResponseWriter, req *http.Request, id string) {
+	s.setDeactivatedAt(resp, req, id, "")
+}
+
+// setDeactivatedAt deactivates a user at the time at, or reactivates them when at is empty,
+// keeping the time of a deactivation that already happened
+func (s *ToDoService) setDeactivatedAt(resp http.ResponseWriter, req *http.Request, id, at string) {
+	u, err := s.Store.RetrieveUser(req.Context(), sql.NullString{String: id, Valid: true})
+	if errors.Is(err, storages.ErrNotFound) {
+		respondError(resp, req, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		respondError(resp, req, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if (u.DeactivatedAt == "") != (at == "") {
+		u.DeactivatedAt = at
+		if err := s.Store.UpdateUser(req.Context(), u); err != nil {
+			respondError(resp, req, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(map[string]*storages.User{
+		"data": u,
+	})
+}
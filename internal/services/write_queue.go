@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/http"
+	"sync"
+)
+
+// WriteQueue bounds the requests writing to storage at once to Concurrency, letting up to
+// Depth more wait for their turn. Requests beyond that are refused with a 429, so a spike
+// is turned away early instead of piling up on the database.
+type WriteQueue struct {
+	Concurrency int
+	Depth       int
+
+	once    sync.Once
+	running chan struct{}
+	waiting chan struct{}
+}
+
+func (q *WriteQueue) init() {
+	q.running = make(chan struct{}, q.Concurrency)
+	q.waiting = make(chan struct{}, q.Concurrency+q.Depth)
+}
+
+// Acquire waits for a turn to write until done is closed, reporting false when the queue is
+// full or done was closed first. A true return must be followed by a Release.
+func (q *WriteQueue) Acquire(done <-chan struct{}) bool {
+	q.once.Do(q.init)
+	select {
+	case q.waiting <- struct{}{}:
+	default:
+		return false
+	}
+	select {
+	case q.running <- struct{}{}:
+		return true
+	case <-done:
+		<-q.waiting
+		return false
+	}
+}
+
+// Release ends a turn given by Acquire
+func (q *WriteQueue) Release() {
+	<-q.running
+	<-q.waiting
+}
+
+// queueWrites holds authenticated requests that may write, anything but GET and HEAD, in the
+// WriteQueue
+func (s *ToDoService) queueWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if s.WriteQueue == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		if !s.WriteQueue.Acquire(req.Context().Done()) {
+			resp.Header().Set("Retry-After", "1")
+			respondError(resp, req, http.StatusTooManyRequests, "too many writes in progress, try again shortly")
+			return
+		}
+		defer s.WriteQueue.Release()
+		next.ServeHTTP(resp, req)
+	})
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/mocks"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// bearerUser accepts Authorization: Bearer <user ID>
+type bearerUser struct{}
+
+func (bearerUser) Validate(req *http.Request) (*auth.Principal, error) {
+	id := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if id == "" || id == req.Header.Get("Authorization") {
+		return nil, auth.ErrNoCredentials
+	}
+	return &auth.Principal{UserID: id}, nil
+}
+
+func TestQueueWrites(t *testing.T) {
+	store := &mocks.StoreMock{
+		RetrieveUserFunc: func(ctx context.Context, userID sql.NullString) (*storages.User, error) {
+			return &storages.User{ID: userID.String, MaxTodo: 5}, nil
+		},
+		IncrementAPIUsageFunc: func(ctx context.Context, userID sql.NullString, date string) (int, error) {
+			return 1, nil
+		},
+		RetrieveCreationThrottleFunc: unthrottled,
+		AddTaskWithLimitPerDayFunc: func(ctx context.Context, task *storages.Task, key *storages.IdempotencyKey) (int, int, error) {
+			return 1, 5, nil
+		},
+	}
+	queue := &WriteQueue{Concurrency: 1}
+	s := &ToDoService{
+		Store:         store,
+		Authenticator: bearerUser{},
+		WriteQueue:    queue,
+		IDGen:         &mocks.GeneratorMock{NewIDFunc: func() string { return "id-1" }},
+	}
+	post := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/tasks", strings.NewReader(`{"content": "buy milk"}`))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// a write holds the only turn
+	if !queue.Acquire(nil) {
+		t.Fatal("Acquire of an empty queue failed")
+	}
+	resp := post("Bearer firstUser")
+	if resp.Code != http.StatusTooManyRequests || resp.Header().Get("Retry-After") != "1" {
+		t.Errorf("write with the queue full: got status %d, Retry-After %q: %s, want 429 and 1", resp.Code, resp.Header().Get("Retry-After"), resp.Body)
+	}
+	if calls := store.IncrementAPIUsageCalls(); len(calls) != 0 {
+		t.Errorf("refused write metered %d times, want none", len(calls))
+	}
+	// unauthenticated requests are refused before they'd wait for a turn
+	if resp := post(""); resp.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated write with the queue full: got status %d: %s, want 401", resp.Code, resp.Body)
+	}
+	// reads don't queue
+	req := httptest.NewRequest("GET", "/tasks?created_date=2020-06-29", nil)
+	req.Header.Set("Authorization", "Bearer firstUser")
+	store.RetrieveTasksFunc = func(ctx context.Context, userID, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+		return nil, nil
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("read with the queue full: got status %d: %s, want 200", rec.Code, rec.Body)
+	}
+
+	queue.Release()
+	if resp := post("Bearer firstUser"); resp.Code != http.StatusOK {
+		t.Errorf("write with a turn free: got status %d: %s, want 200", resp.Code, resp.Body)
+	}
+	// the write released its turn
+	if !queue.Acquire(nil) {
+		t.Error("Acquire after the write failed")
+	}
+	queue.Release()
+}
@@ -0,0 +1,154 @@
+// Package signing manages the ES256 key pairs auth tokens are signed with and
+// publishes their public halves as a JSON Web Key Set
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// Algorithm is the JWS algorithm of generated keys
+const Algorithm = "ES256"
+
+// Generate returns a new key created at now, its ID is the RFC 7638 thumbprint of the public key
+func Generate(now time.Time) (*storages.SigningKey, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storages.SigningKey{
+		ID:         thumbprint(&priv.PublicKey),
+		Algorithm:  Algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})),
+		CreatedAt:  now.UTC().Format(storages.TimeLayout),
+	}, nil
+}
+
+// JWK is the public half of a key in JSON Web Key form
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Ring holds the keys that are currently valid
+type Ring struct {
+	signer   *ecdsa.PrivateKey
+	signerID string
+	// oldest is when the oldest of the keys was created
+	oldest    time.Time
+	verifiers map[string]*ecdsa.PublicKey
+	jwks      JWKS
+}
+
+// NewRing parses keys, the first unretired one signs and all of them verify
+func NewRing(keys []*storages.SigningKey) (*Ring, error) {
+	r := &Ring{verifiers: map[string]*ecdsa.PublicKey{}, jwks: JWKS{Keys: []JWK{}}}
+	for _, k := range keys {
+		if k.Algorithm != Algorithm {
+			return nil, fmt.Errorf("signing key %s: unsupported algorithm %s", k.ID, k.Algorithm)
+		}
+		block, _ := pem.Decode([]byte(k.PrivateKey))
+		if block == nil {
+			return nil, fmt.Errorf("signing key %s: no PEM data", k.ID)
+		}
+		priv, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", k.ID, err)
+		}
+
+		if r.signer == nil && k.RetiredAt == "" {
+			r.signer, r.signerID = priv, k.ID
+		}
+		if created, err := time.Parse(storages.TimeLayout, k.CreatedAt); err == nil && (r.oldest.IsZero() || created.Before(r.oldest)) {
+			r.oldest = created
+		}
+		r.verifiers[k.ID] = &priv.PublicKey
+		r.jwks.Keys = append(r.jwks.Keys, jwk(k.ID, &priv.PublicKey))
+	}
+	return r, nil
+}
+
+// Signer returns the key new tokens are signed with, ok is false when there's none
+func (r *Ring) Signer() (kid string, key *ecdsa.PrivateKey, ok bool) {
+	if r == nil || r.signer == nil {
+		return "", nil, false
+	}
+	return r.signerID, r.signer, true
+}
+
+// SigningSince returns when the oldest key was created, ok is false without a Signer
+func (r *Ring) SigningSince() (since time.Time, ok bool) {
+	if r == nil || r.signer == nil {
+		return time.Time{}, false
+	}
+	return r.oldest, true
+}
+
+// Verifier returns the public key with ID kid
+func (r *Ring) Verifier(kid string) (*ecdsa.PublicKey, error) {
+	if r != nil {
+		if k, ok := r.verifiers[kid]; ok {
+			return k, nil
+		}
+	}
+	return nil, errors.New("signing: unknown key " + kid)
+}
+
+// JWKS returns the public keys
+func (r *Ring) JWKS() JWKS {
+	if r == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return r.jwks
+}
+
+func jwk(kid string, pub *ecdsa.PublicKey) JWK {
+	return JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   coordinate(pub.X.Bytes()),
+		Y:   coordinate(pub.Y.Bytes()),
+		Kid: kid,
+		Use: "sig",
+		Alg: Algorithm,
+	}
+}
+
+// coordinate encodes a P-256 coordinate padded to 32 bytes as RFC 7518 requires
+func coordinate(b []byte) string {
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return base64.RawURLEncoding.EncodeToString(padded)
+}
+
+func thumbprint(pub *ecdsa.PublicKey) string {
+	// members in lexicographic order without whitespace, per RFC 7638
+	doc := `{"crv":"P-256","kty":"EC","x":"` + coordinate(pub.X.Bytes()) + `","y":"` + coordinate(pub.Y.Bytes()) + `"}`
+	sum := sha256.Sum256([]byte(doc))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package signing
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+func TestRing(t *testing.T) {
+	now := time.Now()
+	var keys []*storages.SigningKey
+	for i := 0; i < 3; i++ {
+		k, err := Generate(now.Add(-time.Duration(i) * time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+	// newest first, as the store returns them, with the newest retired
+	keys[0].RetiredAt = now.UTC().Format(storages.TimeLayout)
+	r, err := NewRing(keys)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+
+	if kid, _, ok := r.Signer(); !ok || kid != keys[1].ID {
+		t.Errorf("Signer = %s, %v, want the first unretired key %s", kid, ok, keys[1].ID)
+	}
+	if since, ok := r.SigningSince(); !ok || since.Format(storages.TimeLayout) != keys[2].CreatedAt {
+		t.Errorf("SigningSince = %v, %v, want when the oldest key was created", since, ok)
+	}
+	for _, k := range keys {
+		if _, err := r.Verifier(k.ID); err != nil {
+			t.Errorf("Verifier(%s): %v", k.ID, err)
+		}
+	}
+	if _, err := r.Verifier("nope"); err == nil {
+		t.Error("Verifier of an unknown kid succeeded")
+	}
+
+	jwks := r.JWKS()
+	if len(jwks.Keys) != 3 {
+		t.Fatalf("JWKS has %d keys, want 3", len(jwks.Keys))
+	}
+	for i, k := range jwks.Keys {
+		// the kid is the RFC 7638 thumbprint of the JWK
+		doc, _ := json.Marshal(map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y})
+		sum := sha256.Sum256(doc)
+		if k.Kid != keys[i].ID || k.Kid != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			t.Errorf("JWK %d has kid %s, want the thumbprint %s", i, k.Kid, keys[i].ID)
+		}
+		if x, _ := base64.RawURLEncoding.DecodeString(k.X); len(x) != 32 {
+			t.Errorf("JWK %d has an x of %d bytes, want 32", i, len(x))
+		}
+	}
+
+	// with every key retired nothing signs
+	keys[1].RetiredAt, keys[2].RetiredAt = keys[0].RetiredAt, keys[0].RetiredAt
+	if r, err = NewRing(keys); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := r.Signer(); ok {
+		t.Error("Signer of retired keys succeeded")
+	}
+	if _, ok := r.SigningSince(); ok {
+		t.Error("SigningSince of retired keys succeeded")
+	}
+}
+
+func TestNewRingErrors(t *testing.T) {
+	k, err := Generate(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, bad := range map[string]storages.SigningKey{
+		"algorithm": {ID: k.ID, Algorithm: "RS256", PrivateKey: k.PrivateKey},
+		"no PEM":    {ID: k.ID, Algorithm: Algorithm, PrivateKey: "first"},
+	} {
+		bad := bad
+		if _, err := NewRing([]*storages.SigningKey{&bad}); err == nil {
+			t.Errorf("NewRing of a key with a bad %s succeeded", name)
+		}
+	}
+	var nilRing *Ring
+	if len(nilRing.JWKS().Keys) != 0 {
+		t.Error("JWKS of no ring has keys")
+	}
+}
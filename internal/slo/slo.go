@@ -0,0 +1,138 @@
+// Package slo records service level indicators as good and total event counts, summarized
+// over recent windows. The metrics package exports them for burn-rate alerts.
+package slo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Objective is the share of good events an SLI should stay at, e.g. 0.999
+type Objective struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Target      float64 `json:"target"`
+}
+
+// The objectives recorded
+var (
+	Availability = Objective{
+		Name:        "availability",
+		Description: "API requests answered without a server error",
+		Target:      0.999,
+	}
+	CreateTaskLatency = Objective{
+		Name:        "create_task_latency",
+		Description: "POST /tasks answered without a server error within " + CreateTaskThreshold.String(),
+		Target:      0.99,
+	}
+	Objectives = []Objective{Availability, CreateTaskLatency}
+)
+
+// CreateTaskThreshold is how fast a good POST /tasks is answered
+const CreateTaskThreshold = 300 * time.Millisecond
+
+// Windows are the spans Summary reports on, the usual fast and slow burn-rate alert windows
+var Windows = []time.Duration{5 * time.Minute, time.Hour, 6 * time.Hour}
+
+// bucket counts the events of one minute
+type bucket struct {
+	minute      int64
+	good, total int64
+}
+
+// series counts the events of one objective, since start and per minute over the longest window
+type series struct {
+	good, total int64
+	buckets     []bucket
+}
+
+// Recorder counts events per objective. The zero Recorder is ready to use.
+type Recorder struct {
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// Record counts an event of objective at now
+func (r *Recorder) Record(objective string, good bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.series == nil {
+		r.series = make(map[string]*series)
+	}
+	s := r.series[objective]
+	if s == nil {
+		s = &series{buckets: make([]bucket, int(Windows[len(Windows)-1]/time.Minute))}
+		r.series[objective] = s
+	}
+
+	minute := now.Unix() / 60
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	s.total++
+	if good {
+		b.good++
+		s.good++
+	}
+}
+
+// Status is how an objective fares over the Windows
+type Status struct {
+	Objective
+	Windows []WindowStatus `json:"windows"`
+}
+
+// WindowStatus is how an objective fared over the last Window
+type WindowStatus struct {
+	Window string `json:"window"`
+	Good   int64  `json:"good"`
+	Total  int64  `json:"total"`
+	// Compliance is Good / Total, 1 without events
+	Compliance float64 `json:"compliance"`
+	// BurnRate is how fast the error budget goes, 1 spends exactly the budget the
+	// objective allows, 14.4 over an hour spends 2% of a 30 day budget
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// Summary returns the status of every objective at now
+func (r *Recorder) Summary(now time.Time) []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	minute := now.Unix() / 60
+	statuses := make([]Status, 0, len(Objectives))
+	for _, o := range Objectives {
+		st := Status{Objective: o}
+		for _, w := range Windows {
+			ws := WindowStatus{Window: windowName(w), Compliance: 1}
+			if s := r.series[o.Name]; s != nil {
+				from := minute - int64(w/time.Minute)
+				for _, b := range s.buckets {
+					if b.minute > from && b.minute <= minute {
+						ws.Good += b.good
+						ws.Total += b.total
+					}
+				}
+			}
+			if ws.Total > 0 {
+				ws.Compliance = float64(ws.Good) / float64(ws.Total)
+			}
+			ws.BurnRate = (1 - ws.Compliance) / (1 - o.Target)
+			st.Windows = append(st.Windows, ws)
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// windowName names w the way alerting rules do, e.g. 5m or 6h
+func windowName(w time.Duration) string {
+	if w%time.Hour == 0 {
+		return fmt.Sprintf("%dh", w/time.Hour)
+	}
+	return fmt.Sprintf("%dm", w/time.Minute)
+}
@@ -0,0 +1,134 @@
+// Package sqlcomment tags SQL statements with where they come from, like the request and
+// user they run for, as a leading /*key='value',...*/ comment in the sqlcommenter format,
+// so statements seen in database traces and slow query logs lead back to API requests.
+package sqlcomment
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type tagsKey struct{}
+
+// tags are the comment of a context, nil when statements run with it aren't annotated
+type tags map[string]string
+
+// With returns a copy of ctx whose statements are tagged with key=value too
+func With(ctx context.Context, key, value string) context.Context {
+	prev, ok := ctx.Value(tagsKey{}).(tags)
+	if ok && prev == nil {
+		return ctx
+	}
+	t := make(tags, len(prev)+1)
+	for k, v := range prev {
+		t[k] = v
+	}
+	t[key] = value
+	return context.WithValue(ctx, tagsKey{}, t)
+}
+
+// Skip returns a copy of ctx whose statements aren't annotated, even with tags added later
+func Skip(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tagsKey{}, tags(nil))
+}
+
+// Annotate returns query with the tags of ctx as a leading comment, unchanged without tags.
+// Leading comments keep multi-statement queries intact, SQLite skips them in each statement.
+func Annotate(ctx context.Context, query string) string {
+	t, _ := ctx.Value(tagsKey{}).(tags)
+	if len(t) == 0 {
+		return query
+	}
+
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("/*")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		// escaping keeps */ and quotes out of the comment
+		b.WriteString(url.QueryEscape(k))
+		b.WriteString("='")
+		b.WriteString(strings.ReplaceAll(url.QueryEscape(t[k]), "'", "%27"))
+		b.WriteByte('\'')
+	}
+	b.WriteString("*/ ")
+	b.WriteString(query)
+	return b.String()
+}
+
+// OpenDB opens dsn with d, annotating the statements run with a context
+func OpenDB(d driver.Driver, dsn string) *sql.DB {
+	return sql.OpenDB(&connector{driver: d, dsn: dsn})
+}
+
+type connector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	inner, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: inner}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// conn annotates the statements of the context-aware calls database/sql makes,
+// skipping to its fallbacks for those the wrapped connection doesn't implement
+type conn struct {
+	driver.Conn
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	query = Annotate(ctx, query)
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return e.ExecContext(ctx, Annotate(ctx, query), args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return q.QueryContext(ctx, Annotate(ctx, query), args)
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
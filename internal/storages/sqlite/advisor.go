@@ -0,0 +1,243 @@
+package sqllite
+
+import (
+	"context"
+	"strings"
+)
+
+// QueryPattern is a statement the store runs, named for the report
+type QueryPattern struct {
+	Name string
+	SQL  string
+	// Args are like the ones the statement gets where the plan depends on them, empty
+	// strings are bound without them
+	Args []interface{}
+}
+
+// KnownQueries are the statements of the store that run the most or read the most rows,
+// as the methods named build them
+var KnownQueries = []QueryPattern{
+	{"RetrieveTasks", `SELECT id FROM tasks WHERE user_id = ? AND created_date = ?`, nil},
+	{"RetrieveTasks sorted by created_at", `SELECT id FROM tasks WHERE user_id = ? AND created_date = ? ORDER BY created_at`, nil},
+	{"RetrieveTasks sorted by priority", `SELECT id FROM tasks WHERE user_id = ? AND created_date = ? ORDER BY priority`, nil},
+	{"RetrieveTasks sorted by due_date", `SELECT id FROM tasks WHERE user_id = ? AND created_date = ? ORDER BY due_date`, nil},
+	{"RetrieveTask", `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE id = ? AND user_id = ?`, nil},
+	{"CountTasks", `SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?`, nil},
+	{"RetrieveIncompleteTasks", `SELECT id FROM tasks WHERE user_id = ? AND created_date = ? AND completed_at = ''`, nil},
+	{"RetrievePriorityCounts", `SELECT priority, COUNT(*), SUM(completed_at <> '') FROM tasks
+		WHERE user_id = ? AND created_date = ? GROUP BY priority ORDER BY priority DESC`, nil},
+	{"RetrieveCompletedTasks", `SELECT id FROM tasks WHERE completed_at <> '' AND completed_at >= ? AND completed_at < ?
+		ORDER BY completed_at, id`, nil},
+	{"IterateTasks", `SELECT id FROM tasks WHERE created_date >= ? AND created_date <= ? ORDER BY created_date, created_at, id`, nil},
+	{"RetrieveDayCounts created", `SELECT created_date, COUNT(*) FROM tasks
+		WHERE user_id = ? AND created_date >= ? AND created_date < ? GROUP BY created_date`, nil},
+	{"RetrieveDayCounts completed", `SELECT substr(completed_at, 1, 10), COUNT(*) FROM tasks
+		WHERE user_id = ? AND completed_at <> '' AND completed_at >= ? AND completed_at < ? GROUP BY 1`, nil},
+	{"RetrieveDayCounts tracked", `SELECT substr(started_at, 1, 10), SUM(seconds) FROM time_entries
+		WHERE user_id = ? AND started_at >= ? AND started_at < ? AND stopped_at <> '' GROUP BY 1`, nil},
+	{"RetrieveDayCounts focused", `SELECT substr(started_at, 1, 10), COUNT(*) FROM pomodoros
+		WHERE user_id = ? AND started_at >= ? AND started_at < ? AND completed_at <> '' GROUP BY 1`, nil},
+	{"RetrieveContentSuggestions", `SELECT content, created_date, COUNT(*) AS uses, MAX(created_at) AS last
+		FROM tasks WHERE user_id = ? AND content LIKE ? ESCAPE '\' AND content_truncated = 0
+		GROUP BY content COLLATE NOCASE ORDER BY uses DESC, last DESC LIMIT ?`, []interface{}{"", "buy%", 5}},
+	{"RetrieveCreationRates", `SELECT w.user_id, w.created,
+			(SELECT COUNT(*) FROM tasks b WHERE b.created_at >= ? AND b.created_at < ? AND b.user_id = w.user_id)
+		FROM (SELECT user_id, COUNT(*) AS created FROM tasks
+			WHERE created_at >= ? AND created_at < ? GROUP BY user_id HAVING COUNT(*) >= ?) w
+		ORDER BY w.created DESC, w.user_id`, nil},
+	{"CountTasksCreatedSince", `SELECT COUNT(*), MIN(created_at) FROM tasks WHERE created_at >= ? AND user_id = ?`, nil},
+	{"RetrieveTaskLinks", `SELECT k.task_id FROM task_links k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		JOIN tasks b ON b.id = k.blocked_by_id AND b.user_id = k.user_id
+		WHERE k.user_id = ? ORDER BY k.created_at, k.task_id, k.blocked_by_id`, nil},
+	{"RetrieveLinkedTasks blocked by", `SELECT t.id FROM task_links k
+		JOIN tasks t ON t.id = k.blocked_by_id AND t.user_id = k.user_id
+		WHERE k.task_id = ? AND k.user_id = ? ORDER BY t.created_at, t.id`, nil},
+	{"RetrieveLinkedTasks blocking", `SELECT t.id FROM task_links k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		WHERE k.blocked_by_id = ? AND k.user_id = ? ORDER BY t.created_at, t.id`, nil},
+	{"RetrieveNearbyTasks", `SELECT t.id, k.latitude, k.longitude FROM task_locations k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		WHERE k.user_id = ? AND k.latitude BETWEEN ? AND ? AND k.longitude BETWEEN ? AND ?`, nil},
+	{"RetrieveTaskLabels", `SELECT b.id FROM task_labels k
+		JOIN labels b ON b.id = k.label_id
+		WHERE k.task_id = ? AND b.user_id = ? ORDER BY b.name COLLATE NOCASE, b.id`, nil},
+	{"RetrieveTasksAsOf", `SELECT r.task_id FROM task_revisions r
+		WHERE r.user_id = ? AND r.created_date = ? AND r.valid_from <= ? AND r.deleted = 0
+			AND r.id = (SELECT MAX(id) FROM task_revisions WHERE task_id = r.task_id AND valid_from <= ?)
+		ORDER BY r.created_at, r.task_id`, nil},
+	{"RetrieveTimeEntries", `SELECT id FROM time_entries WHERE task_id = ? AND user_id = ? ORDER BY started_at, id`, nil},
+	{"RetrievePomodoros", `SELECT id FROM pomodoros WHERE task_id = ? AND user_id = ? ORDER BY started_at, id`, nil},
+	{"RetrieveAccountAudit", `SELECT id FROM audit_log WHERE user_id = ? AND (at < ? OR (at = ? AND id < ?))
+		ORDER BY at DESC, id DESC LIMIT ?`, nil},
+	{"RetrieveAuditLog", `SELECT id FROM audit_log WHERE (user_id = ? OR actor_id = ?) AND at >= ? AND at < ? ORDER BY at, id`, nil},
+	{"RetrieveSessions", `SELECT id FROM sessions WHERE user_id = ? AND expires_at > ? AND revoked_at IS NULL ORDER BY created_at`, nil},
+	{"RetrieveShares", `SELECT id FROM shares WHERE user_id = ? AND expires_at > ? AND revoked_at IS NULL
+		ORDER BY created_at, id`, nil},
+	{"RetrieveAPIUsage", `SELECT day, calls FROM api_usage WHERE user_id = ? AND day >= ? AND day <= ? ORDER BY day`, nil},
+	{"RetrieveQuotaAdjustments", `SELECT id FROM quota_adjustments WHERE user_id = ? AND date >= ? AND date <= ?
+		ORDER BY date, created_at, id`, nil},
+	{"RetrieveLimitOffenders", `SELECT user_id, COUNT(*) AS days, SUM(attempts) AS attempts, MAX(date)
+		FROM limit_hits WHERE date >= ? AND date <= ?
+		GROUP BY user_id ORDER BY days DESC, attempts DESC, user_id LIMIT ?`, nil},
+	{"RetrieveAnomalies", `SELECT id FROM anomalies WHERE detected_at >= ? AND detected_at < ? ORDER BY detected_at DESC, id DESC`, nil},
+	{"AddTaskWithIdempotencyKey expiry", `DELETE FROM idempotency_keys WHERE expires_at <= ?`, nil},
+	{"DeleteTasksUndoable expiry", `DELETE FROM undo_ops WHERE expires_at < ?`, nil},
+}
+
+// QueryPlan is how SQLite runs a QueryPattern
+type QueryPlan struct {
+	Pattern string
+	// Steps are the details of EXPLAIN QUERY PLAN
+	Steps []string
+	// Problems are the steps scanning a whole table or sorting all the rows read in a
+	// temporary b-tree, which an index would avoid
+	Problems []string
+}
+
+// IndexAdvice is what AdviseIndexes found
+type IndexAdvice struct {
+	// Missing are the plans with problems
+	Missing []*QueryPlan
+	// Unused are the indexes none of the patterns use, as table.index. Those of a primary key
+	// or a unique constraint are left out, they're used on every write, and so are those
+	// starting with a foreign key, they're used deleting the rows it refers to.
+	Unused []string
+}
+
+// AdviseIndexes explains the plans of patterns, reporting those an index would help and the
+// indexes they don't use. SQLite keeps no statistics of the statements it ran, so the patterns
+// stand in for them.
+func (l *LiteDB) AdviseIndexes(ctx context.Context, patterns []QueryPattern) (*IndexAdvice, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT name, tbl_name, sql FROM sqlite_master
+		WHERE type = 'index' AND sql IS NOT NULL ORDER BY tbl_name, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// the indexes SQLite makes for constraints have no sql
+	type index struct{ name, table string }
+	var indexes []index
+	for rows.Next() {
+		var name, table, stmt string
+		if err := rows.Scan(&name, &table, &stmt); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(strings.ToUpper(stmt), "CREATE UNIQUE") {
+			indexes = append(indexes, index{name, table})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	advice := &IndexAdvice{Missing: []*QueryPlan{}, Unused: []string{}}
+	used := map[string]bool{}
+	for _, p := range patterns {
+		plan, err := l.explain(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		// subqueries are scanned once materialized, that's no table scan, and sorting groups
+		// by their counts takes a b-tree whatever the indexes
+		grouped := strings.Contains(p.SQL, "GROUP BY")
+		subqueries := map[string]bool{}
+		for _, step := range plan.Steps {
+			if f := strings.Fields(step); len(f) == 2 && (f[0] == "MATERIALIZE" || f[0] == "CO-ROUTINE") {
+				subqueries[f[1]] = true
+			}
+		}
+		for _, step := range plan.Steps {
+			if name := planIndex(step); name != "" {
+				used[name] = true
+			}
+			if planProblem(step, grouped, subqueries) {
+				plan.Problems = append(plan.Problems, step)
+			}
+		}
+		if len(plan.Problems) > 0 {
+			advice.Missing = append(advice.Missing, plan)
+		}
+	}
+
+	for _, ix := range indexes {
+		if used[ix.name] {
+			continue
+		}
+		fk, err := l.startsWithForeignKey(ctx, ix.table, ix.name)
+		if err != nil {
+			return nil, err
+		}
+		if !fk {
+			advice.Unused = append(advice.Unused, ix.table+"."+ix.name)
+		}
+	}
+	return advice, nil
+}
+
+// startsWithForeignKey reports whether the first column of an index of table is a foreign key
+func (l *LiteDB) startsWithForeignKey(ctx context.Context, table, index string) (bool, error) {
+	var first string
+	// pragmas take no parameters, the names come from sqlite_master
+	err := l.DB.QueryRowContext(ctx, `SELECT name FROM pragma_index_info(?) WHERE seqno = 0`, index).Scan(&first)
+	if err != nil {
+		return false, err
+	}
+	var n int
+	err = l.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_foreign_key_list(?) WHERE "from" = ?`, table, first).Scan(&n)
+	return n > 0, err
+}
+
+// explain returns the plan of p
+func (l *LiteDB) explain(ctx context.Context, p QueryPattern) (*QueryPlan, error) {
+	args := p.Args
+	if args == nil {
+		args = make([]interface{}, strings.Count(p.SQL, "?"))
+		for i := range args {
+			args[i] = ""
+		}
+	}
+	rows, err := l.DB.QueryContext(ctx, `EXPLAIN QUERY PLAN `+p.SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan := &QueryPlan{Pattern: p.Name}
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, detail)
+	}
+	return plan, rows.Err()
+}
+
+// planIndex returns the index a step of a plan reads, empty when it reads none
+func planIndex(step string) string {
+	for _, using := range []string{" USING COVERING INDEX ", " USING INDEX "} {
+		if i := strings.Index(step, using); i >= 0 {
+			return strings.Fields(step[i+len(using):])[0]
+		}
+	}
+	return ""
+}
+
+// planProblem reports whether a step of a plan scans a table whole, other than through a
+// covering index, or sorts all the rows read in a temporary b-tree
+func planProblem(step string, grouped bool, subqueries map[string]bool) bool {
+	// sorting ties, like by id after created_at, or groups of the rows read costs little
+	if step == "USE TEMP B-TREE FOR ORDER BY" {
+		return !grouped
+	}
+	// older SQLite versions say SCAN TABLE tasks and SCAN SUBQUERY 2
+	fields := strings.Fields(strings.Replace(step, "SCAN TABLE ", "SCAN ", 1))
+	if len(fields) < 2 || fields[0] != "SCAN" || fields[1] == "CONSTANT" || fields[1] == "SUBQUERY" || subqueries[fields[1]] {
+		return false
+	}
+	return !strings.Contains(step, " USING COVERING INDEX ")
+}
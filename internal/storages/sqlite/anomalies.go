@@ -0,0 +1,128 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RetrieveCreationRates returns the users who created at least min tasks from from up to before
+// to, with how many they created from baselineFrom up to before from, most tasks first
+func (l *LiteDB) RetrieveCreationRates(ctx context.Context, baselineFrom, from, to string, min int) ([]*storages.CreationRate, error) {
+	// both counts are read from the (created_at, user_id) index
+	rows, err := l.DB.QueryContext(ctx, `SELECT w.user_id, w.created,
+			(SELECT COUNT(*) FROM tasks b WHERE b.created_at >= ? AND b.created_at < ? AND b.user_id = w.user_id)
+		FROM (SELECT user_id, COUNT(*) AS created FROM tasks
+			WHERE created_at >= ? AND created_at < ? GROUP BY user_id HAVING COUNT(*) >= ?) w
+		ORDER BY w.created DESC, w.user_id`, baselineFrom, from, from, to, min)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []*storages.CreationRate{}
+	for rows.Next() {
+		r := &storages.CreationRate{}
+		if err := rows.Scan(&r.UserID, &r.Created, &r.Baseline); err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	return rates, rows.Err()
+}
+
+// AddAnomaly records a finding, and applies throttle when it isn't nil, unless the user was
+// found in the same window already; first is false then and nothing changes. A throttle
+// replaces the user's current one.
+func (l *LiteDB) AddAnomaly(ctx context.Context, a *storages.Anomaly, throttle *storages.CreationThrottle) (bool, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO anomalies (id, user_id, detected_at, window_start, created, baseline_per_hour, throttled_until)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, a.ID, a.UserID, a.DetectedAt, a.WindowStart, a.Created, a.BaselinePerHour, a.ThrottledUntil)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	if throttle != nil {
+		_, err = tx.ExecContext(ctx, `INSERT INTO creation_throttles (user_id, per_hour, until) VALUES (?, ?, ?)
+			ON CONFLICT (user_id) DO UPDATE SET per_hour = excluded.per_hour, until = excluded.until`,
+			throttle.UserID, throttle.PerHour, throttle.Until)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// RetrieveAnomalies returns the findings detected from from up to before to, newest first
+func (l *LiteDB) RetrieveAnomalies(ctx context.Context, from, to string) ([]*storages.Anomaly, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, user_id, detected_at, window_start, created, baseline_per_hour, throttled_until
+		FROM anomalies WHERE detected_at >= ? AND detected_at < ? ORDER BY detected_at DESC, id DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anomalies := []*storages.Anomaly{}
+	for rows.Next() {
+		a := &storages.Anomaly{}
+		if err := rows.Scan(&a.ID, &a.UserID, &a.DetectedAt, &a.WindowStart, &a.Created, &a.BaselinePerHour, &a.ThrottledUntil); err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+	return anomalies, rows.Err()
+}
+
+// RetrieveCreationThrottle returns the throttle userID has at now, ErrNotFound when they have
+// none or it ended
+func (l *LiteDB) RetrieveCreationThrottle(ctx context.Context, userID sql.NullString, now string) (*storages.CreationThrottle, error) {
+	t := &storages.CreationThrottle{}
+	err := l.DB.QueryRowContext(ctx, `SELECT user_id, per_hour, until FROM creation_throttles WHERE user_id = ? AND until > ?`, userID, now).
+		Scan(&t.UserID, &t.PerHour, &t.Until)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LiftCreationThrottle ends the throttle of userID, ErrNotFound when they have none
+func (l *LiteDB) LiftCreationThrottle(ctx context.Context, userID sql.NullString) error {
+	res, err := l.DB.ExecContext(ctx, `DELETE FROM creation_throttles WHERE user_id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// CountTasksCreatedSince returns how many tasks userID created at since or later, and when the
+// first of them was, empty without any
+func (l *LiteDB) CountTasksCreatedSince(ctx context.Context, userID sql.NullString, since string) (int, string, error) {
+	var count int
+	var oldest sql.NullString
+	err := l.DB.QueryRowContext(ctx, `SELECT COUNT(*), MIN(created_at) FROM tasks WHERE created_at >= ? AND user_id = ?`, since, userID).
+		Scan(&count, &oldest)
+	return count, oldest.String, err
+}
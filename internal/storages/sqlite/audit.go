@@ -0,0 +1,69 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// AddAuditEntry adds e to the audit log
+func (l *LiteDB) AddAuditEntry(ctx context.Context, e *storages.AuditEntry) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO audit_log (id, at, actor_id, user_id, action, detail)
+		VALUES (?, ?, ?, ?, ?, ?)`, e.ID, e.At, e.ActorID, e.UserID, e.Action, e.Detail)
+	return err
+}
+
+// RetrieveAuditLog returns the entries where userID acted or was acted as, at from or after
+// and before to, oldest first
+func (l *LiteDB) RetrieveAuditLog(ctx context.Context, userID sql.NullString, from, to string) ([]*storages.AuditEntry, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, at, actor_id, user_id, action, detail FROM audit_log
+		WHERE (user_id = ? OR actor_id = ?) AND at >= ? AND at < ? ORDER BY at, id`, userID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*storages.AuditEntry{}
+	for rows.Next() {
+		e := &storages.AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.At, &e.ActorID, &e.UserID, &e.Action, &e.Detail); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RetrieveAccountAudit returns the entries of the actions on the account of q.UserID that q
+// selects, newest first
+func (l *LiteDB) RetrieveAccountAudit(ctx context.Context, q *storages.AuditQuery) ([]*storages.AuditEntry, error) {
+	stmt := `SELECT id, at, actor_id, user_id, action, detail FROM audit_log WHERE user_id = ?`
+	args := []interface{}{q.UserID}
+	if q.Action != "" {
+		stmt += ` AND action = ?`
+		args = append(args, q.Action)
+	}
+	if q.BeforeAt != "" {
+		stmt += ` AND (at < ? OR (at = ? AND id < ?))`
+		args = append(args, q.BeforeAt, q.BeforeAt, q.BeforeID)
+	}
+	stmt += ` ORDER BY at DESC, id DESC LIMIT ?`
+	args = append(args, q.N)
+
+	rows, err := l.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*storages.AuditEntry{}
+	for rows.Next() {
+		e := &storages.AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.At, &e.ActorID, &e.UserID, &e.Action, &e.Detail); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,54 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+const (
+	// longContent is the size in bytes beyond which a task's content is kept in task_bodies,
+	// so listing a day's tasks reads short rows. The migration moving contents uses it too.
+	longContent = 1024
+	// summaryRunes is how much of a long content the tasks table keeps
+	summaryRunes = 200
+)
+
+// fullTaskColumnList is taskColumnList reading long contents whole, for queries on tasks
+// that aren't listing them
+var fullTaskColumnList = strings.Replace(taskColumnList, "content",
+	"COALESCE((SELECT body FROM task_bodies WHERE task_id = tasks.id), content)", 1)
+
+// execer runs statements, in a transaction or not
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// summarize returns the content the tasks table keeps for content, and whether it's a summary
+func summarize(content string) (string, bool) {
+	if len(content) <= longContent {
+		return content, false
+	}
+	return string([]rune(content)[:summaryRunes]) + "…", true
+}
+
+// taskRow returns the values insertTaskStmt stores for t, the summary of long content
+// included. Save the content whole with saveBody.
+func taskRow(t *storages.Task) []interface{} {
+	row := *t
+	var truncated bool
+	row.Content, truncated = summarize(t.Content)
+	return append(taskValues(&row), truncated)
+}
+
+// saveBody keeps the content of t in task_bodies when it's too long for the tasks table. Call
+// it before inserting t, for the revision recorded on insert to have the whole content.
+func saveBody(ctx context.Context, db execer, t *storages.Task) error {
+	if _, truncated := summarize(t.Content); !truncated {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, `INSERT OR REPLACE INTO task_bodies (task_id, body) VALUES (?, ?)`, t.ID, t.Content)
+	return err
+}
@@ -3,28 +3,95 @@ package sqllite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/manabie-com/togo/internal/storages"
+	"github.com/manabie-com/togo/internal/retry"
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/mattn/go-sqlite3"
 )
 
-// LiteDB for working with sqllite
+// LiteDB for working with sqllite.
+// Open the DB with _txlock=immediate so transactions that read before writing
+// wait for the write lock instead of failing with SQLITE_BUSY.
 type LiteDB struct {
 	DB *sql.DB
 }
 
+var _ storages.Store = &LiteDB{}
+
+// busyRetry runs a transaction again when SQLite still answered SQLITE_BUSY after
+// its busy timeout, which a burst of writers to one database file can cause
+var busyRetry = retry.Policy{
+	MaxAttempts: 3,
+	Backoff:     retry.Exponential{Base: 50 * time.Millisecond, Max: time.Second},
+	Retryable: func(err error) bool {
+		var se sqlite3.Error
+		return errors.As(err, &se) && se.Code == sqlite3.ErrBusy
+	},
+}
+
 // RetrieveTasks returns tasks if match userID AND createDate.
-func (l *LiteDB) RetrieveTasks(ctx context.Context, userID, createdDate sql.NullString) ([]*storages.Task, error) {
-	stmt := `SELECT id, content, user_id, created_date FROM tasks WHERE user_id = ? AND created_date = ?`
+// Only opts.Fields are read, the rest are left empty.
+func (l *LiteDB) RetrieveTasks(ctx context.Context, userID, createdDate sql.NullString, opts storages.ListOptions) ([]*storages.Task, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = storages.TaskFields
+	}
+	columns, err := taskColumns(fields)
+	if err != nil {
+		return nil, err
+	}
+	withContent := false
+	for _, f := range fields {
+		withContent = withContent || f == "content"
+	}
+	if withContent {
+		columns = append(columns, "content_truncated")
+	}
+
+	stmt := `SELECT ` + strings.Join(columns, ", ") + ` FROM tasks WHERE user_id = ? AND created_date = ?`
+	if opts.Sort != "" {
+		if !storages.IsSortKey(opts.Sort) {
+			return nil, fmt.Errorf("unknown sort key %q", opts.Sort)
+		}
+		// every sort key has a (user_id, created_date, key) index so no temp b-tree is needed
+		stmt += ` ORDER BY ` + opts.Sort
+		if opts.Desc {
+			stmt += ` DESC`
+		}
+	}
+	if opts.Max > 0 {
+		// one more tells there are too many without reading them all
+		stmt += ` LIMIT ` + strconv.Itoa(opts.Max+1)
+	}
 	rows, err := l.DB.QueryContext(ctx, stmt, userID, createdDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tasks []*storages.Task
+	var (
+		tasks []*storages.Task
+		// the tasks are allocated a block at a time and scanned through the same targets
+		block   []storages.Task
+		targets []interface{}
+	)
 	for rows.Next() {
-		t := &storages.Task{}
-		err := rows.Scan(&t.ID, &t.Content, &t.UserID, &t.CreatedDate)
+		if len(block) == 0 {
+			block = make([]storages.Task, taskBlockSize)
+		}
+		t := &block[0]
+		block = block[1:]
+		targets = appendTaskScanTargets(targets[:0], t, fields)
+		if withContent {
+			targets = append(targets, &t.ContentTruncated)
+		}
+		err := rows.Scan(targets...)
 		if err != nil {
 			return nil, err
 		}
@@ -34,30 +101,781 @@ func (l *LiteDB) RetrieveTasks(ctx context.Context, userID, createdDate sql.Null
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if opts.Max > 0 && len(tasks) > opts.Max {
+		return nil, &storages.ResultLimitExceeded{What: "tasks", Max: opts.Max}
+	}
+
+	return tasks, nil
+}
+
+// RetrieveTask returns taskID, ErrNotFound if userID has no such task
+func (l *LiteDB) RetrieveTask(ctx context.Context, userID, taskID sql.NullString) (*storages.Task, error) {
+	t := &storages.Task{}
+	stmt := `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE id = ? AND user_id = ?`
+	err := l.DB.QueryRowContext(ctx, stmt, taskID, userID).Scan(taskValues(t)...)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RetrieveCompletedTasks returns every user's tasks completed at or after from and before to,
+// in completion order
+func (l *LiteDB) RetrieveCompletedTasks(ctx context.Context, from, to string) ([]*storages.Task, error) {
+	c, err := l.IterateCompletedTasks(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
 
+	var tasks []*storages.Task
+	for c.Next() {
+		tasks = append(tasks, c.Task())
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
+// IterateCompletedTasks reads RetrieveCompletedTasks one task at a time
+func (l *LiteDB) IterateCompletedTasks(ctx context.Context, from, to string) (storages.TaskCursor, error) {
+	stmt := `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE completed_at <> '' AND completed_at >= ? AND completed_at < ? ORDER BY completed_at, id`
+	rows, err := l.DB.QueryContext(ctx, stmt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &taskCursor{rows: rows}, nil
+}
+
+// IterateTasks reads every user's tasks created from from to to, both YYYY-MM-DD dates
+// included, one at a time in creation order
+func (l *LiteDB) IterateTasks(ctx context.Context, from, to string) (storages.TaskCursor, error) {
+	stmt := `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE created_date >= ? AND created_date <= ? ORDER BY created_date, created_at, id`
+	rows, err := l.DB.QueryContext(ctx, stmt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &taskCursor{rows: rows}, nil
+}
+
+// taskCursor scans each task of rows when it's reached
+type taskCursor struct {
+	rows *sql.Rows
+	task *storages.Task
+	err  error
+}
+
+func (c *taskCursor) Next() bool {
+	if c.err != nil || !c.rows.Next() {
+		return false
+	}
+	t := &storages.Task{}
+	if err := c.rows.Scan(taskValues(t)...); err != nil {
+		c.err = err
+		return false
+	}
+	c.task = t
+	return true
+}
+
+func (c *taskCursor) Task() *storages.Task {
+	return c.task
+}
+
+func (c *taskCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.rows.Err()
+}
+
+func (c *taskCursor) Close() error {
+	return c.rows.Close()
+}
+
+// taskColumns maps task fields to columns, never interpolating anything but known names
+func taskColumns(fields []string) ([]string, error) {
+	columns := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !storages.IsTaskField(f) {
+			return nil, fmt.Errorf("unknown task field %q", f)
+		}
+		columns = append(columns, f)
+	}
+	return columns, nil
+}
+
+// taskBlockSize is how many tasks RetrieveTasks allocates at once
+const taskBlockSize = 16
+
+func taskScanTargets(t *storages.Task, fields []string) []interface{} {
+	return appendTaskScanTargets(make([]interface{}, 0, len(fields)), t, fields)
+}
+
+// appendTaskScanTargets appends the scan targets of the fields of t to targets
+func appendTaskScanTargets(targets []interface{}, t *storages.Task, fields []string) []interface{} {
+	for _, f := range fields {
+		switch f {
+		case "id":
+			targets = append(targets, &t.ID)
+		case "content":
+			targets = append(targets, &t.Content)
+		case "user_id":
+			targets = append(targets, &t.UserID)
+		case "created_date":
+			targets = append(targets, &t.CreatedDate)
+		case "created_at":
+			targets = append(targets, &t.CreatedAt)
+		case "priority":
+			targets = append(targets, &t.Priority)
+		case "due_date":
+			targets = append(targets, &t.DueDate)
+		case "completed_at":
+			targets = append(targets, &t.CompletedAt)
+		case "over_quota":
+			targets = append(targets, &t.OverQuota)
+		case "estimate_minutes":
+			targets = append(targets, &t.EstimateMinutes)
+		}
+	}
+	return targets
+}
+
+var (
+	// taskColumnList lists every task column in storages.TaskFields order
+	taskColumnList   = strings.Join(storages.TaskFields, ", ")
+	taskPlaceholders = strings.TrimSuffix(strings.Repeat("?, ", len(storages.TaskFields)), ", ")
+	// insertTaskStmt takes the values of taskRow
+	insertTaskStmt = `INSERT INTO tasks (` + taskColumnList + `, content_truncated) VALUES (` + taskPlaceholders + `, ?)`
+)
+
+// taskValues returns the values of every task column in taskColumnList order
+func taskValues(t *storages.Task) []interface{} {
+	return taskScanTargets(t, storages.TaskFields)
+}
+
 // AddTask adds a new task to DB
 func (l *LiteDB) AddTask(ctx context.Context, t *storages.Task) error {
-	stmt := `INSERT INTO tasks (id, content, user_id, created_date) VALUES (?, ?, ?, ?)`
-	_, err := l.DB.ExecContext(ctx, stmt, &t.ID, &t.Content, &t.UserID, &t.CreatedDate)
+	tx, err := l.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	if err := saveBody(ctx, tx, t); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, insertTaskStmt, taskRow(t)...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// ValidateUser returns tasks if match userID AND password
-func (l *LiteDB) ValidateUser(ctx context.Context, userID, pwd sql.NullString) bool {
-	stmt := `SELECT id FROM users WHERE id = ? AND password = ?`
-	row := l.DB.QueryRowContext(ctx, stmt, userID, pwd)
-	u := &storages.User{}
-	err := row.Scan(&u.ID)
+// AddTaskWithLimitPerDay adds a new task unless the user already has their limit of tasks on its date,
+// and returns how many they have now. The count and insert run as one statement, which SQLite
+// executes under the write lock, so concurrent requests can't push a user over the limit.
+// The new count is read in the same transaction, while the lock is still held.
+// With a key that already added a task, DuplicateRequest names that task and nothing is added.
+// Users in QuotaSoft mode get tasks beyond the limit too, with OverQuota set.
+// With LabelsFrom set the task gets that task's labels in the same transaction.
+func (l *LiteDB) AddTaskWithLimitPerDay(ctx context.Context, t *storages.Task, key *storages.IdempotencyKey) (count, maxTodo int, err error) {
+	err = busyRetry.Do(ctx, func(ctx context.Context) error {
+		count, maxTodo, err = l.addTaskWithTransaction(ctx, t, key)
+		return err
+	})
+	return count, maxTodo, err
+}
+
+func (l *LiteDB) addTaskWithTransaction(ctx context.Context, t *storages.Task, key *storages.IdempotencyKey) (count, maxTodo int, err error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return false
+		return 0, 0, err
 	}
+	defer tx.Rollback()
 
-	return true
+	if key != nil {
+		var taskID string
+		err := tx.QueryRowContext(ctx, `SELECT task_id FROM idempotency_keys WHERE user_id = ? AND key = ? AND expires_at > ?`,
+			t.UserID, key.Key, time.Now().UTC().Format(storages.TimeLayout)).Scan(&taskID)
+		if err == nil {
+			return 0, 0, &storages.DuplicateRequest{Key: key.Key, TaskID: taskID}
+		}
+		if err != sql.ErrNoRows {
+			return 0, 0, err
+		}
+	}
+
+	if err := saveBody(ctx, tx, t); err != nil {
+		return 0, 0, err
+	}
+	stmt := `INSERT INTO tasks (` + taskColumnList + `, content_truncated)
+		SELECT ` + taskPlaceholders + `, ?
+		WHERE (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?) < ` + maxTodoOn
+	args := append(taskRow(t), &t.UserID, &t.CreatedDate)
+	args = append(args, maxTodoArgs(&t.UserID, &t.CreatedDate)...)
+	res, err := tx.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	if n == 0 {
+		// users in soft quota mode get the task anyway, flagged
+		mode, err := quotaMode(ctx, tx, t.UserID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if mode != storages.QuotaSoft {
+			return 0, 0, &storages.TaskLimitReached{UserID: t.UserID, Date: t.CreatedDate}
+		}
+		t.OverQuota = true
+		if _, err := tx.ExecContext(ctx, insertTaskStmt, taskRow(t)...); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if t.LabelsFrom != "" {
+		_, err := tx.ExecContext(ctx, `INSERT INTO task_labels (task_id, label_id, created_at)
+			SELECT ?, k.label_id, ? FROM task_labels k JOIN labels b ON b.id = k.label_id
+			WHERE k.task_id = ? AND b.user_id = ?`, t.ID, t.CreatedAt, t.LabelsFrom, t.UserID)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if key != nil {
+		// expired keys are useless, drop them while we're writing anyway
+		if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, time.Now().UTC().Format(storages.TimeLayout)); err != nil {
+			return 0, 0, err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO idempotency_keys (user_id, key, task_id, expires_at) VALUES (?, ?, ?, ?)`,
+			t.UserID, key.Key, t.ID, key.ExpiresAt)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	stmt = `SELECT (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?), ` + maxTodoOn
+	args = append([]interface{}{&t.UserID, &t.CreatedDate}, maxTodoArgs(&t.UserID, &t.CreatedDate)...)
+	err = tx.QueryRowContext(ctx, stmt, args...).Scan(&count, &maxTodo)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, maxTodo, tx.Commit()
+}
+
+// MoveTask changes the date of a task of move.UserID, enforcing the user's limit on the new date
+// the same way AddTaskWithLimitPerDay does, and records the move in task_history.
+// Users in QuotaSoft mode get it moved beyond the limit too, with OverQuota set.
+// Moving a task to the date it already has is a no-op.
+func (l *LiteDB) MoveTask(ctx context.Context, move *storages.TaskMove) (*storages.Task, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var fromDate string
+	err = tx.QueryRowContext(ctx, `SELECT created_date FROM tasks WHERE id = ? AND user_id = ?`, move.TaskID, move.UserID).Scan(&fromDate)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fromDate != move.ToDate {
+		stmt := `UPDATE tasks SET created_date = ? WHERE id = ? AND user_id = ?
+			AND (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?) < ` + maxTodoOn
+		args := append([]interface{}{move.ToDate, move.TaskID, move.UserID, move.UserID, move.ToDate}, maxTodoArgs(move.UserID, move.ToDate)...)
+		res, err := tx.ExecContext(ctx, stmt, args...)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			// users in soft quota mode get the task moved anyway, flagged
+			mode, err := quotaMode(ctx, tx, move.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if mode != storages.QuotaSoft {
+				return nil, &storages.TaskLimitReached{UserID: move.UserID, Date: move.ToDate}
+			}
+			_, err = tx.ExecContext(ctx, `UPDATE tasks SET created_date = ?, over_quota = 1 WHERE id = ? AND user_id = ?`,
+				move.ToDate, move.TaskID, move.UserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		stmt = `INSERT INTO task_history (task_id, user_id, action, from_date, to_date, at) VALUES (?, ?, ?, ?, ?, ?)`
+		_, err = tx.ExecContext(ctx, stmt, move.TaskID, move.UserID, move.Action, fromDate, move.ToDate, move.At)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := retrieveTask(ctx, tx, move.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, tx.Commit()
+}
+
+// CompleteTask marks a task of userID as completed at the given time, completing it again
+// keeps the first time. ErrNotFound if userID has no such task.
+func (l *LiteDB) CompleteTask(ctx context.Context, userID, taskID sql.NullString, at string) (*storages.Task, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt := `UPDATE tasks SET completed_at = CASE WHEN completed_at = '' THEN ? ELSE completed_at END
+		WHERE id = ? AND user_id = ?`
+	res, err := tx.ExecContext(ctx, stmt, at, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, storages.ErrNotFound
+	}
+
+	t, err := retrieveTask(ctx, tx, taskID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, tx.Commit()
+}
+
+// RetrieveTaskOwner returns the ID of the user owning taskID, ErrNotFound if there's no such task
+func (l *LiteDB) RetrieveTaskOwner(ctx context.Context, taskID sql.NullString) (string, error) {
+	var userID string
+	err := l.DB.QueryRowContext(ctx, `SELECT user_id FROM tasks WHERE id = ?`, taskID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", storages.ErrNotFound
+	}
+	return userID, err
+}
+
+// CompleteTasks completes the tasks of userID in taskIDs like CompleteTask, in one transaction,
+// and returns the IDs userID has no task with. With undo, the tasks as they were are kept
+// for UndoTasks until undo.ExpiresAt.
+func (l *LiteDB) CompleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, at string, undo *storages.Undo) ([]string, error) {
+	stmt := `UPDATE tasks SET completed_at = CASE WHEN completed_at = '' THEN ? ELSE completed_at END
+		WHERE id = ? AND user_id = ?`
+	return l.execEachTask(ctx, userID, taskIDs, undo, stmt, func(id string) []interface{} {
+		return []interface{}{at, id, userID}
+	})
+}
+
+// DeleteTasks deletes the tasks of userID in taskIDs in one transaction and returns the IDs
+// userID has no task with. With undo, the tasks are kept for UndoTasks until undo.ExpiresAt.
+func (l *LiteDB) DeleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, undo *storages.Undo) ([]string, error) {
+	return l.execEachTask(ctx, userID, taskIDs, undo, `DELETE FROM tasks WHERE id = ? AND user_id = ?`, func(id string) []interface{} {
+		return []interface{}{id, userID}
+	})
+}
+
+// execEachTask runs stmt with args(id) for every task of userID in ids in one transaction,
+// returning the IDs userID has no task with, and saves the tasks as they were under undo
+func (l *LiteDB) execEachTask(ctx context.Context, userID sql.NullString, ids []string, undo *storages.Undo,
+	stmt string, args func(id string) []interface{}) ([]string, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	selectStmt, err := tx.PrepareContext(ctx, `SELECT `+fullTaskColumnList+` FROM tasks WHERE id = ? AND user_id = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer selectStmt.Close()
+	prepared, err := tx.PrepareContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer prepared.Close()
+
+	var notFound []string
+	before := []*storages.Task{}
+	for _, id := range ids {
+		t := &storages.Task{}
+		err := selectStmt.QueryRowContext(ctx, id, userID).Scan(taskValues(t)...)
+		if err == sql.ErrNoRows {
+			notFound = append(notFound, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		before = append(before, t)
+
+		if _, err := prepared.ExecContext(ctx, args(id)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if undo != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return nil, err
+		}
+		// expired undos are useless, drop them while we're writing anyway
+		if _, err := tx.ExecContext(ctx, `DELETE FROM undo_ops WHERE expires_at < ?`, time.Now().UTC().Format(storages.TimeLayout)); err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO undo_ops (token, user_id, tasks, expires_at) VALUES (?, ?, ?, ?)`,
+			undo.Token, undo.UserID, string(b), undo.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return notFound, tx.Commit()
+}
+
+// UndoTasks puts back the tasks saved under token by a batch operation of userID, returning how
+// many. A token can be used once and only before it expires, ErrNotFound otherwise.
+// Tasks that are no longer on their date count against the limit there like new ones: all
+// tasks are put back or, when one doesn't fit, none and TaskLimitReached is returned.
+// Users in QuotaSoft mode get them back anyway, with OverQuota set.
+func (l *LiteDB) UndoTasks(ctx context.Context, userID sql.NullString, token, now string) (int, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var doc string
+	err = tx.QueryRowContext(ctx, `SELECT tasks FROM undo_ops WHERE token = ? AND user_id = ? AND expires_at > ?`,
+		token, userID, now).Scan(&doc)
+	if err == sql.ErrNoRows {
+		return 0, storages.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	var tasks []*storages.Task
+	if err := json.Unmarshal([]byte(doc), &tasks); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR REPLACE INTO tasks (`+taskColumnList+`, content_truncated) VALUES (`+taskPlaceholders+`, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	limited, err := tx.PrepareContext(ctx, `INSERT INTO tasks (`+taskColumnList+`, content_truncated)
+		SELECT `+taskPlaceholders+`, ?
+		WHERE (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?) < `+maxTodoOn)
+	if err != nil {
+		return 0, err
+	}
+	defer limited.Close()
+	mode, err := quotaMode(ctx, tx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, t := range tasks {
+		// tasks still on their date, e.g. after a batch completion, take no room
+		var createdDate string
+		err := tx.QueryRowContext(ctx, `SELECT created_date FROM tasks WHERE id = ?`, t.ID).Scan(&createdDate)
+		if err != nil && err != sql.ErrNoRows {
+			return 0, err
+		}
+		found := err == nil
+		if found && createdDate != t.CreatedDate {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?`, t.ID); err != nil {
+				return 0, err
+			}
+		}
+		if err := saveBody(ctx, tx, t); err != nil {
+			return 0, err
+		}
+		if found && createdDate == t.CreatedDate {
+			if _, err := stmt.ExecContext(ctx, taskRow(t)...); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		args := append(taskRow(t), &t.UserID, &t.CreatedDate)
+		res, err := limited.ExecContext(ctx, append(args, maxTodoArgs(&t.UserID, &t.CreatedDate)...)...)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if n > 0 {
+			continue
+		}
+		if mode != storages.QuotaSoft {
+			return 0, &storages.TaskLimitReached{UserID: t.UserID, Date: t.CreatedDate}
+		}
+		t.OverQuota = true
+		if _, err := stmt.ExecContext(ctx, taskRow(t)...); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM undo_ops WHERE token = ?`, token); err != nil {
+		return 0, err
+	}
+
+	return len(tasks), tx.Commit()
+}
+
+// quotaMode returns the quota mode of userID, empty for hard when they have no settings
+func quotaMode(ctx context.Context, tx *sql.Tx, userID interface{}) (string, error) {
+	var mode string
+	err := tx.QueryRowContext(ctx, `SELECT quota_mode FROM users WHERE id = ?`, userID).Scan(&mode)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	return mode, nil
+}
+
+func retrieveTask(ctx context.Context, tx *sql.Tx, id string) (*storages.Task, error) {
+	t := &storages.Task{}
+	stmt := `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE id = ?`
+	err := tx.QueryRowContext(ctx, stmt, id).Scan(taskValues(t)...)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// CarryOverTasks copies or moves the incomplete tasks of co.UserID from co.FromDate to co.ToDate.
+// Either all of them are carried or, when that would exceed the limit on co.ToDate, none and
+// TaskLimitReached is returned along with how many would have been. Users in QuotaSoft mode
+// get all of them carried, those beyond the limit with OverQuota set. Each user and date is
+// carried at most once, later calls return 0.
+func (l *LiteDB) CarryOverTasks(ctx context.Context, co *storages.CarryOver) (int, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO carry_overs (user_id, date) VALUES (?, ?)`, co.UserID, co.ToDate)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return 0, err
+	}
+
+	stmt := `SELECT ` + fullTaskColumnList + ` FROM tasks WHERE user_id = ? AND created_date = ? AND completed_at = ''`
+	rows, err := tx.QueryContext(ctx, stmt, co.UserID, co.FromDate)
+	if err != nil {
+		return 0, err
+	}
+	var tasks []*storages.Task
+	for rows.Next() {
+		t := &storages.Task{}
+		if err := rows.Scan(taskValues(t)...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		tasks = append(tasks, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, tx.Commit()
+	}
+
+	var count, maxTodo int
+	stmt = `SELECT (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?), ` + maxTodoOn + ` FROM users WHERE id = ?`
+	args := append([]interface{}{co.UserID, co.ToDate}, maxTodoArgs(co.UserID, co.ToDate)...)
+	if err := tx.QueryRowContext(ctx, stmt, append(args, co.UserID)...).Scan(&count, &maxTodo); err != nil {
+		return 0, err
+	}
+	mode, err := quotaMode(ctx, tx, co.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if count+len(tasks) > maxTodo && mode != storages.QuotaSoft {
+		// keep the carry_overs row so the next run doesn't retry a day we already skipped
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return len(tasks), &storages.TaskLimitReached{UserID: co.UserID, Date: co.ToDate}
+	}
+
+	for i, t := range tasks {
+		over := count+i >= maxTodo
+		if co.Move {
+			_, err = tx.ExecContext(ctx, `UPDATE tasks SET created_date = ?, over_quota = over_quota OR ? WHERE id = ?`, co.ToDate, over, t.ID)
+			if err == nil {
+				_, err = tx.ExecContext(ctx, `INSERT INTO task_history (task_id, user_id, action, from_date, to_date, at) VALUES (?, ?, ?, ?, ?, ?)`,
+					t.ID, co.UserID, "carry_over", co.FromDate, co.ToDate, co.At)
+			}
+		} else {
+			t.ID = co.NewID()
+			t.CreatedDate = co.ToDate
+			t.CreatedAt = co.At
+			t.OverQuota = t.OverQuota || over
+			if err = saveBody(ctx, tx, t); err == nil {
+				_, err = tx.ExecContext(ctx, insertTaskStmt, taskRow(t)...)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(tasks), tx.Commit()
+}
+
+// CountTasks returns how many tasks the user has on createdDate along with their limit that day
+func (l *LiteDB) CountTasks(ctx context.Context, userID, createdDate sql.NullString) (count, maxTodo int, err error) {
+	stmt := `SELECT (SELECT COUNT(*) FROM tasks WHERE user_id = ? AND created_date = ?), ` + maxTodoOn + ` FROM users WHERE id = ?`
+	args := append([]interface{}{userID, createdDate}, maxTodoArgs(userID, createdDate)...)
+	err = l.DB.QueryRowContext(ctx, stmt, append(args, userID)...).Scan(&count, &maxTodo)
+	return count, maxTodo, err
+}
+
+// RetrieveDayCounts returns how many tasks userID created and completed on each day from from
+// up to before to, both YYYY-MM-DD, with the time tracked by the timers they started then and
+// stopped and the pomodoros they started then and completed. Completion, tracking and pomodoro
+// days are UTC. Days without any are left out.
+func (l *LiteDB) RetrieveDayCounts(ctx context.Context, userID sql.NullString, from, to string) ([]*storages.DayCount, error) {
+	// each part is read from the (user_id, created_date), (user_id, completed_at) and
+	// (user_id, started_at) indexes
+	stmt := `SELECT day, SUM(created), SUM(completed), SUM(tracked), SUM(pomodoros), SUM(focus) FROM (
+			SELECT created_date AS day, COUNT(*) AS created, 0 AS completed, 0 AS tracked, 0 AS pomodoros, 0 AS focus FROM tasks
+			WHERE user_id = ? AND created_date >= ? AND created_date < ? GROUP BY created_date
+			UNION ALL
+			SELECT substr(completed_at, 1, 10), 0, COUNT(*), 0, 0, 0 FROM tasks
+			WHERE user_id = ? AND completed_at <> '' AND completed_at >= ? AND completed_at < ? GROUP BY 1
+			UNION ALL
+			SELECT substr(started_at, 1, 10), 0, 0, SUM(seconds), 0, 0 FROM time_entries
+			WHERE user_id = ? AND started_at >= ? AND started_at < ? AND stopped_at <> '' GROUP BY 1
+			UNION ALL
+			SELECT substr(started_at, 1, 10), 0, 0, 0, COUNT(*), SUM(seconds) FROM pomodoros
+			WHERE user_id = ? AND started_at >= ? AND started_at < ? AND completed_at <> '' GROUP BY 1
+		) GROUP BY day ORDER BY day`
+	rows, err := l.DB.QueryContext(ctx, stmt, userID, from, to, userID, from, to, userID, from, to, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*storages.DayCount
+	for rows.Next() {
+		c := &storages.DayCount{}
+		if err := rows.Scan(&c.Date, &c.Created, &c.Completed, &c.TrackedSeconds, &c.Pomodoros, &c.FocusSeconds); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// RetrievePriorityCounts returns how many tasks userID has on createdDate per priority,
+// highest first, leaving out priorities without any
+func (l *LiteDB) RetrievePriorityCounts(ctx context.Context, userID, createdDate sql.NullString) ([]*storages.PriorityCount, error) {
+	stmt := `SELECT priority, COUNT(*), SUM(completed_at <> '') FROM tasks
+		WHERE user_id = ? AND created_date = ? GROUP BY priority ORDER BY priority DESC`
+	rows, err := l.DB.QueryContext(ctx, stmt, userID, createdDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*storages.PriorityCount{}
+	for rows.Next() {
+		c := &storages.PriorityCount{}
+		if err := rows.Scan(&c.Priority, &c.Count, &c.Completed); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// AddTasks inserts many tasks at once, using a single transaction and prepared statement
+// so SQLite syncs to disk once instead of once per row
+func (l *LiteDB) AddTasks(ctx context.Context, tasks []*storages.Task) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, insertTaskStmt)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, t := range tasks {
+		if err := saveBody(ctx, tx, t); err != nil {
+			return err
+		}
+		_, err := stmt.ExecContext(ctx, taskRow(t)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddUser adds a new user to DB
+func (l *LiteDB) AddUser(ctx context.Context, u *storages.User) error {
+	stmt := `INSERT INTO users (id, password, max_todo, role, deactivated_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := l.DB.ExecContext(ctx, stmt, &u.ID, &u.Password, &u.MaxTodo, &u.Role, &u.DeactivatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetrievePasswordHash returns the stored password hash of userID
+func (l *LiteDB) RetrievePasswordHash(ctx context.Context, userID sql.NullString) (string, error) {
+	stmt := `SELECT password FROM users WHERE id = ?`
+	var hash string
+	err := l.DB.QueryRowContext(ctx, stmt, userID).Scan(&hash)
+	return hash, err
+}
+
+// UpdatePasswordHash replaces the stored password hash of userID
+func (l *LiteDB) UpdatePasswordHash(ctx context.Context, userID sql.NullString, hash string) error {
+	stmt := `UPDATE users SET password = ? WHERE id = ?`
+	_, err := l.DB.ExecContext(ctx, stmt, hash, userID)
+	return err
 }
@@ -0,0 +1,51 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/manabie-com/togo/pkg/storages/storagetest"
+)
+
+// newTestDB opens a migrated database in a file of its own, the way main.go opens data.db
+func newTestDB(t *testing.T) *LiteDB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "togo.db")+"?_txlock=immediate")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	l := &LiteDB{DB: db}
+	if err := l.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return l
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformanceTests(t, newTestDB(t))
+}
+
+func TestVerifySchema(t *testing.T) {
+	if err := newTestDB(t).VerifySchema(context.Background()); err != nil {
+		t.Errorf("VerifySchema after Migrate: %v", err)
+	}
+}
+
+func TestOpenRegistered(t *testing.T) {
+	b, err := storages.Open(context.Background(), "sqlite", storages.OpenOptions{DSN: filepath.Join(t.TempDir(), "togo.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer b.DB.Close()
+	if _, ok := b.Store.(*LiteDB); !ok {
+		t.Errorf("Open answered a %T, want *LiteDB", b.Store)
+	}
+	if _, err := storages.Open(context.Background(), "nosuch", storages.OpenOptions{}); err == nil {
+		t.Error("Open of an unknown backend succeeded")
+	}
+}
@@ -0,0 +1,61 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// AddInvite stores a new invite
+func (l *LiteDB) AddInvite(ctx context.Context, inv *storages.Invite) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO invites (code, created_by, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		inv.Code, inv.CreatedBy, inv.CreatedAt, inv.ExpiresAt)
+	return err
+}
+
+// SignUp adds u using up the invite code at the time at, both or neither. It returns
+// ErrNotFound when the code is unknown, used or expired and ErrUserExists when u.ID
+// is taken, leaving the code usable.
+func (l *LiteDB) SignUp(ctx context.Context, code string, u *storages.User, at string) error {
+	return busyRetry.Do(ctx, func(ctx context.Context) error {
+		return l.signUp(ctx, code, u, at)
+	})
+}
+
+func (l *LiteDB) signUp(ctx context.Context, code string, u *storages.User, at string) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE invites SET used_by = ?, used_at = ?
+		WHERE code = ? AND used_by = '' AND expires_at > ?`, u.ID, at, code, at)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE id = ?`, u.ID).Scan(&exists)
+	if err == nil {
+		return storages.ErrUserExists
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO users (id, password, max_todo, role, deactivated_at) VALUES (?, ?, ?, ?, ?)`,
+		u.ID, u.Password, u.MaxTodo, u.Role, u.DeactivatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
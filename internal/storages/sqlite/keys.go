@@ -0,0 +1,73 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RotateSigningKey retires the key in use at k.CreatedAt and makes k the new one
+func (l *LiteDB) RotateSigningKey(ctx context.Context, k *storages.SigningKey) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE signing_keys SET retired_at = ? WHERE retired_at = ''`, k.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	stmt := `INSERT INTO signing_keys (id, algorithm, private_key, created_at) VALUES (?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, stmt, k.ID, k.Algorithm, k.PrivateKey, k.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RetrieveSigningKeys returns the key in use and those retired after retiredAfter, newest first
+func (l *LiteDB) RetrieveSigningKeys(ctx context.Context, retiredAfter string) ([]*storages.SigningKey, error) {
+	stmt := `SELECT id, algorithm, private_key, created_at, retired_at FROM signing_keys
+		WHERE retired_at = '' OR retired_at > ? ORDER BY created_at DESC`
+	rows, err := l.DB.QueryContext(ctx, stmt, retiredAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*storages.SigningKey
+	for rows.Next() {
+		k := &storages.SigningKey{}
+		if err := rows.Scan(&k.ID, &k.Algorithm, &k.PrivateKey, &k.CreatedAt, &k.RetiredAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// AddAPIKey stores an API key
+func (l *LiteDB) AddAPIKey(ctx context.Context, k *storages.APIKey) error {
+	stmt := `INSERT INTO api_keys (id, user_id, name, key_hash, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := l.DB.ExecContext(ctx, stmt, k.ID, k.UserID, k.Name, k.KeyHash, k.CreatedAt)
+	return err
+}
+
+// RetrieveAPIKeyUser returns the user owning the API key with keyHash, ErrNotFound for unknown keys
+func (l *LiteDB) RetrieveAPIKeyUser(ctx context.Context, keyHash string) (string, error) {
+	var userID string
+	err := l.DB.QueryRowContext(ctx, `SELECT user_id FROM api_keys WHERE key_hash = ?`, keyHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", storages.ErrNotFound
+	}
+	return userID, err
+}
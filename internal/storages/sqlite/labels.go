@@ -0,0 +1,150 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/mattn/go-sqlite3"
+)
+
+// AddLabel stores a new label, ErrLabelExists when its user has one of the same name
+// ignoring ASCII case
+func (l *LiteDB) AddLabel(ctx context.Context, label *storages.Label) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO labels (id, user_id, name, color, created_at) VALUES (?, ?, ?, ?, ?)`,
+		label.ID, label.UserID, label.Name, label.Color, label.CreatedAt)
+	if isUniqueViolation(err) {
+		return storages.ErrLabelExists
+	}
+	return err
+}
+
+// RetrieveLabels returns the labels of userID by name
+func (l *LiteDB) RetrieveLabels(ctx context.Context, userID sql.NullString) ([]*storages.Label, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, user_id, name, color, created_at FROM labels
+		WHERE user_id = ? ORDER BY name COLLATE NOCASE, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLabels(rows)
+}
+
+// UpdateLabel renames and recolors the label of label.UserID with label.ID, ErrNotFound if
+// they have none and ErrLabelExists if another of theirs has the new name. CreatedAt is
+// read back into label.
+func (l *LiteDB) UpdateLabel(ctx context.Context, label *storages.Label) error {
+	res, err := l.DB.ExecContext(ctx, `UPDATE labels SET name = ?, color = ? WHERE id = ? AND user_id = ?`,
+		label.Name, label.Color, label.ID, label.UserID)
+	if isUniqueViolation(err) {
+		return storages.ErrLabelExists
+	}
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return l.DB.QueryRowContext(ctx, `SELECT created_at FROM labels WHERE id = ?`, label.ID).Scan(&label.CreatedAt)
+}
+
+// DeleteLabel deletes a label of userID and unassigns it from their tasks, ErrNotFound if
+// they have no such label
+func (l *LiteDB) DeleteLabel(ctx context.Context, userID, labelID sql.NullString) error {
+	return busyRetry.Do(ctx, func(ctx context.Context) error {
+		tx, err := l.DB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM labels WHERE id = ? AND user_id = ?`, labelID, userID)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return storages.ErrNotFound
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE label_id = ?`, labelID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// AssignLabel tags a task of userID with one of their labels, ErrNotFound if either isn't
+// theirs. Assigning a label a task has already does nothing.
+func (l *LiteDB) AssignLabel(ctx context.Context, userID, taskID, labelID sql.NullString, at string) error {
+	res, err := l.DB.ExecContext(ctx, `INSERT INTO task_labels (task_id, label_id, created_at)
+		SELECT t.id, b.id, ? FROM tasks t JOIN labels b ON b.user_id = t.user_id
+		WHERE t.id = ? AND b.id = ? AND t.user_id = ?
+		ON CONFLICT (task_id, label_id) DO UPDATE SET created_at = created_at`,
+		at, taskID, labelID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// UnassignLabel removes a label from a task of userID, ErrNotFound if the task doesn't have it
+func (l *LiteDB) UnassignLabel(ctx context.Context, userID, taskID, labelID sql.NullString) error {
+	res, err := l.DB.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = ? AND label_id = ?
+		AND label_id IN (SELECT id FROM labels WHERE user_id = ?)`, taskID, labelID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// RetrieveTaskLabels returns the labels of a task of userID by name
+func (l *LiteDB) RetrieveTaskLabels(ctx context.Context, userID, taskID sql.NullString) ([]*storages.Label, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT b.id, b.user_id, b.name, b.color, b.created_at FROM task_labels k
+		JOIN labels b ON b.id = k.label_id
+		WHERE k.task_id = ? AND b.user_id = ? ORDER BY b.name COLLATE NOCASE, b.id`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLabels(rows)
+}
+
+func scanLabels(rows *sql.Rows) ([]*storages.Label, error) {
+	defer rows.Close()
+
+	labels := []*storages.Label{}
+	for rows.Next() {
+		label := &storages.Label{}
+		if err := rows.Scan(&label.ID, &label.UserID, &label.Name, &label.Color, &label.CreatedAt); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// isUniqueViolation reports whether err is a UNIQUE constraint failing
+func isUniqueViolation(err error) bool {
+	var se sqlite3.Error
+	return errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintUnique
+}
@@ -0,0 +1,75 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RecordLimitHit counts one more task of userID refused, or added over quota, on date because
+// of the daily limit, filing the hit under the max_todo the user has at the first one
+func (l *LiteDB) RecordLimitHit(ctx context.Context, userID, date sql.NullString, at string) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO limit_hits (user_id, date, max_todo, attempts, first_at)
+		SELECT id, ?, max_todo, 1, ? FROM users WHERE id = ?
+		ON CONFLICT (user_id, date) DO UPDATE SET attempts = attempts + 1`, date, at, userID)
+	return err
+}
+
+// RollUpLimitHits replaces the rollup of date with the hits recorded for it so far, per
+// max_todo, returning how many max_todo values had hits. Run once date is over, running it
+// again later counts hits recorded since.
+func (l *LiteDB) RollUpLimitHits(ctx context.Context, date string) (int, error) {
+	res, err := l.DB.ExecContext(ctx, `INSERT OR REPLACE INTO limit_hit_rollups (date, max_todo, users, attempts, cohort_users)
+		SELECT h.date, h.max_todo, COUNT(*), SUM(h.attempts),
+			(SELECT COUNT(*) FROM users WHERE max_todo = h.max_todo AND deactivated_at = '')
+		FROM limit_hits h WHERE h.date = ? GROUP BY h.max_todo`, date)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// RetrieveLimitHitRollups returns the rollups of the dates from from to to, both included,
+// by date then max_todo
+func (l *LiteDB) RetrieveLimitHitRollups(ctx context.Context, from, to string) ([]*storages.LimitHitRollup, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT date, max_todo, users, attempts, cohort_users FROM limit_hit_rollups
+		WHERE date >= ? AND date <= ? ORDER BY date, max_todo`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rollups := []*storages.LimitHitRollup{}
+	for rows.Next() {
+		r := &storages.LimitHitRollup{}
+		if err := rows.Scan(&r.Date, &r.MaxTodo, &r.Users, &r.Attempts, &r.CohortUsers); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, r)
+	}
+	return rollups, rows.Err()
+}
+
+// RetrieveLimitOffenders returns the n users who hit their limit on the most dates from from
+// to to, both included, the most attempts first among those tied
+func (l *LiteDB) RetrieveLimitOffenders(ctx context.Context, from, to string, n int) ([]*storages.LimitOffender, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT user_id, COUNT(*) AS days, SUM(attempts) AS attempts, MAX(date)
+		FROM limit_hits WHERE date >= ? AND date <= ?
+		GROUP BY user_id ORDER BY days DESC, attempts DESC, user_id LIMIT ?`, from, to, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offenders := []*storages.LimitOffender{}
+	for rows.Next() {
+		o := &storages.LimitOffender{}
+		if err := rows.Scan(&o.UserID, &o.Days, &o.Attempts, &o.LastDate); err != nil {
+			return nil, err
+		}
+		offenders = append(offenders, o)
+	}
+	return offenders, rows.Err()
+}
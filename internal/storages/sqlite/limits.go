@@ -0,0 +1,158 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// maxTodoOn is the limit of a user on a date: their holiday limit when the date is a
+// holiday, their weekend limit on Saturdays and Sundays and max_todo otherwise, plus the
+// quota adjustments granted for the date. It is NULL for unknown users. Bind its
+// parameters with maxTodoArgs.
+const maxTodoOn = `(COALESCE(
+	(SELECT s.max_todo FROM limit_schedules s JOIN holidays h ON h.date = ? WHERE s.user_id = ? AND s.day_kind = 'holiday'),
+	(SELECT max_todo FROM limit_schedules WHERE user_id = ? AND day_kind = 'weekend' AND strftime('%w', ?) IN ('0', '6')),
+	(SELECT max_todo FROM users WHERE id = ?))
+	+ (SELECT COALESCE(SUM(amount), 0) FROM quota_adjustments WHERE user_id = ? AND date = ?))`
+
+func maxTodoArgs(userID, date interface{}) []interface{} {
+	return []interface{}{date, userID, userID, date, userID, userID, date}
+}
+
+// RetrieveLimitSchedule returns the weekend and holiday limits of userID
+func (l *LiteDB) RetrieveLimitSchedule(ctx context.Context, userID sql.NullString) (*storages.LimitSchedule, error) {
+	if err := l.userExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := l.DB.QueryContext(ctx, `SELECT day_kind, max_todo FROM limit_schedules WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sched := &storages.LimitSchedule{}
+	for rows.Next() {
+		var kind string
+		var maxTodo int
+		if err := rows.Scan(&kind, &maxTodo); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case storages.DayWeekend:
+			sched.Weekend = &maxTodo
+		case storages.DayHoliday:
+			sched.Holiday = &maxTodo
+		}
+	}
+	return sched, rows.Err()
+}
+
+// UpdateLimitSchedule replaces the weekend and holiday limits of userID, nil ones fall
+// back to max_todo
+func (l *LiteDB) UpdateLimitSchedule(ctx context.Context, userID sql.NullString, sched *storages.LimitSchedule) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE id = ?`, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return storages.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM limit_schedules WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	for kind, maxTodo := range map[string]*int{storages.DayWeekend: sched.Weekend, storages.DayHoliday: sched.Holiday} {
+		if maxTodo == nil {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO limit_schedules (user_id, day_kind, max_todo) VALUES (?, ?, ?)`,
+			userID, kind, *maxTodo)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceHolidays makes dates the holidays from from up to before to, all YYYY-MM-DD
+func (l *LiteDB) ReplaceHolidays(ctx context.Context, from, to string, dates []string) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM holidays WHERE date >= ? AND date < ?`, from, to); err != nil {
+		return err
+	}
+	for _, d := range dates {
+		if d < from || d >= to {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO holidays (date) VALUES (?)`, d); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddQuotaAdjustment grants a.UserID a.Amount more tasks on a.Date, ErrNotFound if there's
+// no such user
+func (l *LiteDB) AddQuotaAdjustment(ctx context.Context, a *storages.QuotaAdjustment) error {
+	res, err := l.DB.ExecContext(ctx, `INSERT INTO quota_adjustments (id, user_id, date, amount, reason, created_by, created_at)
+		SELECT ?, ?, ?, ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM users WHERE id = ?)`,
+		a.ID, a.UserID, a.Date, a.Amount, a.Reason, a.CreatedBy, a.CreatedAt, a.UserID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// RetrieveQuotaAdjustments returns the adjustments of userID for the dates from to to, both
+// included, in date and creation order
+func (l *LiteDB) RetrieveQuotaAdjustments(ctx context.Context, userID sql.NullString, from, to string) ([]*storages.QuotaAdjustment, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, user_id, date, amount, reason, created_by, created_at
+		FROM quota_adjustments WHERE user_id = ? AND date >= ? AND date <= ? ORDER BY date, created_at, id`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	adjustments := []*storages.QuotaAdjustment{}
+	for rows.Next() {
+		a := &storages.QuotaAdjustment{}
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Date, &a.Amount, &a.Reason, &a.CreatedBy, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, a)
+	}
+	return adjustments, rows.Err()
+}
+
+func (l *LiteDB) userExists(ctx context.Context, userID sql.NullString) error {
+	var exists int
+	err := l.DB.QueryRowContext(ctx, `SELECT 1 FROM users WHERE id = ?`, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return storages.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// linkedTaskColumnList is taskColumnList qualified for queries joining tasks as t, then
+// whether the content is a summary
+var linkedTaskColumnList = "t." + strings.Join(storages.TaskFields, ", t.") + ", t.content_truncated"
+
+// AddTaskLink records link, adding a link that exists already does nothing
+func (l *LiteDB) AddTaskLink(ctx context.Context, link *storages.TaskLink) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO task_links (task_id, blocked_by_id, user_id, created_at)
+		VALUES (?, ?, ?, ?) ON CONFLICT (task_id, blocked_by_id) DO NOTHING`,
+		link.TaskID, link.BlockedByID, link.UserID, link.CreatedAt)
+	return err
+}
+
+// RemoveTaskLink removes the link of userID from taskID to blockedByID, ErrNotFound if there is none
+func (l *LiteDB) RemoveTaskLink(ctx context.Context, userID, taskID, blockedByID sql.NullString) error {
+	res, err := l.DB.ExecContext(ctx, `DELETE FROM task_links WHERE task_id = ? AND blocked_by_id = ? AND user_id = ?`,
+		taskID, blockedByID, userID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// RetrieveTaskLinks returns the links of userID between tasks that weren't deleted
+func (l *LiteDB) RetrieveTaskLinks(ctx context.Context, userID sql.NullString) ([]*storages.TaskLink, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT k.task_id, k.blocked_by_id, k.user_id, k.created_at FROM task_links k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		JOIN tasks b ON b.id = k.blocked_by_id AND b.user_id = k.user_id
+		WHERE k.user_id = ? ORDER BY k.created_at, k.task_id, k.blocked_by_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*storages.TaskLink{}
+	for rows.Next() {
+		link := &storages.TaskLink{}
+		if err := rows.Scan(&link.TaskID, &link.BlockedByID, &link.UserID, &link.CreatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// RetrieveLinkedTasks returns the tasks of userID blocking taskID and those taskID blocks,
+// leaving out deleted ones
+func (l *LiteDB) RetrieveLinkedTasks(ctx context.Context, userID, taskID sql.NullString) (blockedBy, blocking []*storages.Task, err error) {
+	blockedBy, err = l.linkedTasks(ctx, `SELECT `+linkedTaskColumnList+` FROM task_links k
+		JOIN tasks t ON t.id = k.blocked_by_id AND t.user_id = k.user_id
+		WHERE k.task_id = ? AND k.user_id = ? ORDER BY t.created_at, t.id`, taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blocking, err = l.linkedTasks(ctx, `SELECT `+linkedTaskColumnList+` FROM task_links k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		WHERE k.blocked_by_id = ? AND k.user_id = ? ORDER BY t.created_at, t.id`, taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blockedBy, blocking, nil
+}
+
+func (l *LiteDB) linkedTasks(ctx context.Context, stmt string, args ...interface{}) ([]*storages.Task, error) {
+	rows, err := l.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*storages.Task{}
+	for rows.Next() {
+		t := &storages.Task{}
+		if err := rows.Scan(append(taskValues(t), &t.ContentTruncated)...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
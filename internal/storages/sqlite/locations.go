@@ -0,0 +1,115 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/manabie-com/togo/internal/geo"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// SetTaskLocation sets where a task of userID is done, replacing where it was, ErrNotFound if
+// they have no such task
+func (l *LiteDB) SetTaskLocation(ctx context.Context, userID, taskID sql.NullString, loc storages.Location) error {
+	res, err := l.DB.ExecContext(ctx, `INSERT INTO task_locations (task_id, user_id, latitude, longitude)
+		SELECT id, user_id, ?, ? FROM tasks WHERE id = ? AND user_id = ?
+		ON CONFLICT (task_id) DO UPDATE SET latitude = excluded.latitude, longitude = excluded.longitude`,
+		loc.Lat, loc.Lng, taskID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// RemoveTaskLocation forgets where a task of userID is done, ErrNotFound if it has no location
+func (l *LiteDB) RemoveTaskLocation(ctx context.Context, userID, taskID sql.NullString) error {
+	res, err := l.DB.ExecContext(ctx, `DELETE FROM task_locations WHERE task_id = ? AND user_id = ?`, taskID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// RetrieveTaskLocation returns where a task of userID is done, ErrNotFound if it has no
+// location
+func (l *LiteDB) RetrieveTaskLocation(ctx context.Context, userID, taskID sql.NullString) (*storages.Location, error) {
+	loc := &storages.Location{}
+	err := l.DB.QueryRowContext(ctx, `SELECT latitude, longitude FROM task_locations WHERE task_id = ? AND user_id = ?`,
+		taskID, userID).Scan(&loc.Lat, &loc.Lng)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// RetrieveNearbyTasks returns the q.N tasks of q.UserID closest to q.At within q.RadiusKM,
+// closest first. SQLite has no trigonometry, so the rows within the bounding box of the
+// circle are read and their distances computed here.
+func (l *LiteDB) RetrieveNearbyTasks(ctx context.Context, q *storages.NearbyQuery) ([]*storages.NearbyTask, error) {
+	box := geo.BoundingBox(q.At.Lat, q.At.Lng, q.RadiusKM)
+	stmt := `SELECT ` + linkedTaskColumnList + `, k.latitude, k.longitude FROM task_locations k
+		JOIN tasks t ON t.id = k.task_id AND t.user_id = k.user_id
+		WHERE k.user_id = ? AND k.latitude BETWEEN ? AND ?`
+	args := []interface{}{q.UserID, box.MinLat, box.MaxLat}
+	if !box.AllLng {
+		stmt += ` AND k.longitude BETWEEN ? AND ?`
+		args = append(args, box.MinLng, box.MaxLng)
+	}
+	if q.CreatedDate != "" {
+		stmt += ` AND t.created_date = ?`
+		args = append(args, q.CreatedDate)
+	}
+	if q.Open {
+		stmt += ` AND t.completed_at = ''`
+	}
+	rows, err := l.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nearby := []*storages.NearbyTask{}
+	for rows.Next() {
+		nt := &storages.NearbyTask{Task: &storages.Task{}}
+		dest := append(taskValues(nt.Task), &nt.Task.ContentTruncated, &nt.Location.Lat, &nt.Location.Lng)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		nt.DistanceKM = geo.DistanceKM(q.At.Lat, q.At.Lng, nt.Location.Lat, nt.Location.Lng)
+		if nt.DistanceKM <= q.RadiusKM {
+			nearby = append(nearby, nt)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKM != nearby[j].DistanceKM {
+			return nearby[i].DistanceKM < nearby[j].DistanceKM
+		}
+		return nearby[i].Task.ID < nearby[j].Task.ID
+	})
+	if len(nearby) > q.N {
+		nearby = nearby[:q.N]
+	}
+	return nearby, nil
+}
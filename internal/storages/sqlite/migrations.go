@@ -0,0 +1,484 @@
+package sqllite
+
+import (
+	"context"
+	"fmt"
+)
+
+// migrations are applied in order, PRAGMA user_version records how many have run.
+// Never edit an entry once released, append a new one instead.
+var migrations = []string{
+	// 1: base schema, a no-op on databases created from the README
+	`CREATE TABLE IF NOT EXISTS users (
+		id TEXT NOT NULL,
+		password TEXT NOT NULL,
+		max_todo INTEGER DEFAULT 5 NOT NULL,
+		CONSTRAINT users_PK PRIMARY KEY (id)
+	);
+	CREATE TABLE IF NOT EXISTS tasks (
+		id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created_date TEXT NOT NULL,
+		CONSTRAINT tasks_PK PRIMARY KEY (id),
+		CONSTRAINT tasks_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 2: sortable task attributes, each backed by an index matching the list query
+	`ALTER TABLE tasks ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE tasks ADD COLUMN due_date TEXT NOT NULL DEFAULT '';
+	UPDATE tasks SET created_at = created_date WHERE created_at = '';
+	CREATE INDEX tasks_user_date_created_at ON tasks (user_id, created_date, created_at);
+	CREATE INDEX tasks_user_date_priority ON tasks (user_id, created_date, priority);
+	CREATE INDEX tasks_user_date_due_date ON tasks (user_id, created_date, due_date);`,
+
+	// 3: login sessions, one per issued token
+	`CREATE TABLE sessions (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		device TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		revoked_at TEXT,
+		CONSTRAINT sessions_PK PRIMARY KEY (id),
+		CONSTRAINT sessions_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX sessions_user_expires_at ON sessions (user_id, expires_at);`,
+
+	// 4: opt-in to limit reached notifications, sent at most once per user and date
+	`ALTER TABLE users ADD COLUMN notify_limit_reached INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE limit_notifications (
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		CONSTRAINT limit_notifications_PK PRIMARY KEY (user_id, date),
+		CONSTRAINT limit_notifications_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 5: what happened to a task after it was created
+	`CREATE TABLE task_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		from_date TEXT NOT NULL,
+		to_date TEXT NOT NULL,
+		at TEXT NOT NULL
+	);
+	CREATE INDEX task_history_task_id ON task_history (task_id, at);`,
+
+	// 6: task completion and carrying incomplete tasks over to the next day
+	`ALTER TABLE tasks ADD COLUMN completed_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN carry_over TEXT NOT NULL DEFAULT '';
+	CREATE TABLE carry_overs (
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		CONSTRAINT carry_overs_PK PRIMARY KEY (user_id, date),
+		CONSTRAINT carry_overs_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 7: daily digest email
+	`ALTER TABLE users ADD COLUMN daily_digest BOOLEAN NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+	CREATE TABLE digests (
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		CONSTRAINT digests_PK PRIMARY KEY (user_id, date),
+		CONSTRAINT digests_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 8: asymmetric token signing keys, published as a JWKS
+	`CREATE TABLE signing_keys (
+		id TEXT NOT NULL,
+		algorithm TEXT NOT NULL,
+		private_key TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		retired_at TEXT NOT NULL DEFAULT '',
+		CONSTRAINT signing_keys_PK PRIMARY KEY (id)
+	);`,
+
+	// 9: API keys, looked up by hash
+	`CREATE TABLE api_keys (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		key_hash TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		CONSTRAINT api_keys_PK PRIMARY KEY (id),
+		CONSTRAINT api_keys_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE UNIQUE INDEX api_keys_key_hash ON api_keys (key_hash);`,
+
+	// 10: administrators
+	`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT '';`,
+
+	// 11: tasks as they were before a batch operation, restored by an undo
+	`CREATE TABLE undo_ops (
+		token TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		tasks TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		CONSTRAINT undo_ops_PK PRIMARY KEY (token)
+	);
+	CREATE INDEX undo_ops_expires_at ON undo_ops (expires_at);`,
+
+	// 12: the archive job reads the tasks completed each day
+	`CREATE INDEX tasks_completed_at ON tasks (completed_at) WHERE completed_at <> '';`,
+
+	// 13: per user completion counts, for the heatmap
+	`CREATE INDEX tasks_user_completed_at ON tasks (user_id, completed_at) WHERE completed_at <> '';`,
+
+	// 14: completion streaks, extended by the stats job each day
+	`CREATE TABLE streaks (
+		user_id TEXT NOT NULL,
+		current INTEGER NOT NULL,
+		longest INTEGER NOT NULL,
+		last_day TEXT NOT NULL,
+		CONSTRAINT streaks_PK PRIMARY KEY (user_id),
+		CONSTRAINT streaks_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 15: idempotency keys of recent task creations, so clients can retry them safely
+	`CREATE TABLE idempotency_keys (
+		user_id TEXT NOT NULL,
+		key TEXT NOT NULL,
+		task_id TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		CONSTRAINT idempotency_keys_PK PRIMARY KEY (user_id, key),
+		CONSTRAINT idempotency_keys_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX idempotency_keys_expires_at ON idempotency_keys (expires_at);`,
+
+	// 16: API calls per user and UTC day, for metering
+	`CREATE TABLE api_usage (
+		user_id TEXT NOT NULL,
+		day TEXT NOT NULL,
+		calls INTEGER NOT NULL,
+		CONSTRAINT api_usage_PK PRIMARY KEY (user_id, day),
+		CONSTRAINT api_usage_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+
+	// 17: soft quota mode, which adds tasks beyond max_todo flagged over_quota
+	`ALTER TABLE tasks ADD COLUMN over_quota BOOLEAN NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN quota_mode TEXT NOT NULL DEFAULT '';`,
+
+	// 18: limits replacing max_todo on weekends and holidays, and the holiday calendar
+	`CREATE TABLE limit_schedules (
+		user_id TEXT NOT NULL,
+		day_kind TEXT NOT NULL,
+		max_todo INTEGER NOT NULL,
+		CONSTRAINT limit_schedules_PK PRIMARY KEY (user_id, day_kind),
+		CONSTRAINT limit_schedules_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE TABLE holidays (
+		date TEXT NOT NULL,
+		CONSTRAINT holidays_PK PRIMARY KEY (date)
+	);`,
+
+	// 19: audit log of admin impersonation
+	`CREATE TABLE audit_log (
+		id TEXT NOT NULL,
+		at TEXT NOT NULL,
+		actor_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		detail TEXT NOT NULL,
+		CONSTRAINT audit_log_PK PRIMARY KEY (id)
+	);
+	CREATE INDEX audit_log_user_at ON audit_log (user_id, at);
+	CREATE INDEX audit_log_actor_at ON audit_log (actor_id, at);`,
+
+	// 20: "blocked by" links between tasks, kept when a task is deleted so undoing brings them back
+	`CREATE TABLE task_links (
+		task_id TEXT NOT NULL,
+		blocked_by_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		CONSTRAINT task_links_PK PRIMARY KEY (task_id, blocked_by_id),
+		CONSTRAINT task_links_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX task_links_blocked_by ON task_links (blocked_by_id);
+	CREATE INDEX task_links_user ON task_links (user_id);`,
+
+	// 21: every version of every task, written by triggers so no change is missed, for reading
+	// a user's tasks as they were at some point. Tasks already there start at their creation.
+	`CREATE TABLE task_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id TEXT NOT NULL,
+		valid_from TEXT NOT NULL,
+		deleted BOOLEAN NOT NULL DEFAULT 0,
+		content TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created_date TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		priority INTEGER NOT NULL,
+		due_date TEXT NOT NULL,
+		completed_at TEXT NOT NULL,
+		over_quota BOOLEAN NOT NULL
+	);
+	CREATE INDEX task_revisions_task ON task_revisions (task_id, valid_from);
+	CREATE INDEX task_revisions_user ON task_revisions (user_id, created_date);
+	INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		SELECT id, created_at, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota FROM tasks;
+	CREATE TRIGGER tasks_revision_insert AFTER INSERT ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', NEW.content, NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota);
+	END;
+	CREATE TRIGGER tasks_revision_update AFTER UPDATE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', NEW.content, NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota);
+	END;
+	CREATE TRIGGER tasks_revision_delete AFTER DELETE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, deleted, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (OLD.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', 1, OLD.content, OLD.user_id, OLD.created_date, OLD.created_at, OLD.priority, OLD.due_date, OLD.completed_at, OLD.over_quota);
+	END;`,
+
+	// 22: deactivated users keep their data but can't sign in
+	`ALTER TABLE users ADD COLUMN deactivated_at TEXT NOT NULL DEFAULT '';`,
+
+	// 23: single-use invite codes signing up requires
+	`CREATE TABLE invites (
+		code TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		used_by TEXT NOT NULL DEFAULT '',
+		used_at TEXT NOT NULL DEFAULT '',
+		CONSTRAINT invites_PK PRIMARY KEY (code),
+		CONSTRAINT invites_FK FOREIGN KEY (created_by) REFERENCES users(id)
+	);
+	CREATE INDEX invites_created_by ON invites (created_by);`,
+
+	// 24: one-off extra allowances on top of a user's limit for a date
+	`CREATE TABLE quota_adjustments (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		CONSTRAINT quota_adjustments_PK PRIMARY KEY (id),
+		CONSTRAINT quota_adjustments_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX quota_adjustments_user_date ON quota_adjustments (user_id, date);`,
+
+	// 25: who hit their limit on which date, and the daily rollup of it per max_todo
+	`CREATE TABLE limit_hits (
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		max_todo INTEGER NOT NULL,
+		attempts INTEGER NOT NULL,
+		first_at TEXT NOT NULL,
+		CONSTRAINT limit_hits_PK PRIMARY KEY (user_id, date),
+		CONSTRAINT limit_hits_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX limit_hits_date ON limit_hits (date);
+	CREATE TABLE limit_hit_rollups (
+		date TEXT NOT NULL,
+		max_todo INTEGER NOT NULL,
+		users INTEGER NOT NULL,
+		attempts INTEGER NOT NULL,
+		cohort_users INTEGER NOT NULL,
+		CONSTRAINT limit_hit_rollups_PK PRIMARY KEY (date, max_todo)
+	);`,
+
+	// 26: long contents move to task_bodies, leaving a summary for list reads. Revisions keep
+	// whole contents, the triggers are recreated after the summaries are written so those
+	// don't count as changes.
+	`ALTER TABLE tasks ADD COLUMN content_truncated INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE task_bodies (
+		task_id TEXT NOT NULL,
+		body TEXT NOT NULL,
+		CONSTRAINT task_bodies_PK PRIMARY KEY (task_id)
+	);
+	DROP TRIGGER tasks_revision_insert;
+	DROP TRIGGER tasks_revision_update;
+	DROP TRIGGER tasks_revision_delete;
+	INSERT INTO task_bodies (task_id, body) SELECT id, content FROM tasks WHERE length(CAST(content AS BLOB)) > 1024;
+	UPDATE tasks SET content = substr(content, 1, 200) || '…', content_truncated = 1 WHERE length(CAST(content AS BLOB)) > 1024;
+	CREATE TRIGGER tasks_revision_insert AFTER INSERT ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', COALESCE((SELECT body FROM task_bodies WHERE task_id = NEW.id), NEW.content),
+			NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota);
+	END;
+	CREATE TRIGGER tasks_revision_update AFTER UPDATE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', COALESCE((SELECT body FROM task_bodies WHERE task_id = NEW.id), NEW.content),
+			NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota);
+	END;
+	CREATE TRIGGER tasks_revision_delete AFTER DELETE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, deleted, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota)
+		VALUES (OLD.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', 1, COALESCE((SELECT body FROM task_bodies WHERE task_id = OLD.id), OLD.content),
+			OLD.user_id, OLD.created_date, OLD.created_at, OLD.priority, OLD.due_date, OLD.completed_at, OLD.over_quota);
+		DELETE FROM task_bodies WHERE task_id = OLD.id;
+	END;`,
+
+	// 27: content prefixes, case insensitive like LIKE, for suggestions
+	`CREATE INDEX tasks_user_content ON tasks (user_id, content COLLATE NOCASE);`,
+
+	// 28: labels users define and the tasks they're assigned to, assignments are kept when a
+	// task is deleted so undoing brings them back
+	`CREATE TABLE labels (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		color TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		CONSTRAINT labels_PK PRIMARY KEY (id),
+		CONSTRAINT labels_name UNIQUE (user_id, name COLLATE NOCASE),
+		CONSTRAINT labels_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE TABLE task_labels (
+		task_id TEXT NOT NULL,
+		label_id TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		CONSTRAINT task_labels_PK PRIMARY KEY (task_id, label_id),
+		CONSTRAINT task_labels_FK FOREIGN KEY (label_id) REFERENCES labels(id)
+	);
+	CREATE INDEX task_labels_label ON task_labels (label_id);`,
+
+	// 29: where tasks are done, kept when a task is deleted so undoing brings it back. Nearby
+	// tasks are first narrowed down by latitude.
+	`CREATE TABLE task_locations (
+		task_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		latitude REAL NOT NULL,
+		longitude REAL NOT NULL,
+		CONSTRAINT task_locations_PK PRIMARY KEY (task_id),
+		CONSTRAINT task_locations_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX task_locations_user_latitude ON task_locations (user_id, latitude);`,
+
+	// 30: links showing a day of a user's tasks to anyone who has them
+	`CREATE TABLE shares (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		date TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		expires_at TEXT NOT NULL,
+		revoked_at TEXT,
+		CONSTRAINT shares_PK PRIMARY KEY (id),
+		CONSTRAINT shares_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX shares_user_expires_at ON shares (user_id, expires_at);`,
+
+	// 31: the team standup report, users are in it unless they opt out. standup_reports
+	// records the dates whose report went out, once for every instance.
+	`ALTER TABLE users ADD COLUMN standup_opt_out BOOLEAN NOT NULL DEFAULT 0;
+	CREATE TABLE standup_reports (
+		date TEXT NOT NULL,
+		sent_at TEXT NOT NULL,
+		CONSTRAINT standup_reports_PK PRIMARY KEY (date)
+	);`,
+
+	// 32: estimates of tasks, 0 without one, and the time tracked on them. stopped_at is empty
+	// while the timer runs, a user has one running at most. Entries are kept when a task is
+	// deleted so undoing brings them back.
+	`ALTER TABLE tasks ADD COLUMN estimate_minutes INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE task_revisions ADD COLUMN estimate_minutes INTEGER NOT NULL DEFAULT 0;
+	DROP TRIGGER tasks_revision_insert;
+	DROP TRIGGER tasks_revision_update;
+	DROP TRIGGER tasks_revision_delete;
+	CREATE TRIGGER tasks_revision_insert AFTER INSERT ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota, estimate_minutes)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', COALESCE((SELECT body FROM task_bodies WHERE task_id = NEW.id), NEW.content),
+			NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota, NEW.estimate_minutes);
+	END;
+	CREATE TRIGGER tasks_revision_update AFTER UPDATE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota, estimate_minutes)
+		VALUES (NEW.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', COALESCE((SELECT body FROM task_bodies WHERE task_id = NEW.id), NEW.content),
+			NEW.user_id, NEW.created_date, NEW.created_at, NEW.priority, NEW.due_date, NEW.completed_at, NEW.over_quota, NEW.estimate_minutes);
+	END;
+	CREATE TRIGGER tasks_revision_delete AFTER DELETE ON tasks BEGIN
+		INSERT INTO task_revisions (task_id, valid_from, deleted, content, user_id, created_date, created_at, priority, due_date, completed_at, over_quota, estimate_minutes)
+		VALUES (OLD.id, strftime('%Y-%m-%dT%H:%M:%f', 'now') || '000Z', 1, COALESCE((SELECT body FROM task_bodies WHERE task_id = OLD.id), OLD.content),
+			OLD.user_id, OLD.created_date, OLD.created_at, OLD.priority, OLD.due_date, OLD.completed_at, OLD.over_quota, OLD.estimate_minutes);
+		DELETE FROM task_bodies WHERE task_id = OLD.id;
+	END;
+	CREATE TABLE time_entries (
+		id TEXT NOT NULL,
+		task_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		stopped_at TEXT NOT NULL DEFAULT '',
+		seconds INTEGER NOT NULL DEFAULT 0,
+		CONSTRAINT time_entries_PK PRIMARY KEY (id),
+		CONSTRAINT time_entries_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX time_entries_task ON time_entries (task_id, started_at);
+	CREATE INDEX time_entries_user_started_at ON time_entries (user_id, started_at);
+	CREATE UNIQUE INDEX time_entries_running ON time_entries (user_id) WHERE stopped_at = '';`,
+
+	// 33: pomodoro focus sessions on tasks, completed_at is empty while one runs and a user
+	// has one running at most. Like time entries they are kept when a task is deleted.
+	`CREATE TABLE pomodoros (
+		id TEXT NOT NULL,
+		task_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		planned_minutes INTEGER NOT NULL,
+		completed_at TEXT NOT NULL DEFAULT '',
+		seconds INTEGER NOT NULL DEFAULT 0,
+		CONSTRAINT pomodoros_PK PRIMARY KEY (id),
+		CONSTRAINT pomodoros_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX pomodoros_task ON pomodoros (task_id, started_at);
+	CREATE INDEX pomodoros_user_started_at ON pomodoros (user_id, started_at);
+	CREATE UNIQUE INDEX pomodoros_running ON pomodoros (user_id) WHERE completed_at = '';`,
+
+	// 34: users found creating tasks much faster than usual, once per hour they were found in,
+	// and the stricter creation limits applied to them. The index serves counting tasks
+	// created in an hour.
+	`CREATE INDEX tasks_created_at ON tasks (created_at, user_id);
+	CREATE TABLE anomalies (
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		detected_at TEXT NOT NULL,
+		window_start TEXT NOT NULL,
+		created INTEGER NOT NULL,
+		baseline_per_hour REAL NOT NULL,
+		throttled_until TEXT NOT NULL,
+		CONSTRAINT anomalies_PK PRIMARY KEY (id),
+		CONSTRAINT anomalies_window UNIQUE (user_id, window_start),
+		CONSTRAINT anomalies_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);
+	CREATE INDEX anomalies_detected_at ON anomalies (detected_at);
+	CREATE TABLE creation_throttles (
+		user_id TEXT NOT NULL,
+		per_hour INTEGER NOT NULL,
+		until TEXT NOT NULL,
+		CONSTRAINT creation_throttles_PK PRIMARY KEY (user_id),
+		CONSTRAINT creation_throttles_FK FOREIGN KEY (user_id) REFERENCES users(id)
+	);`,
+}
+
+// Migrate brings the schema up to date
+func (l *LiteDB) Migrate(ctx context.Context) error {
+	var version int
+	if err := l.DB.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := l.DB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// StartPomodoro stores a new running pomodoro, ErrPomodoroRunning when its user has one
+// running already
+func (l *LiteDB) StartPomodoro(ctx context.Context, p *storages.Pomodoro) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO pomodoros (id, task_id, user_id, started_at, planned_minutes) VALUES (?, ?, ?, ?, ?)`,
+		p.ID, p.TaskID, p.UserID, p.StartedAt, p.PlannedMinutes)
+	if isUniqueViolation(err) {
+		return storages.ErrPomodoroRunning
+	}
+	return err
+}
+
+// CompletePomodoro completes a running pomodoro on a task of userID at at, recording how long
+// it lasted. ErrNotFound if they have no such pomodoro running.
+func (l *LiteDB) CompletePomodoro(ctx context.Context, userID, taskID, pomodoroID sql.NullString, at string) (*storages.Pomodoro, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	p := &storages.Pomodoro{}
+	err = tx.QueryRowContext(ctx, `SELECT id, task_id, user_id, started_at, planned_minutes FROM pomodoros
+		WHERE id = ? AND task_id = ? AND user_id = ? AND completed_at = ''`, pomodoroID, taskID, userID).
+		Scan(&p.ID, &p.TaskID, &p.UserID, &p.StartedAt, &p.PlannedMinutes)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.CompletedAt = at
+	if p.Seconds, err = elapsedSeconds(p.StartedAt, at); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE pomodoros SET completed_at = ?, seconds = ? WHERE id = ?`, p.CompletedAt, p.Seconds, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return p, tx.Commit()
+}
+
+// RetrievePomodoros returns the pomodoros on a task of userID, the running one included, in
+// the order they started
+func (l *LiteDB) RetrievePomodoros(ctx context.Context, userID, taskID sql.NullString) ([]*storages.Pomodoro, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, task_id, user_id, started_at, planned_minutes, completed_at, seconds
+		FROM pomodoros WHERE task_id = ? AND user_id = ? ORDER BY started_at, id`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pomodoros := []*storages.Pomodoro{}
+	for rows.Next() {
+		p := &storages.Pomodoro{}
+		if err := rows.Scan(&p.ID, &p.TaskID, &p.UserID, &p.StartedAt, &p.PlannedMinutes, &p.CompletedAt, &p.Seconds); err != nil {
+			return nil, err
+		}
+		pomodoros = append(pomodoros, p)
+	}
+	return pomodoros, rows.Err()
+}
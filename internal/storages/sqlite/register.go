@@ -0,0 +1,92 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manabie-com/togo/internal/sqlcomment"
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/mattn/go-sqlite3"
+)
+
+func init() {
+	storages.Register("sqlite", open)
+	storages.RegisterChecker("sqlite", check)
+}
+
+// open opens the database file opts.DSN names, migrates it and checks its schema
+func open(ctx context.Context, opts storages.OpenOptions) (*storages.Backend, error) {
+	dsn := opts.DSN + "?_txlock=immediate"
+	var db *sql.DB
+	if opts.SQLComments {
+		db = sqlcomment.OpenDB(&sqlite3.SQLiteDriver{}, dsn)
+	} else {
+		var err error
+		db, err = sql.Open("sqlite3", dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l := &LiteDB{DB: db}
+	if err := l.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating: %w", err)
+	}
+	if err := l.VerifySchema(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("verifying schema: %w", err)
+	}
+	return &storages.Backend{Store: l, DB: db}, nil
+}
+
+// check opens the database file opts.DSN names the way open would without creating or
+// migrating it, answering it when its schema is the one this build expects
+func check(ctx context.Context, opts storages.OpenOptions) (*storages.Backend, string, error) {
+	path := opts.DSN
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		dir := filepath.Dir(path)
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			return nil, "", fmt.Errorf("%s doesn't exist and neither does directory %s", path, dir)
+		}
+		return nil, path + " doesn't exist yet, the server creates it", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	db, err := sql.Open("sqlite3", path+"?mode=rw&_txlock=immediate")
+	if err != nil {
+		return nil, "", err
+	}
+	l := &LiteDB{DB: db}
+	version, latest, err := l.SchemaVersion(ctx)
+	if err != nil {
+		db.Close()
+		return nil, "", err
+	}
+	// the server writes, so it needs the write lock, which an immediate transaction takes
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		db.Close()
+		return nil, "", fmt.Errorf("taking the write lock: %w", err)
+	}
+	tx.Rollback()
+
+	switch {
+	case version > latest:
+		db.Close()
+		return nil, "", fmt.Errorf("schema is at migration %d, newer than the %d of this build", version, latest)
+	case version < latest:
+		db.Close()
+		return nil, fmt.Sprintf("%s at migration %d, the server applies %d more at startup", path, version, latest-version), nil
+	}
+	if err := l.VerifySchema(ctx); err != nil {
+		db.Close()
+		return nil, "", err
+	}
+	return &storages.Backend{Store: l, DB: db}, fmt.Sprintf("%s at migration %d", path, version), nil
+}
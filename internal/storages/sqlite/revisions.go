@@ -0,0 +1,33 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RetrieveTasksAsOf returns the tasks userID had on createdDate at the time at, as they were
+// then, in creation order. Tasks deleted by then are left out, those deleted since are in.
+func (l *LiteDB) RetrieveTasksAsOf(ctx context.Context, userID, createdDate sql.NullString, at string) ([]*storages.Task, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT r.task_id, r.content, r.user_id, r.created_date, r.created_at,
+			r.priority, r.due_date, r.completed_at, r.over_quota, r.estimate_minutes
+		FROM task_revisions r
+		WHERE r.user_id = ? AND r.created_date = ? AND r.valid_from <= ? AND r.deleted = 0
+			AND r.id = (SELECT MAX(id) FROM task_revisions WHERE task_id = r.task_id AND valid_from <= ?)
+		ORDER BY r.created_at, r.task_id`, userID, createdDate, at, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*storages.Task{}
+	for rows.Next() {
+		t := &storages.Task{}
+		if err := rows.Scan(taskValues(t)...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
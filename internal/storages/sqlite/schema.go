@@ -0,0 +1,122 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaVersion returns the migration the database is at and the latest one this build has
+func (l *LiteDB) SchemaVersion(ctx context.Context) (version, latest int, err error) {
+	err = l.DB.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version)
+	return version, len(migrations), err
+}
+
+// VerifySchema fails when the database isn't at the latest migration or lacks a table, column,
+// index or trigger the migrations create, e.g. because it was altered by hand. Call it after
+// Migrate so a broken database stops the server at startup instead of failing its first queries.
+func (l *LiteDB) VerifySchema(ctx context.Context) error {
+	var version int
+	if err := l.DB.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version != len(migrations) {
+		return fmt.Errorf("schema is at migration %d, this build expects %d", version, len(migrations))
+	}
+
+	// the expected schema is whatever the migrations produce on an empty database
+	empty := sql.OpenDB(dsnConnector{driver: l.DB.Driver(), dsn: ":memory:"})
+	defer empty.Close()
+	// every connection to :memory: is a new database
+	empty.SetMaxOpenConns(1)
+	if err := (&LiteDB{DB: empty}).Migrate(ctx); err != nil {
+		return fmt.Errorf("building the expected schema: %w", err)
+	}
+
+	want, err := schemaObjects(ctx, empty)
+	if err != nil {
+		return err
+	}
+	got, err := schemaObjects(ctx, l.DB)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for obj := range want {
+		if !got[obj] {
+			missing = append(missing, obj)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("schema drift, missing %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// schemaObjects names the tables, columns, indexes and triggers of db like "table tasks",
+// "column tasks.id" and "index tasks_user_date_priority"
+func schemaObjects(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT type, name, tbl_name FROM sqlite_master
+		WHERE type IN ('table', 'index', 'trigger') AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	objects := map[string]bool{}
+	var tables []string
+	for rows.Next() {
+		var typ, name, table string
+		if err := rows.Scan(&typ, &name, &table); err != nil {
+			return nil, err
+		}
+		objects[typ+" "+name] = true
+		if typ == "table" {
+			tables = append(tables, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		cols, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+		if err != nil {
+			return nil, err
+		}
+		for cols.Next() {
+			var col string
+			if err := cols.Scan(&col); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			objects["column "+table+"."+col] = true
+		}
+		cols.Close()
+		if err := cols.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+// dsnConnector opens connections to another database through the driver of an existing *sql.DB
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,77 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// AddSession adds a new session to DB
+func (l *LiteDB) AddSession(ctx context.Context, sess *storages.Session) error {
+	stmt := `INSERT INTO sessions (id, user_id, device, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := l.DB.ExecContext(ctx, stmt, &sess.ID, &sess.UserID, &sess.Device, &sess.CreatedAt, &sess.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetrieveSessions returns the sessions of userID that are neither revoked nor expired at now
+func (l *LiteDB) RetrieveSessions(ctx context.Context, userID sql.NullString, now string) ([]*storages.Session, error) {
+	stmt := `SELECT id, user_id, device, created_at, expires_at FROM sessions
+		WHERE user_id = ? AND expires_at > ? AND revoked_at IS NULL ORDER BY created_at`
+	rows, err := l.DB.QueryContext(ctx, stmt, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*storages.Session
+	for rows.Next() {
+		sess := &storages.Session{}
+		err := rows.Scan(&sess.ID, &sess.UserID, &sess.Device, &sess.CreatedAt, &sess.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// ValidateSession returns true if sessionID belongs to userID and is neither revoked nor expired at now
+func (l *LiteDB) ValidateSession(ctx context.Context, userID, sessionID sql.NullString, now string) bool {
+	stmt := `SELECT id FROM sessions WHERE id = ? AND user_id = ? AND expires_at > ? AND revoked_at IS NULL`
+	var id string
+	err := l.DB.QueryRowContext(ctx, stmt, sessionID, userID, now).Scan(&id)
+	if err != nil {
+		return false
+	}
+
+	return true
+}
+
+// RevokeSession marks a session of userID as revoked at now, ErrNotFound if there is no such active session
+func (l *LiteDB) RevokeSession(ctx context.Context, userID, sessionID sql.NullString, now string) error {
+	stmt := `UPDATE sessions SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`
+	res, err := l.DB.ExecContext(ctx, stmt, now, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+
+	return nil
+}
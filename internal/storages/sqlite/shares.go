@@ -0,0 +1,69 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// AddShare stores a new share
+func (l *LiteDB) AddShare(ctx context.Context, share *storages.Share) error {
+	_, err := l.DB.ExecContext(ctx, `INSERT INTO shares (id, user_id, date, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`,
+		share.ID, share.UserID, share.Date, share.CreatedAt, share.ExpiresAt)
+	return err
+}
+
+// RetrieveShares returns the shares of userID neither revoked nor expired at now, oldest first
+func (l *LiteDB) RetrieveShares(ctx context.Context, userID sql.NullString, now string) ([]*storages.Share, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, user_id, date, created_at, expires_at FROM shares
+		WHERE user_id = ? AND expires_at > ? AND revoked_at IS NULL ORDER BY created_at, id`, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []*storages.Share{}
+	for rows.Next() {
+		share := &storages.Share{}
+		if err := rows.Scan(&share.ID, &share.UserID, &share.Date, &share.CreatedAt, &share.ExpiresAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// RetrieveShare returns shareID, ErrNotFound if there is no such share or it was revoked or
+// expired at now
+func (l *LiteDB) RetrieveShare(ctx context.Context, shareID sql.NullString, now string) (*storages.Share, error) {
+	share := &storages.Share{}
+	err := l.DB.QueryRowContext(ctx, `SELECT id, user_id, date, created_at, expires_at FROM shares
+		WHERE id = ? AND expires_at > ? AND revoked_at IS NULL`, shareID, now).
+		Scan(&share.ID, &share.UserID, &share.Date, &share.CreatedAt, &share.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// RevokeShare ends a share of userID at now, ErrNotFound if they have no such share or it was
+// revoked already
+func (l *LiteDB) RevokeShare(ctx context.Context, userID, shareID sql.NullString, now string) error {
+	res, err := l.DB.ExecContext(ctx, `UPDATE shares SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`,
+		now, shareID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
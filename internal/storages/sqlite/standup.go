@@ -0,0 +1,62 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RetrieveStandup returns every active user who didn't opt out of the standup report, by ID,
+// with the tasks of yesterday they completed and their tasks of today, highest priority first.
+// Those with neither are in it too, having nothing to report is worth reporting.
+func (l *LiteDB) RetrieveStandup(ctx context.Context, yesterday, today string) ([]*storages.StandupMember, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT u.id, t.content, t.created_date FROM users u
+		LEFT JOIN tasks t ON t.user_id = u.id
+			AND ((t.created_date = ? AND t.completed_at <> '') OR t.created_date = ?)
+		WHERE NOT u.standup_opt_out AND u.deactivated_at = ''
+		ORDER BY u.id, t.priority DESC, t.created_at`, yesterday, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*storages.StandupMember{}
+	var m *storages.StandupMember
+	for rows.Next() {
+		var userID string
+		var content, date sql.NullString
+		if err := rows.Scan(&userID, &content, &date); err != nil {
+			return nil, err
+		}
+		if m == nil || m.UserID != userID {
+			m = &storages.StandupMember{UserID: userID, Completed: []string{}, Planned: []string{}}
+			members = append(members, m)
+		}
+		switch {
+		case !content.Valid:
+		case date.String == today:
+			m.Planned = append(m.Planned, content.String)
+		default:
+			m.Completed = append(m.Completed, content.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// MarkStandupSent records that the standup report of date went out at at, first is false when
+// that was already recorded
+func (l *LiteDB) MarkStandupSent(ctx context.Context, date, at string) (bool, error) {
+	res, err := l.DB.ExecContext(ctx, `INSERT OR IGNORE INTO standup_reports (date, sent_at) VALUES (?, ?)`, date, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,50 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// UpdateStreaks extends, or restarts, the streak of every user who completed a task on day,
+// a YYYY-MM-DD UTC date, returning how many streaks changed. Streaks already counting day or
+// a later day are left alone, so running it twice, or late for an earlier day, is harmless.
+func (l *LiteDB) UpdateStreaks(ctx context.Context, day string) (int, error) {
+	d, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return 0, err
+	}
+	prev := d.AddDate(0, 0, -1).Format("2006-01-02")
+	next := d.AddDate(0, 0, 1).Format("2006-01-02")
+
+	stmt := `INSERT INTO streaks (user_id, current, longest, last_day)
+		SELECT DISTINCT user_id, 1, 1, ? FROM tasks
+		WHERE completed_at <> '' AND completed_at >= ? AND completed_at < ?
+		ON CONFLICT (user_id) DO UPDATE SET
+			current = CASE WHEN last_day = ? THEN current + 1 ELSE 1 END,
+			longest = MAX(longest, CASE WHEN last_day = ? THEN current + 1 ELSE 1 END),
+			last_day = excluded.last_day
+		WHERE excluded.last_day > last_day`
+	res, err := l.DB.ExecContext(ctx, stmt, day, day, next, prev, prev)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// RetrieveStreak returns the streak of userID as of its last day, a zero Streak if it has none
+func (l *LiteDB) RetrieveStreak(ctx context.Context, userID sql.NullString) (*storages.Streak, error) {
+	st := &storages.Streak{}
+	stmt := `SELECT current, longest, last_day FROM streaks WHERE user_id = ?`
+	err := l.DB.QueryRowContext(ctx, stmt, userID).Scan(&st.Current, &st.Longest, &st.LastDay)
+	if err == sql.ErrNoRows {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
@@ -0,0 +1,40 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// likeEscaper makes a LIKE pattern match its text literally with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// RetrieveContentSuggestions returns the n contents of userID's tasks starting with prefix,
+// ignoring ASCII case, that they wrote the most, the most recent first among those tied.
+// Contents differing only in case count as one, spelled as, and dated like, the last task
+// written with them. Summaries of long contents are left out.
+func (l *LiteDB) RetrieveContentSuggestions(ctx context.Context, userID sql.NullString, prefix string, n int) ([]*storages.ContentSuggestion, error) {
+	// LIKE reads the range of the prefix from tasks_user_content, the bare content and
+	// created_date columns are taken from the row of MAX(created_at)
+	rows, err := l.DB.QueryContext(ctx, `SELECT content, created_date, COUNT(*) AS uses, MAX(created_at) AS last
+		FROM tasks WHERE user_id = ? AND content LIKE ? ESCAPE '\' AND content_truncated = 0
+		GROUP BY content COLLATE NOCASE ORDER BY uses DESC, last DESC LIMIT ?`,
+		userID, likeEscaper.Replace(prefix)+"%", n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []*storages.ContentSuggestion{}
+	for rows.Next() {
+		var last string
+		sg := &storages.ContentSuggestion{}
+		if err := rows.Scan(&sg.Content, &sg.LastDate, &sg.Count, &last); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, sg)
+	}
+	return suggestions, rows.Err()
+}
@@ -0,0 +1,137 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// SetTaskEstimate sets how many minutes a task of userID is expected to take, 0 to remove the
+// estimate. ErrNotFound if userID has no such task.
+func (l *LiteDB) SetTaskEstimate(ctx context.Context, userID, taskID sql.NullString, minutes int) error {
+	res, err := l.DB.ExecContext(ctx, `UPDATE tasks SET estimate_minutes = ? WHERE id = ? AND user_id = ?`, minutes, taskID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+	return nil
+}
+
+// StartTimer starts tracking time on e.TaskID from e.StartedAt. A user has one timer running
+// at most, the one running on another task is stopped at e.StartedAt and returned, nil when
+// there was none. ErrTimerRunning if the timer of e.TaskID runs already.
+func (l *LiteDB) StartTimer(ctx context.Context, e *storages.TimeEntry) (*storages.TimeEntry, error) {
+	var stopped *storages.TimeEntry
+	err := busyRetry.Do(ctx, func(ctx context.Context) error {
+		tx, err := l.DB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		stopped, err = stopTimer(ctx, tx, e.UserID, e.StartedAt)
+		if err == storages.ErrNotFound {
+			stopped = nil
+		} else if err != nil {
+			return err
+		}
+		if stopped != nil && stopped.TaskID == e.TaskID {
+			return storages.ErrTimerRunning
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO time_entries (id, task_id, user_id, started_at) VALUES (?, ?, ?, ?)`,
+			e.ID, e.TaskID, e.UserID, e.StartedAt)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	return stopped, err
+}
+
+// StopTimer stops the timer of a task of userID at at, returning the entry it tracked.
+// ErrNotFound if the task's timer isn't running.
+func (l *LiteDB) StopTimer(ctx context.Context, userID, taskID sql.NullString, at string) (*storages.TimeEntry, error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	e, err := stopTimer(ctx, tx, userID.String, at)
+	if err != nil {
+		return nil, err
+	}
+	if e.TaskID != taskID.String {
+		return nil, storages.ErrNotFound
+	}
+	return e, tx.Commit()
+}
+
+// stopTimer stops the timer userID has running at at, ErrNotFound if none is
+func stopTimer(ctx context.Context, tx *sql.Tx, userID, at string) (*storages.TimeEntry, error) {
+	e := &storages.TimeEntry{UserID: userID, StoppedAt: at}
+	err := tx.QueryRowContext(ctx, `SELECT id, task_id, started_at FROM time_entries WHERE user_id = ? AND stopped_at = ''`, userID).
+		Scan(&e.ID, &e.TaskID, &e.StartedAt)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Seconds, err = elapsedSeconds(e.StartedAt, at); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE time_entries SET stopped_at = ?, seconds = ? WHERE id = ?`, e.StoppedAt, e.Seconds, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// RetrieveTimeEntries returns the time tracked on a task of userID, the running entry
+// included, in the order it was
+func (l *LiteDB) RetrieveTimeEntries(ctx context.Context, userID, taskID sql.NullString) ([]*storages.TimeEntry, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT id, task_id, user_id, started_at, stopped_at, seconds FROM time_entries
+		WHERE task_id = ? AND user_id = ? ORDER BY started_at, id`, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*storages.TimeEntry{}
+	for rows.Next() {
+		e := &storages.TimeEntry{}
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.StartedAt, &e.StoppedAt, &e.Seconds); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// elapsedSeconds returns the whole seconds from from to to, both in storages.TimeLayout. Spans
+// that would end before they started, because clocks moved, last 0 seconds.
+func elapsedSeconds(from, to string) (int, error) {
+	start, err := time.Parse(storages.TimeLayout, from)
+	if err != nil {
+		return 0, err
+	}
+	end, err := time.Parse(storages.TimeLayout, to)
+	if err != nil {
+		return 0, err
+	}
+	if d := end.Sub(start); d > 0 {
+		return int(d.Round(time.Second) / time.Second), nil
+	}
+	return 0, nil
+}
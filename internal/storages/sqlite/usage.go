@@ -0,0 +1,51 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// IncrementAPIUsage counts one more API call of userID on day, a YYYY-MM-DD UTC date,
+// and returns how many calls they made that day
+func (l *LiteDB) IncrementAPIUsage(ctx context.Context, userID sql.NullString, day string) (calls int, err error) {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO api_usage (user_id, day, calls) VALUES (?, ?, 1)
+		ON CONFLICT (user_id, day) DO UPDATE SET calls = calls + 1`, userID, day)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.QueryRowContext(ctx, `SELECT calls FROM api_usage WHERE user_id = ? AND day = ?`, userID, day).Scan(&calls)
+	if err != nil {
+		return 0, err
+	}
+
+	return calls, tx.Commit()
+}
+
+// RetrieveAPIUsage returns the calls of userID on each day from from to to, both included,
+// leaving out days without any
+func (l *LiteDB) RetrieveAPIUsage(ctx context.Context, userID sql.NullString, from, to string) ([]*storages.APIUsage, error) {
+	rows, err := l.DB.QueryContext(ctx, `SELECT day, calls FROM api_usage
+		WHERE user_id = ? AND day >= ? AND day <= ? ORDER BY day`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []*storages.APIUsage{}
+	for rows.Next() {
+		u := &storages.APIUsage{}
+		if err := rows.Scan(&u.Date, &u.Calls); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
@@ -0,0 +1,163 @@
+package sqllite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+// RetrieveUser returns userID without its password hash, ErrNotFound if there's no such user
+func (l *LiteDB) RetrieveUser(ctx context.Context, userID sql.NullString) (*storages.User, error) {
+	u := &storages.User{}
+	stmt := `SELECT id, max_todo, role, deactivated_at FROM users WHERE id = ?`
+	err := l.DB.QueryRowContext(ctx, stmt, userID).Scan(&u.ID, &u.MaxTodo, &u.Role, &u.DeactivatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UpdateUser replaces the limit, role and deactivation of u.ID, ErrNotFound if there's no such user
+func (l *LiteDB) UpdateUser(ctx context.Context, u *storages.User) error {
+	stmt := `UPDATE users SET max_todo = ?, role = ?, deactivated_at = ? WHERE id = ?`
+	res, err := l.DB.ExecContext(ctx, stmt, u.MaxTodo, u.Role, u.DeactivatedAt, u.ID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+
+	return nil
+}
+
+// RetrieveUserSettings returns the settings of userID
+func (l *LiteDB) RetrieveUserSettings(ctx context.Context, userID sql.NullString) (*storages.UserSettings, error) {
+	stmt := `SELECT notify_limit_reached, carry_over, daily_digest, email, timezone, quota_mode, standup_opt_out FROM users WHERE id = ?`
+	settings := &storages.UserSettings{}
+	err := l.DB.QueryRowContext(ctx, stmt, userID).Scan(
+		&settings.NotifyLimitReached, &settings.CarryOver, &settings.DailyDigest, &settings.Email, &settings.Timezone, &settings.QuotaMode, &settings.StandupOptOut,
+	)
+	if err == sql.ErrNoRows {
+		return nil, storages.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpdateUserSettings replaces the settings of userID
+func (l *LiteDB) UpdateUserSettings(ctx context.Context, userID sql.NullString, settings *storages.UserSettings) error {
+	stmt := `UPDATE users SET notify_limit_reached = ?, carry_over = ?, daily_digest = ?, email = ?, timezone = ?, quota_mode = ?, standup_opt_out = ? WHERE id = ?`
+	res, err := l.DB.ExecContext(ctx, stmt,
+		settings.NotifyLimitReached, settings.CarryOver, settings.DailyDigest, settings.Email, settings.Timezone, settings.QuotaMode, settings.StandupOptOut, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storages.ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkDigestSent records that userID got the digest for date, first is false when that was already recorded
+func (l *LiteDB) MarkDigestSent(ctx context.Context, userID, date sql.NullString) (bool, error) {
+	stmt := `INSERT OR IGNORE INTO digests (user_id, date) VALUES (?, ?)`
+	res, err := l.DB.ExecContext(ctx, stmt, userID, date)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// MarkLimitNotified records that userID was notified about reaching the limit on date,
+// first is false when that was already recorded
+func (l *LiteDB) MarkLimitNotified(ctx context.Context, userID, date sql.NullString) (bool, error) {
+	stmt := `INSERT OR IGNORE INTO limit_notifications (user_id, date) VALUES (?, ?)`
+	res, err := l.DB.ExecContext(ctx, stmt, userID, date)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// RetrieveCarryOverUsers returns the active users that opted in to carrying over tasks, keyed by ID with their mode
+func (l *LiteDB) RetrieveCarryOverUsers(ctx context.Context) (map[string]string, error) {
+	stmt := `SELECT id, carry_over FROM users WHERE carry_over <> '' AND deactivated_at = ''`
+	rows, err := l.DB.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := map[string]string{}
+	for rows.Next() {
+		var id, mode string
+		if err := rows.Scan(&id, &mode); err != nil {
+			return nil, err
+		}
+		users[id] = mode
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// RetrieveDigestRecipients returns the active users that opted in to the daily digest and have an email
+func (l *LiteDB) RetrieveDigestRecipients(ctx context.Context) ([]*storages.DigestRecipient, error) {
+	stmt := `SELECT id, email, timezone FROM users WHERE daily_digest AND email <> '' AND deactivated_at = ''`
+	rows, err := l.DB.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*storages.DigestRecipient
+	for rows.Next() {
+		r := &storages.DigestRecipient{}
+		if err := rows.Scan(&r.UserID, &r.Email, &r.Timezone); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,139 @@
+// Package tlsconfig serves TLS from certificate files that are reloaded when they change,
+// so certificates issued by a short-lived CA rotate without restarting the server
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Files are the PEM files a server's TLS config is built from
+type Files struct {
+	Cert string
+	Key  string
+	// ClientCA verifies client certificates, which are then required (mutual TLS).
+	// Clients aren't asked for certificates when it's empty.
+	ClientCA string
+}
+
+// Reloader builds tls.Configs from Files, picking up changes Watch notices
+type Reloader struct {
+	files Files
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	clients *x509.CertPool
+	mtimes  [3]time.Time
+}
+
+// New loads files, failing when any of them is missing or invalid
+func New(files Files) (*Reloader, error) {
+	r := &Reloader{files: files}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Config returns a server config that always uses the latest certificates
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+			}
+			if r.clients != nil {
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+				c.ClientCAs = r.clients
+			}
+			return c, nil
+		},
+	}
+}
+
+// Watch checks the files every interval until ctx is done and reloads them when one changed.
+// A reload that fails, e.g. because the key was written before the certificate, is retried
+// on the next check and the previous certificates stay in use.
+func (r *Reloader) Watch(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		mtimes, err := r.modTimes()
+		if err != nil {
+			log.Println("error checking tls files", err)
+			continue
+		}
+		r.mu.RLock()
+		changed := mtimes != r.mtimes
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		if err := r.load(); err != nil {
+			log.Println("error reloading tls files", err)
+			continue
+		}
+		log.Println("reloaded tls certificates")
+	}
+}
+
+func (r *Reloader) load() error {
+	mtimes, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.files.Cert, r.files.Key)
+	if err != nil {
+		return err
+	}
+
+	var clients *x509.CertPool
+	if r.files.ClientCA != "" {
+		pem, err := os.ReadFile(r.files.ClientCA)
+		if err != nil {
+			return err
+		}
+		clients = x509.NewCertPool()
+		if !clients.AppendCertsFromPEM(pem) {
+			return errors.New("tlsconfig: no certificates in " + r.files.ClientCA)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clients, r.mtimes = &cert, clients, mtimes
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Reloader) modTimes() ([3]time.Time, error) {
+	var mtimes [3]time.Time
+	for i, path := range []string{r.files.Cert, r.files.Key, r.files.ClientCA} {
+		if path == "" {
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return mtimes, err
+		}
+		mtimes[i] = fi.ModTime()
+	}
+	return mtimes, nil
+}
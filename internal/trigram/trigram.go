@@ -0,0 +1,44 @@
+// Package trigram compares short texts by the three letter sequences they share, the way
+// PostgreSQL's pg_trgm does, to tell near-identical task contents apart from different ones
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Set returns the trigrams of s: each word, lowercased and padded with two spaces before and
+// one after, contributes its runs of three runes. Other characters only separate words.
+func Set(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Similarity is the share of the trigrams of a and b they have in common, from 0 for
+// nothing shared to 1 for the same words
+func Similarity(a, b string) float64 {
+	return SetSimilarity(Set(a), Set(b))
+}
+
+// SetSimilarity is Similarity for texts whose Set is already known
+func SetSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
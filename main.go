@@ -1,26 +1,299 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	// user timezones must load on hosts without a zoneinfo database
+	_ "time/tzdata"
 
+	"github.com/manabie-com/togo/internal/app"
+	"github.com/manabie-com/togo/internal/archive"
+	"github.com/manabie-com/togo/internal/auth"
+	"github.com/manabie-com/togo/internal/captcha"
+	"github.com/manabie-com/togo/internal/config"
+	"github.com/manabie-com/togo/internal/holidays"
+	"github.com/manabie-com/togo/internal/httpclient"
+	"github.com/manabie-com/togo/internal/idgen"
+	"github.com/manabie-com/togo/internal/jobs"
+	"github.com/manabie-com/togo/internal/mail"
+	"github.com/manabie-com/togo/internal/metrics"
+	"github.com/manabie-com/togo/internal/moderation"
+	"github.com/manabie-com/togo/internal/notify"
+	"github.com/manabie-com/togo/internal/password"
+	"github.com/manabie-com/togo/internal/rules"
+	"github.com/manabie-com/togo/internal/secrets"
 	"github.com/manabie-com/togo/internal/services"
-	sqllite "github.com/manabie-com/togo/internal/storages/sqlite"
-
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/manabie-com/togo/internal/sigv4"
+	"github.com/manabie-com/togo/pkg/storages"
+	// registers the "sqlite" backend
+	_ "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/internal/tlsconfig"
 )
 
 func main() {
-	db, err := sql.Open("sqlite3", "./data.db")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resolver := secrets.NewResolver()
+	secret := func(ref string) string {
+		v, err := resolver.Resolve(context.Background(), ref)
+		if err != nil {
+			log.Fatal("error resolving secret: ", err)
+		}
+		return v
+	}
+	jwtKey, err := resolver.Watch(context.Background(), cfg.JWTKey, time.Duration(cfg.SecretRefreshSeconds)*time.Second)
 	if err != nil {
-		log.Fatal("error opening db", err)
+		log.Fatal("error resolving secret: ", err)
+	}
+	if jwtKey.Get() == config.DefaultJWTKey && !cfg.Dev {
+		log.Fatal("TOGO_JWT_KEY is the built-in dev key anyone can sign tokens with, set a key or TOGO_DEV=true")
 	}
 
-	http.ListenAndServe(":5050", &services.ToDoService{
-		JWTKey: "wqGyEBBfPK9w3Lxw",
-		Store: &sqllite.LiteDB{
-			DB: db,
-		},
+	backend, err := storages.Open(context.Background(), cfg.DBBackend, storages.OpenOptions{
+		DSN:         cfg.DBPath,
+		SQLComments: cfg.SQLComments,
 	})
+	if err != nil {
+		log.Fatal("error opening db: ", err)
+	}
+	db, store := backend.DB, backend.Store
+
+	components := &app.App{}
+	components.Add(&app.Database{DB: db, Warm: int(cfg.DBWarmConnections)})
+
+	gen, err := idgen.New(cfg.IDStrategy, cfg.NodeID)
+	if err != nil {
+		log.Fatal("error creating id generator", err)
+	}
+
+	passwords, err := password.New(cfg.PasswordHash)
+	if err != nil {
+		log.Fatal("error creating password hasher", err)
+	}
+
+	srv := &services.ToDoService{
+		JWTKey:      jwtKey,
+		Store:       store,
+		IDGen:       gen,
+		Passwords:   passwords,
+		APIQuota:    int(cfg.APIDailyQuota),
+		UsersInvite: cfg.UsersInvite,
+
+		DuplicateSimilarity: float64(cfg.DuplicateSimilarity) / 100,
+	}
+	authenticators := auth.Chain{&auth.APIKey{Store: store}, srv.TokenValidator()}
+	if cfg.OIDCIntrospectionURL != "" {
+		authenticators = append(authenticators,
+			auth.NewIntrospection(cfg.OIDCIntrospectionURL, cfg.OIDCClientID, secret(cfg.OIDCClientSecret)))
+	}
+	srv.Authenticator = authenticators
+
+	if err := srv.LoadSigningKeys(context.Background()); err != nil {
+		log.Fatal("error loading signing keys: ", err)
+	}
+	components.Add(app.NewJob("signing key refresh", func(ctx context.Context) {
+		jobs.RunEvery(ctx, services.KeyRefresh, func(ctx context.Context, _ time.Time) {
+			if err := srv.LoadSigningKeys(ctx); err != nil {
+				log.Println("error reloading signing keys", err)
+			}
+		})
+	}))
+
+	srv.Metrics, err = metrics.New(cfg.Metrics, cfg.StatsDAddr, cfg.StatsDPrefix)
+	if err != nil {
+		log.Fatal("error creating metrics backend: ", err)
+	}
+
+	if cfg.WriteConcurrency > 0 {
+		srv.WriteQueue = &services.WriteQueue{
+			Concurrency: int(cfg.WriteConcurrency),
+			Depth:       int(cfg.WriteQueueDepth),
+		}
+	}
+
+	if cfg.RulesDir != "" {
+		scripts, err := rules.Load(cfg.RulesDir, time.Duration(cfg.RulesTimeoutMS)*time.Millisecond)
+		if err != nil {
+			log.Fatal("error loading rules: ", err)
+		}
+		log.Printf("checking new tasks with %d rules from %s", scripts.Len(), cfg.RulesDir)
+		srv.RegisterHook(services.Hook{
+			BeforeCreate: func(ctx context.Context, t *storages.Task) error {
+				reason, err := scripts.Check(ctx, t)
+				if err != nil {
+					return err
+				}
+				if reason != "" {
+					return &services.Veto{Reason: reason}
+				}
+				return nil
+			},
+		})
+	}
+
+	if cfg.BlockedTerms != "" {
+		blocked, err := moderation.Parse(secret(cfg.BlockedTerms))
+		if err != nil {
+			log.Fatal("error loading blocked terms: ", err)
+		}
+		log.Printf("refusing new tasks with %d blocked terms", blocked.Len())
+		srv.RegisterHook(services.Hook{
+			BeforeCreate: func(ctx context.Context, t *storages.Task) error {
+				if blocked.Match(t.Content) != "" {
+					return &services.Veto{Code: "blocked_term", Reason: "the content contains a blocked term"}
+				}
+				return nil
+			},
+		})
+	}
+
+	if cfg.CaptchaThreshold > 0 {
+		srv.LoginGuard = &services.LoginGuard{
+			Verifier:  captcha.NewSiteVerify(cfg.CaptchaVerifyURL, secret(cfg.CaptchaSecret)),
+			Threshold: int(cfg.CaptchaThreshold),
+			Window:    15 * time.Minute,
+		}
+	}
+
+	srv.ResultLimits = services.ResultLimits{
+		MaxPageSize:  int(cfg.MaxPageSize),
+		MaxRangeDays: int(cfg.MaxRangeDays),
+	}
+
+	srv.SignupGuard = &services.SignupGuard{
+		MaxPerIP: int(cfg.SignupMaxPerIP),
+		Window:   time.Duration(cfg.SignupWindowMinutes) * time.Minute,
+		Delay:    time.Duration(cfg.SignupDelayMS) * time.Millisecond,
+	}
+	if cfg.SignupBlockedDomains != "" {
+		srv.SignupGuard.BlockedDomains = services.ParseDomains(secret(cfg.SignupBlockedDomains))
+		log.Printf("refusing signups from %d email domains", len(srv.SignupGuard.BlockedDomains))
+	}
+
+	if cfg.LimitWebhookURL != "" {
+		srv.Notifier = notify.NewWebhook(cfg.LimitWebhookURL, secret(cfg.LimitWebhookSecret))
+	}
+
+	if cfg.SMTPAddr != "" {
+		srv.Mailer = &mail.SMTP{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.MailFrom,
+			Username: cfg.SMTPUsername,
+			Password: secret(cfg.SMTPPassword),
+		}
+		srv.DigestHour = int(cfg.DigestHour)
+		components.Add(app.NewJob("daily digests", func(ctx context.Context) {
+			jobs.RunEvery(ctx, time.Hour, srv.SendDigests)
+		}))
+	}
+
+	if cfg.StandupEmails != "" {
+		srv.StandupEmails = strings.Fields(strings.ReplaceAll(cfg.StandupEmails, ",", " "))
+	}
+	if cfg.StandupSlackURL != "" {
+		srv.StandupSlack = notify.NewSlack(secret(cfg.StandupSlackURL))
+	}
+	if srv.StandupSlack != nil || (srv.Mailer != nil && len(srv.StandupEmails) > 0) {
+		srv.StandupHour = int(cfg.StandupHour)
+		components.Add(app.NewJob("standup report", func(ctx context.Context) {
+			jobs.RunEvery(ctx, time.Hour, srv.SendStandup)
+		}))
+	}
+
+	switch {
+	case cfg.HolidayCountry != "":
+		srv.Holidays = holidays.NewNager(cfg.HolidayCountry)
+	case cfg.Holidays != "":
+		srv.Holidays = holidays.List(strings.Fields(strings.ReplaceAll(cfg.Holidays, ",", " ")))
+	}
+	components.Add(app.NewJob("holiday sync", func(ctx context.Context) {
+		srv.SyncHolidays(ctx, time.Now())
+		jobs.RunDaily(ctx, time.Local, srv.SyncHolidays)
+	}))
+
+	if cfg.AnomalyMinTasks > 0 {
+		srv.Anomalies = &services.AnomalyPolicy{
+			Factor:      float64(cfg.AnomalyFactor),
+			MinTasks:    int(cfg.AnomalyMinTasks),
+			Throttle:    int(cfg.AnomalyThrottle),
+			ThrottleFor: time.Duration(cfg.AnomalyThrottleHours) * time.Hour,
+		}
+		components.Add(app.NewJob("anomaly detection", func(ctx context.Context) {
+			jobs.RunEvery(ctx, time.Hour, srv.AnalyzeCreationRates)
+		}))
+	}
+
+	components.Add(app.NewJob("carry over", func(ctx context.Context) {
+		jobs.RunDaily(ctx, time.Local, srv.CarryOver)
+	}))
+	// catch up on the day that ended while the server was down, if it was
+	srv.UpdateStats(context.Background(), time.Now())
+	components.Add(app.NewJob("stats", func(ctx context.Context) {
+		jobs.RunDaily(ctx, time.UTC, srv.UpdateStats)
+	}))
+
+	if cfg.ArchiveBucket != "" {
+		creds, ok := sigv4.CredentialsFromEnv()
+		if cfg.ArchiveAccessKeyID != "" {
+			creds, ok = sigv4.Credentials{
+				AccessKeyID:     cfg.ArchiveAccessKeyID,
+				SecretAccessKey: secret(cfg.ArchiveSecretAccessKey),
+			}, true
+		}
+		if !ok {
+			log.Fatal("TOGO_ARCHIVE_BUCKET needs TOGO_ARCHIVE_ACCESS_KEY_ID or AWS credentials")
+		}
+		srv.Archive = &archive.S3{
+			Bucket:      cfg.ArchiveBucket,
+			Region:      cfg.ArchiveRegion,
+			Endpoint:    cfg.ArchiveEndpoint,
+			Credentials: creds,
+			Client:      httpclient.New(),
+		}
+		srv.ArchivePrefix = cfg.ArchivePrefix
+		components.Add(app.NewJob("archive", func(ctx context.Context) {
+			jobs.RunDaily(ctx, time.Local, srv.ArchiveCompleted)
+		}))
+	}
+
+	server := &http.Server{Addr: cfg.Addr, Handler: srv}
+	server.RegisterOnShutdown(srv.CloseStreams)
+	var adminServer *http.Server
+	if cfg.AdminAddr != "" {
+		srv.SeparateAdmin = true
+		adminServer = &http.Server{Addr: cfg.AdminAddr, Handler: srv.AdminHandler()}
+	}
+	if cfg.TLSCert != "" {
+		certs, err := tlsconfig.New(tlsconfig.Files{Cert: cfg.TLSCert, Key: cfg.TLSKey, ClientCA: cfg.TLSClientCA})
+		if err != nil {
+			log.Fatal("error loading tls files: ", err)
+		}
+		components.Add(app.NewJob("tls reload", func(ctx context.Context) {
+			certs.Watch(ctx, 10*time.Second)
+		}))
+		server.TLSConfig = certs.Config()
+		if adminServer != nil {
+			adminServer.TLSConfig = certs.Config()
+		}
+	}
+	// the servers start last and stop first, so requests never meet a stopped component
+	if adminServer != nil {
+		components.Add(&app.HTTPServer{Server: adminServer})
+	}
+	components.Add(&app.HTTPServer{Server: server})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := components.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
 }
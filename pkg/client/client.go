@@ -0,0 +1,329 @@
+// Package client calls the togo API from other Go services. It logs in with a user's
+// credentials, logs in again when the token expires, retries transient failures and
+// sends an idempotency key with every task creation so retries can't add it twice.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manabie-com/togo/internal/httpclient"
+)
+
+// Doer sends HTTP requests, *http.Client satisfies it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client calls the API at BaseURL as UserID. It is safe for concurrent use.
+type Client struct {
+	BaseURL  string
+	UserID   string
+	Password string
+	// HTTP sends the requests, New sets it to a client that retries transient failures
+	HTTP Doer
+
+	mu    sync.Mutex
+	token string
+}
+
+// New returns a Client for the API at baseURL, like http://localhost:5050
+func New(baseURL, userID, password string) *Client {
+	return &Client{BaseURL: baseURL, UserID: userID, Password: password, HTTP: httpclient.New()}
+}
+
+// Task is a task as the API returns it
+type Task struct {
+	ID          string `json:"id"`
+	Content     string `json:"content"`
+	UserID      string `json:"user_id"`
+	CreatedDate string `json:"created_date"`
+	CreatedAt   string `json:"created_at"`
+	Priority    int    `json:"priority"`
+	DueDate     string `json:"due_date"`
+	// CompletedAt is empty while the task is incomplete
+	CompletedAt string `json:"completed_at"`
+	// OverQuota is set on tasks added beyond the daily limit in soft quota mode
+	OverQuota bool `json:"over_quota"`
+	// EstimateMinutes is 0 without an estimate
+	EstimateMinutes int `json:"estimate_minutes"`
+}
+
+// NewTask is a task to create
+type NewTask struct {
+	Content  string `json:"content"`
+	Priority int    `json:"priority,omitempty"`
+	// DueDate is formatted as YYYY-MM-DD
+	DueDate         string `json:"due_date,omitempty"`
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+}
+
+// ListOptions select the tasks ListTasks returns
+type ListOptions struct {
+	// CreatedDate is formatted as YYYY-MM-DD, today in the local timezone when empty
+	CreatedDate string
+	// Sort is one of the API's sort keys, like priority or due_date
+	Sort string
+	Desc bool
+}
+
+// Error is an answer of the API other than 2xx
+type Error struct {
+	StatusCode int
+	Message    string `json:"error"`
+	// Fields tells what failed per field for 400 answers
+	Fields []FieldError `json:"fields"`
+	// RequestID names the request in the server logs, quote it when reporting the error
+	RequestID string `json:"request_id"`
+}
+
+// FieldError is one failed constraint of an invalid request
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("togo: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("togo: %d %s: %s (request %s)", e.StatusCode, http.StatusText(e.StatusCode), e.Message, e.RequestID)
+}
+
+// Login gets a token for the client's user. Other methods call it when needed.
+func (c *Client) Login(ctx context.Context) error {
+	form := url.Values{"user_id": {c.UserID}, "password": {c.Password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var out struct {
+		Data string `json:"data"`
+	}
+	if err := c.send(req, &out); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.token = out.Data
+	c.mu.Unlock()
+	return nil
+}
+
+// CreateTask adds a task for today, subject to the user's daily limit
+func (c *Client) CreateTask(ctx context.Context, t NewTask) (*Task, error) {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{"Idempotency-Key": {uuid.New().String()}}
+
+	var out struct {
+		Data *Task `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/tasks", body, header, &out); err != nil {
+		return nil, err
+	}
+	return out.Data, nil
+}
+
+// ListTasks returns the user's tasks created on opts.CreatedDate
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) ([]*Task, error) {
+	q := url.Values{"created_date": {opts.CreatedDate}}
+	if opts.CreatedDate == "" {
+		q.Set("created_date", time.Now().Format("2006-01-02"))
+	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
+	if opts.Desc {
+		q.Set("order", "desc")
+	}
+	path := "/tasks?" + q.Encode()
+
+	var out struct {
+		Data []*Task `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Data, nil
+}
+
+// CompleteTask marks one of the user's tasks completed
+func (c *Client) CompleteTask(ctx context.Context, id string) (*Task, error) {
+	var out struct {
+		Data *Task `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/tasks/"+url.PathEscape(id)+"/complete", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Data, nil
+}
+
+// DuplicateTask copies the content and priority of task id to date, today when empty.
+// Like CreateTask it is safe to retry.
+func (c *Client) DuplicateTask(ctx context.Context, id, date string) (*Task, error) {
+	path := "/tasks/" + url.PathEscape(id) + "/duplicate"
+	if date != "" {
+		path += "?" + url.Values{"date": {date}}.Encode()
+	}
+	header := http.Header{"Idempotency-Key": {uuid.New().String()}}
+
+	var out struct {
+		Data *Task `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodPost, path, nil, header, &out); err != nil {
+		return nil, err
+	}
+	return out.Data, nil
+}
+
+// CountTasks returns how many tasks the user has on date, today in the local timezone when
+// empty, and how many more the daily limit lets them add
+func (c *Client) CountTasks(ctx context.Context, date string) (count, remaining int, err error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	var out struct {
+		Data struct {
+			Count     int `json:"count"`
+			Remaining int `json:"remaining"`
+		} `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/tasks/count?"+url.Values{"date": {date}}.Encode(), nil, nil, &out); err != nil {
+		return 0, 0, err
+	}
+	return out.Data.Count, out.Data.Remaining, nil
+}
+
+// DeleteTasks deletes up to 100 of the user's tasks at once, returning the IDs they have no
+// such task for
+func (c *Client) DeleteTasks(ctx context.Context, ids []string) (notFound []string, err error) {
+	body, err := json.Marshal(map[string][]string{"ids": ids})
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Data struct {
+			Failed []struct {
+				ID string `json:"id"`
+			} `json:"failed"`
+		} `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodDelete, "/tasks:batchDelete", body, nil, &out); err != nil {
+		return nil, err
+	}
+	for _, f := range out.Data.Failed {
+		notFound = append(notFound, f.ID)
+	}
+	return notFound, nil
+}
+
+// CreateInvite returns a single-use code signing up one user, valid for a week. Only
+// administrators may create them unless the server lets every user.
+func (c *Client) CreateInvite(ctx context.Context) (string, error) {
+	var out struct {
+		Data struct {
+			Code string `json:"code"`
+		} `json:"data"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/invites", []byte(`{}`), nil, &out); err != nil {
+		return "", err
+	}
+	return out.Data.Code, nil
+}
+
+// SignUp creates the client's user with its password, using up an invite code
+func (c *Client) SignUp(ctx context.Context, code string) error {
+	body, err := json.Marshal(map[string]string{"code": code, "user_id": c.UserID, "password": c.Password})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/signup", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var out struct{}
+	return c.send(req, &out)
+}
+
+// DeactivateUser stops a user from logging in and using the API, their tasks are kept.
+// The client's user must be an administrator.
+func (c *Client) DeactivateUser(ctx context.Context, id string) error {
+	var out struct{}
+	return c.call(ctx, http.MethodPost, "/admin/users/"+url.PathEscape(id)+"/deactivate", nil, nil, &out)
+}
+
+// call sends an authenticated request, logging in first when there is no token yet
+// and once more when the API rejects the token it has
+func (c *Client) call(ctx context.Context, method, path string, body []byte, header http.Header, out interface{}) error {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+
+	for relogin := token == ""; ; relogin = true {
+		if relogin {
+			if err := c.Login(ctx); err != nil {
+				return err
+			}
+			c.mu.Lock()
+			token = c.token
+			c.mu.Unlock()
+		}
+
+		var r io.Reader
+		if body != nil {
+			r = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, r)
+		if err != nil {
+			return err
+		}
+		for k, vs := range header {
+			req.Header[k] = vs
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		err = c.send(req, out)
+		if e, ok := err.(*Error); ok && e.StatusCode == http.StatusUnauthorized && !relogin {
+			continue
+		}
+		return err
+	}
+}
+
+// send decodes a 2xx JSON answer into out, and other answers into an *Error
+func (c *Client) send(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		e := &Error{StatusCode: resp.StatusCode}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(b, e) != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(b))
+		}
+		return e
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,523 @@
+package storages
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when the record to change doesn't exist or isn't the caller's
+var ErrNotFound = errors.New("not found")
+
+// ErrUserExists is returned when signing up a user ID that is already taken
+var ErrUserExists = errors.New("user already exists")
+
+// ErrLabelExists is returned when a user names a label like one they have already
+var ErrLabelExists = errors.New("label already exists")
+
+// ErrTimerRunning is returned when starting the timer of a task that is running already
+var ErrTimerRunning = errors.New("timer already running")
+
+// ErrPomodoroRunning is returned when starting a pomodoro while the user has one running
+var ErrPomodoroRunning = errors.New("pomodoro already running")
+
+// TimeLayout is the fixed width UTC layout timestamps are stored in, so they sort as text
+const TimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// Task reflects tasks in DB
+type Task struct {
+	ID          string `json:"id"`
+	Content     string `json:"content"`
+	UserID      string `json:"user_id"`
+	CreatedDate string `json:"created_date"`
+	CreatedAt   string `json:"created_at"`
+	Priority    int    `json:"priority"`
+	DueDate     string `json:"due_date"`
+	// CompletedAt is empty while the task is incomplete
+	CompletedAt string `json:"completed_at"`
+	// OverQuota is set on tasks a user with QuotaSoft added beyond max_todo
+	OverQuota bool `json:"over_quota"`
+	// EstimateMinutes is how long the task is expected to take, 0 without an estimate
+	EstimateMinutes int `json:"estimate_minutes"`
+	// ContentHTML is Content rendered from markdown, only set on reads asking for it
+	// and never stored
+	ContentHTML string `json:"content_html,omitempty"`
+	// ContentTruncated is set on list reads of tasks whose Content is too long to list, Content
+	// is then a summary and reading the task alone answers it whole
+	ContentTruncated bool `json:"content_truncated,omitempty"`
+	// LabelsFrom is a task of the same user whose labels a task being added gets along with it,
+	// it's never stored
+	LabelsFrom string `json:"-"`
+}
+
+// TaskLink records that TaskID can't be done before BlockedByID, both tasks of UserID
+type TaskLink struct {
+	TaskID      string `json:"task_id"`
+	BlockedByID string `json:"blocked_by_id"`
+	UserID      string `json:"-"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Label is a name and a #rrggbb or #rgb color a user tags their tasks with
+type Label struct {
+	ID        string `json:"id"`
+	UserID    string `json:"-"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Location is a point on Earth in decimal degrees, as GPS gives them
+type Location struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// NearbyTask is a task with its location, DistanceKM kilometers away from where it was
+// looked for
+type NearbyTask struct {
+	Task       *Task    `json:"task"`
+	Location   Location `json:"location"`
+	DistanceKM float64  `json:"distance_km"`
+}
+
+// NearbyQuery looks for a user's tasks within RadiusKM of At, the N closest of them. With
+// CreatedDate only the tasks of that date, with Open only the incomplete ones.
+type NearbyQuery struct {
+	UserID      string
+	At          Location
+	RadiusKM    float64
+	CreatedDate string
+	Open        bool
+	N           int
+}
+
+// DayCount is how many tasks a user created and completed on a day, and the time they tracked
+// and focused
+type DayCount struct {
+	Date      string `json:"date"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+	// TrackedSeconds is the time tracked by the timers started that day and stopped since
+	TrackedSeconds int `json:"tracked_seconds"`
+	// Pomodoros counts the pomodoros started that day and completed since, FocusSeconds is how
+	// long they lasted
+	Pomodoros    int `json:"pomodoros"`
+	FocusSeconds int `json:"focus_seconds"`
+}
+
+// ContentSuggestion is a task content a user wrote Count times, the last time for LastDate
+type ContentSuggestion struct {
+	Content  string `json:"content"`
+	Count    int    `json:"count"`
+	LastDate string `json:"last_date"`
+}
+
+// PriorityCount is how many of a user's tasks on a date have a priority, and how many of
+// them are completed
+type PriorityCount struct {
+	Priority  int `json:"priority"`
+	Count     int `json:"count"`
+	Completed int `json:"completed"`
+}
+
+// APIUsage is how many API calls a user made on a UTC day
+type APIUsage struct {
+	Date  string `json:"date"`
+	Calls int    `json:"calls"`
+}
+
+// LimitHitRollup is how many of the users whose max_todo was MaxTodo hit their limit on Date,
+// out of the CohortUsers active users with that max_todo when the day was rolled up
+type LimitHitRollup struct {
+	Date        string `json:"date"`
+	MaxTodo     int    `json:"max_todo"`
+	Users       int    `json:"users"`
+	Attempts    int    `json:"attempts"`
+	CohortUsers int    `json:"cohort_users"`
+}
+
+// LimitOffender is a user who hit their limit on Days dates, with Attempts tasks refused or
+// added over quota in total, the last time on LastDate
+type LimitOffender struct {
+	UserID   string `json:"user_id"`
+	Days     int    `json:"days"`
+	Attempts int    `json:"attempts"`
+	LastDate string `json:"last_date"`
+}
+
+// Invite lets one person sign up with Code until ExpiresAt, UsedBy is who did
+type Invite struct {
+	Code      string `json:"code"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+	UsedBy    string `json:"used_by"`
+	UsedAt    string `json:"used_at"`
+}
+
+// AuditEntry records an action on the account of a user, by them or an admin
+type AuditEntry struct {
+	ID string `json:"id"`
+	At string `json:"at"`
+	// ActorID is who acted, UserID the user whose account it was
+	ActorID string `json:"actor_id"`
+	UserID  string `json:"user_id"`
+	// Action is one of the Audit constants
+	Action string `json:"action"`
+	// Detail is the scope of an impersonation token, the method and URI of a request or
+	// what changed
+	Detail string `json:"detail"`
+}
+
+// Audited actions
+const (
+	// AuditImpersonate is an admin getting a token acting as the user, AuditRequest a
+	// request made with it
+	AuditImpersonate = "impersonate"
+	AuditRequest     = "request"
+	// AuditLogin is the user logging in with their password
+	AuditLogin = "login"
+	// AuditLimitChange is an admin changing the daily limits of the user
+	AuditLimitChange = "limit_change"
+	// AuditDelete is the user deleting tasks
+	AuditDelete = "delete"
+)
+
+// AuditQuery selects the N latest entries on the account of UserID, those of Action when set,
+// and before the entry at BeforeAt with BeforeID when set, newest first
+type AuditQuery struct {
+	UserID   string
+	Action   string
+	BeforeAt string
+	BeforeID string
+	N        int
+}
+
+// Streak is a user's run of consecutive UTC days with at least one completed task, up to LastDay
+type Streak struct {
+	Current int    `json:"current"`
+	Longest int    `json:"longest"`
+	LastDay string `json:"last_day"`
+}
+
+// TaskFields lists the task fields a client can select when listing
+var TaskFields = []string{"id", "content", "user_id", "created_date", "created_at", "priority", "due_date", "completed_at", "over_quota", "estimate_minutes"}
+
+// IsTaskField reports whether name is one of TaskFields
+func IsTaskField(name string) bool {
+	for _, f := range TaskFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns the named fields of t keyed by their JSON name
+func (t *Task) Fields(names []string) map[string]interface{} {
+	return t.FieldsInto(make(map[string]interface{}, len(names)), names)
+}
+
+// FieldsInto is Fields setting them in m, an empty map, and returning it
+func (t *Task) FieldsInto(m map[string]interface{}, names []string) map[string]interface{} {
+	for _, n := range names {
+		switch n {
+		case "id":
+			m[n] = t.ID
+		case "content":
+			m[n] = t.Content
+			if t.ContentHTML != "" {
+				m["content_html"] = t.ContentHTML
+			}
+		case "user_id":
+			m[n] = t.UserID
+		case "created_date":
+			m[n] = t.CreatedDate
+		case "created_at":
+			m[n] = t.CreatedAt
+		case "priority":
+			m[n] = t.Priority
+		case "due_date":
+			m[n] = t.DueDate
+		case "completed_at":
+			m[n] = t.CompletedAt
+		case "over_quota":
+			m[n] = t.OverQuota
+		case "estimate_minutes":
+			m[n] = t.EstimateMinutes
+		}
+	}
+	return m
+}
+
+// SortKeys lists the task fields a list can be ordered by
+var SortKeys = []string{"created_at", "priority", "due_date"}
+
+// IsSortKey reports whether name is one of SortKeys
+func IsSortKey(name string) bool {
+	for _, k := range SortKeys {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOptions controls which columns are read and how results are ordered
+type ListOptions struct {
+	// Fields to read, all of TaskFields when empty
+	Fields []string
+	// Sort is one of SortKeys, storage order when empty
+	Sort string
+	Desc bool
+	// Max is the most tasks read, RetrieveTasks returns a *ResultLimitExceeded when more match.
+	// 0 reads them all.
+	Max int
+}
+
+// User reflects users data from DB
+type User struct {
+	ID string `json:"id"`
+	// Password holds the hash produced by the password package
+	Password string `json:"-"`
+	MaxTodo  int    `json:"max_todo"`
+	// Role is RoleAdmin for administrators and empty for everyone else
+	Role string `json:"role"`
+	// DeactivatedAt is empty while the user may sign in, their data is kept either way
+	DeactivatedAt string `json:"deactivated_at"`
+}
+
+// RoleAdmin may manage other users
+const RoleAdmin = "admin"
+
+// TaskMove describes moving a task of a user to another date
+type TaskMove struct {
+	TaskID string
+	UserID string
+	ToDate string
+	// Action is recorded in the task history, e.g. "snooze"
+	Action string
+	At     string
+}
+
+// UserSettings are preferences users manage themselves
+type UserSettings struct {
+	NotifyLimitReached bool `json:"notify_limit_reached"`
+	// CarryOver is what happens to incomplete tasks at midnight: CarryOverCopy, CarryOverMove or nothing when empty
+	CarryOver string `json:"carry_over"`
+	// DailyDigest emails a summary of the day to Email every morning in Timezone
+	DailyDigest bool   `json:"daily_digest"`
+	Email       string `json:"email"`
+	// Timezone is an IANA name such as Asia/Ho_Chi_Minh, the server's when empty
+	Timezone string `json:"timezone"`
+	// QuotaMode is QuotaSoft, or QuotaHard when empty
+	QuotaMode string `json:"quota_mode"`
+	// StandupOptOut leaves the user out of the team standup report
+	StandupOptOut bool `json:"standup_opt_out"`
+}
+
+// Quota modes, what adding a task beyond max_todo does
+const (
+	// QuotaHard refuses the task
+	QuotaHard = "hard"
+	// QuotaSoft adds it flagged OverQuota
+	QuotaSoft = "soft"
+)
+
+// LimitSchedule replaces a user's max_todo on weekends and holidays, nil ones don't
+type LimitSchedule struct {
+	Weekend *int `json:"weekend"`
+	Holiday *int `json:"holiday"`
+}
+
+// QuotaAdjustment grants UserID Amount more tasks than their limit on Date, once
+type QuotaAdjustment struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Date      string `json:"date"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Day kinds a LimitSchedule has a limit for
+const (
+	DayWeekend = "weekend"
+	DayHoliday = "holiday"
+)
+
+// DigestRecipient is a user who opted in to the daily digest
+type DigestRecipient struct {
+	UserID   string
+	Email    string
+	Timezone string
+}
+
+// StandupMember is what a user of the team standup report did yesterday and plans today
+type StandupMember struct {
+	UserID string `json:"user_id"`
+	// Completed are the contents of the tasks of yesterday they completed
+	Completed []string `json:"completed"`
+	// Planned are the contents of their tasks of today, completed or not
+	Planned []string `json:"planned"`
+}
+
+// Carry over modes
+const (
+	CarryOverCopy = "copy"
+	CarryOverMove = "move"
+)
+
+// CarryOver describes carrying the incomplete tasks of a user from one date to the next
+type CarryOver struct {
+	UserID   string
+	FromDate string
+	ToDate   string
+	// Move changes the date of the tasks instead of copying them
+	Move bool
+	// NewID names the copies
+	NewID func() string
+	At    string
+}
+
+// SigningKey is a key pair tokens are signed with, the newest unretired one signs
+// and retired ones keep verifying tokens issued before they were rotated out
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	// PrivateKey is PEM encoded
+	PrivateKey string
+	CreatedAt  string
+	// RetiredAt is empty for the key in use
+	RetiredAt string
+}
+
+// Undo lets a user reverse a batch operation until ExpiresAt
+type Undo struct {
+	Token     string
+	UserID    string
+	ExpiresAt string
+}
+
+// IdempotencyKey is a key the client sent with a task creation, adding the task again
+// with the same Key returns the first one instead until ExpiresAt
+type IdempotencyKey struct {
+	Key       string
+	ExpiresAt string
+}
+
+// APIKey lets scripts act as a user without logging in
+type APIKey struct {
+	ID     string
+	UserID string
+	Name   string
+	// KeyHash is the hex SHA-256 of the key, which itself isn't stored
+	KeyHash   string
+	CreatedAt string
+}
+
+// Session is a login on one device, tokens carry its ID so it can be revoked
+type Session struct {
+	ID        string `json:"id"`
+	UserID    string `json:"-"`
+	Device    string `json:"device"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// Share lets anyone with its link read UserID's tasks of Date until ExpiresAt or until it's
+// revoked
+type Share struct {
+	ID        string `json:"id"`
+	UserID    string `json:"-"`
+	Date      string `json:"date"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// TaskLimitReached is returned when a user already has max_todo tasks on a date
+type TaskLimitReached struct {
+	UserID string
+	Date   string
+}
+
+func (e *TaskLimitReached) Error() string {
+	return fmt.Sprintf("user %s reached the daily task limit for %s", e.UserID, e.Date)
+}
+
+// ResultLimitExceeded is returned for reads asking for more than the server answers at once
+type ResultLimitExceeded struct {
+	// What is "tasks" or "results" for too many of them and "days" for too long a range of dates
+	What string
+	Max  int
+}
+
+func (e *ResultLimitExceeded) Error() string {
+	return fmt.Sprintf("more than %d %s asked for", e.Max, e.What)
+}
+
+// DuplicateRequest is returned when a task was already added under an idempotency key
+type DuplicateRequest struct {
+	Key    string
+	TaskID string
+}
+
+func (e *DuplicateRequest) Error() string {
+	return fmt.Sprintf("task %s was already added with idempotency key %s", e.TaskID, e.Key)
+}
+
+// TimeEntry is a span of time tracked on a task with its timer
+type TimeEntry struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"task_id"`
+	UserID    string `json:"user_id"`
+	StartedAt string `json:"started_at"`
+	// StoppedAt is empty while the timer runs
+	StoppedAt string `json:"stopped_at"`
+	// Seconds is how long the timer ran, 0 while it runs
+	Seconds int `json:"seconds"`
+}
+
+// Pomodoro is a focus session on a task
+type Pomodoro struct {
+	ID             string `json:"id"`
+	TaskID         string `json:"task_id"`
+	UserID         string `json:"user_id"`
+	StartedAt      string `json:"started_at"`
+	PlannedMinutes int    `json:"planned_minutes"`
+	// CompletedAt is empty while the pomodoro runs
+	CompletedAt string `json:"completed_at"`
+	// Seconds is how long the pomodoro lasted, 0 while it runs
+	Seconds int `json:"seconds"`
+}
+
+// CreationRate is how many tasks a user created in an hour, and in the baseline period before it
+type CreationRate struct {
+	UserID   string
+	Created  int
+	Baseline int
+}
+
+// Anomaly is a user found creating tasks much faster than they usually do
+type Anomaly struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	DetectedAt string `json:"detected_at"`
+	// WindowStart is the start of the hour the tasks were created in
+	WindowStart string `json:"window_start"`
+	Created     int    `json:"created"`
+	// BaselinePerHour is how many tasks an hour the user created before
+	BaselinePerHour float64 `json:"baseline_per_hour"`
+	// ThrottledUntil is when the CreationThrottle applied for it ends, empty when the user was
+	// only flagged
+	ThrottledUntil string `json:"throttled_until"`
+}
+
+// CreationThrottle limits how many tasks a user may create an hour until some time, on top of
+// max_todo
+type CreationThrottle struct {
+	UserID  string `json:"user_id"`
+	PerHour int    `json:"per_hour"`
+	Until   string `json:"until"`
+}
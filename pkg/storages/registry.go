@@ -0,0 +1,106 @@
+package storages
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Backend is an opened Store and the connection pool under it
+type Backend struct {
+	Store Store
+	// DB is warmed at startup and closed at shutdown by the app
+	DB *sql.DB
+}
+
+// OpenOptions are what every backend is opened with
+type OpenOptions struct {
+	// DSN names the database, its form is up to the backend
+	DSN string
+	// SQLComments tags statements with the request ID and user they run for
+	SQLComments bool
+}
+
+// Opener opens a backend migrated to the current schema
+type Opener func(ctx context.Context, opts OpenOptions) (*Backend, error)
+
+// Checker is a preflight check of a backend: it tells whether the backend would open with
+// opts, without creating or migrating anything, along with what it found. The Backend is
+// answered when its schema is the current one, for further checks against its Store.
+type Checker func(ctx context.Context, opts OpenOptions) (b *Backend, detail string, err error)
+
+var (
+	openersMu sync.RWMutex
+	openers   = make(map[string]Opener)
+	checkers  = make(map[string]Checker)
+)
+
+// Register makes a backend available to Open under name. Backends register themselves in an
+// init function, so importing one is enough to select it. Registering a name twice panics.
+func Register(name string, open Opener) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if open == nil {
+		panic("storages: Register opener is nil")
+	}
+	if _, dup := openers[name]; dup {
+		panic("storages: Register called twice for backend " + name)
+	}
+	openers[name] = open
+}
+
+// RegisterChecker makes a preflight check available to Check for the backend registered
+// under name. Registering one twice panics.
+func RegisterChecker(name string, check Checker) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if check == nil {
+		panic("storages: RegisterChecker checker is nil")
+	}
+	if _, dup := checkers[name]; dup {
+		panic("storages: RegisterChecker called twice for backend " + name)
+	}
+	checkers[name] = check
+}
+
+// Backends returns the names of the registered backends, sorted
+func Backends() []string {
+	openersMu.RLock()
+	defer openersMu.RUnlock()
+	names := make([]string, 0, len(openers))
+	for name := range openers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the backend registered under name
+func Open(ctx context.Context, name string, opts OpenOptions) (*Backend, error) {
+	openersMu.RLock()
+	open, ok := openers[name]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage backend %q, registered: %s", name, strings.Join(Backends(), ", "))
+	}
+	return open(ctx, opts)
+}
+
+// Check runs the preflight check of the backend registered under name. A backend without one
+// is only known to be registered.
+func Check(ctx context.Context, name string, opts OpenOptions) (b *Backend, detail string, err error) {
+	openersMu.RLock()
+	_, ok := openers[name]
+	check := checkers[name]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("unknown storage backend %q, registered: %s", name, strings.Join(Backends(), ", "))
+	}
+	if check == nil {
+		return nil, "backend " + name + " has no preflight check", nil
+	}
+	return check(ctx, opts)
+}
@@ -0,0 +1,40 @@
+package storagetest_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/manabie-com/togo/internal/storages/sqlite"
+	"github.com/manabie-com/togo/pkg/storages"
+	"github.com/manabie-com/togo/pkg/storages/storagetest"
+)
+
+// wrappedStore is a backend the way one outside this module would write it, with only the
+// public packages at hand. It lends its storage from sqlite to keep the test self-contained.
+type wrappedStore struct {
+	storages.Store
+}
+
+func init() {
+	storages.Register("storagetest-wrapped", func(ctx context.Context, opts storages.OpenOptions) (*storages.Backend, error) {
+		b, err := storages.Open(ctx, "sqlite", opts)
+		if err != nil {
+			return nil, err
+		}
+		b.Store = &wrappedStore{Store: b.Store}
+		return b, nil
+	})
+}
+
+func TestExternalBackend(t *testing.T) {
+	b, err := storages.Open(context.Background(), "storagetest-wrapped", storages.OpenOptions{DSN: filepath.Join(t.TempDir(), "togo.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer b.DB.Close()
+	if _, ok := b.Store.(*wrappedStore); !ok {
+		t.Fatalf("Open answered a %T, want *wrappedStore", b.Store)
+	}
+	storagetest.RunConformanceTests(t, b.Store)
+}
@@ -0,0 +1,2188 @@
+// Package storagetest checks that a storages.Store behaves the way the service layer expects.
+// Backends, in this module or outside it, call RunConformanceTests from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.RunConformanceTests(t, newStore(t))
+//	}
+package storagetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manabie-com/togo/pkg/storages"
+)
+
+const date = "2020-06-29"
+
+// RunConformanceTests runs the suite against s, which must be writable.
+// Every test creates its own users so s may already hold other data.
+func RunConformanceTests(t *testing.T, s storages.Store) {
+	t.Run("AddAndRetrieve", func(t *testing.T) { testAddAndRetrieve(t, s) })
+	t.Run("RetrieveOtherUserOrDate", func(t *testing.T) { testRetrieveIsolation(t, s) })
+	t.Run("Projection", func(t *testing.T) { testProjection(t, s) })
+	t.Run("Sort", func(t *testing.T) { testSort(t, s) })
+	t.Run("MaxResults", func(t *testing.T) { testMaxResults(t, s) })
+	t.Run("BulkInsert", func(t *testing.T) { testBulkInsert(t, s) })
+	t.Run("LimitPerDay", func(t *testing.T) { testLimitPerDay(t, s) })
+	t.Run("ConcurrentLimit", func(t *testing.T) { testConcurrentLimit(t, s) })
+	t.Run("IdempotentAdd", func(t *testing.T) { testIdempotentAdd(t, s) })
+	t.Run("SoftQuota", func(t *testing.T) { testSoftQuota(t, s) })
+	t.Run("LimitSchedule", func(t *testing.T) { testLimitSchedule(t, s) })
+	t.Run("TaskLinks", func(t *testing.T) { testTaskLinks(t, s) })
+	t.Run("Labels", func(t *testing.T) { testLabels(t, s) })
+	t.Run("Locations", func(t *testing.T) { testLocations(t, s) })
+	t.Run("TimeTracking", func(t *testing.T) { testTimeTracking(t, s) })
+	t.Run("Pomodoros", func(t *testing.T) { testPomodoros(t, s) })
+	t.Run("MoveTask", func(t *testing.T) { testMoveTask(t, s) })
+	t.Run("CompleteTask", func(t *testing.T) { testCompleteTask(t, s) })
+	t.Run("Batch", func(t *testing.T) { testBatch(t, s) })
+	t.Run("UndoLimit", func(t *testing.T) { testUndoLimit(t, s) })
+	t.Run("CompletedTasks", func(t *testing.T) { testCompletedTasks(t, s) })
+	t.Run("IterateTasks", func(t *testing.T) { testIterateTasks(t, s) })
+	t.Run("TasksAsOf", func(t *testing.T) { testTasksAsOf(t, s) })
+	t.Run("Invites", func(t *testing.T) { testInvites(t, s) })
+	t.Run("QuotaAdjustments", func(t *testing.T) { testQuotaAdjustments(t, s) })
+	t.Run("CarryOver", func(t *testing.T) { testCarryOver(t, s) })
+	t.Run("Count", func(t *testing.T) { testCount(t, s) })
+	t.Run("DayCounts", func(t *testing.T) { testDayCounts(t, s) })
+	t.Run("PriorityCounts", func(t *testing.T) { testPriorityCounts(t, s) })
+	t.Run("ContentSuggestions", func(t *testing.T) { testContentSuggestions(t, s) })
+	t.Run("Streaks", func(t *testing.T) { testStreaks(t, s) })
+	t.Run("APIUsage", func(t *testing.T) { testAPIUsage(t, s) })
+	t.Run("AuditLog", func(t *testing.T) { testAuditLog(t, s) })
+	t.Run("Anomalies", func(t *testing.T) { testAnomalies(t, s) })
+	t.Run("AccountAudit", func(t *testing.T) { testAccountAudit(t, s) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, s) })
+	t.Run("PasswordHash", func(t *testing.T) { testPasswordHash(t, s) })
+	t.Run("SigningKeys", func(t *testing.T) { testSigningKeys(t, s) })
+	t.Run("Sessions", func(t *testing.T) { testSessions(t, s) })
+	t.Run("Shares", func(t *testing.T) { testShares(t, s) })
+	t.Run("Standup", func(t *testing.T) { testStandup(t, s) })
+	t.Run("UserSettings", func(t *testing.T) { testUserSettings(t, s) })
+	t.Run("LimitHits", func(t *testing.T) { testLimitHits(t, s) })
+	t.Run("LongContent", func(t *testing.T) { testLongContent(t, s) })
+	t.Run("Errors", func(t *testing.T) { testErrors(t, s) })
+}
+
+func testAddAndRetrieve(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	want := newTask(u, "first")
+	want.Priority = 2
+	want.DueDate = "2020-07-01"
+	if err := s.AddTask(ctx, want); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	got := retrieve(t, s, u, date, storages.ListOptions{})
+	if len(got) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(got))
+	}
+	if *got[0] != *want {
+		t.Errorf("got %+v, want %+v", *got[0], *want)
+	}
+}
+
+func testRetrieveIsolation(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+
+	tomorrow := newTask(u, "tomorrow")
+	tomorrow.CreatedDate = "2020-06-30"
+	for _, task := range []*storages.Task{newTask(other, "not mine"), tomorrow} {
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+
+	if got := retrieve(t, s, u, date, storages.ListOptions{}); len(got) != 0 {
+		t.Errorf("got %d tasks, want tasks of other users and dates to be excluded", len(got))
+	}
+}
+
+func testProjection(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	task := newTask(u, "projected")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id", "content"}})
+	if len(got) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(got))
+	}
+	want := storages.Task{ID: task.ID, Content: task.Content}
+	if *got[0] != want {
+		t.Errorf("got %+v, want only id and content set: %+v", *got[0], want)
+	}
+}
+
+func testSort(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	for _, p := range []int{2, 3, 1} {
+		task := newTask(u, "sorted")
+		task.Priority = p
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		desc bool
+		want []int
+	}{
+		{false, []int{1, 2, 3}},
+		{true, []int{3, 2, 1}},
+	} {
+		got := retrieve(t, s, u, date, storages.ListOptions{Sort: "priority", Desc: tc.desc})
+		if len(got) != len(tc.want) {
+			t.Fatalf("got %d tasks, want %d", len(got), len(tc.want))
+		}
+		for i, task := range got {
+			if task.Priority != tc.want[i] {
+				t.Errorf("desc=%v: position %d has priority %d, want %d", tc.desc, i, task.Priority, tc.want[i])
+			}
+		}
+	}
+}
+
+func testMaxResults(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	for i := 0; i < 3; i++ {
+		if err := s.AddTask(ctx, newTask(u, "capped")); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+
+	if got := retrieve(t, s, u, date, storages.ListOptions{Max: 3}); len(got) != 3 {
+		t.Errorf("got %d tasks with Max 3, want 3", len(got))
+	}
+	_, err := s.RetrieveTasks(ctx, valid(u.ID), valid(date), storages.ListOptions{Max: 2})
+	var limitErr *storages.ResultLimitExceeded
+	if !errors.As(err, &limitErr) || limitErr.Max != 2 || limitErr.What != "tasks" {
+		t.Errorf("RetrieveTasks with Max 2 = %v, want a ResultLimitExceeded of 2 tasks", err)
+	}
+}
+
+func testBulkInsert(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	tasks := make([]*storages.Task, 500)
+	for i := range tasks {
+		tasks[i] = newTask(u, "bulk")
+	}
+	if err := s.AddTasks(ctx, tasks); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id"}}); len(got) != len(tasks) {
+		t.Errorf("got %d tasks, want %d", len(got), len(tasks))
+	}
+
+	// a failing row must not leave the rest of the batch behind
+	dup := []*storages.Task{newTask(u, "ok"), tasks[0]}
+	if err := s.AddTasks(ctx, dup); err == nil {
+		t.Fatal("AddTasks with a duplicate id: want error")
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id"}}); len(got) != len(tasks) {
+		t.Errorf("got %d tasks after failed batch, want %d", len(got), len(tasks))
+	}
+}
+
+func testLimitPerDay(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 2)
+
+	for i := 0; i < 2; i++ {
+		count, maxTodo, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "within limit"), nil)
+		if err != nil {
+			t.Fatalf("AddTaskWithLimitPerDay %d: %v", i, err)
+		}
+		if count != i+1 || maxTodo != 2 {
+			t.Errorf("AddTaskWithLimitPerDay %d: got %d of %d, want %d of 2", i, count, maxTodo, i+1)
+		}
+	}
+
+	_, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "over limit"), nil)
+	var limitErr *storages.TaskLimitReached
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("got %v, want TaskLimitReached", err)
+	}
+	if limitErr.UserID != u.ID || limitErr.Date != date {
+		t.Errorf("got %+v, want user %s and date %s", limitErr, u.ID, date)
+	}
+
+	// the limit is per day
+	tomorrow := newTask(u, "tomorrow")
+	tomorrow.CreatedDate = "2020-06-30"
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, tomorrow, nil); err != nil {
+		t.Errorf("AddTaskWithLimitPerDay on another date: %v", err)
+	}
+}
+
+func testConcurrentLimit(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	const limit, attempts = 5, 50
+	u := newUser(t, s, limit)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		added    int
+		rejected int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "concurrent"), nil)
+			var limitErr *storages.TaskLimitReached
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				added++
+			case errors.As(err, &limitErr):
+				rejected++
+			default:
+				t.Errorf("AddTaskWithLimitPerDay: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if added != limit || rejected != attempts-limit {
+		t.Errorf("added %d and rejected %d, want %d and %d", added, rejected, limit, attempts-limit)
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id"}}); len(got) != limit {
+		t.Errorf("stored %d tasks, want %d", len(got), limit)
+	}
+}
+
+func testIdempotentAdd(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	key := &storages.IdempotencyKey{Key: "retried", ExpiresAt: time.Now().Add(time.Hour).UTC().Format(storages.TimeLayout)}
+
+	first := newTask(u, "once")
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, first, key); err != nil {
+		t.Fatalf("AddTaskWithLimitPerDay: %v", err)
+	}
+	_, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "once"), key)
+	var dup *storages.DuplicateRequest
+	if !errors.As(err, &dup) {
+		t.Fatalf("got %v, want DuplicateRequest", err)
+	}
+	if dup.TaskID != first.ID {
+		t.Errorf("got task %s, want %s", dup.TaskID, first.ID)
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id"}}); len(got) != 1 {
+		t.Errorf("stored %d tasks, want 1", len(got))
+	}
+
+	// keys are per user, and expire
+	other := newUser(t, s, 5)
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(other, "once"), key); err != nil {
+		t.Errorf("AddTaskWithLimitPerDay for another user: %v", err)
+	}
+	expired := &storages.IdempotencyKey{Key: "expired", ExpiresAt: time.Now().Add(-time.Hour).UTC().Format(storages.TimeLayout)}
+	for i := 0; i < 2; i++ {
+		if _, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "expired key"), expired); err != nil {
+			t.Errorf("AddTaskWithLimitPerDay %d with an expired key: %v", i, err)
+		}
+	}
+}
+
+func testSoftQuota(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 1)
+	if err := s.UpdateUserSettings(ctx, valid(u.ID), &storages.UserSettings{QuotaMode: storages.QuotaSoft}); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+
+	within, over := newTask(u, "within"), newTask(u, "over")
+	for _, task := range []*storages.Task{within, over} {
+		if _, _, err := s.AddTaskWithLimitPerDay(ctx, task, nil); err != nil {
+			t.Fatalf("AddTaskWithLimitPerDay %s: %v", task.Content, err)
+		}
+	}
+	if within.OverQuota || !over.OverQuota {
+		t.Errorf("got over_quota %v and %v, want false and true", within.OverQuota, over.OverQuota)
+	}
+
+	got := retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id", "over_quota"}})
+	flagged := 0
+	for _, task := range got {
+		if task.OverQuota {
+			flagged++
+		}
+	}
+	if len(got) != 2 || flagged != 1 {
+		t.Errorf("stored %d tasks with %d over quota, want 2 with 1", len(got), flagged)
+	}
+}
+
+func testLimitSchedule(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	const saturday, holiday = "2020-06-27", "2020-07-01"
+
+	weekend, none := 1, 0
+	if err := s.UpdateLimitSchedule(ctx, valid(u.ID), &storages.LimitSchedule{Weekend: &weekend, Holiday: &none}); err != nil {
+		t.Fatalf("UpdateLimitSchedule: %v", err)
+	}
+	if err := s.ReplaceHolidays(ctx, "2020-01-01", "2021-01-01", []string{holiday}); err != nil {
+		t.Fatalf("ReplaceHolidays: %v", err)
+	}
+	sched, err := s.RetrieveLimitSchedule(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrieveLimitSchedule: %v", err)
+	}
+	if sched.Weekend == nil || *sched.Weekend != 1 || sched.Holiday == nil || *sched.Holiday != 0 {
+		t.Errorf("got schedule %+v, want weekend 1 and holiday 0", sched)
+	}
+
+	for _, c := range []struct {
+		date    string
+		maxTodo int
+	}{{saturday, 1}, {holiday, 0}, {date, 5}} {
+		if _, maxTodo, err := s.CountTasks(ctx, valid(u.ID), valid(c.date)); err != nil || maxTodo != c.maxTodo {
+			t.Errorf("CountTasks on %s: got limit %d (%v), want %d", c.date, maxTodo, err, c.maxTodo)
+		}
+	}
+
+	task := newTask(u, "saturday")
+	task.CreatedDate = saturday
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, task, nil); err != nil {
+		t.Fatalf("AddTaskWithLimitPerDay on a weekend: %v", err)
+	}
+	var limitErr *storages.TaskLimitReached
+	for _, d := range []string{saturday, holiday} {
+		task := newTask(u, "over the schedule")
+		task.CreatedDate = d
+		if _, _, err := s.AddTaskWithLimitPerDay(ctx, task, nil); !errors.As(err, &limitErr) {
+			t.Errorf("AddTaskWithLimitPerDay on %s: got %v, want TaskLimitReached", d, err)
+		}
+	}
+
+	// without a schedule max_todo applies every day
+	if err := s.UpdateLimitSchedule(ctx, valid(u.ID), &storages.LimitSchedule{}); err != nil {
+		t.Fatalf("UpdateLimitSchedule: %v", err)
+	}
+	task = newTask(u, "holiday")
+	task.CreatedDate = holiday
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, task, nil); err != nil {
+		t.Errorf("AddTaskWithLimitPerDay on a holiday without a schedule: %v", err)
+	}
+	if err := s.ReplaceHolidays(ctx, "2020-01-01", "2021-01-01", nil); err != nil {
+		t.Fatalf("ReplaceHolidays: %v", err)
+	}
+}
+
+func testTaskLinks(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	a, b, c := newTask(u, "a"), newTask(u, "b"), newTask(u, "c")
+	if err := s.AddTasks(ctx, []*storages.Task{a, b, c}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	for _, l := range []*storages.TaskLink{
+		{TaskID: a.ID, BlockedByID: b.ID, UserID: u.ID, CreatedAt: at},
+		{TaskID: a.ID, BlockedByID: c.ID, UserID: u.ID, CreatedAt: at},
+		{TaskID: b.ID, BlockedByID: c.ID, UserID: u.ID, CreatedAt: at},
+		// adding a link twice is a no-op
+		{TaskID: b.ID, BlockedByID: c.ID, UserID: u.ID, CreatedAt: at},
+	} {
+		if err := s.AddTaskLink(ctx, l); err != nil {
+			t.Fatalf("AddTaskLink: %v", err)
+		}
+	}
+
+	links, err := s.RetrieveTaskLinks(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTaskLinks: %v", err)
+	}
+	if len(links) != 3 {
+		t.Errorf("got %d links, want 3", len(links))
+	}
+	if other, err := s.RetrieveTaskLinks(ctx, valid(newUser(t, s, 5).ID)); err != nil || len(other) != 0 {
+		t.Errorf("got %d links of another user, want none (err %v)", len(other), err)
+	}
+
+	blockedBy, blocking, err := s.RetrieveLinkedTasks(ctx, valid(u.ID), valid(b.ID))
+	if err != nil {
+		t.Fatalf("RetrieveLinkedTasks: %v", err)
+	}
+	if len(blockedBy) != 1 || blockedBy[0].ID != c.ID {
+		t.Errorf("got %d blockers of b, want c", len(blockedBy))
+	}
+	if len(blocking) != 1 || blocking[0].ID != a.ID {
+		t.Errorf("got %d tasks blocked by b, want a", len(blocking))
+	}
+
+	// links to deleted tasks are left out until undone
+	if _, err := s.DeleteTasks(ctx, valid(u.ID), []string{c.ID}, nil); err != nil {
+		t.Fatalf("DeleteTasks: %v", err)
+	}
+	blockedBy, _, err = s.RetrieveLinkedTasks(ctx, valid(u.ID), valid(a.ID))
+	if err != nil {
+		t.Fatalf("RetrieveLinkedTasks: %v", err)
+	}
+	if len(blockedBy) != 1 || blockedBy[0].ID != b.ID {
+		t.Errorf("got %d blockers of a after deleting c, want b", len(blockedBy))
+	}
+
+	if err := s.RemoveTaskLink(ctx, valid(u.ID), valid(a.ID), valid(b.ID)); err != nil {
+		t.Fatalf("RemoveTaskLink: %v", err)
+	}
+	if err := s.RemoveTaskLink(ctx, valid(u.ID), valid(a.ID), valid(b.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("removing a removed link: got %v, want ErrNotFound", err)
+	}
+}
+
+func testLabels(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+	other := newUser(t, s, 10)
+	task, theirs := newTask(u, "labelled"), newTask(other, "theirs")
+	if err := s.AddTasks(ctx, []*storages.Task{task, theirs}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	work := &storages.Label{ID: uuid.New().String(), UserID: u.ID, Name: "Work", Color: "#1e90ff", CreatedAt: at}
+	home := &storages.Label{ID: uuid.New().String(), UserID: u.ID, Name: "home", Color: "#fff", CreatedAt: at}
+	for _, label := range []*storages.Label{work, home} {
+		if err := s.AddLabel(ctx, label); err != nil {
+			t.Fatalf("AddLabel: %v", err)
+		}
+	}
+	dup := &storages.Label{ID: uuid.New().String(), UserID: u.ID, Name: "WORK", Color: "#000", CreatedAt: at}
+	if err := s.AddLabel(ctx, dup); !errors.Is(err, storages.ErrLabelExists) {
+		t.Errorf("AddLabel of a taken name: got %v, want ErrLabelExists", err)
+	}
+	dup.UserID = other.ID
+	if err := s.AddLabel(ctx, dup); err != nil {
+		t.Errorf("AddLabel of a name another user took: %v", err)
+	}
+
+	labels, err := s.RetrieveLabels(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrieveLabels: %v", err)
+	}
+	if len(labels) != 2 || labels[0].ID != home.ID || labels[1].ID != work.ID {
+		t.Errorf("got %+v, want home then Work", labels)
+	}
+
+	renamed := &storages.Label{ID: work.ID, UserID: u.ID, Name: "Home", Color: "#000000"}
+	if err := s.UpdateLabel(ctx, renamed); !errors.Is(err, storages.ErrLabelExists) {
+		t.Errorf("UpdateLabel to a taken name: got %v, want ErrLabelExists", err)
+	}
+	renamed.Name = "Office"
+	if err := s.UpdateLabel(ctx, renamed); err != nil {
+		t.Fatalf("UpdateLabel: %v", err)
+	}
+	if renamed.CreatedAt != at {
+		t.Errorf("got created_at %q, want %q", renamed.CreatedAt, at)
+	}
+	renamed.UserID = other.ID
+	if err := s.UpdateLabel(ctx, renamed); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UpdateLabel of another user's label: got %v, want ErrNotFound", err)
+	}
+
+	for _, label := range []*storages.Label{work, home, work} {
+		if err := s.AssignLabel(ctx, valid(u.ID), valid(task.ID), valid(label.ID), at); err != nil {
+			t.Fatalf("AssignLabel: %v", err)
+		}
+	}
+	if err := s.AssignLabel(ctx, valid(u.ID), valid(task.ID), valid(dup.ID), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("AssignLabel of another user's label: got %v, want ErrNotFound", err)
+	}
+	if err := s.AssignLabel(ctx, valid(u.ID), valid(theirs.ID), valid(work.ID), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("AssignLabel to another user's task: got %v, want ErrNotFound", err)
+	}
+	labels, err = s.RetrieveTaskLabels(ctx, valid(u.ID), valid(task.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTaskLabels: %v", err)
+	}
+	if len(labels) != 2 || labels[0].ID != home.ID || labels[1].Name != "Office" {
+		t.Errorf("got %+v, want home then Office", labels)
+	}
+
+	// a task added with LabelsFrom gets the labels of that task, none of another user's
+	for _, from := range []*storages.Task{task, theirs} {
+		copied := newTask(u, "copied")
+		copied.LabelsFrom = from.ID
+		if _, _, err := s.AddTaskWithLimitPerDay(ctx, copied, nil); err != nil {
+			t.Fatalf("AddTaskWithLimitPerDay: %v", err)
+		}
+		labels, err := s.RetrieveTaskLabels(ctx, valid(u.ID), valid(copied.ID))
+		if err != nil {
+			t.Fatalf("RetrieveTaskLabels: %v", err)
+		}
+		if want := map[bool]int{true: 2, false: 0}[from == task]; len(labels) != want {
+			t.Errorf("got %d labels copied from %s, want %d", len(labels), from.Content, want)
+		}
+	}
+
+	if err := s.UnassignLabel(ctx, valid(u.ID), valid(task.ID), valid(home.ID)); err != nil {
+		t.Fatalf("UnassignLabel: %v", err)
+	}
+	if err := s.UnassignLabel(ctx, valid(u.ID), valid(task.ID), valid(home.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UnassignLabel twice: got %v, want ErrNotFound", err)
+	}
+	if err := s.DeleteLabel(ctx, valid(other.ID), valid(work.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("DeleteLabel of another user's label: got %v, want ErrNotFound", err)
+	}
+	if err := s.DeleteLabel(ctx, valid(u.ID), valid(work.ID)); err != nil {
+		t.Fatalf("DeleteLabel: %v", err)
+	}
+	labels, err = s.RetrieveTaskLabels(ctx, valid(u.ID), valid(task.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTaskLabels: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("got %+v, want the deleted label unassigned", labels)
+	}
+}
+
+func testLocations(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+	other := newUser(t, s, 10)
+	// Ben Thanh market, the Opera House 730m away and Cu Chi 32km away
+	market, opera, far, done := newTask(u, "market"), newTask(u, "opera"), newTask(u, "far"), newTask(u, "done")
+	theirs, unplaced := newTask(other, "theirs"), newTask(u, "unplaced")
+	if err := s.AddTasks(ctx, []*storages.Task{market, opera, far, done, theirs, unplaced}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	if _, err := s.CompleteTask(ctx, valid(u.ID), valid(done.ID), time.Now().UTC().Format(storages.TimeLayout)); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+
+	at := storages.Location{Lat: 10.7725, Lng: 106.6980}
+	for task, loc := range map[*storages.Task]storages.Location{
+		market: {Lat: 0, Lng: 0},
+		opera:  {Lat: 10.7766, Lng: 106.7032},
+		far:    {Lat: 10.9730, Lng: 106.4930},
+		done:   {Lat: 10.7730, Lng: 106.6985},
+		theirs: at,
+	} {
+		if err := s.SetTaskLocation(ctx, valid(task.UserID), valid(task.ID), loc); err != nil {
+			t.Fatalf("SetTaskLocation: %v", err)
+		}
+	}
+	if err := s.SetTaskLocation(ctx, valid(u.ID), valid(market.ID), at); err != nil {
+		t.Fatalf("SetTaskLocation again: %v", err)
+	}
+	if err := s.SetTaskLocation(ctx, valid(u.ID), valid(theirs.ID), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("SetTaskLocation of another user's task: got %v, want ErrNotFound", err)
+	}
+	loc, err := s.RetrieveTaskLocation(ctx, valid(u.ID), valid(market.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTaskLocation: %v", err)
+	}
+	if *loc != at {
+		t.Errorf("got %+v, want the location set last %+v", *loc, at)
+	}
+	if _, err := s.RetrieveTaskLocation(ctx, valid(u.ID), valid(unplaced.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveTaskLocation of a task without one: got %v, want ErrNotFound", err)
+	}
+
+	nearby, err := s.RetrieveNearbyTasks(ctx, &storages.NearbyQuery{UserID: u.ID, At: at, RadiusKM: 5, Open: true, N: 10})
+	if err != nil {
+		t.Fatalf("RetrieveNearbyTasks: %v", err)
+	}
+	if len(nearby) != 2 || nearby[0].Task.ID != market.ID || nearby[1].Task.ID != opera.ID {
+		t.Fatalf("got %+v, want the open tasks within 5km, closest first", nearby)
+	}
+	if nearby[0].DistanceKM != 0 || nearby[1].DistanceKM < 0.7 || nearby[1].DistanceKM > 0.8 {
+		t.Errorf("got distances %v and %v, want 0 and about 0.75", nearby[0].DistanceKM, nearby[1].DistanceKM)
+	}
+	nearby, err = s.RetrieveNearbyTasks(ctx, &storages.NearbyQuery{UserID: u.ID, At: at, RadiusKM: 50, N: 3})
+	if err != nil {
+		t.Fatalf("RetrieveNearbyTasks: %v", err)
+	}
+	if len(nearby) != 3 || nearby[1].Task.ID != done.ID {
+		t.Errorf("got %+v, want market, done and opera", nearby)
+	}
+
+	if err := s.RemoveTaskLocation(ctx, valid(u.ID), valid(market.ID)); err != nil {
+		t.Fatalf("RemoveTaskLocation: %v", err)
+	}
+	if err := s.RemoveTaskLocation(ctx, valid(u.ID), valid(market.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RemoveTaskLocation twice: got %v, want ErrNotFound", err)
+	}
+}
+
+func testMoveTask(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 1)
+	other := newUser(t, s, 1)
+	const tomorrow = "2020-06-30"
+
+	task := newTask(u, "snoozed")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	move := &storages.TaskMove{TaskID: task.ID, UserID: u.ID, ToDate: tomorrow, Action: "snooze",
+		At: time.Now().UTC().Format(storages.TimeLayout)}
+
+	moved, err := s.MoveTask(ctx, move)
+	if err != nil {
+		t.Fatalf("MoveTask: %v", err)
+	}
+	if moved.ID != task.ID || moved.CreatedDate != tomorrow || moved.Content != task.Content {
+		t.Errorf("got %+v, want task %s on %s", moved, task.ID, tomorrow)
+	}
+	if got := retrieve(t, s, u, tomorrow, storages.ListOptions{}); len(got) != 1 {
+		t.Errorf("got %d tasks on the new date, want 1", len(got))
+	}
+
+	// moving to the same date doesn't count the task against itself
+	if _, err := s.MoveTask(ctx, move); err != nil {
+		t.Errorf("MoveTask to the current date: %v", err)
+	}
+
+	full := newTask(u, "today")
+	if err := s.AddTask(ctx, full); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	_, err = s.MoveTask(ctx, &storages.TaskMove{TaskID: full.ID, UserID: u.ID, ToDate: tomorrow, Action: "snooze", At: move.At})
+	var limitErr *storages.TaskLimitReached
+	if !errors.As(err, &limitErr) {
+		t.Errorf("MoveTask onto a full date: got %v, want TaskLimitReached", err)
+	}
+
+	// in soft mode the task is moved anyway, flagged
+	if err := s.UpdateUserSettings(ctx, valid(u.ID), &storages.UserSettings{QuotaMode: storages.QuotaSoft}); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+	moved, err = s.MoveTask(ctx, &storages.TaskMove{TaskID: full.ID, UserID: u.ID, ToDate: tomorrow, Action: "snooze", At: move.At})
+	if err != nil {
+		t.Fatalf("MoveTask onto a full date in soft mode: %v", err)
+	}
+	if moved.CreatedDate != tomorrow || !moved.OverQuota {
+		t.Errorf("MoveTask in soft mode: got %+v, want it on %s over quota", moved, tomorrow)
+	}
+	if got := retrieve(t, s, u, tomorrow, storages.ListOptions{}); len(got) != 2 {
+		t.Errorf("got %d tasks on the new date after a soft move, want 2", len(got))
+	}
+
+	_, err = s.MoveTask(ctx, &storages.TaskMove{TaskID: task.ID, UserID: other.ID, ToDate: date, Action: "snooze", At: move.At})
+	if !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("MoveTask of another user's task: got %v, want ErrNotFound", err)
+	}
+}
+
+func testCompleteTask(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+
+	task := newTask(u, "done")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	done, err := s.CompleteTask(ctx, valid(u.ID), valid(task.ID), at)
+	if err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	if done.ID != task.ID || done.CompletedAt != at {
+		t.Errorf("got %+v, want task %s completed at %s", done, task.ID, at)
+	}
+
+	// completing again keeps the first time
+	again, err := s.CompleteTask(ctx, valid(u.ID), valid(task.ID), "2099-01-01T00:00:00.000000Z")
+	if err != nil {
+		t.Fatalf("CompleteTask again: %v", err)
+	}
+	if again.CompletedAt != at {
+		t.Errorf("completed again at %s, want %s kept", again.CompletedAt, at)
+	}
+
+	if _, err := s.CompleteTask(ctx, valid(other.ID), valid(task.ID), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("CompleteTask of another user's task: got %v, want ErrNotFound", err)
+	}
+
+	got, err := s.RetrieveTask(ctx, valid(u.ID), valid(task.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTask: %v", err)
+	}
+	if got.Content != "done" || got.CompletedAt != at {
+		t.Errorf("RetrieveTask: got %+v, want task %s completed at %s", got, task.ID, at)
+	}
+	if _, err := s.RetrieveTask(ctx, valid(other.ID), valid(task.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveTask of another user's task: got %v, want ErrNotFound", err)
+	}
+}
+
+func testCompletedTasks(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+	// a day no other test completes tasks on
+	const before, late, early, after = "1999-12-31T23:59:59.999999Z", "2000-01-01T18:00:00.000000Z",
+		"2000-01-01T00:00:00.000000Z", "2000-01-02T00:00:00.000000Z"
+
+	var want []string
+	for _, c := range []struct {
+		u  *storages.User
+		at string
+	}{{u, late}, {other, early}, {u, before}, {other, after}, {u, ""}} {
+		task := newTask(c.u, "task")
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+		if c.at == "" {
+			continue
+		}
+		if _, err := s.CompleteTask(ctx, valid(c.u.ID), valid(task.ID), c.at); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+		if c.at == early || c.at == late {
+			want = append(want, task.ID)
+		}
+	}
+
+	got, err := s.RetrieveCompletedTasks(ctx, early, after)
+	if err != nil {
+		t.Fatalf("RetrieveCompletedTasks: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != want[1] || got[1].ID != want[0] {
+		t.Fatalf("got %+v, want tasks %v in completion order", got, []string{want[1], want[0]})
+	}
+	if got[0].Content != "task" || got[0].UserID != other.ID || got[0].CompletedAt != early {
+		t.Errorf("got %+v, want every field read", got[0])
+	}
+}
+
+func testIterateTasks(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	var want []string
+	for _, d := range []string{"2019-03-02", "2019-03-01", "2019-03-03", "2019-02-28", "2019-03-02"} {
+		task := newTask(u, "task")
+		task.CreatedDate = d
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+		if d >= "2019-03-01" && d <= "2019-03-02" {
+			want = append(want, task.ID)
+		}
+	}
+
+	c, err := s.IterateTasks(ctx, "2019-03-01", "2019-03-02")
+	if err != nil {
+		t.Fatalf("IterateTasks: %v", err)
+	}
+	defer c.Close()
+	var got []string
+	for c.Next() {
+		if c.Task().UserID == u.ID {
+			got = append(got, c.Task().ID)
+		}
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if len(got) != 3 || got[0] != want[1] || got[1] != want[0] || got[2] != want[2] {
+		t.Fatalf("got %v, want tasks %v in creation order", got, []string{want[1], want[0], want[2]})
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func testTasksAsOf(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	// revisions are timestamped by the store's own clock
+	instant := func() string {
+		time.Sleep(5 * time.Millisecond)
+		at := time.Now().UTC().Format(storages.TimeLayout)
+		time.Sleep(5 * time.Millisecond)
+		return at
+	}
+
+	before := instant()
+	done, gone := newTask(u, "done"), newTask(u, "gone")
+	if err := s.AddTasks(ctx, []*storages.Task{done, gone}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	added := instant()
+	if _, err := s.CompleteTask(ctx, valid(u.ID), valid(done.ID), added); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	if _, err := s.DeleteTasks(ctx, valid(u.ID), []string{gone.ID}, nil); err != nil {
+		t.Fatalf("DeleteTasks: %v", err)
+	}
+	changed := instant()
+
+	for _, c := range []struct {
+		at        string
+		want      []string
+		completed bool
+	}{{before, nil, false}, {added, []string{done.ID, gone.ID}, false}, {changed, []string{done.ID}, true}} {
+		got, err := s.RetrieveTasksAsOf(ctx, valid(u.ID), valid(date), c.at)
+		if err != nil {
+			t.Fatalf("RetrieveTasksAsOf: %v", err)
+		}
+		ids := map[string]bool{}
+		for _, task := range got {
+			ids[task.ID] = true
+		}
+		if len(got) != len(c.want) {
+			t.Fatalf("as of %s got %+v, want tasks %v", c.at, got, c.want)
+		}
+		for _, id := range c.want {
+			if !ids[id] {
+				t.Fatalf("as of %s got %+v, want tasks %v", c.at, got, c.want)
+			}
+		}
+		for _, task := range got {
+			if task.ID == done.ID && (task.CompletedAt != "") != c.completed {
+				t.Errorf("as of %s got %+v, want completed %v", c.at, task, c.completed)
+			}
+		}
+	}
+}
+
+func testInvites(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	admin := newUser(t, s, 5)
+	now := time.Now().UTC()
+	at := now.Format(storages.TimeLayout)
+	newInvite := func(expiresAt time.Time) string {
+		inv := &storages.Invite{
+			Code:      uuid.New().String(),
+			CreatedBy: admin.ID,
+			CreatedAt: at,
+			ExpiresAt: expiresAt.Format(storages.TimeLayout),
+		}
+		if err := s.AddInvite(ctx, inv); err != nil {
+			t.Fatalf("AddInvite: %v", err)
+		}
+		return inv.Code
+	}
+	invited := func() *storages.User {
+		return &storages.User{ID: "storagetest-" + uuid.New().String(), Password: "hash", MaxTodo: 5}
+	}
+
+	code := newInvite(now.Add(time.Hour))
+	if err := s.SignUp(ctx, code, &storages.User{ID: admin.ID, Password: "hash"}, at); !errors.Is(err, storages.ErrUserExists) {
+		t.Errorf("SignUp as an existing user: got %v, want ErrUserExists", err)
+	}
+	u := invited()
+	if err := s.SignUp(ctx, code, u, at); err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if got, err := s.RetrieveUser(ctx, valid(u.ID)); err != nil || got.MaxTodo != 5 {
+		t.Errorf("RetrieveUser after SignUp: got %+v, %v", got, err)
+	}
+	if err := s.SignUp(ctx, code, invited(), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("SignUp with a used code: got %v, want ErrNotFound", err)
+	}
+	if err := s.SignUp(ctx, newInvite(now.Add(-time.Second)), invited(), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("SignUp with an expired code: got %v, want ErrNotFound", err)
+	}
+	if err := s.SignUp(ctx, uuid.New().String(), invited(), at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("SignUp with an unknown code: got %v, want ErrNotFound", err)
+	}
+
+	// concurrent sign ups with one code make one user
+	code = newInvite(now.Add(time.Hour))
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.SignUp(ctx, code, invited(), at)
+		}(i)
+	}
+	wg.Wait()
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, storages.ErrNotFound) {
+			t.Errorf("concurrent SignUp: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("got %d concurrent sign ups with one code, want 1", succeeded)
+	}
+}
+
+func testQuotaAdjustments(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 1)
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "within limit"), nil); err != nil {
+		t.Fatalf("AddTaskWithLimitPerDay: %v", err)
+	}
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	for _, a := range []*storages.QuotaAdjustment{
+		{ID: uuid.New().String(), UserID: u.ID, Date: date, Amount: 2, Reason: "launch", CreatedBy: u.ID, CreatedAt: at},
+		{ID: uuid.New().String(), UserID: u.ID, Date: date, Amount: 1, CreatedBy: u.ID, CreatedAt: at},
+		{ID: uuid.New().String(), UserID: u.ID, Date: "2020-06-30", Amount: 5, CreatedBy: u.ID, CreatedAt: at},
+	} {
+		if err := s.AddQuotaAdjustment(ctx, a); err != nil {
+			t.Fatalf("AddQuotaAdjustment: %v", err)
+		}
+	}
+	for i := 2; i <= 4; i++ {
+		count, maxTodo, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "adjusted"), nil)
+		if err != nil {
+			t.Fatalf("AddTaskWithLimitPerDay %d: %v", i, err)
+		}
+		if count != i || maxTodo != 4 {
+			t.Errorf("AddTaskWithLimitPerDay %d: got %d of %d, want %d of 4", i, count, maxTodo, i)
+		}
+	}
+	var limitErr *storages.TaskLimitReached
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "over limit"), nil); !errors.As(err, &limitErr) {
+		t.Fatalf("got %v, want TaskLimitReached", err)
+	}
+
+	got, err := s.RetrieveQuotaAdjustments(ctx, valid(u.ID), date, date)
+	if err != nil {
+		t.Fatalf("RetrieveQuotaAdjustments: %v", err)
+	}
+	if len(got) != 2 || got[0].Amount+got[1].Amount != 3 {
+		t.Errorf("got %+v, want the 2 adjustments of %s", got, date)
+	}
+	err = s.AddQuotaAdjustment(ctx, &storages.QuotaAdjustment{ID: uuid.New().String(), UserID: uuid.New().String(), Date: date, Amount: 1, CreatedAt: at})
+	if !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("AddQuotaAdjustment for an unknown user: got %v, want ErrNotFound", err)
+	}
+}
+
+func testBatch(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+	mine, theirs := newTask(u, "mine"), newTask(other, "theirs")
+	if err := s.AddTasks(ctx, []*storages.Task{mine, newTask(u, "kept")}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	if err := s.AddTask(ctx, theirs); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	notFound, err := s.CompleteTasks(ctx, valid(u.ID), []string{mine.ID, theirs.ID}, at, nil)
+	if err != nil {
+		t.Fatalf("CompleteTasks: %v", err)
+	}
+	if len(notFound) != 1 || notFound[0] != theirs.ID {
+		t.Errorf("CompleteTasks: got not found %v, want [%s]", notFound, theirs.ID)
+	}
+	for _, task := range retrieve(t, s, u, date, storages.ListOptions{}) {
+		if (task.ID == mine.ID) != (task.CompletedAt == at) {
+			t.Errorf("task %s completed at %q after CompleteTasks of %s", task.ID, task.CompletedAt, mine.ID)
+		}
+	}
+
+	later := time.Now().Add(time.Minute).UTC().Format(storages.TimeLayout)
+	undo := &storages.Undo{Token: uuid.New().String(), UserID: u.ID, ExpiresAt: later}
+	notFound, err = s.DeleteTasks(ctx, valid(u.ID), []string{mine.ID, theirs.ID}, undo)
+	if err != nil {
+		t.Fatalf("DeleteTasks: %v", err)
+	}
+	if len(notFound) != 1 || notFound[0] != theirs.ID {
+		t.Errorf("DeleteTasks: got not found %v, want [%s]", notFound, theirs.ID)
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{}); len(got) != 1 || got[0].ID == mine.ID {
+		t.Errorf("got %+v after DeleteTasks, want only the kept task", got)
+	}
+	if got := retrieve(t, s, other, date, storages.ListOptions{}); len(got) != 1 {
+		t.Errorf("DeleteTasks removed another user's task")
+	}
+
+	if _, err := s.UndoTasks(ctx, valid(other.ID), undo.Token, at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UndoTasks by another user: got %v, want ErrNotFound", err)
+	}
+	restored, err := s.UndoTasks(ctx, valid(u.ID), undo.Token, at)
+	if err != nil {
+		t.Fatalf("UndoTasks: %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("UndoTasks restored %d tasks, want 1", restored)
+	}
+	got := retrieve(t, s, u, date, storages.ListOptions{})
+	if len(got) != 2 {
+		t.Errorf("got %d tasks after UndoTasks, want 2", len(got))
+	}
+	for _, task := range got {
+		if task.ID == mine.ID && task.CompletedAt != at {
+			t.Errorf("restored task completed at %q, want %s", task.CompletedAt, at)
+		}
+	}
+	if _, err := s.UndoTasks(ctx, valid(u.ID), undo.Token, at); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UndoTasks twice: got %v, want ErrNotFound", err)
+	}
+
+	expired := &storages.Undo{Token: uuid.New().String(), UserID: u.ID, ExpiresAt: at}
+	if _, err := s.CompleteTasks(ctx, valid(u.ID), []string{mine.ID}, at, expired); err != nil {
+		t.Fatalf("CompleteTasks: %v", err)
+	}
+	if _, err := s.UndoTasks(ctx, valid(u.ID), expired.Token, later); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UndoTasks after it expired: got %v, want ErrNotFound", err)
+	}
+}
+
+func testUndoLimit(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	later := time.Now().Add(time.Minute).UTC().Format(storages.TimeLayout)
+	at := time.Now().UTC().Format(storages.TimeLayout)
+
+	// a deleted task whose place was taken can't come back
+	u := newUser(t, s, 1)
+	deleted := newTask(u, "deleted")
+	if err := s.AddTask(ctx, deleted); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	undo := &storages.Undo{Token: uuid.New().String(), UserID: u.ID, ExpiresAt: later}
+	if _, err := s.DeleteTasks(ctx, valid(u.ID), []string{deleted.ID}, undo); err != nil {
+		t.Fatalf("DeleteTasks: %v", err)
+	}
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, newTask(u, "replacement"), nil); err != nil {
+		t.Fatalf("AddTaskWithLimitPerDay: %v", err)
+	}
+	var limitErr *storages.TaskLimitReached
+	if _, err := s.UndoTasks(ctx, valid(u.ID), undo.Token, at); !errors.As(err, &limitErr) {
+		t.Errorf("UndoTasks over the limit: got %v, want TaskLimitReached", err)
+	}
+	if got := retrieve(t, s, u, date, storages.ListOptions{}); len(got) != 1 {
+		t.Errorf("got %d tasks after a refused UndoTasks, want 1", len(got))
+	}
+
+	// completing takes no room, undoing it works at the limit
+	completed := &storages.Undo{Token: uuid.New().String(), UserID: u.ID, ExpiresAt: later}
+	kept := retrieve(t, s, u, date, storages.ListOptions{})[0]
+	if _, err := s.CompleteTasks(ctx, valid(u.ID), []string{kept.ID}, at, completed); err != nil {
+		t.Fatalf("CompleteTasks: %v", err)
+	}
+	if _, err := s.UndoTasks(ctx, valid(u.ID), completed.Token, at); err != nil {
+		t.Errorf("UndoTasks of a completion at the limit: %v", err)
+	}
+
+	// in soft mode the task comes back flagged
+	if err := s.UpdateUserSettings(ctx, valid(u.ID), &storages.UserSettings{QuotaMode: storages.QuotaSoft}); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+	restored, err := s.UndoTasks(ctx, valid(u.ID), undo.Token, at)
+	if err != nil {
+		t.Fatalf("UndoTasks in soft mode: %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("UndoTasks restored %d tasks, want 1", restored)
+	}
+	for _, task := range retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id", "over_quota"}}) {
+		if (task.ID == deleted.ID) != task.OverQuota {
+			t.Errorf("task %s over_quota %v after UndoTasks in soft mode", task.ID, task.OverQuota)
+		}
+	}
+}
+
+func testPriorityCounts(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+
+	var tasks []*storages.Task
+	for i, p := range []int{0, 2, 2, 2, 0} {
+		task := newTask(u, "task "+strconv.Itoa(i))
+		task.Priority = p
+		tasks = append(tasks, task)
+	}
+	tomorrow := newTask(u, "tomorrow")
+	tomorrow.CreatedDate = "2020-06-30"
+	if err := s.AddTasks(ctx, append(tasks, tomorrow)); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	for _, task := range tasks[1:3] {
+		if _, err := s.CompleteTask(ctx, valid(u.ID), valid(task.ID), at); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+	}
+
+	counts, err := s.RetrievePriorityCounts(ctx, valid(u.ID), valid(date))
+	if err != nil {
+		t.Fatalf("RetrievePriorityCounts: %v", err)
+	}
+	want := []storages.PriorityCount{{Priority: 2, Count: 3, Completed: 2}, {Priority: 0, Count: 2, Completed: 0}}
+	if len(counts) != len(want) {
+		t.Fatalf("got %d priorities, want %d", len(counts), len(want))
+	}
+	for i := range want {
+		if *counts[i] != want[i] {
+			t.Errorf("priority %d: got %+v, want %+v", i, *counts[i], want[i])
+		}
+	}
+}
+
+func testContentSuggestions(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+	other := newUser(t, s, 10)
+
+	var tasks []*storages.Task
+	for i, content := range []string{"Stand-up", "Standing desk", "stand-up", "st_x", "Call mom"} {
+		task := newTask(u, content)
+		task.CreatedAt = "2020-06-29T0" + strconv.Itoa(i) + ":00:00.000000Z"
+		tasks = append(tasks, task)
+	}
+	tasks[2].CreatedDate = "2020-06-30"
+	if err := s.AddTasks(ctx, append(tasks, newTask(other, "Standing desk"), newTask(other, "Standing desk"))); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+
+	got, err := s.RetrieveContentSuggestions(ctx, valid(u.ID), "sta", 10)
+	if err != nil {
+		t.Fatalf("RetrieveContentSuggestions: %v", err)
+	}
+	want := []storages.ContentSuggestion{
+		{Content: "stand-up", Count: 2, LastDate: "2020-06-30"},
+		{Content: "Standing desk", Count: 1, LastDate: date},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d suggestions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("suggestion %d: got %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+
+	got, err = s.RetrieveContentSuggestions(ctx, valid(u.ID), "st_", 10)
+	if err != nil {
+		t.Fatalf("RetrieveContentSuggestions: %v", err)
+	}
+	if len(got) != 1 || got[0].Content != "st_x" {
+		t.Errorf("got %+v, want only st_x, the prefix matched literally", got)
+	}
+	got, err = s.RetrieveContentSuggestions(ctx, valid(u.ID), "sta", 1)
+	if err != nil {
+		t.Fatalf("RetrieveContentSuggestions: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %d suggestions, want 1", len(got))
+	}
+}
+
+func testTimeTracking(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	first, second := newTask(u, "first"), newTask(u, "second")
+	first.EstimateMinutes = 30
+	if err := s.AddTasks(ctx, []*storages.Task{first, second}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+
+	if err := s.SetTaskEstimate(ctx, valid(u.ID), valid(second.ID), 45); err != nil {
+		t.Fatalf("SetTaskEstimate: %v", err)
+	}
+	if err := s.SetTaskEstimate(ctx, valid(newUser(t, s, 5).ID), valid(second.ID), 5); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("SetTaskEstimate of another user's task: got %v, want ErrNotFound", err)
+	}
+	for _, task := range retrieve(t, s, u, date, storages.ListOptions{Fields: []string{"id", "estimate_minutes"}}) {
+		want := map[string]int{first.ID: 30, second.ID: 45}[task.ID]
+		if task.EstimateMinutes != want {
+			t.Errorf("task %s: got estimate %d, want %d", task.ID, task.EstimateMinutes, want)
+		}
+	}
+
+	start := func(task *storages.Task, at string) (*storages.TimeEntry, *storages.TimeEntry, error) {
+		e := &storages.TimeEntry{ID: uuid.New().String(), TaskID: task.ID, UserID: u.ID, StartedAt: at}
+		stopped, err := s.StartTimer(ctx, e)
+		return e, stopped, err
+	}
+	e1, stopped, err := start(first, "2020-06-29T08:00:00.000000Z")
+	if err != nil || stopped != nil {
+		t.Fatalf("StartTimer: got %+v, %v, want nothing stopped", stopped, err)
+	}
+	if _, _, err := start(first, "2020-06-29T08:10:00.000000Z"); !errors.Is(err, storages.ErrTimerRunning) {
+		t.Errorf("StartTimer of a running timer: got %v, want ErrTimerRunning", err)
+	}
+	// starting the second task's timer stops the first's
+	e2, stopped, err := start(second, "2020-06-29T08:20:00.000000Z")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if stopped == nil || stopped.ID != e1.ID || stopped.Seconds != 1200 {
+		t.Errorf("StartTimer stopped %+v, want %s after 1200 seconds", stopped, e1.ID)
+	}
+	if _, err := s.StopTimer(ctx, valid(u.ID), valid(first.ID), "2020-06-29T08:30:00.000000Z"); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("StopTimer of a stopped timer: got %v, want ErrNotFound", err)
+	}
+	got, err := s.StopTimer(ctx, valid(u.ID), valid(second.ID), "2020-06-29T08:21:30.400000Z")
+	if err != nil {
+		t.Fatalf("StopTimer: %v", err)
+	}
+	if got.ID != e2.ID || got.Seconds != 90 || got.StoppedAt != "2020-06-29T08:21:30.400000Z" {
+		t.Errorf("StopTimer: got %+v, want %s stopped after 90 seconds", got, e2.ID)
+	}
+	// running entries are listed but not counted
+	if _, _, err := start(first, "2020-06-30T09:00:00.000000Z"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+
+	entries, err := s.RetrieveTimeEntries(ctx, valid(u.ID), valid(first.ID))
+	if err != nil {
+		t.Fatalf("RetrieveTimeEntries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != e1.ID || entries[1].StoppedAt != "" {
+		t.Errorf("got %+v, want the stopped entry then the running one", entries)
+	}
+	counts, err := s.RetrieveDayCounts(ctx, valid(u.ID), date, "2020-07-01")
+	if err != nil {
+		t.Fatalf("RetrieveDayCounts: %v", err)
+	}
+	want := []storages.DayCount{{Date: date, Created: 2, TrackedSeconds: 1290}}
+	if len(counts) != 1 || *counts[0] != want[0] {
+		t.Errorf("got %+v, want %+v", counts, want)
+	}
+}
+
+func testPomodoros(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	task := newTask(u, "focus")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	start := func(at string) (*storages.Pomodoro, error) {
+		p := &storages.Pomodoro{ID: uuid.New().String(), TaskID: task.ID, UserID: u.ID, StartedAt: at, PlannedMinutes: 25}
+		return p, s.StartPomodoro(ctx, p)
+	}
+	first, err := start("2020-06-29T08:00:00.000000Z")
+	if err != nil {
+		t.Fatalf("StartPomodoro: %v", err)
+	}
+	if _, err := start("2020-06-29T08:05:00.000000Z"); !errors.Is(err, storages.ErrPomodoroRunning) {
+		t.Errorf("StartPomodoro while one runs: got %v, want ErrPomodoroRunning", err)
+	}
+	if _, err := s.CompletePomodoro(ctx, valid(newUser(t, s, 5).ID), valid(task.ID), valid(first.ID), "2020-06-29T08:25:00.000000Z"); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("CompletePomodoro of another user's: got %v, want ErrNotFound", err)
+	}
+	got, err := s.CompletePomodoro(ctx, valid(u.ID), valid(task.ID), valid(first.ID), "2020-06-29T08:25:00.000000Z")
+	if err != nil {
+		t.Fatalf("CompletePomodoro: %v", err)
+	}
+	if got.Seconds != 1500 || got.CompletedAt != "2020-06-29T08:25:00.000000Z" || got.PlannedMinutes != 25 {
+		t.Errorf("CompletePomodoro: got %+v, want 1500 seconds", got)
+	}
+	if _, err := s.CompletePomodoro(ctx, valid(u.ID), valid(task.ID), valid(first.ID), "2020-06-29T08:30:00.000000Z"); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("CompletePomodoro twice: got %v, want ErrNotFound", err)
+	}
+	// running pomodoros are listed but not counted
+	if _, err := start("2020-06-29T09:00:00.000000Z"); err != nil {
+		t.Fatalf("StartPomodoro after completing: %v", err)
+	}
+
+	pomodoros, err := s.RetrievePomodoros(ctx, valid(u.ID), valid(task.ID))
+	if err != nil {
+		t.Fatalf("RetrievePomodoros: %v", err)
+	}
+	if len(pomodoros) != 2 || *pomodoros[0] != *got || pomodoros[1].CompletedAt != "" {
+		t.Errorf("got %+v, want the completed pomodoro then the running one", pomodoros)
+	}
+	counts, err := s.RetrieveDayCounts(ctx, valid(u.ID), date, "2020-06-30")
+	if err != nil {
+		t.Fatalf("RetrieveDayCounts: %v", err)
+	}
+	want := storages.DayCount{Date: date, Created: 1, Pomodoros: 1, FocusSeconds: 1500}
+	if len(counts) != 1 || *counts[0] != want {
+		t.Errorf("got %+v, want %+v", counts, want)
+	}
+}
+
+func testDayCounts(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+
+	open, done, late := newTask(u, "open"), newTask(u, "done"), newTask(u, "late")
+	late.CreatedDate = "2020-06-30"
+	if err := s.AddTasks(ctx, []*storages.Task{open, done, late, newTask(other, "theirs")}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	for id, at := range map[string]string{done.ID: "2020-07-01T08:00:00.000000Z", late.ID: "2020-07-01T09:00:00.000000Z"} {
+		if _, err := s.CompleteTask(ctx, valid(u.ID), valid(id), at); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+	}
+
+	got, err := s.RetrieveDayCounts(ctx, valid(u.ID), date, "2020-07-01")
+	if err != nil {
+		t.Fatalf("RetrieveDayCounts: %v", err)
+	}
+	want := []storages.DayCount{{Date: date, Created: 2}, {Date: "2020-06-30", Created: 1}}
+	if len(got) != len(want) || *got[0] != want[0] || *got[1] != want[1] {
+		t.Errorf("got %+v, want %+v without the day after to", got, want)
+	}
+
+	got, err = s.RetrieveDayCounts(ctx, valid(u.ID), "2020-07-01", "2020-07-02")
+	if err != nil {
+		t.Fatalf("RetrieveDayCounts: %v", err)
+	}
+	if len(got) != 1 || *got[0] != (storages.DayCount{Date: "2020-07-01", Completed: 2}) {
+		t.Errorf("got %+v, want 2 completed on 2020-07-01", got)
+	}
+}
+
+func testStreaks(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+
+	// days no other test completes tasks on
+	days := []string{"1990-03-01", "1990-03-02", "1990-03-04", "1990-03-05", "1990-03-06"}
+	for _, day := range days {
+		task := newTask(u, "task")
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+		if _, err := s.CompleteTask(ctx, valid(u.ID), valid(task.ID), day+"T12:00:00.000000Z"); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+	}
+
+	if st, err := s.RetrieveStreak(ctx, valid(u.ID)); err != nil || *st != (storages.Streak{}) {
+		t.Errorf("RetrieveStreak before any update: got %+v, %v, want a zero streak", st, err)
+	}
+
+	for _, c := range []struct {
+		day  string
+		want storages.Streak
+	}{
+		{"1990-03-01", storages.Streak{Current: 1, Longest: 1, LastDay: "1990-03-01"}},
+		{"1990-03-02", storages.Streak{Current: 2, Longest: 2, LastDay: "1990-03-02"}},
+		// running a day twice or late changes nothing
+		{"1990-03-02", storages.Streak{Current: 2, Longest: 2, LastDay: "1990-03-02"}},
+		{"1990-03-01", storages.Streak{Current: 2, Longest: 2, LastDay: "1990-03-02"}},
+		// nothing completed on the 3rd
+		{"1990-03-03", storages.Streak{Current: 2, Longest: 2, LastDay: "1990-03-02"}},
+		{"1990-03-04", storages.Streak{Current: 1, Longest: 2, LastDay: "1990-03-04"}},
+		{"1990-03-05", storages.Streak{Current: 2, Longest: 2, LastDay: "1990-03-05"}},
+		{"1990-03-06", storages.Streak{Current: 3, Longest: 3, LastDay: "1990-03-06"}},
+	} {
+		if _, err := s.UpdateStreaks(ctx, c.day); err != nil {
+			t.Fatalf("UpdateStreaks(%s): %v", c.day, err)
+		}
+		st, err := s.RetrieveStreak(ctx, valid(u.ID))
+		if err != nil {
+			t.Fatalf("RetrieveStreak: %v", err)
+		}
+		if *st != c.want {
+			t.Errorf("after UpdateStreaks(%s): got %+v, want %+v", c.day, st, c.want)
+		}
+	}
+}
+
+func testCarryOver(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	const tomorrow = "2020-06-30"
+	at := time.Now().UTC().Format(storages.TimeLayout)
+
+	for _, move := range []bool{false, true} {
+		u := newUser(t, s, 2)
+		open, done := newTask(u, "open"), newTask(u, "done")
+		if err := s.AddTasks(ctx, []*storages.Task{open, done}); err != nil {
+			t.Fatalf("AddTasks: %v", err)
+		}
+		if _, err := s.CompleteTask(ctx, valid(u.ID), valid(done.ID), at); err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+
+		co := &storages.CarryOver{UserID: u.ID, FromDate: date, ToDate: tomorrow, Move: move,
+			NewID: func() string { return uuid.New().String() }, At: at}
+		n, err := s.CarryOverTasks(ctx, co)
+		if err != nil || n != 1 {
+			t.Fatalf("CarryOverTasks(move=%v): got %d, %v, want 1 task carried", move, n, err)
+		}
+		got := retrieve(t, s, u, tomorrow, storages.ListOptions{})
+		if len(got) != 1 || got[0].Content != open.Content || (got[0].ID == open.ID) != move {
+			t.Errorf("CarryOverTasks(move=%v): got %+v on the new date", move, got)
+		}
+		wantLeft := 2
+		if move {
+			wantLeft = 1
+		}
+		if left := retrieve(t, s, u, date, storages.ListOptions{}); len(left) != wantLeft {
+			t.Errorf("CarryOverTasks(move=%v): %d tasks left on the old date, want %d", move, len(left), wantLeft)
+		}
+
+		// the date is only carried over once
+		if n, err := s.CarryOverTasks(ctx, co); err != nil || n != 0 {
+			t.Errorf("CarryOverTasks again: got %d, %v, want nothing carried", n, err)
+		}
+	}
+
+	u := newUser(t, s, 1)
+	if err := s.AddTasks(ctx, []*storages.Task{newTask(u, "open")}); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+	full := newTask(u, "already there")
+	full.CreatedDate = tomorrow
+	if err := s.AddTask(ctx, full); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	n, err := s.CarryOverTasks(ctx, &storages.CarryOver{UserID: u.ID, FromDate: date, ToDate: tomorrow,
+		NewID: func() string { return uuid.New().String() }, At: at})
+	var limitErr *storages.TaskLimitReached
+	if !errors.As(err, &limitErr) || n != 1 {
+		t.Errorf("CarryOverTasks onto a full date: got %d, %v, want 1 and TaskLimitReached", n, err)
+	}
+	if got := retrieve(t, s, u, tomorrow, storages.ListOptions{}); len(got) != 1 {
+		t.Errorf("got %d tasks on the full date, want 1", len(got))
+	}
+
+	// in soft mode all are carried, those beyond the limit flagged
+	for _, move := range []bool{false, true} {
+		u := newUser(t, s, 2)
+		if err := s.UpdateUserSettings(ctx, valid(u.ID), &storages.UserSettings{QuotaMode: storages.QuotaSoft}); err != nil {
+			t.Fatalf("UpdateUserSettings: %v", err)
+		}
+		if err := s.AddTasks(ctx, []*storages.Task{newTask(u, "first"), newTask(u, "second")}); err != nil {
+			t.Fatalf("AddTasks: %v", err)
+		}
+		there := newTask(u, "already there")
+		there.CreatedDate = tomorrow
+		if err := s.AddTask(ctx, there); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+		n, err := s.CarryOverTasks(ctx, &storages.CarryOver{UserID: u.ID, FromDate: date, ToDate: tomorrow, Move: move,
+			NewID: func() string { return uuid.New().String() }, At: at})
+		if err != nil || n != 2 {
+			t.Fatalf("CarryOverTasks(move=%v) in soft mode: got %d, %v, want 2 tasks carried", move, n, err)
+		}
+		got := retrieve(t, s, u, tomorrow, storages.ListOptions{Fields: []string{"id", "over_quota"}})
+		over := 0
+		for _, task := range got {
+			if task.OverQuota {
+				over++
+			}
+		}
+		if len(got) != 3 || over != 1 {
+			t.Errorf("CarryOverTasks(move=%v) in soft mode: got %d tasks, %d over quota, want 3 and 1", move, len(got), over)
+		}
+	}
+}
+
+func testCount(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 3)
+
+	for want := 0; want <= 2; want++ {
+		count, maxTodo, err := s.CountTasks(ctx, valid(u.ID), valid(date))
+		if err != nil {
+			t.Fatalf("CountTasks: %v", err)
+		}
+		if count != want || maxTodo != u.MaxTodo {
+			t.Errorf("got count %d and max %d, want %d and %d", count, maxTodo, want, u.MaxTodo)
+		}
+		if err := s.AddTask(ctx, newTask(u, "counted")); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+}
+
+func testPasswordHash(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	got, err := s.RetrievePasswordHash(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrievePasswordHash: %v", err)
+	}
+	if got != u.Password {
+		t.Errorf("got hash %q, want %q", got, u.Password)
+	}
+
+	const updated = "$bcrypt$2a$10$updated"
+	if err := s.UpdatePasswordHash(ctx, valid(u.ID), updated); err != nil {
+		t.Fatalf("UpdatePasswordHash: %v", err)
+	}
+	if got, _ := s.RetrievePasswordHash(ctx, valid(u.ID)); got != updated {
+		t.Errorf("got hash %q after update, want %q", got, updated)
+	}
+
+	if _, err := s.RetrievePasswordHash(ctx, valid(uuid.New().String())); err == nil {
+		t.Error("RetrievePasswordHash for an unknown user: want error")
+	}
+}
+
+func testSessions(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	other := newUser(t, s, 5)
+
+	const now = "2020-06-29T10:00:00.000000Z"
+	phone := &storages.Session{ID: uuid.New().String(), UserID: u.ID, Device: "phone",
+		CreatedAt: "2020-06-29T09:00:00.000000Z", ExpiresAt: "2020-06-29T11:00:00.000000Z"}
+	expired := &storages.Session{ID: uuid.New().String(), UserID: u.ID, Device: "laptop",
+		CreatedAt: "2020-06-29T08:00:00.000000Z", ExpiresAt: "2020-06-29T09:00:00.000000Z"}
+	for _, sess := range []*storages.Session{phone, expired} {
+		if err := s.AddSession(ctx, sess); err != nil {
+			t.Fatalf("AddSession: %v", err)
+		}
+	}
+
+	got, err := s.RetrieveSessions(ctx, valid(u.ID), now)
+	if err != nil {
+		t.Fatalf("RetrieveSessions: %v", err)
+	}
+	if len(got) != 1 || *got[0] != *phone {
+		t.Errorf("got %+v, want only the unexpired session", got)
+	}
+	if !s.ValidateSession(ctx, valid(u.ID), valid(phone.ID), now) {
+		t.Error("ValidateSession rejected an active session")
+	}
+	if s.ValidateSession(ctx, valid(u.ID), valid(expired.ID), now) {
+		t.Error("ValidateSession accepted an expired session")
+	}
+	if s.ValidateSession(ctx, valid(other.ID), valid(phone.ID), now) {
+		t.Error("ValidateSession accepted another user's session")
+	}
+
+	if err := s.RevokeSession(ctx, valid(other.ID), valid(phone.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RevokeSession of another user's session: got %v, want ErrNotFound", err)
+	}
+	if err := s.RevokeSession(ctx, valid(u.ID), valid(phone.ID), now); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if s.ValidateSession(ctx, valid(u.ID), valid(phone.ID), now) {
+		t.Error("ValidateSession accepted a revoked session")
+	}
+	if err := s.RevokeSession(ctx, valid(u.ID), valid(phone.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RevokeSession twice: got %v, want ErrNotFound", err)
+	}
+}
+
+func testAPIUsage(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	for i, day := range []string{"2020-06-28", "2020-06-29", "2020-06-29", "2020-06-30"} {
+		if _, err := s.IncrementAPIUsage(ctx, valid(u.ID), day); err != nil {
+			t.Fatalf("IncrementAPIUsage %d: %v", i, err)
+		}
+	}
+	calls, err := s.IncrementAPIUsage(ctx, valid(u.ID), "2020-06-29")
+	if err != nil {
+		t.Fatalf("IncrementAPIUsage: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+
+	usage, err := s.RetrieveAPIUsage(ctx, valid(u.ID), "2020-06-29", "2020-06-30")
+	if err != nil {
+		t.Fatalf("RetrieveAPIUsage: %v", err)
+	}
+	want := []storages.APIUsage{{Date: "2020-06-29", Calls: 3}, {Date: "2020-06-30", Calls: 1}}
+	if len(usage) != len(want) {
+		t.Fatalf("got %d days, want %d", len(usage), len(want))
+	}
+	for i := range want {
+		if *usage[i] != want[i] {
+			t.Errorf("day %d: got %+v, want %+v", i, *usage[i], want[i])
+		}
+	}
+}
+
+func testAnomalies(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	burst, steady, quiet := newUser(t, s, 100), newUser(t, s, 100), newUser(t, s, 100)
+
+	// a window no other test creates tasks in
+	var tasks []*storages.Task
+	add := func(u *storages.User, n int, at string) {
+		for i := 0; i < n; i++ {
+			task := newTask(u, "task")
+			task.CreatedAt = at
+			tasks = append(tasks, task)
+		}
+	}
+	add(burst, 5, "2001-01-01T10:30:00.000000Z")
+	add(burst, 1, "2000-12-31T10:00:00.000000Z")
+	add(steady, 4, "2001-01-01T10:59:59.999999Z")
+	add(steady, 20, "2000-12-30T08:00:00.000000Z")
+	add(quiet, 1, "2001-01-01T10:00:00.000000Z")
+	add(quiet, 10, "2001-01-01T11:00:00.000000Z")
+	if err := s.AddTasks(ctx, tasks); err != nil {
+		t.Fatalf("AddTasks: %v", err)
+	}
+
+	rates, err := s.RetrieveCreationRates(ctx, "2000-12-25T10:00:00.000000Z", "2001-01-01T10:00:00.000000Z", "2001-01-01T11:00:00.000000Z", 2)
+	if err != nil {
+		t.Fatalf("RetrieveCreationRates: %v", err)
+	}
+	want := []storages.CreationRate{{UserID: burst.ID, Created: 5, Baseline: 1}, {UserID: steady.ID, Created: 4, Baseline: 20}}
+	if len(rates) != len(want) || *rates[0] != want[0] || *rates[1] != want[1] {
+		t.Errorf("RetrieveCreationRates: got %+v, want %+v", rates, want)
+	}
+
+	a := &storages.Anomaly{ID: uuid.New().String(), UserID: burst.ID, DetectedAt: "2001-01-01T11:00:01.000000Z",
+		WindowStart: "2001-01-01T10:00:00.000000Z", Created: 5, BaselinePerHour: 1.0 / 168, ThrottledUntil: "2001-01-02T11:00:00.000000Z"}
+	throttle := &storages.CreationThrottle{UserID: burst.ID, PerHour: 2, Until: a.ThrottledUntil}
+	if first, err := s.AddAnomaly(ctx, a, throttle); err != nil || !first {
+		t.Fatalf("AddAnomaly: got %v, %v, want first", first, err)
+	}
+	again := *a
+	again.ID = uuid.New().String()
+	if first, err := s.AddAnomaly(ctx, &again, &storages.CreationThrottle{UserID: burst.ID, PerHour: 1, Until: "2001-01-03T11:00:00.000000Z"}); err != nil || first {
+		t.Errorf("AddAnomaly of the same window: got %v, %v, want not first", first, err)
+	}
+
+	got, err := s.RetrieveAnomalies(ctx, "2001-01-01", "2001-01-02")
+	if err != nil {
+		t.Fatalf("RetrieveAnomalies: %v", err)
+	}
+	if len(got) != 1 || *got[0] != *a {
+		t.Errorf("RetrieveAnomalies: got %+v, want only %+v", got, *a)
+	}
+
+	th, err := s.RetrieveCreationThrottle(ctx, valid(burst.ID), "2001-01-01T12:00:00.000000Z")
+	if err != nil {
+		t.Fatalf("RetrieveCreationThrottle: %v", err)
+	}
+	if *th != *throttle {
+		t.Errorf("RetrieveCreationThrottle: got %+v, want the first one %+v", *th, *throttle)
+	}
+	if _, err := s.RetrieveCreationThrottle(ctx, valid(burst.ID), a.ThrottledUntil); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveCreationThrottle once it ended: got %v, want ErrNotFound", err)
+	}
+
+	count, oldest, err := s.CountTasksCreatedSince(ctx, valid(burst.ID), "2001-01-01T00:00:00.000000Z")
+	if err != nil || count != 5 || oldest != "2001-01-01T10:30:00.000000Z" {
+		t.Errorf("CountTasksCreatedSince: got %d, %q, %v, want 5 from 10:30", count, oldest, err)
+	}
+
+	if err := s.LiftCreationThrottle(ctx, valid(burst.ID)); err != nil {
+		t.Fatalf("LiftCreationThrottle: %v", err)
+	}
+	if err := s.LiftCreationThrottle(ctx, valid(burst.ID)); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("LiftCreationThrottle twice: got %v, want ErrNotFound", err)
+	}
+}
+
+func testAuditLog(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	admin, u, other := newUser(t, s, 5), newUser(t, s, 5), newUser(t, s, 5)
+
+	entries := []*storages.AuditEntry{
+		{ID: uuid.New().String(), At: "2020-06-28T23:59:59.000000Z", ActorID: admin.ID, UserID: u.ID, Action: storages.AuditImpersonate, Detail: "read: ticket"},
+		{ID: uuid.New().String(), At: "2020-06-29T08:00:00.000000Z", ActorID: admin.ID, UserID: u.ID, Action: storages.AuditRequest, Detail: "GET /tasks"},
+		{ID: uuid.New().String(), At: "2020-06-29T09:00:00.000000Z", ActorID: admin.ID, UserID: other.ID, Action: storages.AuditRequest, Detail: "GET /tasks"},
+		{ID: uuid.New().String(), At: "2020-06-30T00:00:00.000000Z", ActorID: admin.ID, UserID: u.ID, Action: storages.AuditRequest, Detail: "GET /me/settings"},
+	}
+	for i, e := range entries {
+		if err := s.AddAuditEntry(ctx, e); err != nil {
+			t.Fatalf("AddAuditEntry %d: %v", i, err)
+		}
+	}
+
+	got, err := s.RetrieveAuditLog(ctx, valid(u.ID), "2020-06-29", "2020-06-30")
+	if err != nil {
+		t.Fatalf("RetrieveAuditLog: %v", err)
+	}
+	if len(got) != 1 || *got[0] != *entries[1] {
+		t.Errorf("got %d entries of the impersonated user, want only %+v", len(got), *entries[1])
+	}
+
+	got, err = s.RetrieveAuditLog(ctx, valid(admin.ID), "2020-06-28", "2020-07-01")
+	if err != nil {
+		t.Fatalf("RetrieveAuditLog: %v", err)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("got %d entries of the admin, want %d", len(got), len(entries))
+	}
+	for i := range entries {
+		if *got[i] != *entries[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, *got[i], *entries[i])
+		}
+	}
+}
+
+func testAccountAudit(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	admin, u, other := newUser(t, s, 5), newUser(t, s, 5), newUser(t, s, 5)
+
+	entries := []*storages.AuditEntry{
+		{ID: "a1", At: "2020-06-29T08:00:00.000000Z", ActorID: u.ID, UserID: u.ID, Action: storages.AuditLogin, Detail: "from 127.0.0.1"},
+		{ID: "a2", At: "2020-06-29T09:00:00.000000Z", ActorID: admin.ID, UserID: u.ID, Action: storages.AuditLimitChange, Detail: "max_todo 5 to 10"},
+		{ID: "a3", At: "2020-06-29T09:00:00.000000Z", ActorID: u.ID, UserID: u.ID, Action: storages.AuditLogin, Detail: "from 127.0.0.1"},
+		{ID: "a4", At: "2020-06-29T10:00:00.000000Z", ActorID: other.ID, UserID: other.ID, Action: storages.AuditLogin, Detail: "from 127.0.0.1"},
+		{ID: "a5", At: "2020-06-29T11:00:00.000000Z", ActorID: u.ID, UserID: u.ID, Action: storages.AuditDelete, Detail: "1 tasks: t1"},
+	}
+	for i, e := range entries {
+		if err := s.AddAuditEntry(ctx, e); err != nil {
+			t.Fatalf("AddAuditEntry %d: %v", i, err)
+		}
+	}
+
+	check := func(q storages.AuditQuery, want ...*storages.AuditEntry) {
+		t.Helper()
+		got, err := s.RetrieveAccountAudit(ctx, &q)
+		if err != nil {
+			t.Fatalf("RetrieveAccountAudit %+v: %v", q, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("RetrieveAccountAudit %+v: got %d entries, want %d", q, len(got), len(want))
+		}
+		for i := range want {
+			if *got[i] != *want[i] {
+				t.Errorf("RetrieveAccountAudit %+v: entry %d: got %+v, want %+v", q, i, *got[i], *want[i])
+			}
+		}
+	}
+	// newest first, entries at the same time by ID, without the other user's
+	check(storages.AuditQuery{UserID: u.ID, N: 10}, entries[4], entries[2], entries[1], entries[0])
+	check(storages.AuditQuery{UserID: u.ID, N: 2}, entries[4], entries[2])
+	check(storages.AuditQuery{UserID: u.ID, BeforeAt: entries[2].At, BeforeID: entries[2].ID, N: 10}, entries[1], entries[0])
+	check(storages.AuditQuery{UserID: u.ID, Action: storages.AuditLogin, N: 10}, entries[2], entries[0])
+	check(storages.AuditQuery{UserID: u.ID, Action: storages.AuditLogin, BeforeAt: entries[2].At, BeforeID: entries[2].ID, N: 10}, entries[0])
+}
+
+func testUsers(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	got, err := s.RetrieveUser(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrieveUser: %v", err)
+	}
+	if got.ID != u.ID || got.MaxTodo != 5 || got.Role != "" || got.Password != "" {
+		t.Errorf("got %+v, want %s with max_todo 5, no role and no password hash", got, u.ID)
+	}
+
+	got.MaxTodo, got.Role = 7, storages.RoleAdmin
+	if err := s.UpdateUser(ctx, got); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if again, _ := s.RetrieveUser(ctx, valid(u.ID)); again == nil || *again != *got {
+		t.Errorf("got %+v after update, want %+v", again, got)
+	}
+
+	got.DeactivatedAt = time.Now().UTC().Format(storages.TimeLayout)
+	if err := s.UpdateUser(ctx, got); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if again, _ := s.RetrieveUser(ctx, valid(u.ID)); again == nil || *again != *got {
+		t.Errorf("got %+v after deactivating, want %+v", again, got)
+	}
+
+	task := newTask(u, "owned")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	if owner, err := s.RetrieveTaskOwner(ctx, valid(task.ID)); err != nil || owner != u.ID {
+		t.Errorf("RetrieveTaskOwner: got %q, %v, want %s", owner, err, u.ID)
+	}
+
+	unknown := valid(uuid.New().String())
+	if _, err := s.RetrieveUser(ctx, unknown); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveUser for an unknown user: got %v, want ErrNotFound", err)
+	}
+	if err := s.UpdateUser(ctx, &storages.User{ID: unknown.String}); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UpdateUser for an unknown user: got %v, want ErrNotFound", err)
+	}
+	if _, err := s.RetrieveTaskOwner(ctx, unknown); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveTaskOwner for an unknown task: got %v, want ErrNotFound", err)
+	}
+}
+
+func testSigningKeys(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	// keys are global, so only look at the ones this test adds
+	before := time.Now().UTC()
+	first := &storages.SigningKey{ID: uuid.New().String(), Algorithm: "ES256", PrivateKey: "first",
+		CreatedAt: before.Format(storages.TimeLayout)}
+	second := &storages.SigningKey{ID: uuid.New().String(), Algorithm: "ES256", PrivateKey: "second",
+		CreatedAt: before.Add(time.Second).Format(storages.TimeLayout)}
+	for _, k := range []*storages.SigningKey{first, second} {
+		if err := s.RotateSigningKey(ctx, k); err != nil {
+			t.Fatalf("RotateSigningKey: %v", err)
+		}
+	}
+
+	keys, err := s.RetrieveSigningKeys(ctx, before.Format(storages.TimeLayout))
+	if err != nil {
+		t.Fatalf("RetrieveSigningKeys: %v", err)
+	}
+	if len(keys) < 2 || keys[0].ID != second.ID || keys[0].RetiredAt != "" || keys[1].ID != first.ID || keys[1].RetiredAt != second.CreatedAt {
+		t.Fatalf("got %d keys, want %s in use then %s retired at %s", len(keys), second.ID, first.ID, second.CreatedAt)
+	}
+
+	keys, err = s.RetrieveSigningKeys(ctx, second.CreatedAt)
+	if err != nil {
+		t.Fatalf("RetrieveSigningKeys: %v", err)
+	}
+	for _, k := range keys {
+		if k.ID == first.ID {
+			t.Errorf("RetrieveSigningKeys returned %s, retired before the cutoff", first.ID)
+		}
+	}
+}
+
+func testShares(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+	other := newUser(t, s, 10)
+
+	now := "2020-06-29T12:00:00.000000Z"
+	open := &storages.Share{ID: uuid.New().String(), UserID: u.ID, Date: date, CreatedAt: now, ExpiresAt: "2020-07-06T12:00:00.000000Z"}
+	expired := &storages.Share{ID: uuid.New().String(), UserID: u.ID, Date: date, CreatedAt: "2020-06-20T12:00:00.000000Z", ExpiresAt: now}
+	for _, share := range []*storages.Share{open, expired} {
+		if err := s.AddShare(ctx, share); err != nil {
+			t.Fatalf("AddShare: %v", err)
+		}
+	}
+
+	shares, err := s.RetrieveShares(ctx, valid(u.ID), now)
+	if err != nil {
+		t.Fatalf("RetrieveShares: %v", err)
+	}
+	if len(shares) != 1 || *shares[0] != *open {
+		t.Errorf("got %+v, want only the share that hasn't expired", shares)
+	}
+	got, err := s.RetrieveShare(ctx, valid(open.ID), now)
+	if err != nil {
+		t.Fatalf("RetrieveShare: %v", err)
+	}
+	if *got != *open {
+		t.Errorf("got %+v, want %+v", *got, *open)
+	}
+	if _, err := s.RetrieveShare(ctx, valid(expired.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveShare of an expired share: got %v, want ErrNotFound", err)
+	}
+
+	if err := s.RevokeShare(ctx, valid(other.ID), valid(open.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RevokeShare of another user's share: got %v, want ErrNotFound", err)
+	}
+	if err := s.RevokeShare(ctx, valid(u.ID), valid(open.ID), now); err != nil {
+		t.Fatalf("RevokeShare: %v", err)
+	}
+	if err := s.RevokeShare(ctx, valid(u.ID), valid(open.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RevokeShare twice: got %v, want ErrNotFound", err)
+	}
+	if _, err := s.RetrieveShare(ctx, valid(open.ID), now); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveShare of a revoked share: got %v, want ErrNotFound", err)
+	}
+}
+
+func testStandup(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 10)
+	idle := newUser(t, s, 10)
+	out := newUser(t, s, 10)
+	if err := s.UpdateUserSettings(ctx, valid(out.ID), &storages.UserSettings{StandupOptOut: true}); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+
+	const yesterday = "2020-06-28"
+	done, left, low, high := newTask(u, "shipped"), newTask(u, "left over"), newTask(u, "low"), newTask(u, "high")
+	done.CreatedDate, left.CreatedDate = yesterday, yesterday
+	high.Priority = 2
+	hidden := newTask(out, "hidden")
+	for _, task := range []*storages.Task{done, left, low, high, hidden} {
+		if err := s.AddTask(ctx, task); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+	if _, err := s.CompleteTask(ctx, valid(u.ID), valid(done.ID), time.Now().UTC().Format(storages.TimeLayout)); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+
+	members, err := s.RetrieveStandup(ctx, yesterday, date)
+	if err != nil {
+		t.Fatalf("RetrieveStandup: %v", err)
+	}
+	got := map[string]*storages.StandupMember{}
+	for _, m := range members {
+		got[m.UserID] = m
+	}
+	if m := got[u.ID]; m == nil || len(m.Completed) != 1 || m.Completed[0] != "shipped" ||
+		len(m.Planned) != 2 || m.Planned[0] != "high" || m.Planned[1] != "low" {
+		t.Errorf("RetrieveStandup: got %+v, want shipped completed and high then low planned", m)
+	}
+	if m := got[idle.ID]; m == nil || len(m.Completed) != 0 || len(m.Planned) != 0 {
+		t.Errorf("RetrieveStandup: got %+v for a user without tasks, want them with nothing", m)
+	}
+	if m := got[out.ID]; m != nil {
+		t.Errorf("RetrieveStandup: got %+v for a user who opted out", m)
+	}
+
+	if first, err := s.MarkStandupSent(ctx, date, time.Now().UTC().Format(storages.TimeLayout)); err != nil || !first {
+		t.Errorf("MarkStandupSent: got %v, %v, want first", first, err)
+	}
+	if first, err := s.MarkStandupSent(ctx, date, time.Now().UTC().Format(storages.TimeLayout)); err != nil || first {
+		t.Errorf("MarkStandupSent again: got %v, %v, want not first", first, err)
+	}
+}
+
+func testUserSettings(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	got, err := s.RetrieveUserSettings(ctx, valid(u.ID))
+	if err != nil {
+		t.Fatalf("RetrieveUserSettings: %v", err)
+	}
+	if *got != (storages.UserSettings{}) {
+		t.Errorf("got %+v, want everything off for a new user", *got)
+	}
+
+	want := storages.UserSettings{NotifyLimitReached: true, CarryOver: storages.CarryOverMove,
+		DailyDigest: true, Email: "someone@example.com", Timezone: "Asia/Ho_Chi_Minh", QuotaMode: storages.QuotaSoft,
+		StandupOptOut: true}
+	if err := s.UpdateUserSettings(ctx, valid(u.ID), &want); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+	if got, _ := s.RetrieveUserSettings(ctx, valid(u.ID)); got == nil || *got != want {
+		t.Errorf("got %+v after update, want %+v", got, want)
+	}
+	users, err := s.RetrieveCarryOverUsers(ctx)
+	if err != nil {
+		t.Fatalf("RetrieveCarryOverUsers: %v", err)
+	}
+	if users[u.ID] != storages.CarryOverMove {
+		t.Errorf("RetrieveCarryOverUsers: got mode %q for %s, want %q", users[u.ID], u.ID, storages.CarryOverMove)
+	}
+	recipients, err := s.RetrieveDigestRecipients(ctx)
+	if err != nil {
+		t.Fatalf("RetrieveDigestRecipients: %v", err)
+	}
+	found := false
+	for _, r := range recipients {
+		if r.UserID == u.ID {
+			found = r.Email == want.Email && r.Timezone == want.Timezone
+		}
+	}
+	if !found {
+		t.Errorf("RetrieveDigestRecipients: %s missing or with the wrong email or timezone", u.ID)
+	}
+	for i, want := range []bool{true, false} {
+		first, err := s.MarkDigestSent(ctx, valid(u.ID), valid(date))
+		if err != nil {
+			t.Fatalf("MarkDigestSent: %v", err)
+		}
+		if first != want {
+			t.Errorf("MarkDigestSent call %d: got first %v, want %v", i+1, first, want)
+		}
+	}
+
+	u.DeactivatedAt = time.Now().UTC().Format(storages.TimeLayout)
+	if err := s.UpdateUser(ctx, u); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if users, _ := s.RetrieveCarryOverUsers(ctx); users[u.ID] != "" {
+		t.Errorf("RetrieveCarryOverUsers: got deactivated user %s", u.ID)
+	}
+	recipients, _ = s.RetrieveDigestRecipients(ctx)
+	for _, r := range recipients {
+		if r.UserID == u.ID {
+			t.Errorf("RetrieveDigestRecipients: got deactivated user %s", u.ID)
+		}
+	}
+
+	unknown := valid(uuid.New().String())
+	if _, err := s.RetrieveUserSettings(ctx, unknown); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("RetrieveUserSettings for an unknown user: got %v, want ErrNotFound", err)
+	}
+	if err := s.UpdateUserSettings(ctx, unknown, &want); !errors.Is(err, storages.ErrNotFound) {
+		t.Errorf("UpdateUserSettings for an unknown user: got %v, want ErrNotFound", err)
+	}
+
+	for i, want := range []bool{true, false} {
+		first, err := s.MarkLimitNotified(ctx, valid(u.ID), valid(date))
+		if err != nil {
+			t.Fatalf("MarkLimitNotified: %v", err)
+		}
+		if first != want {
+			t.Errorf("MarkLimitNotified call %d: got first=%v, want %v", i+1, first, want)
+		}
+	}
+}
+
+func testErrors(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+
+	task := newTask(u, "once")
+	if err := s.AddTask(ctx, task); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	if err := s.AddTask(ctx, task); err == nil {
+		t.Error("AddTask with a duplicate id: want error")
+	}
+	if err := s.AddUser(ctx, u); err == nil {
+		t.Error("AddUser with a duplicate id: want error")
+	}
+	if _, _, err := s.CountTasks(ctx, valid(uuid.New().String()), valid(date)); err == nil {
+		t.Error("CountTasks for an unknown user: want error")
+	}
+	if _, err := s.RetrieveTasks(ctx, valid(u.ID), valid(date), storages.ListOptions{Fields: []string{"password"}}); err == nil {
+		t.Error("RetrieveTasks with an unknown field: want error")
+	}
+	if _, err := s.RetrieveTasks(ctx, valid(u.ID), valid(date), storages.ListOptions{Sort: "content; DROP TABLE tasks"}); err == nil {
+		t.Error("RetrieveTasks with an unknown sort key: want error")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := s.AddTask(cancelled, newTask(u, "cancelled")); err == nil {
+		t.Error("AddTask with a cancelled context: want error")
+	}
+}
+
+func testLimitHits(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	// a max_todo no other test uses, so the cohort is these users alone
+	maxTodo := 100000 + int(time.Now().UnixNano()%100000)
+	heavy, light := newUser(t, s, maxTodo), newUser(t, s, maxTodo)
+	newUser(t, s, maxTodo)
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	for _, hit := range []struct{ user, date string }{
+		{heavy.ID, date}, {heavy.ID, date}, {heavy.ID, "2020-06-30"}, {light.ID, date},
+	} {
+		if err := s.RecordLimitHit(ctx, valid(hit.user), valid(hit.date), at); err != nil {
+			t.Fatalf("RecordLimitHit: %v", err)
+		}
+	}
+	if _, err := s.RollUpLimitHits(ctx, date); err != nil {
+		t.Fatalf("RollUpLimitHits: %v", err)
+	}
+
+	rollups, err := s.RetrieveLimitHitRollups(ctx, date, date)
+	if err != nil {
+		t.Fatalf("RetrieveLimitHitRollups: %v", err)
+	}
+	var got *storages.LimitHitRollup
+	for _, r := range rollups {
+		if r.MaxTodo == maxTodo {
+			got = r
+		}
+	}
+	if got == nil || got.Users != 2 || got.Attempts != 3 || got.CohortUsers != 3 {
+		t.Errorf("got rollup %+v, want 2 of 3 users with 3 attempts", got)
+	}
+
+	offenders, err := s.RetrieveLimitOffenders(ctx, date, "2020-06-30", 1000)
+	if err != nil {
+		t.Fatalf("RetrieveLimitOffenders: %v", err)
+	}
+	rank := map[string]int{}
+	for i, o := range offenders {
+		rank[o.UserID] = i + 1
+		if o.UserID == heavy.ID && (o.Days != 2 || o.Attempts != 3 || o.LastDate != "2020-06-30") {
+			t.Errorf("got offender %+v, want 2 days and 3 attempts up to 2020-06-30", o)
+		}
+	}
+	if rank[heavy.ID] == 0 || rank[light.ID] == 0 || rank[heavy.ID] > rank[light.ID] {
+		t.Errorf("got ranks %d and %d, want the heavier offender first", rank[heavy.ID], rank[light.ID])
+	}
+}
+
+func testLongContent(t *testing.T, s storages.Store) {
+	ctx := context.Background()
+	u := newUser(t, s, 5)
+	long := newTask(u, strings.Repeat("a long description ", 500))
+	if _, _, err := s.AddTaskWithLimitPerDay(ctx, long, nil); err != nil {
+		t.Fatalf("AddTaskWithLimitPerDay: %v", err)
+	}
+
+	whole := func(when string) {
+		t.Helper()
+		got, err := s.RetrieveTask(ctx, valid(u.ID), valid(long.ID))
+		if err != nil {
+			t.Fatalf("RetrieveTask %s: %v", when, err)
+		}
+		if got.Content != long.Content || got.ContentTruncated {
+			t.Errorf("RetrieveTask %s: got %d bytes, want the whole %d", when, len(got.Content), len(long.Content))
+		}
+	}
+	whole("after adding")
+
+	listed := retrieve(t, s, u, date, storages.ListOptions{})
+	if len(listed) != 1 || (listed[0].Content != long.Content) != listed[0].ContentTruncated {
+		t.Errorf("got %+v, want the whole content or a summary flagged as one", listed)
+	}
+
+	at := time.Now().UTC().Format(storages.TimeLayout)
+	tasks, err := s.RetrieveTasksAsOf(ctx, valid(u.ID), valid(date), at)
+	if err != nil {
+		t.Fatalf("RetrieveTasksAsOf: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Content != long.Content {
+		t.Errorf("RetrieveTasksAsOf: got %+v, want the whole content", tasks)
+	}
+
+	undo := &storages.Undo{Token: uuid.New().String(), UserID: u.ID, ExpiresAt: time.Now().Add(time.Minute).UTC().Format(storages.TimeLayout)}
+	if _, err := s.DeleteTasks(ctx, valid(u.ID), []string{long.ID}, undo); err != nil {
+		t.Fatalf("DeleteTasks: %v", err)
+	}
+	if _, err := s.UndoTasks(ctx, valid(u.ID), undo.Token, at); err != nil {
+		t.Fatalf("UndoTasks: %v", err)
+	}
+	whole("after undoing its deletion")
+}
+
+func newUser(t *testing.T, s storages.Store, maxTodo int) *storages.User {
+	t.Helper()
+	u := &storages.User{
+		ID:       "storagetest-" + uuid.New().String(),
+		Password: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5",
+		MaxTodo:  maxTodo,
+	}
+	if err := s.AddUser(context.Background(), u); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	return u
+}
+
+func newTask(u *storages.User, content string) *storages.Task {
+	return &storages.Task{
+		ID:          uuid.New().String(),
+		Content:     content,
+		UserID:      u.ID,
+		CreatedDate: date,
+		CreatedAt:   time.Now().UTC().Format(storages.TimeLayout),
+	}
+}
+
+func retrieve(t *testing.T, s storages.Store, u *storages.User, createdDate string, opts storages.ListOptions) []*storages.Task {
+	t.Helper()
+	tasks, err := s.RetrieveTasks(context.Background(), valid(u.ID), valid(createdDate), opts)
+	if err != nil {
+		t.Fatalf("RetrieveTasks: %v", err)
+	}
+	return tasks
+}
+
+func valid(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
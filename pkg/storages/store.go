@@ -0,0 +1,113 @@
+package storages
+
+import (
+	"context"
+	"database/sql"
+)
+
+// TaskCursor reads tasks one at a time, so jobs going through many of them hold one in memory.
+// It is used like sql.Rows: call Next before each Task, check Err once Next returns false, and
+// always Close it.
+type TaskCursor interface {
+	// Next advances to the next task, returning false after the last one or on an error
+	Next() bool
+	// Task is the task Next advanced to
+	Task() *Task
+	Err() error
+	Close() error
+}
+
+// Store is implemented by every storage backend the service layer can run on
+type Store interface {
+	RetrieveTasks(ctx context.Context, userID, createdDate sql.NullString, opts ListOptions) ([]*Task, error)
+	RetrieveTask(ctx context.Context, userID, taskID sql.NullString) (*Task, error)
+	RetrieveCompletedTasks(ctx context.Context, from, to string) ([]*Task, error)
+	IterateCompletedTasks(ctx context.Context, from, to string) (TaskCursor, error)
+	IterateTasks(ctx context.Context, from, to string) (TaskCursor, error)
+	AddTask(ctx context.Context, t *Task) error
+	AddTasks(ctx context.Context, tasks []*Task) error
+	AddTaskWithLimitPerDay(ctx context.Context, t *Task, key *IdempotencyKey) (count, maxTodo int, err error)
+	MoveTask(ctx context.Context, move *TaskMove) (*Task, error)
+	AddTaskLink(ctx context.Context, link *TaskLink) error
+	RemoveTaskLink(ctx context.Context, userID, taskID, blockedByID sql.NullString) error
+	RetrieveTaskLinks(ctx context.Context, userID sql.NullString) ([]*TaskLink, error)
+	AddLabel(ctx context.Context, label *Label) error
+	RetrieveLabels(ctx context.Context, userID sql.NullString) ([]*Label, error)
+	UpdateLabel(ctx context.Context, label *Label) error
+	DeleteLabel(ctx context.Context, userID, labelID sql.NullString) error
+	AssignLabel(ctx context.Context, userID, taskID, labelID sql.NullString, at string) error
+	UnassignLabel(ctx context.Context, userID, taskID, labelID sql.NullString) error
+	RetrieveTaskLabels(ctx context.Context, userID, taskID sql.NullString) ([]*Label, error)
+	SetTaskLocation(ctx context.Context, userID, taskID sql.NullString, loc Location) error
+	RemoveTaskLocation(ctx context.Context, userID, taskID sql.NullString) error
+	RetrieveTaskLocation(ctx context.Context, userID, taskID sql.NullString) (*Location, error)
+	SetTaskEstimate(ctx context.Context, userID, taskID sql.NullString, minutes int) error
+	StartTimer(ctx context.Context, e *TimeEntry) (stopped *TimeEntry, err error)
+	StopTimer(ctx context.Context, userID, taskID sql.NullString, at string) (*TimeEntry, error)
+	RetrieveTimeEntries(ctx context.Context, userID, taskID sql.NullString) ([]*TimeEntry, error)
+	StartPomodoro(ctx context.Context, p *Pomodoro) error
+	CompletePomodoro(ctx context.Context, userID, taskID, pomodoroID sql.NullString, at string) (*Pomodoro, error)
+	RetrievePomodoros(ctx context.Context, userID, taskID sql.NullString) ([]*Pomodoro, error)
+	RetrieveCreationRates(ctx context.Context, baselineFrom, from, to string, min int) ([]*CreationRate, error)
+	AddAnomaly(ctx context.Context, a *Anomaly, throttle *CreationThrottle) (first bool, err error)
+	RetrieveAnomalies(ctx context.Context, from, to string) ([]*Anomaly, error)
+	RetrieveCreationThrottle(ctx context.Context, userID sql.NullString, now string) (*CreationThrottle, error)
+	LiftCreationThrottle(ctx context.Context, userID sql.NullString) error
+	CountTasksCreatedSince(ctx context.Context, userID sql.NullString, since string) (count int, oldest string, err error)
+	RetrieveNearbyTasks(ctx context.Context, q *NearbyQuery) ([]*NearbyTask, error)
+	RetrieveTasksAsOf(ctx context.Context, userID, createdDate sql.NullString, at string) ([]*Task, error)
+	RetrieveLinkedTasks(ctx context.Context, userID, taskID sql.NullString) (blockedBy, blocking []*Task, err error)
+	CompleteTask(ctx context.Context, userID, taskID sql.NullString, at string) (*Task, error)
+	CompleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, at string, undo *Undo) (notFound []string, err error)
+	DeleteTasks(ctx context.Context, userID sql.NullString, taskIDs []string, undo *Undo) (notFound []string, err error)
+	UndoTasks(ctx context.Context, userID sql.NullString, token, now string) (restored int, err error)
+	CarryOverTasks(ctx context.Context, co *CarryOver) (int, error)
+	CountTasks(ctx context.Context, userID, createdDate sql.NullString) (count, maxTodo int, err error)
+	RetrievePriorityCounts(ctx context.Context, userID, createdDate sql.NullString) ([]*PriorityCount, error)
+	RetrieveContentSuggestions(ctx context.Context, userID sql.NullString, prefix string, n int) ([]*ContentSuggestion, error)
+	RetrieveDayCounts(ctx context.Context, userID sql.NullString, from, to string) ([]*DayCount, error)
+	UpdateStreaks(ctx context.Context, day string) (int, error)
+	RetrieveStreak(ctx context.Context, userID sql.NullString) (*Streak, error)
+	RetrieveLimitSchedule(ctx context.Context, userID sql.NullString) (*LimitSchedule, error)
+	UpdateLimitSchedule(ctx context.Context, userID sql.NullString, sched *LimitSchedule) error
+	AddQuotaAdjustment(ctx context.Context, a *QuotaAdjustment) error
+	RetrieveQuotaAdjustments(ctx context.Context, userID sql.NullString, from, to string) ([]*QuotaAdjustment, error)
+	ReplaceHolidays(ctx context.Context, from, to string, dates []string) error
+	IncrementAPIUsage(ctx context.Context, userID sql.NullString, day string) (calls int, err error)
+	RetrieveAPIUsage(ctx context.Context, userID sql.NullString, from, to string) ([]*APIUsage, error)
+	AddAuditEntry(ctx context.Context, e *AuditEntry) error
+	RetrieveAuditLog(ctx context.Context, userID sql.NullString, from, to string) ([]*AuditEntry, error)
+	RetrieveAccountAudit(ctx context.Context, q *AuditQuery) ([]*AuditEntry, error)
+	AddUser(ctx context.Context, u *User) error
+	AddInvite(ctx context.Context, inv *Invite) error
+	SignUp(ctx context.Context, code string, u *User, at string) error
+	RetrieveUser(ctx context.Context, userID sql.NullString) (*User, error)
+	UpdateUser(ctx context.Context, u *User) error
+	RetrieveTaskOwner(ctx context.Context, taskID sql.NullString) (string, error)
+	RetrievePasswordHash(ctx context.Context, userID sql.NullString) (string, error)
+	UpdatePasswordHash(ctx context.Context, userID sql.NullString, hash string) error
+	RetrieveUserSettings(ctx context.Context, userID sql.NullString) (*UserSettings, error)
+	UpdateUserSettings(ctx context.Context, userID sql.NullString, settings *UserSettings) error
+	RetrieveCarryOverUsers(ctx context.Context) (map[string]string, error)
+	RetrieveDigestRecipients(ctx context.Context) ([]*DigestRecipient, error)
+	MarkDigestSent(ctx context.Context, userID, date sql.NullString) (first bool, err error)
+	RetrieveStandup(ctx context.Context, yesterday, today string) ([]*StandupMember, error)
+	MarkStandupSent(ctx context.Context, date, at string) (first bool, err error)
+	MarkLimitNotified(ctx context.Context, userID, date sql.NullString) (first bool, err error)
+	RecordLimitHit(ctx context.Context, userID, date sql.NullString, at string) error
+	RollUpLimitHits(ctx context.Context, date string) (int, error)
+	RetrieveLimitHitRollups(ctx context.Context, from, to string) ([]*LimitHitRollup, error)
+	RetrieveLimitOffenders(ctx context.Context, from, to string, n int) ([]*LimitOffender, error)
+	RotateSigningKey(ctx context.Context, k *SigningKey) error
+	RetrieveSigningKeys(ctx context.Context, retiredAfter string) ([]*SigningKey, error)
+	AddAPIKey(ctx context.Context, k *APIKey) error
+	RetrieveAPIKeyUser(ctx context.Context, keyHash string) (string, error)
+	AddSession(ctx context.Context, sess *Session) error
+	RetrieveSessions(ctx context.Context, userID sql.NullString, now string) ([]*Session, error)
+	ValidateSession(ctx context.Context, userID, sessionID sql.NullString, now string) bool
+	RevokeSession(ctx context.Context, userID, sessionID sql.NullString, now string) error
+	AddShare(ctx context.Context, share *Share) error
+	RetrieveShares(ctx context.Context, userID sql.NullString, now string) ([]*Share, error)
+	RetrieveShare(ctx context.Context, shareID sql.NullString, now string) (*Share, error)
+	RevokeShare(ctx context.Context, userID, shareID sql.NullString, now string) error
+}
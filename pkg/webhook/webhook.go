@@ -0,0 +1,173 @@
+// Package webhook verifies inbound webhook requests, like Slack's and GitHub's, and refuses
+// captured requests sent again. Integrations receiving webhooks wrap their handler with a
+// Verifier:
+//
+//	v := &webhook.Verifier{Verify: webhook.Slack(signingSecret), Replays: &webhook.ReplayCache{}}
+//	r.Post("/integrations/slack", v.Middleware(slackEvents).ServeHTTP)
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tolerance is how far from now a signed timestamp may be. Requests older than that are
+// refused, so replays only need to be remembered for as long.
+const Tolerance = 5 * time.Minute
+
+// DefaultMaxBody bounds the bodies a Verifier reads unless it sets MaxBody
+const DefaultMaxBody = 1 << 20
+
+// Errors Verify functions and Verifier answer
+var (
+	ErrSignature = errors.New("webhook: missing or wrong signature")
+	ErrTimestamp = errors.New("webhook: timestamp missing or too far from now")
+	ErrReplay    = errors.New("webhook: request already received")
+	ErrTooLarge  = errors.New("webhook: body too large")
+)
+
+// VerifyFunc checks the signature of a request with its body at now. It returns the ID the
+// delivery is known by, which a replay has too, and until when it must be remembered.
+type VerifyFunc func(h http.Header, body []byte, now time.Time) (id string, until time.Time, err error)
+
+// Slack verifies Slack's v0 signatures: X-Slack-Signature is v0= and the hex HMAC-SHA256 of
+// "v0:<X-Slack-Request-Timestamp>:<body>" keyed with the app's signing secret. The signature
+// identifies the delivery, it covers the timestamp.
+func Slack(signingSecret string) VerifyFunc {
+	return func(h http.Header, body []byte, now time.Time) (string, time.Time, error) {
+		ts := h.Get("X-Slack-Request-Timestamp")
+		at, err := timestamp(ts, now)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		sig := h.Get("X-Slack-Signature")
+		mac := hmac.New(sha256.New, []byte(signingSecret))
+		mac.Write([]byte("v0:" + ts + ":"))
+		mac.Write(body)
+		if !hmac.Equal([]byte(sig), []byte("v0="+hex.EncodeToString(mac.Sum(nil)))) {
+			return "", time.Time{}, ErrSignature
+		}
+		return "slack:" + sig, at.Add(Tolerance), nil
+	}
+}
+
+// GitHub verifies X-Hub-Signature-256, sha256= and the hex HMAC-SHA256 of the body keyed
+// with the webhook secret. GitHub signs no timestamp, so X-GitHub-Delivery identifies the
+// delivery and is remembered for 24 hours; redelivering it from GitHub is refused as well.
+func GitHub(secret string) VerifyFunc {
+	return func(h http.Header, body []byte, now time.Time) (string, time.Time, error) {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if !hmac.Equal([]byte(h.Get("X-Hub-Signature-256")), []byte("sha256="+hex.EncodeToString(mac.Sum(nil)))) {
+			return "", time.Time{}, ErrSignature
+		}
+		id := h.Get("X-GitHub-Delivery")
+		if id == "" {
+			return "", time.Time{}, ErrSignature
+		}
+		return "github:" + id, now.Add(24 * time.Hour), nil
+	}
+}
+
+// timestamp parses Unix seconds, failing when they're further than Tolerance from now
+func timestamp(s string, now time.Time) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrTimestamp
+	}
+	at := time.Unix(sec, 0)
+	if d := now.Sub(at); d > Tolerance || d < -Tolerance {
+		return time.Time{}, ErrTimestamp
+	}
+	return at, nil
+}
+
+// ReplayCache remembers the deliveries received until they expire. It's kept in memory, so
+// each instance behind a load balancer has its own. The zero ReplayCache is ready to use.
+type ReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Seen records id until until, reporting whether it was recorded already
+func (c *ReplayCache) Seen(id string, until, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seen == nil {
+		c.seen = map[string]time.Time{}
+	}
+	if exp, ok := c.seen[id]; ok && now.Before(exp) {
+		return true
+	}
+	// expired deliveries are swept whenever the cache reaches a power of two from 1024 of them
+	if len(c.seen) >= 1024 && len(c.seen)&(len(c.seen)-1) == 0 {
+		for k, exp := range c.seen {
+			if !now.Before(exp) {
+				delete(c.seen, k)
+			}
+		}
+	}
+	c.seen[id] = until
+	return false
+}
+
+// Verifier refuses requests Verify fails with 401, and those Replays saw already with 409
+type Verifier struct {
+	Verify VerifyFunc
+	// Replays remembers deliveries, they aren't checked for replays when nil
+	Replays *ReplayCache
+	// MaxBody bounds the bodies read, DefaultMaxBody when 0. Longer ones answer 413.
+	MaxBody int64
+}
+
+// Check verifies req, reading its body and putting it back for handlers to read
+func (v *Verifier) Check(req *http.Request, now time.Time) error {
+	max := v.MaxBody
+	if max == 0 {
+		max = DefaultMaxBody
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, max+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) > max {
+		return ErrTooLarge
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	id, until, err := v.Verify(req.Header, body, now)
+	if err != nil {
+		return err
+	}
+	if v.Replays != nil && v.Replays.Seen(id, until, now) {
+		return ErrReplay
+	}
+	return nil
+}
+
+// Middleware calls next with the requests Check accepts
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		err := v.Check(req, time.Now())
+		switch {
+		case err == nil:
+			next.ServeHTTP(resp, req)
+		case errors.Is(err, ErrReplay):
+			http.Error(resp, err.Error(), http.StatusConflict)
+		case errors.Is(err, ErrSignature), errors.Is(err, ErrTimestamp):
+			http.Error(resp, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, ErrTooLarge):
+			http.Error(resp, err.Error(), http.StatusRequestEntityTooLarge)
+		default:
+			http.Error(resp, "webhook: reading the body failed", http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slackRequest is the request Slack sends with body at ts, signed with secret
+func slackRequest(secret, body string, ts time.Time) *http.Request {
+	sec := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + sec + ":" + body))
+	req := httptest.NewRequest("POST", "/integrations/slack", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", sec)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// githubRequest is the request GitHub sends with body as delivery id, signed with secret
+func githubRequest(secret, body, id string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest("POST", "/integrations/github", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set("X-GitHub-Delivery", id)
+	return req
+}
+
+func TestSlack(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	body := `{"type":"event_callback"}`
+	tests := []struct {
+		name string
+		req  *http.Request
+		want error
+	}{
+		{"signed", slackRequest("secret", body, now), nil},
+		{"signed a while ago", slackRequest("secret", body, now.Add(-Tolerance+time.Second)), nil},
+		{"wrong secret", slackRequest("other", body, now), ErrSignature},
+		{"too old", slackRequest("secret", body, now.Add(-Tolerance-time.Second)), ErrTimestamp},
+		{"in the future", slackRequest("secret", body, now.Add(Tolerance+time.Second)), ErrTimestamp},
+		{"no timestamp", httptest.NewRequest("POST", "/", strings.NewReader(body)), ErrTimestamp},
+	}
+	for _, tt := range tests {
+		v := &Verifier{Verify: Slack("secret")}
+		if err := v.Check(tt.req, now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Check: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// the timestamp is signed, moving it into the tolerance breaks the signature
+	req := slackRequest("secret", body, now.Add(-time.Hour))
+	req.Header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	if err := (&Verifier{Verify: Slack("secret")}).Check(req, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("Check of a request with its timestamp changed: got %v, want %v", err, ErrSignature)
+	}
+
+	// so is the body
+	req = slackRequest("secret", body, now)
+	req.Body = io.NopCloser(strings.NewReader(`{"type":"url_verification"}`))
+	if err := (&Verifier{Verify: Slack("secret")}).Check(req, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("Check of a request with its body changed: got %v, want %v", err, ErrSignature)
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	body := `{"action":"opened"}`
+	tests := []struct {
+		name string
+		req  *http.Request
+		want error
+	}{
+		{"signed", githubRequest("secret", body, "d1"), nil},
+		{"wrong secret", githubRequest("other", body, "d1"), ErrSignature},
+		{"no delivery", githubRequest("secret", body, ""), ErrSignature},
+	}
+	for _, tt := range tests {
+		v := &Verifier{Verify: GitHub("secret")}
+		if err := v.Check(tt.req, now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Check: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCheckKeepsBody(t *testing.T) {
+	body := `{"type":"event_callback"}`
+	req := githubRequest("secret", body, "d1")
+	if err := (&Verifier{Verify: GitHub("secret")}).Check(req, time.Now()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if got, err := io.ReadAll(req.Body); err != nil || string(got) != body {
+		t.Errorf("body after Check: got %q, %v, want %q", got, err, body)
+	}
+
+	req = githubRequest("secret", strings.Repeat("x", 11), "d2")
+	if err := (&Verifier{Verify: GitHub("secret"), MaxBody: 10}).Check(req, time.Now()); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Check of a body over MaxBody: got %v, want %v", err, ErrTooLarge)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	v := &Verifier{Verify: GitHub("secret"), Replays: &ReplayCache{}}
+	if err := v.Check(githubRequest("secret", "{}", "d1"), now); err != nil {
+		t.Fatalf("Check of the first delivery: %v", err)
+	}
+	if err := v.Check(githubRequest("secret", "{}", "d1"), now.Add(time.Hour)); !errors.Is(err, ErrReplay) {
+		t.Errorf("Check of the same delivery again: got %v, want %v", err, ErrReplay)
+	}
+	if err := v.Check(githubRequest("secret", "{}", "d2"), now); err != nil {
+		t.Errorf("Check of another delivery: %v", err)
+	}
+	// a replay failing the signature isn't one, it doesn't take up the ID either
+	if err := v.Check(githubRequest("other", "{}", "d3"), now); !errors.Is(err, ErrSignature) {
+		t.Errorf("Check of a delivery with a wrong secret: got %v, want %v", err, ErrSignature)
+	}
+	if err := v.Check(githubRequest("secret", "{}", "d3"), now); err != nil {
+		t.Errorf("Check of the delivery signed right: %v", err)
+	}
+	// deliveries are forgotten once they expire
+	if err := v.Check(githubRequest("secret", "{}", "d1"), now.Add(25*time.Hour)); err != nil {
+		t.Errorf("Check of the delivery after 25 hours: %v", err)
+	}
+
+	// Slack deliveries are known by their signature, so they're remembered for the tolerance
+	v = &Verifier{Verify: Slack("secret"), Replays: &ReplayCache{}}
+	if err := v.Check(slackRequest("secret", "{}", now), now); err != nil {
+		t.Fatalf("Check of the first Slack delivery: %v", err)
+	}
+	if err := v.Check(slackRequest("secret", "{}", now), now.Add(time.Minute)); !errors.Is(err, ErrReplay) {
+		t.Errorf("Check of the same Slack delivery again: got %v, want %v", err, ErrReplay)
+	}
+}
+
+func TestReplayCacheSweep(t *testing.T) {
+	var c ReplayCache
+	now := time.Unix(1600000000, 0)
+	for i := 0; i < 1024; i++ {
+		c.Seen(strconv.Itoa(i), now.Add(time.Minute), now)
+	}
+	later := now.Add(time.Hour)
+	if c.Seen("new", later.Add(time.Minute), later) {
+		t.Fatal("Seen of a new ID reported it seen")
+	}
+	if len(c.seen) != 1 {
+		t.Errorf("cache keeps %d IDs after the sweep, want 1", len(c.seen))
+	}
+	if !c.Seen("new", later.Add(time.Minute), later) {
+		t.Error("Seen of the ID again reported it new")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	v := &Verifier{Verify: GitHub("secret"), Replays: &ReplayCache{}, MaxBody: 64}
+	var got string
+	h := v.Middleware(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got = string(body)
+	}))
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"signed", githubRequest("secret", "{}", "d1"), http.StatusOK},
+		{"replayed", githubRequest("secret", "{}", "d1"), http.StatusConflict},
+		{"wrong secret", githubRequest("other", "{}", "d2"), http.StatusUnauthorized},
+		{"too large", githubRequest("secret", strings.Repeat("x", 65), "d3"), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, tt.req)
+		if resp.Code != tt.want {
+			t.Errorf("%s: got status %d: %s, want %d", tt.name, resp.Code, resp.Body, tt.want)
+		}
+	}
+	if got != "{}" {
+		t.Errorf("handler read %q, want the body", got)
+	}
+}